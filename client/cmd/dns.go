@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var dnsCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Inspect the client DNS resolver",
+	Long:  "Provides commands for inspecting the DNS resolver of the NetBird client.",
+}
+
+var dnsQueriesCmd = &cobra.Command{
+	Use:   "queries",
+	Short: "List recent DNS queries",
+	Long: "Lists the DNS queries recently handled by the client resolver with the handler that answered them: " +
+		"the NetBird records (local), a nameserver group, the original nameservers of the host (host), a DNS route or none.",
+	Example: "  netbird dns queries",
+	RunE:    dnsQueriesList,
+}
+
+func init() {
+	rootCmd.AddCommand(dnsCmd)
+	dnsCmd.AddCommand(dnsQueriesCmd)
+}
+
+func dnsQueriesList(cmd *cobra.Command, _ []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.GetDNSQueries(cmd.Context(), &proto.GetDNSQueriesRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get DNS queries: %v", status.Convert(err).Message())
+	}
+
+	if !resp.GetEnabled() {
+		cmd.Println("DNS query logging is disabled, set NB_ENABLE_DNS_QUERY_LOG=true in the environment of the NetBird service to enable it.")
+	} else if len(resp.GetQueries()) == 0 {
+		cmd.Println("No DNS queries recorded.")
+	} else {
+		for _, query := range resp.GetQueries() {
+			cmd.Println(formatDNSQuery(query))
+		}
+	}
+
+	if stats := resp.GetStats(); stats != nil {
+		cmd.Printf("\nDNS queries: %s\n", parseDNSQueryStats(mapDNSQueryStats(stats)))
+	}
+
+	return nil
+}
+
+func formatDNSQuery(query *proto.DNSQuery) string {
+	rcode := query.GetRcode()
+	if rcode == "" {
+		rcode = "no answer"
+	}
+
+	line := fmt.Sprintf("%s %-6s %s %s via %s",
+		query.GetTimestamp().AsTime().Local().Format(time.RFC3339),
+		query.GetType(),
+		query.GetName(),
+		rcode,
+		query.GetHandler(),
+	)
+
+	if query.GetPattern() != "" {
+		line += " [" + query.GetPattern() + "]"
+	}
+	if query.GetCached() {
+		line += " cached"
+	} else if query.GetUpstream() != "" {
+		line += " upstream " + query.GetUpstream()
+	}
+
+	return line + " in " + query.GetLatency().AsDuration().Round(time.Microsecond).String()
+}
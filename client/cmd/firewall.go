@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var firewallCmd = &cobra.Command{
+	Use:   "firewall",
+	Short: "Inspect the client firewall",
+	Long:  "Provides commands for inspecting the firewall rules applied by the NetBird client.",
+}
+
+var firewallRulesCmd = &cobra.Command{
+	Use:     "rules",
+	Short:   "List peer firewall rules",
+	Long:    "Lists the peer firewall rules with the policy they were generated from and the number of packets they matched.",
+	Example: "  netbird firewall rules",
+	RunE:    firewallRulesList,
+}
+
+func init() {
+	rootCmd.AddCommand(firewallCmd)
+	firewallCmd.AddCommand(firewallRulesCmd)
+}
+
+func firewallRulesList(cmd *cobra.Command, _ []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.ListFirewallRules(cmd.Context(), &proto.ListFirewallRulesRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to list firewall rules: %v", status.Convert(err).Message())
+	}
+
+	if len(resp.GetRules()) == 0 {
+		cmd.Println("No firewall rules applied.")
+		return nil
+	}
+
+	cmd.Printf("%-36s %-15s %-4s %-6s %-6s %-11s %12s %14s\n", "POLICY", "PEER", "DIR", "ACTION", "PROTO", "PORT", "PACKETS", "BYTES")
+	for _, rule := range resp.GetRules() {
+		cmd.Println(formatFirewallRule(rule))
+	}
+
+	return nil
+}
+
+func formatFirewallRule(rule *proto.FirewallRule) string {
+	policyID := rule.GetPolicyId()
+	if policyID == "" {
+		policyID = "-"
+	}
+	port := rule.GetPort()
+	if port == "" {
+		port = "all"
+	}
+
+	return fmt.Sprintf("%-36s %-15s %-4s %-6s %-6s %-11s %12d %14d",
+		policyID,
+		rule.GetPeerIp(),
+		rule.GetDirection(),
+		rule.GetAction(),
+		rule.GetProtocol(),
+		port,
+		rule.GetPackets(),
+		rule.GetBytes(),
+	)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"net/netip"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+var flowsCmd = &cobra.Command{
+	Use:     "flows",
+	Short:   "List connection flow events",
+	Long:    "Lists the connection flow events recorded by the userspace packet filter that were not uploaded to the management service yet.",
+	Example: "  netbird flows",
+	RunE:    flowsList,
+}
+
+func init() {
+	rootCmd.AddCommand(flowsCmd)
+}
+
+func flowsList(cmd *cobra.Command, _ []string) error {
+	conn, err := getClient(cmd)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Errorf(errCloseConnection, err)
+		}
+	}()
+
+	client := proto.NewDaemonServiceClient(conn)
+	resp, err := client.GetFlows(cmd.Context(), &proto.GetFlowsRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to get flows: %v", status.Convert(err).Message())
+	}
+
+	if !resp.GetEnabled() {
+		cmd.Println("Flow logging is disabled.")
+		return nil
+	}
+
+	if len(resp.GetEvents()) == 0 {
+		cmd.Println("No flow events recorded.")
+		return nil
+	}
+
+	for _, event := range resp.GetEvents() {
+		cmd.Println(formatFlowEvent(event))
+	}
+
+	return nil
+}
+
+func formatFlowEvent(event *proto.FlowEvent) string {
+	line := fmt.Sprintf("%s %-5s %-7s %-6s %s -> %s",
+		event.GetTimestamp().AsTime().Local().Format(time.RFC3339),
+		event.GetType(),
+		event.GetDirection(),
+		event.GetProtocol(),
+		formatFlowEndpoint(event.GetSourceIp(), event.GetSourcePort()),
+		formatFlowEndpoint(event.GetDestIp(), event.GetDestPort()),
+	)
+
+	if event.GetProtocol() == "icmp" || event.GetProtocol() == "icmpv6" {
+		line += fmt.Sprintf(" type %d code %d", event.GetIcmpType(), event.GetIcmpCode())
+	}
+
+	if event.GetType() == "end" {
+		line += fmt.Sprintf(" rx %d pkts/%d bytes tx %d pkts/%d bytes",
+			event.GetRxPackets(), event.GetRxBytes(), event.GetTxPackets(), event.GetTxBytes())
+	}
+
+	if event.GetRuleId() != "" {
+		line += " rule " + event.GetRuleId()
+	}
+
+	return line
+}
+
+func formatFlowEndpoint(ip string, port uint32) string {
+	if port == 0 {
+		return ip
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ip
+	}
+	return netip.AddrPortFrom(addr, uint16(port)).String()
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/sftp"
+	"github.com/spf13/cobra"
+
+	nbssh "github.com/netbirdio/netbird/client/ssh"
+)
+
+var cpCmd = &cobra.Command{
+	Use:   "cp source destination",
+	Short: "copy files to or from a peer",
+	Long: "Copies a file to or from the SSH server of a peer over SFTP. The remote file is written as [user@]host:path, " +
+		"relative remote paths are resolved from the home directory of the user. SFTP has to be enabled for the remote peer.",
+	Example: "  netbird cp ./dump.sql root@peer-a:/tmp/dump.sql\n" +
+		"  netbird cp root@peer-a:/var/log/syslog .",
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		src, dst := args[0], args[1]
+		srcHost, srcPath, srcRemote := parseRemotePath(src)
+		dstHost, dstPath, dstRemote := parseRemotePath(dst)
+		if srcRemote == dstRemote {
+			return errors.New("exactly one of source and destination has to be a remote path in the [user@]host:path format")
+		}
+
+		ctx, pemKey, err := prepareSSH(cmd)
+		if err != nil || pemKey == nil {
+			return err
+		}
+
+		remoteHost := srcHost
+		if dstRemote {
+			remoteHost = dstHost
+		}
+		user, host = parseUserHost(remoteHost)
+
+		c, err := dialSSH(host, pemKey, cmd)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+
+		client, err := c.NewSFTPClient()
+		if err != nil {
+			return fmt.Errorf("%w, make sure SFTP is enabled for the remote peer", err)
+		}
+		defer func() {
+			_ = client.Close()
+		}()
+
+		if dstRemote {
+			return upload(ctx, client, src, dstPath)
+		}
+		return download(ctx, client, srcPath, dst)
+	},
+}
+
+var sftpServerCmd = &cobra.Command{
+	Use:    nbssh.SFTPServerCommand,
+	Short:  "serve SFTP on stdin and stdout",
+	Long:   "Serves SFTP on stdin and stdout. It is started by the NetBird SSH server to handle SFTP sessions with the permissions of the local user.",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workDir, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("get working directory: %w", err)
+		}
+		return nbssh.ServeSFTP(os.Stdin, os.Stdout, workDir)
+	},
+}
+
+func init() {
+	cpCmd.Flags().IntVarP(&port, "port", "p", nbssh.DefaultSSHPort, "Sets remote SSH port. Defaults to "+fmt.Sprint(nbssh.DefaultSSHPort))
+	rootCmd.AddCommand(cpCmd)
+	rootCmd.AddCommand(sftpServerCmd)
+}
+
+// parseRemotePath splits a [user@]host:path argument, local paths are returned as is.
+// Windows drive letters are not treated as hosts.
+func parseRemotePath(arg string) (string, string, bool) {
+	remoteHost, remotePath, found := strings.Cut(arg, ":")
+	if !found || len(remoteHost) <= 1 || strings.ContainsAny(remoteHost, `/\`) {
+		return "", arg, false
+	}
+	return remoteHost, remotePath, true
+}
+
+func upload(ctx context.Context, client *sftp.Client, localPath, remotePath string) error {
+	src, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", localPath, err)
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+
+	if remotePath == "" {
+		remotePath = "."
+	}
+	if info, err := client.Stat(remotePath); err == nil && info.IsDir() {
+		remotePath = path.Join(remotePath, filepath.Base(localPath))
+	}
+
+	dst, err := client.Create(remotePath)
+	if err != nil {
+		return fmt.Errorf("create remote file %s: %w", remotePath, err)
+	}
+	defer func() {
+		_ = dst.Close()
+	}()
+
+	if _, err := io.Copy(dst, &ctxReader{ctx: ctx, r: src}); err != nil {
+		return fmt.Errorf("upload %s: %w", localPath, err)
+	}
+	return nil
+}
+
+func download(ctx context.Context, client *sftp.Client, remotePath, localPath string) error {
+	src, err := client.Open(remotePath)
+	if err != nil {
+		return fmt.Errorf("open remote file %s: %w", remotePath, err)
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+
+	if info, err := os.Stat(localPath); err == nil && info.IsDir() {
+		localPath = filepath.Join(localPath, path.Base(remotePath))
+	}
+
+	dst, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", localPath, err)
+	}
+	defer func() {
+		_ = dst.Close()
+	}()
+
+	if _, err := io.Copy(dst, &ctxReader{ctx: ctx, r: src}); err != nil {
+		return fmt.Errorf("download %s: %w", remotePath, err)
+	}
+	return nil
+}
+
+// ctxReader stops reading once the context is done
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *ctxReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
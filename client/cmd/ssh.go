@@ -17,51 +17,34 @@ import (
 )
 
 var (
-	port int
-	user = "root"
-	host string
+	port           int
+	user           = "root"
+	host           string
+	localForwards  []string
+	remoteForwards []string
+	noCommand      bool
 )
 
 var sshCmd = &cobra.Command{
-	Use: "ssh [user@]host",
+	Use: "ssh [user@]host [command]",
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
 			return errors.New("requires a host argument")
 		}
 
-		split := strings.Split(args[0], "@")
-		if len(split) == 2 {
-			user = split[0]
-			host = split[1]
-		} else {
-			host = args[0]
-		}
+		user, host = parseUserHost(args[0])
 
 		return nil
 	},
 	Short: "connect to a remote SSH server",
+	Long: "Connects to the SSH server of a peer and opens a terminal or runs the given command.\n" +
+		"Ports can be forwarded with -L and -R if port forwarding is enabled for the remote peer.",
+	Example: "  netbird ssh root@peer-a\n" +
+		"  netbird ssh root@peer-a -- ls -la /tmp\n" +
+		"  netbird ssh -N -L 5432:localhost:5432 root@peer-a",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		SetFlagsFromEnvVars(rootCmd)
-		SetFlagsFromEnvVars(cmd)
-
-		cmd.SetOut(cmd.OutOrStdout())
-
-		err := util.InitLog(logLevel, "console")
-		if err != nil {
-			return fmt.Errorf("failed initializing log %v", err)
-		}
-
-		if !util.IsAdmin() {
-			cmd.Printf("error: you must have Administrator privileges to run this command\n")
-			return nil
-		}
-
-		ctx := internal.CtxInitState(cmd.Context())
-
-		config, err := internal.UpdateConfig(internal.ConfigInput{
-			ConfigPath: configPath,
-		})
-		if err != nil {
+		ctx, pemKey, err := prepareSSH(cmd)
+		if err != nil || pemKey == nil {
 			return err
 		}
 
@@ -69,9 +52,13 @@ var sshCmd = &cobra.Command{
 		signal.Notify(sig, syscall.SIGTERM, syscall.SIGINT)
 		sshctx, cancel := context.WithCancel(ctx)
 
+		command := strings.Join(args[1:], " ")
 		go func() {
 			// blocking
-			if err := runSSH(sshctx, host, []byte(config.SSHKey), cmd); err != nil {
+			if err := runSSH(sshctx, host, pemKey, cmd, command); err != nil {
+				if status, ok := nbssh.ExitStatus(err); ok {
+					os.Exit(status)
+				}
 				cmd.Printf("Error: %v\n", err)
 				os.Exit(1)
 			}
@@ -88,13 +75,64 @@ var sshCmd = &cobra.Command{
 	},
 }
 
-func runSSH(ctx context.Context, addr string, pemKey []byte, cmd *cobra.Command) error {
-	c, err := nbssh.DialWithKey(fmt.Sprintf("%s:%d", addr, port), user, pemKey)
+// prepareSSH initializes the logging and returns the SSH key of the peer,
+// the key is nil if the command is not run with Administrator privileges
+func prepareSSH(cmd *cobra.Command) (context.Context, []byte, error) {
+	SetFlagsFromEnvVars(rootCmd)
+	SetFlagsFromEnvVars(cmd)
+
+	cmd.SetOut(cmd.OutOrStdout())
+
+	err := util.InitLog(logLevel, "console")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed initializing log %v", err)
+	}
+
+	if !util.IsAdmin() {
+		cmd.Printf("error: you must have Administrator privileges to run this command\n")
+		return nil, nil, nil
+	}
+
+	ctx := internal.CtxInitState(cmd.Context())
+
+	config, err := internal.UpdateConfig(internal.ConfigInput{
+		ConfigPath: configPath,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ctx, []byte(config.SSHKey), nil
+}
+
+func parseUserHost(arg string) (string, string) {
+	split := strings.Split(arg, "@")
+	if len(split) == 2 {
+		return split[0], split[1]
+	}
+	return user, arg
+}
+
+func dialSSH(addr string, pemKey []byte, cmd *cobra.Command) (*nbssh.Client, error) {
+	hostKeyCallback, err := nbssh.NewKnownHostsCallback(nbssh.DefaultKnownHostsPath())
+	if err != nil {
+		return nil, fmt.Errorf("load peer host keys, make sure the NetBird daemon is running: %w", err)
+	}
+
+	c, err := nbssh.DialWithKey(fmt.Sprintf("%s:%d", addr, port), user, pemKey, hostKeyCallback)
 	if err != nil {
 		cmd.Printf("Error: %v\n", err)
 		cmd.Printf("Couldn't connect. Please check the connection status or if the ssh server is enabled on the other peer" +
 			"\nYou can verify the connection by running:\n\n" +
 			" netbird status\n\n")
+		return nil, err
+	}
+	return c, nil
+}
+
+func runSSH(ctx context.Context, addr string, pemKey []byte, cmd *cobra.Command, command string) error {
+	c, err := dialSSH(addr, pemKey, cmd)
+	if err != nil {
 		return err
 	}
 	go func() {
@@ -105,14 +143,72 @@ func runSSH(ctx context.Context, addr string, pemKey []byte, cmd *cobra.Command)
 		}
 	}()
 
-	err = c.OpenTerminal()
-	if err != nil {
+	if err := startForwards(ctx, c, cmd); err != nil {
 		return err
 	}
 
+	switch {
+	case noCommand:
+		<-ctx.Done()
+		return nil
+	case command != "":
+		return c.Exec(command, os.Stdin, os.Stdout, os.Stderr)
+	default:
+		return c.OpenTerminal()
+	}
+}
+
+func startForwards(ctx context.Context, c *nbssh.Client, cmd *cobra.Command) error {
+	for _, spec := range localForwards {
+		listenAddr, targetAddr, err := parseForward(spec)
+		if err != nil {
+			return fmt.Errorf("invalid local forward %s: %w", spec, err)
+		}
+		go func() {
+			if err := c.ForwardLocal(ctx, listenAddr, targetAddr); err != nil {
+				cmd.PrintErrf("local forward %s stopped: %v\n", spec, err)
+			}
+		}()
+	}
+
+	for _, spec := range remoteForwards {
+		listenAddr, targetAddr, err := parseForward(spec)
+		if err != nil {
+			return fmt.Errorf("invalid remote forward %s: %w", spec, err)
+		}
+		go func() {
+			if err := c.ForwardRemote(ctx, listenAddr, targetAddr); err != nil {
+				cmd.PrintErrf("remote forward %s stopped: %v\n", spec, err)
+			}
+		}()
+	}
 	return nil
 }
 
+// parseForward parses a forward specification in the [bind_address:]port:host:hostport format
+// and returns the listen and target addresses. The listener binds to the loopback address by default.
+func parseForward(spec string) (string, string, error) {
+	parts := strings.Split(spec, ":")
+	switch len(parts) {
+	case 3:
+		parts = append([]string{"127.0.0.1"}, parts...)
+	case 4:
+	default:
+		return "", "", errors.New("expected [bind_address:]port:host:hostport")
+	}
+
+	for _, p := range parts[1:] {
+		if p == "" {
+			return "", "", errors.New("expected [bind_address:]port:host:hostport")
+		}
+	}
+
+	return parts[0] + ":" + parts[1], parts[2] + ":" + parts[3], nil
+}
+
 func init() {
 	sshCmd.PersistentFlags().IntVarP(&port, "port", "p", nbssh.DefaultSSHPort, "Sets remote SSH port. Defaults to "+fmt.Sprint(nbssh.DefaultSSHPort))
+	sshCmd.Flags().StringArrayVarP(&localForwards, "local-forward", "L", nil, "Forwards a local port to a host reachable from the remote peer: [bind_address:]port:host:hostport")
+	sshCmd.Flags().StringArrayVarP(&remoteForwards, "remote-forward", "R", nil, "Forwards a port of the remote peer to a host reachable from this peer: [bind_address:]port:host:hostport")
+	sshCmd.Flags().BoolVarP(&noCommand, "no-command", "N", false, "Does not open a terminal or run a command, useful for port forwarding only")
 }
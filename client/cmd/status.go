@@ -85,6 +85,20 @@ type nsServerGroupStateOutput struct {
 	Error   string   `json:"error" yaml:"error"`
 }
 
+type dnsQueryStatsOutput struct {
+	Total     uint64            `json:"total" yaml:"total"`
+	Failed    uint64            `json:"failed" yaml:"failed"`
+	ByHandler map[string]uint64 `json:"byHandler" yaml:"byHandler"`
+	ByRcode   map[string]uint64 `json:"byRcode" yaml:"byRcode"`
+}
+
+type dnsCacheStatsOutput struct {
+	Hits      uint64 `json:"hits" yaml:"hits"`
+	Misses    uint64 `json:"misses" yaml:"misses"`
+	Evictions uint64 `json:"evictions" yaml:"evictions"`
+	Entries   int64  `json:"entries" yaml:"entries"`
+}
+
 type statusOutputOverview struct {
 	Peers               peersStateOutput           `json:"peers" yaml:"peers"`
 	CliVersion          string                     `json:"cliVersion" yaml:"cliVersion"`
@@ -101,6 +115,8 @@ type statusOutputOverview struct {
 	Routes              []string                   `json:"routes" yaml:"routes"`
 	Networks            []string                   `json:"networks" yaml:"networks"`
 	NSServerGroups      []nsServerGroupStateOutput `json:"dnsServers" yaml:"dnsServers"`
+	DNSQueries          *dnsQueryStatsOutput       `json:"dnsQueries,omitempty" yaml:"dnsQueries,omitempty"`
+	DNSCache            *dnsCacheStatsOutput       `json:"dnsCache,omitempty" yaml:"dnsCache,omitempty"`
 }
 
 var (
@@ -287,6 +303,8 @@ func convertToStatusOutputOverview(resp *proto.StatusResponse) statusOutputOverv
 		Routes:              pbFullStatus.GetLocalPeerState().GetNetworks(),
 		Networks:            pbFullStatus.GetLocalPeerState().GetNetworks(),
 		NSServerGroups:      mapNSGroups(pbFullStatus.GetDnsServers()),
+		DNSQueries:          mapDNSQueryStats(pbFullStatus.GetDnsQueryStats()),
+		DNSCache:            mapDNSCacheStats(pbFullStatus.GetDnsCacheStats()),
 	}
 
 	if anonymizeFlag {
@@ -336,6 +354,30 @@ func mapNSGroups(servers []*proto.NSGroupState) []nsServerGroupStateOutput {
 	return mappedNSGroups
 }
 
+func mapDNSQueryStats(stats *proto.DNSQueryStats) *dnsQueryStatsOutput {
+	if stats == nil {
+		return nil
+	}
+	return &dnsQueryStatsOutput{
+		Total:     stats.GetTotal(),
+		Failed:    stats.GetFailed(),
+		ByHandler: stats.GetByHandler(),
+		ByRcode:   stats.GetByRcode(),
+	}
+}
+
+func mapDNSCacheStats(stats *proto.DNSCacheStats) *dnsCacheStatsOutput {
+	if stats == nil {
+		return nil
+	}
+	return &dnsCacheStatsOutput{
+		Hits:      stats.GetHits(),
+		Misses:    stats.GetMisses(),
+		Evictions: stats.GetEvictions(),
+		Entries:   stats.GetEntries(),
+	}
+}
+
 func mapPeers(peers []*proto.PeerState) peersStateOutput {
 	var peersStateDetail []peerStateDetailOutput
 	peersConnected := 0
@@ -526,6 +568,13 @@ func parseGeneralSummary(overview statusOutputOverview, showURL bool, showRelays
 				errorString,
 			)
 		}
+		if overview.DNSQueries != nil {
+			dnsServersString += "\nDNS queries: " + parseDNSQueryStats(overview.DNSQueries)
+		}
+		if overview.DNSCache != nil {
+			dnsServersString += fmt.Sprintf("\nDNS cache: %d hits, %d misses, %d entries, %d evictions",
+				overview.DNSCache.Hits, overview.DNSCache.Misses, overview.DNSCache.Entries, overview.DNSCache.Evictions)
+		}
 	} else {
 		dnsServersString = fmt.Sprintf("%d/%d Available", countEnabled(overview.NSServerGroups), len(overview.NSServerGroups))
 	}
@@ -580,6 +629,20 @@ func parseGeneralSummary(overview statusOutputOverview, showURL bool, showRelays
 	return summary
 }
 
+func parseDNSQueryStats(stats *dnsQueryStatsOutput) string {
+	handlers := make([]string, 0, len(stats.ByHandler))
+	for handler, count := range stats.ByHandler {
+		handlers = append(handlers, fmt.Sprintf("%s: %d", handler, count))
+	}
+	sort.Strings(handlers)
+
+	result := fmt.Sprintf("%d", stats.Total)
+	if len(handlers) > 0 {
+		result += fmt.Sprintf(" (%s)", strings.Join(handlers, ", "))
+	}
+	return fmt.Sprintf("%s, %d without answer", result, stats.Failed)
+}
+
 func parseToFullDetailSummary(overview statusOutputOverview) string {
 	parsedPeersString := parsePeers(overview.Peers, overview.RosenpassEnabled, overview.RosenpassPermissive)
 	summary := parseGeneralSummary(overview, true, true, true)
@@ -859,9 +922,16 @@ func anonymizeOverview(a *anonymize.Anonymizer, overview *statusOutputOverview)
 			overview.NSServerGroups[i].Domains[j] = a.AnonymizeDomain(domain)
 		}
 		for j, ns := range nsGroup.Servers {
-			host, port, err := net.SplitHostPort(ns)
+			// the encrypted nameservers are prefixed with their type, e.g. tls://1.1.1.1:853
+			scheme, addr, found := strings.Cut(ns, "://")
+			if !found {
+				scheme, addr = "", ns
+			} else {
+				scheme += "://"
+			}
+			host, port, err := net.SplitHostPort(addr)
 			if err == nil {
-				overview.NSServerGroups[i].Servers[j] = fmt.Sprintf("%s:%s", a.AnonymizeIPString(host), port)
+				overview.NSServerGroups[i].Servers[j] = fmt.Sprintf("%s%s:%s", scheme, a.AnonymizeIPString(host), port)
 			}
 		}
 	}
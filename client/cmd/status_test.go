@@ -627,3 +627,18 @@ func TestTimeAgo(t *testing.T) {
 		})
 	}
 }
+
+func TestParsingDNSQueryStats(t *testing.T) {
+	stats := &dnsQueryStatsOutput{
+		Total:  12,
+		Failed: 1,
+		ByHandler: map[string]uint64{
+			"nameserver-group": 7,
+			"local":            4,
+			"none":             1,
+		},
+	}
+
+	assert.Equal(t, "12 (local: 4, nameserver-group: 7, none: 1), 1 without answer", parseDNSQueryStats(stats))
+	assert.Equal(t, "0, 0 without answer", parseDNSQueryStats(&dnsQueryStatsOutput{}))
+}
@@ -3,6 +3,7 @@ package iptables
 import (
 	"fmt"
 	"net"
+	"slices"
 	"strconv"
 
 	"github.com/coreos/go-iptables/iptables"
@@ -38,6 +39,11 @@ type aclManager struct {
 	optionalEntries map[string][]entry
 	ipsetStore      *ipsetStore
 
+	// rules holds the peer rules by ID, chainRules holds the specs of the
+	// iptables rules in the order they are appended to the input rules chain
+	rules      map[string]*Rule
+	chainRules [][]string
+
 	stateManager *statemanager.Manager
 }
 
@@ -50,6 +56,7 @@ func newAclManager(iptablesClient *iptables.IPTables, wgIface iFaceMapper, routi
 		entries:         make(map[string][][]string),
 		optionalEntries: make(map[string][]entry),
 		ipsetStore:      newIpsetStore(),
+		rules:           make(map[string]*Rule),
 	}
 
 	if err := ipset.Init(); err != nil {
@@ -86,14 +93,9 @@ func (m *aclManager) AddPeerFiltering(
 	action firewall.Action,
 	ipsetName string,
 ) ([]firewall.Rule, error) {
-	var dPortVal, sPortVal string
-	if dPort != nil && dPort.Values != nil {
-		// TODO: we support only one port per rule in current implementation of ACLs
-		dPortVal = strconv.Itoa(dPort.Values[0])
-	}
-	if sPort != nil && sPort.Values != nil {
-		sPortVal = strconv.Itoa(sPort.Values[0])
-	}
+	// TODO: we support only one port or one port range per rule in current implementation of ACLs
+	dPortVal := portSpec(dPort)
+	sPortVal := portSpec(sPort)
 
 	chain := chainNameInputRules
 
@@ -107,13 +109,15 @@ func (m *aclManager) AddPeerFiltering(
 			// if ruleset already exists it means we already have the firewall rule
 			// so we need to update IPs in the ruleset and return new fw.Rule object for ACL manager.
 			ipList.addIP(ip.String())
-			return []firewall.Rule{&Rule{
+			rule := &Rule{
 				ruleID:    uuid.New().String(),
 				ipsetName: ipsetName,
 				ip:        ip.String(),
 				chain:     chain,
 				specs:     specs,
-			}}, nil
+			}
+			m.rules[rule.ruleID] = rule
+			return []firewall.Rule{rule}, nil
 		}
 
 		if err := ipset.Flush(ipsetName); err != nil {
@@ -141,6 +145,7 @@ func (m *aclManager) AddPeerFiltering(
 	if err := m.iptablesClient.Append("filter", chain, specs...); err != nil {
 		return nil, err
 	}
+	m.chainRules = append(m.chainRules, specs)
 
 	rule := &Rule{
 		ruleID:    uuid.New().String(),
@@ -149,6 +154,7 @@ func (m *aclManager) AddPeerFiltering(
 		ip:        ip.String(),
 		chain:     chain,
 	}
+	m.rules[rule.ruleID] = rule
 
 	m.updateState()
 
@@ -162,6 +168,8 @@ func (m *aclManager) DeletePeerRule(rule firewall.Rule) error {
 		return fmt.Errorf("invalid rule type")
 	}
 
+	delete(m.rules, r.ruleID)
+
 	if ipsetList, ok := m.ipsetStore.ipset(r.ipsetName); ok {
 		// delete IP from ruleset IPs list and ipset
 		if _, ok := ipsetList.ips[r.ip]; ok {
@@ -189,6 +197,9 @@ func (m *aclManager) DeletePeerRule(rule firewall.Rule) error {
 	if err := m.iptablesClient.Delete(tableName, r.chain, r.specs...); err != nil {
 		return fmt.Errorf("failed to delete rule: %s, %v: %w", r.chain, r.specs, err)
 	}
+	if i := m.chainRuleIndex(r.specs); i >= 0 {
+		m.chainRules = slices.Delete(m.chainRules, i, i+1)
+	}
 
 	m.updateState()
 
@@ -257,9 +268,45 @@ func (m *aclManager) cleanChains() error {
 		m.ipsetStore.deleteIpset(ipsetName)
 	}
 
+	m.rules = make(map[string]*Rule)
+	m.chainRules = nil
+
 	return nil
 }
 
+// GetRuleCounters reads the packet and byte counters of the peer rules from the input rules chain
+func (m *aclManager) GetRuleCounters() (map[string]firewall.RuleCounter, error) {
+	stats, err := m.iptablesClient.StructuredStats(tableName, chainNameInputRules)
+	if err != nil {
+		return nil, fmt.Errorf("get %s stats: %w", chainNameInputRules, err)
+	}
+
+	// the chain contains only the peer rules, so the stats are in the same order as the appended rules
+	if len(stats) != len(m.chainRules) {
+		return nil, fmt.Errorf("chain %s has %d rules, expected %d", chainNameInputRules, len(stats), len(m.chainRules))
+	}
+
+	counters := make(map[string]firewall.RuleCounter, len(m.rules))
+	for id, rule := range m.rules {
+		i := m.chainRuleIndex(rule.specs)
+		if i < 0 {
+			continue
+		}
+		counters[id] = firewall.RuleCounter{
+			Packets: stats[i].Packets,
+			Bytes:   stats[i].Bytes,
+		}
+	}
+
+	return counters, nil
+}
+
+func (m *aclManager) chainRuleIndex(specs []string) int {
+	return slices.IndexFunc(m.chainRules, func(s []string) bool {
+		return slices.Equal(s, specs)
+	})
+}
+
 func (m *aclManager) createDefaultChains() error {
 	// chain netbird-acl-input-rules
 	if err := m.iptablesClient.NewChain(tableName, chainNameInputRules); err != nil {
@@ -380,6 +427,17 @@ func filterRuleSpecs(ip net.IP, protocol, sPort, dPort string, action firewall.A
 	return append(specs, "-j", actionToStr(action))
 }
 
+// portSpec returns the --sport/--dport value of a single port or a port range
+func portSpec(port *firewall.Port) string {
+	if port == nil || len(port.Values) == 0 {
+		return ""
+	}
+	if port.IsRange && len(port.Values) == 2 {
+		return fmt.Sprintf("%d:%d", port.Values[0], port.Values[1])
+	}
+	return strconv.Itoa(port.Values[0])
+}
+
 func actionToStr(action firewall.Action) string {
 	if action == firewall.ActionAccept {
 		return "ACCEPT"
@@ -213,6 +213,14 @@ func (m *Manager) AllowNetbird() error {
 // Flush doesn't need to be implemented for this manager
 func (m *Manager) Flush() error { return nil }
 
+// GetRuleCounters returns the iptables counters of the peer rules
+func (m *Manager) GetRuleCounters() (map[string]firewall.RuleCounter, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.aclMgr.GetRuleCounters()
+}
+
 func getConntrackEstablished() []string {
 	return []string{"-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED", "-j", "ACCEPT"}
 }
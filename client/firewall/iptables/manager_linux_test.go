@@ -81,6 +81,10 @@ func TestIptablesManager(t *testing.T) {
 			rr := r.(*Rule)
 			checkRuleSpecs(t, ipv4Client, rr.chain, true, rr.specs...)
 		}
+
+		counters, err := manager.GetRuleCounters()
+		require.NoError(t, err, "failed to get rule counters")
+		require.Contains(t, counters, rule2[0].GetRuleID(), "expected a counter for the rule")
 	})
 
 	t.Run("delete second rule", func(t *testing.T) {
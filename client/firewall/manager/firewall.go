@@ -99,6 +99,15 @@ type Manager interface {
 
 	// Flush the changes to firewall controller
 	Flush() error
+
+	// GetRuleCounters returns the hit counters of the peer filtering rules keyed by rule ID
+	GetRuleCounters() (map[string]RuleCounter, error)
+}
+
+// RuleCounter holds the number of packets and bytes matched by a rule
+type RuleCounter struct {
+	Packets uint64
+	Bytes   uint64
 }
 
 func GenKey(format string, pair RouterPair) string {
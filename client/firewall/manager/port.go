@@ -1,6 +1,8 @@
 package manager
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 )
 
@@ -33,8 +35,68 @@ type Port struct {
 	Values []int
 }
 
+// NewPort creates a port set from the given ports
+func NewPort(ports ...int) (*Port, error) {
+	p := &Port{Values: ports}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// NewPortRange creates a port range from start to end, both included
+func NewPortRange(start, end int) (*Port, error) {
+	p := &Port{IsRange: true, Values: []int{start, end}}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Validate checks that the port values are valid port numbers and that a range has a start and an end
+func (p *Port) Validate() error {
+	if len(p.Values) == 0 {
+		return errors.New("no port values")
+	}
+
+	for _, port := range p.Values {
+		if port < 1 || port > 65535 {
+			return fmt.Errorf("invalid port number %d", port)
+		}
+	}
+
+	if p.IsRange {
+		if len(p.Values) != 2 {
+			return fmt.Errorf("port range requires 2 values, got %d", len(p.Values))
+		}
+		if p.Values[0] > p.Values[1] {
+			return fmt.Errorf("invalid port range %d-%d", p.Values[0], p.Values[1])
+		}
+	}
+
+	return nil
+}
+
+// Match returns true if the port is within the range or in the port set
+func (p *Port) Match(port uint16) bool {
+	if p.IsRange {
+		return len(p.Values) == 2 && int(port) >= p.Values[0] && int(port) <= p.Values[1]
+	}
+
+	for _, value := range p.Values {
+		if int(port) == value {
+			return true
+		}
+	}
+	return false
+}
+
 // String interface implementation
 func (p *Port) String() string {
+	if p.IsRange && len(p.Values) == 2 {
+		return fmt.Sprintf("%d-%d", p.Values[0], p.Values[1])
+	}
+
 	var ports string
 	for _, port := range p.Values {
 		if ports != "" {
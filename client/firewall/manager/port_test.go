@@ -0,0 +1,55 @@
+package manager_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/firewall/manager"
+)
+
+func TestPort_Match(t *testing.T) {
+	set, err := manager.NewPort(22, 80, 443)
+	require.NoError(t, err)
+	assert.True(t, set.Match(22))
+	assert.True(t, set.Match(443))
+	assert.False(t, set.Match(8080))
+	assert.Equal(t, "22,80,443", set.String())
+
+	portRange, err := manager.NewPortRange(49152, 65535)
+	require.NoError(t, err)
+	assert.True(t, portRange.Match(49152))
+	assert.True(t, portRange.Match(65535))
+	assert.False(t, portRange.Match(49151))
+	assert.Equal(t, "49152-65535", portRange.String())
+}
+
+func TestPort_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		port    manager.Port
+		wantErr bool
+	}{
+		{name: "single port", port: manager.Port{Values: []int{80}}},
+		{name: "port set", port: manager.Port{Values: []int{80, 443}}},
+		{name: "port range", port: manager.Port{IsRange: true, Values: []int{1000, 2000}}},
+		{name: "single port range", port: manager.Port{IsRange: true, Values: []int{1000, 1000}}},
+		{name: "no values", port: manager.Port{}, wantErr: true},
+		{name: "zero port", port: manager.Port{Values: []int{0}}, wantErr: true},
+		{name: "port out of bounds", port: manager.Port{Values: []int{65536}}, wantErr: true},
+		{name: "reversed range", port: manager.Port{IsRange: true, Values: []int{2000, 1000}}, wantErr: true},
+		{name: "range with one value", port: manager.Port{IsRange: true, Values: []int{1000}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.port.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
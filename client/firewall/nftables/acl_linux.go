@@ -309,37 +309,15 @@ func (m *AclManager) addIOFiltering(
 	}
 
 	if sPort != nil && len(sPort.Values) != 0 {
-		expressions = append(expressions,
-			&expr.Payload{
-				DestRegister: 1,
-				Base:         expr.PayloadBaseTransportHeader,
-				Offset:       0,
-				Len:          2,
-			},
-			&expr.Cmp{
-				Op:       expr.CmpOpEq,
-				Register: 1,
-				Data:     encodePort(*sPort),
-			},
-		)
+		expressions = append(expressions, peerPortExprs(*sPort, 0)...)
 	}
 
 	if dPort != nil && len(dPort.Values) != 0 {
-		expressions = append(expressions,
-			&expr.Payload{
-				DestRegister: 1,
-				Base:         expr.PayloadBaseTransportHeader,
-				Offset:       2,
-				Len:          2,
-			},
-			&expr.Cmp{
-				Op:       expr.CmpOpEq,
-				Register: 1,
-				Data:     encodePort(*dPort),
-			},
-		)
+		expressions = append(expressions, peerPortExprs(*dPort, 2)...)
 	}
 
+	expressions = append(expressions, &expr.Counter{})
+
 	switch action {
 	case firewall.ActionAccept:
 		expressions = append(expressions, &expr.Verdict{Kind: expr.VerdictAccept})
@@ -672,6 +650,40 @@ func (m *AclManager) refreshRuleHandles(chain *nftables.Chain) error {
 	return nil
 }
 
+// GetRuleCounters reads the counters of the peer rules from the input rules chain
+func (m *AclManager) GetRuleCounters() (map[string]firewall.RuleCounter, error) {
+	counters := make(map[string]firewall.RuleCounter)
+	if m.workTable == nil || m.chainInputRules == nil {
+		return counters, nil
+	}
+
+	list, err := m.rConn.GetRules(m.workTable, m.chainInputRules)
+	if err != nil {
+		return nil, fmt.Errorf("get rules: %w", err)
+	}
+
+	for _, rule := range list {
+		if len(rule.UserData) == 0 {
+			continue
+		}
+		ruleID := string(bytes.Split(rule.UserData, []byte(" "))[0])
+		if _, ok := m.rules[ruleID]; !ok {
+			continue
+		}
+		for _, e := range rule.Exprs {
+			if counter, ok := e.(*expr.Counter); ok {
+				counters[ruleID] = firewall.RuleCounter{
+					Packets: counter.Packets,
+					Bytes:   counter.Bytes,
+				}
+				break
+			}
+		}
+	}
+
+	return counters, nil
+}
+
 func generatePeerRuleId(ip net.IP, sPort *firewall.Port, dPort *firewall.Port, action firewall.Action, ipset *nftables.Set) string {
 	rulesetID := ":"
 	if sPort != nil {
@@ -689,9 +701,42 @@ func generatePeerRuleId(ip net.IP, sPort *firewall.Port, dPort *firewall.Port, a
 	return "set:" + ipset.Name + rulesetID
 }
 
-func encodePort(port firewall.Port) []byte {
+// peerPortExprs matches the transport header port at the given offset against a single port or a port range
+func peerPortExprs(port firewall.Port, offset uint32) []expr.Any {
+	exprs := []expr.Any{
+		&expr.Payload{
+			DestRegister: 1,
+			Base:         expr.PayloadBaseTransportHeader,
+			Offset:       offset,
+			Len:          2,
+		},
+	}
+
+	if port.IsRange && len(port.Values) == 2 {
+		return append(exprs,
+			&expr.Cmp{
+				Op:       expr.CmpOpGte,
+				Register: 1,
+				Data:     encodePort(port.Values[0]),
+			},
+			&expr.Cmp{
+				Op:       expr.CmpOpLte,
+				Register: 1,
+				Data:     encodePort(port.Values[1]),
+			},
+		)
+	}
+
+	return append(exprs, &expr.Cmp{
+		Op:       expr.CmpOpEq,
+		Register: 1,
+		Data:     encodePort(port.Values[0]),
+	})
+}
+
+func encodePort(port int) []byte {
 	bs := make([]byte, 2)
-	binary.BigEndian.PutUint16(bs, uint16(port.Values[0]))
+	binary.BigEndian.PutUint16(bs, uint16(port))
 	return bs
 }
 
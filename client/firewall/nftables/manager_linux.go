@@ -329,6 +329,14 @@ func (m *Manager) Flush() error {
 	return m.aclManager.Flush()
 }
 
+// GetRuleCounters returns the nftables counters of the peer rules
+func (m *Manager) GetRuleCounters() (map[string]firewall.RuleCounter, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.aclManager.GetRuleCounters()
+}
+
 func (m *Manager) createWorkTable() (*nftables.Table, error) {
 	tables, err := m.rConn.ListTablesOfFamily(nftables.TableFamilyIPv4)
 	if err != nil {
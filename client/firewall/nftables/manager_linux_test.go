@@ -145,10 +145,15 @@ func TestNftablesManager(t *testing.T) {
 			Register: 1,
 			Data:     []byte{0, 53},
 		},
+		&expr.Counter{},
 		&expr.Verdict{Kind: expr.VerdictDrop},
 	}
 	require.ElementsMatch(t, rules[1].Exprs, expectedExprs2, "expected the same expressions")
 
+	counters, err := manager.GetRuleCounters()
+	require.NoError(t, err, "failed to get rule counters")
+	require.Contains(t, counters, rule[0].GetRuleID(), "expected a counter for the rule")
+
 	for _, r := range rule {
 		err = manager.DeletePeerRule(r)
 		require.NoError(t, err, "failed to delete rule")
@@ -3,19 +3,58 @@ package conntrack
 
 import (
 	"net"
+	"net/netip"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/google/uuid"
+
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
 )
 
 // BaseConnTrack provides common fields and locking for all connection types
 type BaseConnTrack struct {
+	Direction   nftypes.Direction
+	RuleID      string
 	SourceIP    net.IP
 	DestIP      net.IP
 	SourcePort  uint16
 	DestPort    uint16
 	lastSeen    atomic.Int64 // Unix nano for atomic access
 	established atomic.Bool
+	ended       atomic.Bool
+
+	// flowID is generated with the first flow event, the connections are not logged while the flow logger is disabled
+	flowID     uuid.UUID
+	flowIDOnce sync.Once
+
+	sourceAddr netip.Addr
+	destAddr   netip.Addr
+
+	packetsTx atomic.Uint64
+	packetsRx atomic.Uint64
+	bytesTx   atomic.Uint64
+	bytesRx   atomic.Uint64
+}
+
+// newBaseConnTrack creates the common state of a new connection initiated in the given direction
+func newBaseConnTrack(ipPool *PreallocatedIPs, direction nftypes.Direction, ruleID string, srcIP, dstIP net.IP, srcPort, dstPort uint16) BaseConnTrack {
+	srcIPCopy := ipPool.Get()
+	dstIPCopy := ipPool.Get()
+	copyIP(srcIPCopy, srcIP)
+	copyIP(dstIPCopy, dstIP)
+
+	return BaseConnTrack{
+		Direction:  direction,
+		RuleID:     ruleID,
+		SourceIP:   srcIPCopy,
+		DestIP:     dstIPCopy,
+		SourcePort: srcPort,
+		DestPort:   dstPort,
+		sourceAddr: toAddr(srcIP),
+		destAddr:   toAddr(dstIP),
+	}
 }
 
 // these small methods will be inlined by the compiler
@@ -40,12 +79,72 @@ func (b *BaseConnTrack) GetLastSeen() time.Time {
 	return time.Unix(0, b.lastSeen.Load())
 }
 
+// UpdateCounters adds a packet of the given size to the counters,
+// packets sent by the local peer are counted as egress and packets received as ingress
+func (b *BaseConnTrack) UpdateCounters(direction nftypes.Direction, size int) {
+	if direction == nftypes.Egress {
+		b.packetsTx.Add(1)
+		b.bytesTx.Add(uint64(size))
+		return
+	}
+	b.packetsRx.Add(1)
+	b.bytesRx.Add(uint64(size))
+}
+
+// GetCounters returns the received and sent packets and bytes of the connection
+func (b *BaseConnTrack) GetCounters() (packetsRx, packetsTx, bytesRx, bytesTx uint64) {
+	return b.packetsRx.Load(), b.packetsTx.Load(), b.bytesRx.Load(), b.bytesTx.Load()
+}
+
+// markEnded returns true for the first call only, it guards the end event of the connection
+func (b *BaseConnTrack) markEnded() bool {
+	return b.ended.CompareAndSwap(false, true)
+}
+
+// FlowID returns the ID shared by the flow events of the connection
+func (b *BaseConnTrack) FlowID() uuid.UUID {
+	b.flowIDOnce.Do(func() {
+		b.flowID = uuid.New()
+	})
+	return b.flowID
+}
+
+// flowEvent returns the flow event of the connection, end events carry the counters
+func (b *BaseConnTrack) flowEvent(typ nftypes.Type, protocol nftypes.Protocol) nftypes.EventFields {
+	event := nftypes.EventFields{
+		FlowID:     b.FlowID(),
+		Type:       typ,
+		RuleID:     b.RuleID,
+		Direction:  b.Direction,
+		Protocol:   protocol,
+		SourceIP:   b.sourceAddr,
+		DestIP:     b.destAddr,
+		SourcePort: b.SourcePort,
+		DestPort:   b.DestPort,
+	}
+	if typ == nftypes.TypeEnd {
+		event.RxPackets, event.TxPackets, event.RxBytes, event.TxBytes = b.GetCounters()
+	}
+	return event
+}
+
 // timeoutExceeded checks if the connection has exceeded the given timeout
 func (b *BaseConnTrack) timeoutExceeded(timeout time.Duration) bool {
 	lastSeen := time.Unix(0, b.lastSeen.Load())
 	return time.Since(lastSeen) > timeout
 }
 
+// flowLogEnabled returns true if the flow events should be built and stored
+func flowLogEnabled(flowLogger nftypes.FlowLogger) bool {
+	return flowLogger != nil && flowLogger.Enabled()
+}
+
+// toAddr converts the IP of a packet to netip.Addr
+func toAddr(ip net.IP) netip.Addr {
+	addr, _ := netip.AddrFromSlice(ip)
+	return addr.Unmap()
+}
+
 // IPAddr is a fixed-size IP address to avoid allocations
 type IPAddr [16]byte
 
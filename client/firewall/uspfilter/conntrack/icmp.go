@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/google/gopacket/layers"
+
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
 )
 
 const (
@@ -39,10 +41,11 @@ type ICMPTracker struct {
 	mutex         sync.RWMutex
 	done          chan struct{}
 	ipPool        *PreallocatedIPs
+	flowLogger    nftypes.FlowLogger
 }
 
-// NewICMPTracker creates a new ICMP connection tracker
-func NewICMPTracker(timeout time.Duration) *ICMPTracker {
+// NewICMPTracker creates a new ICMP connection tracker, the flow logger is optional
+func NewICMPTracker(timeout time.Duration, flowLogger nftypes.FlowLogger) *ICMPTracker {
 	if timeout == 0 {
 		timeout = DefaultICMPTimeout
 	}
@@ -53,44 +56,66 @@ func NewICMPTracker(timeout time.Duration) *ICMPTracker {
 		cleanupTicker: time.NewTicker(ICMPCleanupInterval),
 		done:          make(chan struct{}),
 		ipPool:        NewPreallocatedIPs(),
+		flowLogger:    flowLogger,
 	}
 
 	go tracker.cleanupRoutine()
 	return tracker
 }
 
-// TrackOutbound records an outbound ICMP Echo Request
-func (t *ICMPTracker) TrackOutbound(srcIP net.IP, dstIP net.IP, id uint16, seq uint16) {
+// TrackOutbound records an outbound ICMP packet of the given size.
+// Echo Requests are tracked as connections, Echo Replies are accounted to the requests of remote peers.
+func (t *ICMPTracker) TrackOutbound(srcIP net.IP, dstIP net.IP, id uint16, seq uint16, icmpType uint8, size int) {
+	switch icmpType {
+	case uint8(layers.ICMPv4TypeEchoRequest):
+		t.track(nftypes.Egress, "", srcIP, dstIP, id, seq, size)
+	case uint8(layers.ICMPv4TypeEchoReply):
+		t.mutex.RLock()
+		conn, exists := t.connections[makeICMPKey(dstIP, srcIP, id, seq)]
+		t.mutex.RUnlock()
+
+		if exists {
+			conn.UpdateCounters(nftypes.Egress, size)
+		}
+	}
+}
+
+// TrackInbound records an inbound ICMP Echo Request of the given size accepted by the rule with the given ID
+func (t *ICMPTracker) TrackInbound(srcIP net.IP, dstIP net.IP, id uint16, seq uint16, icmpType uint8, ruleID string, size int) {
+	if icmpType != uint8(layers.ICMPv4TypeEchoRequest) {
+		return
+	}
+	t.track(nftypes.Ingress, ruleID, srcIP, dstIP, id, seq, size)
+}
+
+func (t *ICMPTracker) track(direction nftypes.Direction, ruleID string, srcIP net.IP, dstIP net.IP, id uint16, seq uint16, size int) {
 	key := makeICMPKey(srcIP, dstIP, id, seq)
 	now := time.Now().UnixNano()
 
 	t.mutex.Lock()
 	conn, exists := t.connections[key]
 	if !exists {
-		srcIPCopy := t.ipPool.Get()
-		dstIPCopy := t.ipPool.Get()
-		copyIP(srcIPCopy, srcIP)
-		copyIP(dstIPCopy, dstIP)
-
 		conn = &ICMPConnTrack{
-			BaseConnTrack: BaseConnTrack{
-				SourceIP: srcIPCopy,
-				DestIP:   dstIPCopy,
-			},
-			ID:       id,
-			Sequence: seq,
+			BaseConnTrack: newBaseConnTrack(t.ipPool, direction, ruleID, srcIP, dstIP, 0, 0),
+			ID:            id,
+			Sequence:      seq,
 		}
 		conn.lastSeen.Store(now)
 		conn.established.Store(true)
 		t.connections[key] = conn
+
+		if flowLogEnabled(t.flowLogger) {
+			t.flowLogger.StoreEvent(t.flowEvent(conn, nftypes.TypeStart))
+		}
 	}
 	t.mutex.Unlock()
 
 	conn.lastSeen.Store(now)
+	conn.UpdateCounters(direction, size)
 }
 
-// IsValidInbound checks if an inbound ICMP Echo Reply matches a tracked request
-func (t *ICMPTracker) IsValidInbound(srcIP net.IP, dstIP net.IP, id uint16, seq uint16, icmpType uint8) bool {
+// IsValidInbound checks if an inbound ICMP Echo Reply of the given size matches a tracked request
+func (t *ICMPTracker) IsValidInbound(srcIP net.IP, dstIP net.IP, id uint16, seq uint16, icmpType uint8, size int) bool {
 	switch icmpType {
 	case uint8(layers.ICMPv4TypeDestinationUnreachable),
 		uint8(layers.ICMPv4TypeTimeExceeded):
@@ -115,11 +140,15 @@ func (t *ICMPTracker) IsValidInbound(srcIP net.IP, dstIP net.IP, id uint16, seq
 		return false
 	}
 
-	return conn.IsEstablished() &&
+	valid := conn.IsEstablished() &&
 		ValidateIPs(MakeIPAddr(srcIP), conn.DestIP) &&
 		ValidateIPs(MakeIPAddr(dstIP), conn.SourceIP) &&
 		conn.ID == id &&
 		conn.Sequence == seq
+	if valid {
+		conn.UpdateCounters(nftypes.Ingress, size)
+	}
+	return valid
 }
 
 func (t *ICMPTracker) cleanupRoutine() {
@@ -138,6 +167,7 @@ func (t *ICMPTracker) cleanup() {
 
 	for key, conn := range t.connections {
 		if conn.timeoutExceeded(t.timeout) {
+			t.endConn(conn)
 			t.ipPool.Put(conn.SourceIP)
 			t.ipPool.Put(conn.DestIP)
 			delete(t.connections, key)
@@ -152,6 +182,7 @@ func (t *ICMPTracker) Close() {
 
 	t.mutex.Lock()
 	for _, conn := range t.connections {
+		t.endConn(conn)
 		t.ipPool.Put(conn.SourceIP)
 		t.ipPool.Put(conn.DestIP)
 	}
@@ -159,6 +190,20 @@ func (t *ICMPTracker) Close() {
 	t.mutex.Unlock()
 }
 
+// endConn emits the end event of the connection
+func (t *ICMPTracker) endConn(conn *ICMPConnTrack) {
+	if conn.markEnded() && flowLogEnabled(t.flowLogger) {
+		t.flowLogger.StoreEvent(t.flowEvent(conn, nftypes.TypeEnd))
+	}
+}
+
+// flowEvent returns the flow event of the Echo Request connection
+func (t *ICMPTracker) flowEvent(conn *ICMPConnTrack, typ nftypes.Type) nftypes.EventFields {
+	event := conn.flowEvent(typ, nftypes.ICMP)
+	event.ICMPType = uint8(layers.ICMPv4TypeEchoRequest)
+	return event
+}
+
 // makeICMPKey creates an ICMP connection key
 func makeICMPKey(srcIP net.IP, dstIP net.IP, id uint16, seq uint16) ICMPConnKey {
 	return ICMPConnKey{
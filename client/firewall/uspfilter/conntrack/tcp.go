@@ -6,6 +6,8 @@ import (
 	"net"
 	"sync"
 	"time"
+
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
 )
 
 const (
@@ -73,48 +75,39 @@ type TCPTracker struct {
 	done          chan struct{}
 	timeout       time.Duration
 	ipPool        *PreallocatedIPs
+	flowLogger    nftypes.FlowLogger
 }
 
-// NewTCPTracker creates a new TCP connection tracker
-func NewTCPTracker(timeout time.Duration) *TCPTracker {
+// NewTCPTracker creates a new TCP connection tracker, the flow logger is optional
+func NewTCPTracker(timeout time.Duration, flowLogger nftypes.FlowLogger) *TCPTracker {
 	tracker := &TCPTracker{
 		connections:   make(map[ConnKey]*TCPConnTrack),
 		cleanupTicker: time.NewTicker(TCPCleanupInterval),
 		done:          make(chan struct{}),
 		timeout:       timeout,
 		ipPool:        NewPreallocatedIPs(),
+		flowLogger:    flowLogger,
 	}
 
 	go tracker.cleanupRoutine()
 	return tracker
 }
 
-// TrackOutbound processes an outbound TCP packet and updates connection state
-func (t *TCPTracker) TrackOutbound(srcIP net.IP, dstIP net.IP, srcPort uint16, dstPort uint16, flags uint8) {
-	// Create key before lock
+// TrackOutbound processes an outbound TCP packet of the given size and updates connection state.
+// Replies to connections initiated by a remote peer are accounted to these connections.
+func (t *TCPTracker) TrackOutbound(srcIP net.IP, dstIP net.IP, srcPort uint16, dstPort uint16, flags uint8, size int) {
+	// Create keys before lock
 	key := makeConnKey(srcIP, dstIP, srcPort, dstPort)
+	reverseKey := makeConnKey(dstIP, srcIP, dstPort, srcPort)
 	now := time.Now().UnixNano()
 
 	t.mutex.Lock()
 	conn, exists := t.connections[key]
 	if !exists {
-		// Use preallocated IPs
-		srcIPCopy := t.ipPool.Get()
-		dstIPCopy := t.ipPool.Get()
-		copyIP(srcIPCopy, srcIP)
-		copyIP(dstIPCopy, dstIP)
-
-		conn = &TCPConnTrack{
-			BaseConnTrack: BaseConnTrack{
-				SourceIP:   srcIPCopy,
-				DestIP:     dstIPCopy,
-				SourcePort: srcPort,
-				DestPort:   dstPort,
-			},
-			State: TCPStateNew,
-		}
-		conn.lastSeen.Store(now)
-		conn.established.Store(false)
+		conn, exists = t.connections[reverseKey]
+	}
+	if !exists {
+		conn = t.newConn(nftypes.Egress, "", srcIP, dstIP, srcPort, dstPort, now)
 		t.connections[key] = conn
 	}
 	t.mutex.Unlock()
@@ -122,12 +115,63 @@ func (t *TCPTracker) TrackOutbound(srcIP net.IP, dstIP net.IP, srcPort uint16, d
 	// Lock individual connection for state update
 	conn.Lock()
 	t.updateState(conn, flags, true)
+	closed := isClosedState(conn.State)
+	conn.Unlock()
+	conn.lastSeen.Store(now)
+	conn.UpdateCounters(nftypes.Egress, size)
+
+	if closed {
+		t.endConn(conn)
+	}
+}
+
+// TrackInbound processes an inbound TCP packet of the given size accepted by the rule with the given ID.
+// A new connection is tracked for SYN packets not belonging to a known connection initiated by the remote peer.
+func (t *TCPTracker) TrackInbound(srcIP net.IP, dstIP net.IP, srcPort uint16, dstPort uint16, flags uint8, ruleID string, size int) {
+	key := makeConnKey(srcIP, dstIP, srcPort, dstPort)
+	now := time.Now().UnixNano()
+
+	t.mutex.Lock()
+	conn, exists := t.connections[key]
+	if !exists {
+		if flags&TCPSyn == 0 || flags&TCPAck != 0 {
+			t.mutex.Unlock()
+			return
+		}
+		conn = t.newConn(nftypes.Ingress, ruleID, srcIP, dstIP, srcPort, dstPort, now)
+		t.connections[key] = conn
+	}
+	t.mutex.Unlock()
+
+	conn.Lock()
+	t.updateState(conn, flags, false)
+	closed := isClosedState(conn.State)
 	conn.Unlock()
 	conn.lastSeen.Store(now)
+	conn.UpdateCounters(nftypes.Ingress, size)
+
+	if closed {
+		t.endConn(conn)
+	}
+}
+
+// newConn creates a connection and emits its start event, must be called with the tracker lock held
+func (t *TCPTracker) newConn(direction nftypes.Direction, ruleID string, srcIP, dstIP net.IP, srcPort, dstPort uint16, now int64) *TCPConnTrack {
+	conn := &TCPConnTrack{
+		BaseConnTrack: newBaseConnTrack(t.ipPool, direction, ruleID, srcIP, dstIP, srcPort, dstPort),
+		State:         TCPStateNew,
+	}
+	conn.lastSeen.Store(now)
+	conn.established.Store(false)
+
+	if flowLogEnabled(t.flowLogger) {
+		t.flowLogger.StoreEvent(conn.flowEvent(nftypes.TypeStart, nftypes.TCP))
+	}
+	return conn
 }
 
-// IsValidInbound checks if an inbound TCP packet matches a tracked connection
-func (t *TCPTracker) IsValidInbound(srcIP net.IP, dstIP net.IP, srcPort uint16, dstPort uint16, flags uint8) bool {
+// IsValidInbound checks if an inbound TCP packet of the given size matches a tracked outbound connection
+func (t *TCPTracker) IsValidInbound(srcIP net.IP, dstIP net.IP, srcPort uint16, dstPort uint16, flags uint8, size int) bool {
 	if !isValidFlagCombination(flags) {
 		return false
 	}
@@ -149,6 +193,8 @@ func (t *TCPTracker) IsValidInbound(srcIP net.IP, dstIP net.IP, srcPort uint16,
 			conn.State = TCPStateClosed
 			conn.SetEstablished(false)
 			conn.Unlock()
+			conn.UpdateCounters(nftypes.Ingress, size)
+			t.endConn(conn)
 			return true
 		}
 		conn.Unlock()
@@ -160,9 +206,18 @@ func (t *TCPTracker) IsValidInbound(srcIP net.IP, dstIP net.IP, srcPort uint16,
 	conn.UpdateLastSeen()
 	isEstablished := conn.IsEstablished()
 	isValidState := t.isValidStateForFlags(conn.State, flags)
+	closed := isClosedState(conn.State)
 	conn.Unlock()
 
-	return isEstablished || isValidState
+	if !isEstablished && !isValidState {
+		return false
+	}
+
+	conn.UpdateCounters(nftypes.Ingress, size)
+	if closed {
+		t.endConn(conn)
+	}
+	return true
 }
 
 // updateState updates the TCP connection state based on flags
@@ -314,6 +369,7 @@ func (t *TCPTracker) cleanup() {
 
 		lastSeen := conn.GetLastSeen()
 		if time.Since(lastSeen) > timeout {
+			t.endConn(conn)
 			// Return IPs to pool
 			t.ipPool.Put(conn.SourceIP)
 			t.ipPool.Put(conn.DestIP)
@@ -330,6 +386,7 @@ func (t *TCPTracker) Close() {
 	// Clean up all remaining IPs
 	t.mutex.Lock()
 	for _, conn := range t.connections {
+		t.endConn(conn)
 		t.ipPool.Put(conn.SourceIP)
 		t.ipPool.Put(conn.DestIP)
 	}
@@ -337,6 +394,19 @@ func (t *TCPTracker) Close() {
 	t.mutex.Unlock()
 }
 
+// isClosedState checks if the connection is closed from the point of view of the flow logging,
+// the TIME-WAIT state only catches retransmissions
+func isClosedState(state TCPState) bool {
+	return state == TCPStateClosed || state == TCPStateTimeWait
+}
+
+// endConn emits the end event of the connection
+func (t *TCPTracker) endConn(conn *TCPConnTrack) {
+	if conn.markEnded() && flowLogEnabled(t.flowLogger) {
+		t.flowLogger.StoreEvent(conn.flowEvent(nftypes.TypeEnd, nftypes.TCP))
+	}
+}
+
 func isValidFlagCombination(flags uint8) bool {
 	// Invalid: SYN+FIN
 	if flags&TCPSyn != 0 && flags&TCPFin != 0 {
@@ -4,6 +4,8 @@ import (
 	"net"
 	"sync"
 	"time"
+
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
 )
 
 const (
@@ -26,10 +28,11 @@ type UDPTracker struct {
 	mutex         sync.RWMutex
 	done          chan struct{}
 	ipPool        *PreallocatedIPs
+	flowLogger    nftypes.FlowLogger
 }
 
-// NewUDPTracker creates a new UDP connection tracker
-func NewUDPTracker(timeout time.Duration) *UDPTracker {
+// NewUDPTracker creates a new UDP connection tracker, the flow logger is optional
+func NewUDPTracker(timeout time.Duration, flowLogger nftypes.FlowLogger) *UDPTracker {
 	if timeout == 0 {
 		timeout = DefaultUDPTimeout
 	}
@@ -40,44 +43,69 @@ func NewUDPTracker(timeout time.Duration) *UDPTracker {
 		cleanupTicker: time.NewTicker(UDPCleanupInterval),
 		done:          make(chan struct{}),
 		ipPool:        NewPreallocatedIPs(),
+		flowLogger:    flowLogger,
 	}
 
 	go tracker.cleanupRoutine()
 	return tracker
 }
 
-// TrackOutbound records an outbound UDP connection
-func (t *UDPTracker) TrackOutbound(srcIP net.IP, dstIP net.IP, srcPort uint16, dstPort uint16) {
+// TrackOutbound records an outbound UDP packet of the given size.
+// Replies to connections initiated by a remote peer are accounted to these connections.
+func (t *UDPTracker) TrackOutbound(srcIP net.IP, dstIP net.IP, srcPort uint16, dstPort uint16, size int) {
 	key := makeConnKey(srcIP, dstIP, srcPort, dstPort)
+	reverseKey := makeConnKey(dstIP, srcIP, dstPort, srcPort)
 	now := time.Now().UnixNano()
 
 	t.mutex.Lock()
 	conn, exists := t.connections[key]
 	if !exists {
-		srcIPCopy := t.ipPool.Get()
-		dstIPCopy := t.ipPool.Get()
-		copyIP(srcIPCopy, srcIP)
-		copyIP(dstIPCopy, dstIP)
-
-		conn = &UDPConnTrack{
-			BaseConnTrack: BaseConnTrack{
-				SourceIP:   srcIPCopy,
-				DestIP:     dstIPCopy,
-				SourcePort: srcPort,
-				DestPort:   dstPort,
-			},
-		}
-		conn.lastSeen.Store(now)
-		conn.established.Store(true)
+		conn, exists = t.connections[reverseKey]
+	}
+	if !exists {
+		conn = t.newConn(nftypes.Egress, "", srcIP, dstIP, srcPort, dstPort, now)
 		t.connections[key] = conn
 	}
 	t.mutex.Unlock()
 
 	conn.lastSeen.Store(now)
+	conn.UpdateCounters(nftypes.Egress, size)
 }
 
-// IsValidInbound checks if an inbound packet matches a tracked connection
-func (t *UDPTracker) IsValidInbound(srcIP net.IP, dstIP net.IP, srcPort uint16, dstPort uint16) bool {
+// TrackInbound records an inbound UDP packet of the given size accepted by the rule with the given ID.
+// A new connection is tracked if the packet doesn't belong to a known connection initiated by the remote peer.
+func (t *UDPTracker) TrackInbound(srcIP net.IP, dstIP net.IP, srcPort uint16, dstPort uint16, ruleID string, size int) {
+	key := makeConnKey(srcIP, dstIP, srcPort, dstPort)
+	now := time.Now().UnixNano()
+
+	t.mutex.Lock()
+	conn, exists := t.connections[key]
+	if !exists {
+		conn = t.newConn(nftypes.Ingress, ruleID, srcIP, dstIP, srcPort, dstPort, now)
+		t.connections[key] = conn
+	}
+	t.mutex.Unlock()
+
+	conn.lastSeen.Store(now)
+	conn.UpdateCounters(nftypes.Ingress, size)
+}
+
+// newConn creates a connection and emits its start event, must be called with the tracker lock held
+func (t *UDPTracker) newConn(direction nftypes.Direction, ruleID string, srcIP, dstIP net.IP, srcPort, dstPort uint16, now int64) *UDPConnTrack {
+	conn := &UDPConnTrack{
+		BaseConnTrack: newBaseConnTrack(t.ipPool, direction, ruleID, srcIP, dstIP, srcPort, dstPort),
+	}
+	conn.lastSeen.Store(now)
+	conn.established.Store(true)
+
+	if flowLogEnabled(t.flowLogger) {
+		t.flowLogger.StoreEvent(conn.flowEvent(nftypes.TypeStart, nftypes.UDP))
+	}
+	return conn
+}
+
+// IsValidInbound checks if an inbound packet of the given size matches a tracked outbound connection
+func (t *UDPTracker) IsValidInbound(srcIP net.IP, dstIP net.IP, srcPort uint16, dstPort uint16, size int) bool {
 	key := makeConnKey(dstIP, srcIP, dstPort, srcPort)
 
 	t.mutex.RLock()
@@ -92,11 +120,15 @@ func (t *UDPTracker) IsValidInbound(srcIP net.IP, dstIP net.IP, srcPort uint16,
 		return false
 	}
 
-	return conn.IsEstablished() &&
+	valid := conn.IsEstablished() &&
 		ValidateIPs(MakeIPAddr(srcIP), conn.DestIP) &&
 		ValidateIPs(MakeIPAddr(dstIP), conn.SourceIP) &&
 		conn.DestPort == srcPort &&
 		conn.SourcePort == dstPort
+	if valid {
+		conn.UpdateCounters(nftypes.Ingress, size)
+	}
+	return valid
 }
 
 // cleanupRoutine periodically removes stale connections
@@ -117,6 +149,7 @@ func (t *UDPTracker) cleanup() {
 
 	for key, conn := range t.connections {
 		if conn.timeoutExceeded(t.timeout) {
+			t.endConn(conn)
 			t.ipPool.Put(conn.SourceIP)
 			t.ipPool.Put(conn.DestIP)
 			delete(t.connections, key)
@@ -131,6 +164,7 @@ func (t *UDPTracker) Close() {
 
 	t.mutex.Lock()
 	for _, conn := range t.connections {
+		t.endConn(conn)
 		t.ipPool.Put(conn.SourceIP)
 		t.ipPool.Put(conn.DestIP)
 	}
@@ -138,6 +172,13 @@ func (t *UDPTracker) Close() {
 	t.mutex.Unlock()
 }
 
+// endConn emits the end event of the connection
+func (t *UDPTracker) endConn(conn *UDPConnTrack) {
+	if conn.markEnded() && flowLogEnabled(t.flowLogger) {
+		t.flowLogger.StoreEvent(conn.flowEvent(nftypes.TypeEnd, nftypes.UDP))
+	}
+}
+
 // GetConnection safely retrieves a connection state
 func (t *UDPTracker) GetConnection(srcIP net.IP, srcPort uint16, dstIP net.IP, dstPort uint16) (*UDPConnTrack, bool) {
 	t.mutex.RLock()
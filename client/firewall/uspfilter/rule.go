@@ -2,8 +2,11 @@ package uspfilter
 
 import (
 	"net"
+	"sync/atomic"
 
 	"github.com/google/gopacket"
+
+	firewall "github.com/netbirdio/netbird/client/firewall/manager"
 )
 
 // Rule to handle management of rules
@@ -13,14 +16,37 @@ type Rule struct {
 	ipLayer    gopacket.LayerType
 	matchByIP  bool
 	protoLayer gopacket.LayerType
-	sPort      uint16
-	dPort      uint16
+	sPort      *firewall.Port
+	dPort      *firewall.Port
 	drop       bool
 	comment    string
+	counter    *ruleCounter
 
 	udpHook func([]byte) bool
 }
 
+// ruleCounter counts the packets matched by a rule, it is shared between the copies of the rule
+type ruleCounter struct {
+	packets atomic.Uint64
+	bytes   atomic.Uint64
+}
+
+func (c *ruleCounter) add(size int) {
+	c.packets.Add(1)
+	c.bytes.Add(uint64(size))
+}
+
+// matchPorts returns true if the rule has no port restrictions or if the source or the destination port matches
+func (r *Rule) matchPorts(srcPort, dstPort uint16) bool {
+	if r.sPort == nil && r.dPort == nil {
+		return true
+	}
+	if r.sPort != nil && r.sPort.Match(srcPort) {
+		return true
+	}
+	return r.dPort != nil && r.dPort.Match(dstPort)
+}
+
 // GetRuleID returns the rule id
 func (r *Rule) GetRuleID() string {
 	return r.id
@@ -5,6 +5,7 @@ import (
 	"net"
 	"net/netip"
 	"os"
+	"slices"
 	"strconv"
 	"sync"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/netbirdio/netbird/client/firewall/uspfilter/conntrack"
 	"github.com/netbirdio/netbird/client/iface"
 	"github.com/netbirdio/netbird/client/iface/device"
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
 	"github.com/netbirdio/netbird/client/internal/statemanager"
 )
 
@@ -54,6 +56,7 @@ type Manager struct {
 	udpTracker  *conntrack.UDPTracker
 	icmpTracker *conntrack.ICMPTracker
 	tcpTracker  *conntrack.TCPTracker
+	flowLogger  nftypes.FlowLogger
 }
 
 // decoder for packages
@@ -69,13 +72,13 @@ type decoder struct {
 	parser  *gopacket.DecodingLayerParser
 }
 
-// Create userspace firewall manager constructor
-func Create(iface IFaceMapper) (*Manager, error) {
-	return create(iface)
+// Create userspace firewall manager constructor, the flow logger is optional
+func Create(iface IFaceMapper, flowLogger nftypes.FlowLogger) (*Manager, error) {
+	return create(iface, flowLogger)
 }
 
-func CreateWithNativeFirewall(iface IFaceMapper, nativeFirewall firewall.Manager) (*Manager, error) {
-	mgr, err := create(iface)
+func CreateWithNativeFirewall(iface IFaceMapper, nativeFirewall firewall.Manager, flowLogger nftypes.FlowLogger) (*Manager, error) {
+	mgr, err := create(iface, flowLogger)
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +87,7 @@ func CreateWithNativeFirewall(iface IFaceMapper, nativeFirewall firewall.Manager
 	return mgr, nil
 }
 
-func create(iface IFaceMapper) (*Manager, error) {
+func create(iface IFaceMapper, flowLogger nftypes.FlowLogger) (*Manager, error) {
 	disableConntrack, _ := strconv.ParseBool(os.Getenv(EnvDisableConntrack))
 
 	m := &Manager{
@@ -105,15 +108,16 @@ func create(iface IFaceMapper) (*Manager, error) {
 		incomingRules: make(map[string]RuleSet),
 		wgIface:       iface,
 		stateful:      !disableConntrack,
+		flowLogger:    flowLogger,
 	}
 
 	// Only initialize trackers if stateful mode is enabled
 	if disableConntrack {
 		log.Info("conntrack is disabled")
 	} else {
-		m.udpTracker = conntrack.NewUDPTracker(conntrack.DefaultUDPTimeout)
-		m.icmpTracker = conntrack.NewICMPTracker(conntrack.DefaultICMPTimeout)
-		m.tcpTracker = conntrack.NewTCPTracker(conntrack.DefaultTCPTimeout)
+		m.udpTracker = conntrack.NewUDPTracker(conntrack.DefaultUDPTimeout, flowLogger)
+		m.icmpTracker = conntrack.NewICMPTracker(conntrack.DefaultICMPTimeout, flowLogger)
+		m.tcpTracker = conntrack.NewTCPTracker(conntrack.DefaultTCPTimeout, flowLogger)
 	}
 
 	if err := iface.SetFilter(m); err != nil {
@@ -169,6 +173,7 @@ func (m *Manager) AddPeerFiltering(
 		matchByIP: true,
 		drop:      action == firewall.ActionDrop,
 		comment:   comment,
+		counter:   &ruleCounter{},
 	}
 	if ipNormalized := ip.To4(); ipNormalized != nil {
 		r.ipLayer = layers.LayerTypeIPv4
@@ -179,12 +184,18 @@ func (m *Manager) AddPeerFiltering(
 		r.matchByIP = false
 	}
 
-	if sPort != nil && len(sPort.Values) == 1 {
-		r.sPort = uint16(sPort.Values[0])
+	if sPort != nil {
+		if err := sPort.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid source port: %w", err)
+		}
+		r.sPort = copyPort(sPort)
 	}
 
-	if dPort != nil && len(dPort.Values) == 1 {
-		r.dPort = uint16(dPort.Values[0])
+	if dPort != nil {
+		if err := dPort.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid destination port: %w", err)
+		}
+		r.dPort = copyPort(dPort)
 	}
 
 	switch proto {
@@ -253,6 +264,26 @@ func (m *Manager) SetLegacyManagement(isLegacy bool) error {
 // Flush doesn't need to be implemented for this manager
 func (m *Manager) Flush() error { return nil }
 
+// GetRuleCounters returns the number of packets matched by each peer rule
+func (m *Manager) GetRuleCounters() (map[string]firewall.RuleCounter, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	counters := make(map[string]firewall.RuleCounter)
+	for _, rules := range m.incomingRules {
+		for id, rule := range rules {
+			if rule.counter == nil {
+				continue
+			}
+			counters[id] = firewall.RuleCounter{
+				Packets: rule.counter.packets.Load(),
+				Bytes:   rule.counter.bytes.Load(),
+			}
+		}
+	}
+	return counters, nil
+}
+
 // DropOutgoing filter outgoing packets
 func (m *Manager) DropOutgoing(packetData []byte) bool {
 	return m.processOutgoingHooks(packetData)
@@ -284,11 +315,13 @@ func (m *Manager) processOutgoingHooks(packetData []byte) bool {
 		return false
 	}
 
+	size := len(packetData)
+
 	// Always process UDP hooks
 	if d.decoded[1] == layers.LayerTypeUDP {
 		// Track UDP state only if enabled
 		if m.stateful {
-			m.trackUDPOutbound(d, srcIP, dstIP)
+			m.trackUDPOutbound(d, srcIP, dstIP, size)
 		}
 		return m.checkUDPHooks(d, dstIP, packetData)
 	}
@@ -297,9 +330,9 @@ func (m *Manager) processOutgoingHooks(packetData []byte) bool {
 	if m.stateful {
 		switch d.decoded[1] {
 		case layers.LayerTypeTCP:
-			m.trackTCPOutbound(d, srcIP, dstIP)
+			m.trackTCPOutbound(d, srcIP, dstIP, size)
 		case layers.LayerTypeICMPv4:
-			m.trackICMPOutbound(d, srcIP, dstIP)
+			m.trackICMPOutbound(d, srcIP, dstIP, size)
 		}
 	}
 
@@ -317,7 +350,7 @@ func (m *Manager) extractIPs(d *decoder) (srcIP, dstIP net.IP) {
 	}
 }
 
-func (m *Manager) trackTCPOutbound(d *decoder, srcIP, dstIP net.IP) {
+func (m *Manager) trackTCPOutbound(d *decoder, srcIP, dstIP net.IP, size int) {
 	flags := getTCPFlags(&d.tcp)
 	m.tcpTracker.TrackOutbound(
 		srcIP,
@@ -325,6 +358,7 @@ func (m *Manager) trackTCPOutbound(d *decoder, srcIP, dstIP net.IP) {
 		uint16(d.tcp.SrcPort),
 		uint16(d.tcp.DstPort),
 		flags,
+		size,
 	)
 }
 
@@ -351,12 +385,13 @@ func getTCPFlags(tcp *layers.TCP) uint8 {
 	return flags
 }
 
-func (m *Manager) trackUDPOutbound(d *decoder, srcIP, dstIP net.IP) {
+func (m *Manager) trackUDPOutbound(d *decoder, srcIP, dstIP net.IP, size int) {
 	m.udpTracker.TrackOutbound(
 		srcIP,
 		dstIP,
 		uint16(d.udp.SrcPort),
 		uint16(d.udp.DstPort),
+		size,
 	)
 }
 
@@ -364,7 +399,7 @@ func (m *Manager) checkUDPHooks(d *decoder, dstIP net.IP, packetData []byte) boo
 	for _, ipKey := range []string{dstIP.String(), "0.0.0.0", "::"} {
 		if rules, exists := m.outgoingRules[ipKey]; exists {
 			for _, rule := range rules {
-				if rule.udpHook != nil && (rule.dPort == 0 || rule.dPort == uint16(d.udp.DstPort)) {
+				if rule.udpHook != nil && (rule.dPort == nil || rule.dPort.Match(uint16(d.udp.DstPort))) {
 					return rule.udpHook(packetData)
 				}
 			}
@@ -373,15 +408,15 @@ func (m *Manager) checkUDPHooks(d *decoder, dstIP net.IP, packetData []byte) boo
 	return false
 }
 
-func (m *Manager) trackICMPOutbound(d *decoder, srcIP, dstIP net.IP) {
-	if d.icmp4.TypeCode.Type() == layers.ICMPv4TypeEchoRequest {
-		m.icmpTracker.TrackOutbound(
-			srcIP,
-			dstIP,
-			d.icmp4.Id,
-			d.icmp4.Seq,
-		)
-	}
+func (m *Manager) trackICMPOutbound(d *decoder, srcIP, dstIP net.IP, size int) {
+	m.icmpTracker.TrackOutbound(
+		srcIP,
+		dstIP,
+		d.icmp4.Id,
+		d.icmp4.Seq,
+		d.icmp4.TypeCode.Type(),
+		size,
+	)
 }
 
 // dropFilter implements filtering logic for incoming packets
@@ -408,12 +443,106 @@ func (m *Manager) dropFilter(packetData []byte, rules map[string]RuleSet) bool {
 		return false
 	}
 
+	size := len(packetData)
+
 	// Check connection state only if enabled
-	if m.stateful && m.isValidTrackedConnection(d, srcIP, dstIP) {
+	if m.stateful && m.isValidTrackedConnection(d, srcIP, dstIP, size) {
 		return false
 	}
 
-	return m.applyRules(srcIP, packetData, rules, d)
+	rule, drop := m.applyRules(srcIP, packetData, rules, d)
+	// packets consumed by hooks are not part of the flows
+	if rule.udpHook != nil {
+		return drop
+	}
+
+	if rule.counter != nil {
+		rule.counter.add(size)
+	}
+
+	if drop {
+		m.logDroppedPacket(d, srcIP, dstIP, rule.id)
+		return true
+	}
+
+	if m.stateful {
+		m.trackInbound(d, srcIP, dstIP, rule.id, size)
+	}
+	return false
+}
+
+// trackInbound tracks the inbound packet accepted by the rule with the given ID
+func (m *Manager) trackInbound(d *decoder, srcIP, dstIP net.IP, ruleID string, size int) {
+	switch d.decoded[1] {
+	case layers.LayerTypeTCP:
+		m.tcpTracker.TrackInbound(
+			srcIP,
+			dstIP,
+			uint16(d.tcp.SrcPort),
+			uint16(d.tcp.DstPort),
+			getTCPFlags(&d.tcp),
+			ruleID,
+			size,
+		)
+	case layers.LayerTypeUDP:
+		m.udpTracker.TrackInbound(
+			srcIP,
+			dstIP,
+			uint16(d.udp.SrcPort),
+			uint16(d.udp.DstPort),
+			ruleID,
+			size,
+		)
+	case layers.LayerTypeICMPv4:
+		m.icmpTracker.TrackInbound(
+			srcIP,
+			dstIP,
+			d.icmp4.Id,
+			d.icmp4.Seq,
+			d.icmp4.TypeCode.Type(),
+			ruleID,
+			size,
+		)
+	}
+}
+
+// logDroppedPacket emits a drop flow event for the inbound packet, the rule ID is empty for the default policy
+func (m *Manager) logDroppedPacket(d *decoder, srcIP, dstIP net.IP, ruleID string) {
+	if m.flowLogger == nil || !m.flowLogger.Enabled() {
+		return
+	}
+
+	event := nftypes.EventFields{
+		FlowID:    uuid.New(),
+		Type:      nftypes.TypeDrop,
+		RuleID:    ruleID,
+		Direction: nftypes.Ingress,
+	}
+	event.SourceIP, _ = netip.AddrFromSlice(srcIP)
+	event.DestIP, _ = netip.AddrFromSlice(dstIP)
+	event.SourceIP = event.SourceIP.Unmap()
+	event.DestIP = event.DestIP.Unmap()
+
+	switch d.decoded[1] {
+	case layers.LayerTypeTCP:
+		event.Protocol = nftypes.TCP
+		event.SourcePort = uint16(d.tcp.SrcPort)
+		event.DestPort = uint16(d.tcp.DstPort)
+	case layers.LayerTypeUDP:
+		event.Protocol = nftypes.UDP
+		event.SourcePort = uint16(d.udp.SrcPort)
+		event.DestPort = uint16(d.udp.DstPort)
+	case layers.LayerTypeICMPv4:
+		event.Protocol = nftypes.ICMP
+		event.ICMPType = d.icmp4.TypeCode.Type()
+		event.ICMPCode = d.icmp4.TypeCode.Code()
+	case layers.LayerTypeICMPv6:
+		event.Protocol = nftypes.ICMPv6
+		event.ICMPType = d.icmp6.TypeCode.Type()
+		event.ICMPCode = d.icmp6.TypeCode.Code()
+	}
+
+	m.flowLogger.StoreEvent(event)
 }
 
 func (m *Manager) isValidPacket(d *decoder, packetData []byte) bool {
@@ -433,7 +562,7 @@ func (m *Manager) isWireguardTraffic(srcIP, dstIP net.IP) bool {
 	return m.wgNetwork.Contains(srcIP) && m.wgNetwork.Contains(dstIP)
 }
 
-func (m *Manager) isValidTrackedConnection(d *decoder, srcIP, dstIP net.IP) bool {
+func (m *Manager) isValidTrackedConnection(d *decoder, srcIP, dstIP net.IP, size int) bool {
 	switch d.decoded[1] {
 	case layers.LayerTypeTCP:
 		return m.tcpTracker.IsValidInbound(
@@ -442,6 +571,7 @@ func (m *Manager) isValidTrackedConnection(d *decoder, srcIP, dstIP net.IP) bool
 			uint16(d.tcp.SrcPort),
 			uint16(d.tcp.DstPort),
 			getTCPFlags(&d.tcp),
+			size,
 		)
 
 	case layers.LayerTypeUDP:
@@ -450,6 +580,7 @@ func (m *Manager) isValidTrackedConnection(d *decoder, srcIP, dstIP net.IP) bool
 			dstIP,
 			uint16(d.udp.SrcPort),
 			uint16(d.udp.DstPort),
+			size,
 		)
 
 	case layers.LayerTypeICMPv4:
@@ -459,6 +590,7 @@ func (m *Manager) isValidTrackedConnection(d *decoder, srcIP, dstIP net.IP) bool
 			d.icmp4.Id,
 			d.icmp4.Seq,
 			d.icmp4.TypeCode.Type(),
+			size,
 		)
 
 		// TODO: ICMPv6
@@ -467,24 +599,26 @@ func (m *Manager) isValidTrackedConnection(d *decoder, srcIP, dstIP net.IP) bool
 	return false
 }
 
-func (m *Manager) applyRules(srcIP net.IP, packetData []byte, rules map[string]RuleSet, d *decoder) bool {
-	if filter, ok := validateRule(srcIP, packetData, rules[srcIP.String()], d); ok {
-		return filter
+// applyRules returns the matching rule and whether the packet should be dropped,
+// the rule is empty if the packet is dropped by the default policy
+func (m *Manager) applyRules(srcIP net.IP, packetData []byte, rules map[string]RuleSet, d *decoder) (Rule, bool) {
+	if rule, filter, ok := validateRule(srcIP, packetData, rules[srcIP.String()], d); ok {
+		return rule, filter
 	}
 
-	if filter, ok := validateRule(srcIP, packetData, rules["0.0.0.0"], d); ok {
-		return filter
+	if rule, filter, ok := validateRule(srcIP, packetData, rules["0.0.0.0"], d); ok {
+		return rule, filter
 	}
 
-	if filter, ok := validateRule(srcIP, packetData, rules["::"], d); ok {
-		return filter
+	if rule, filter, ok := validateRule(srcIP, packetData, rules["::"], d); ok {
+		return rule, filter
 	}
 
 	// Default policy: DROP ALL
-	return true
+	return Rule{}, true
 }
 
-func validateRule(ip net.IP, packetData []byte, rules map[string]Rule, d *decoder) (bool, bool) {
+func validateRule(ip net.IP, packetData []byte, rules map[string]Rule, d *decoder) (Rule, bool, bool) {
 	payloadLayer := d.decoded[1]
 	for _, rule := range rules {
 		if rule.matchByIP && !ip.Equal(rule.ip) {
@@ -492,7 +626,7 @@ func validateRule(ip net.IP, packetData []byte, rules map[string]Rule, d *decode
 		}
 
 		if rule.protoLayer == layerTypeAll {
-			return rule.drop, true
+			return rule, rule.drop, true
 		}
 
 		if payloadLayer != rule.protoLayer {
@@ -501,36 +635,32 @@ func validateRule(ip net.IP, packetData []byte, rules map[string]Rule, d *decode
 
 		switch payloadLayer {
 		case layers.LayerTypeTCP:
-			if rule.sPort == 0 && rule.dPort == 0 {
-				return rule.drop, true
-			}
-			if rule.sPort != 0 && rule.sPort == uint16(d.tcp.SrcPort) {
-				return rule.drop, true
-			}
-			if rule.dPort != 0 && rule.dPort == uint16(d.tcp.DstPort) {
-				return rule.drop, true
+			if rule.matchPorts(uint16(d.tcp.SrcPort), uint16(d.tcp.DstPort)) {
+				return rule, rule.drop, true
 			}
 		case layers.LayerTypeUDP:
 			// if rule has UDP hook (and if we are here we match this rule)
 			// we ignore rule.drop and call this hook
 			if rule.udpHook != nil {
-				return rule.udpHook(packetData), true
+				return rule, rule.udpHook(packetData), true
 			}
 
-			if rule.sPort == 0 && rule.dPort == 0 {
-				return rule.drop, true
-			}
-			if rule.sPort != 0 && rule.sPort == uint16(d.udp.SrcPort) {
-				return rule.drop, true
-			}
-			if rule.dPort != 0 && rule.dPort == uint16(d.udp.DstPort) {
-				return rule.drop, true
+			if rule.matchPorts(uint16(d.udp.SrcPort), uint16(d.udp.DstPort)) {
+				return rule, rule.drop, true
 			}
 		case layers.LayerTypeICMPv4, layers.LayerTypeICMPv6:
-			return rule.drop, true
+			return rule, rule.drop, true
 		}
 	}
-	return false, false
+	return Rule{}, false, false
+}
+
+// copyPort returns a copy of the port so that later changes of the caller don't affect the rule
+func copyPort(port *firewall.Port) *firewall.Port {
+	return &firewall.Port{
+		IsRange: port.IsRange,
+		Values:  slices.Clone(port.Values),
+	}
 }
 
 // SetNetwork of the wireguard interface to which filtering applied
@@ -548,7 +678,6 @@ func (m *Manager) AddUDPPacketHook(
 		id:         uuid.New().String(),
 		ip:         ip,
 		protoLayer: layers.LayerTypeUDP,
-		dPort:      dPort,
 		ipLayer:    layers.LayerTypeIPv6,
 		comment:    fmt.Sprintf("UDP Hook direction: %v, ip:%v, dport:%d", in, ip, dPort),
 		udpHook:    hook,
@@ -558,6 +687,10 @@ func (m *Manager) AddUDPPacketHook(
 		r.ipLayer = layers.LayerTypeIPv4
 	}
 
+	if dPort != 0 {
+		r.dPort = &firewall.Port{Values: []int{int(dPort)}}
+	}
+
 	m.mutex.Lock()
 	if in {
 		if _, ok := m.incomingRules[r.ip.String()]; !ok {
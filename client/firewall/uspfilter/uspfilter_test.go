@@ -3,6 +3,7 @@ package uspfilter
 import (
 	"fmt"
 	"net"
+	"net/netip"
 	"sync"
 	"testing"
 	"time"
@@ -15,6 +16,8 @@ import (
 	"github.com/netbirdio/netbird/client/firewall/uspfilter/conntrack"
 	"github.com/netbirdio/netbird/client/iface"
 	"github.com/netbirdio/netbird/client/iface/device"
+	"github.com/netbirdio/netbird/client/internal/netflow/logger"
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
 )
 
 type IFaceMock struct {
@@ -41,7 +44,7 @@ func TestManagerCreate(t *testing.T) {
 		SetFilterFunc: func(device.PacketFilter) error { return nil },
 	}
 
-	m, err := Create(ifaceMock)
+	m, err := Create(ifaceMock, nil)
 	if err != nil {
 		t.Errorf("failed to create Manager: %v", err)
 		return
@@ -61,7 +64,7 @@ func TestManagerAddPeerFiltering(t *testing.T) {
 		},
 	}
 
-	m, err := Create(ifaceMock)
+	m, err := Create(ifaceMock, nil)
 	if err != nil {
 		t.Errorf("failed to create Manager: %v", err)
 		return
@@ -95,7 +98,7 @@ func TestManagerDeleteRule(t *testing.T) {
 		SetFilterFunc: func(device.PacketFilter) error { return nil },
 	}
 
-	m, err := Create(ifaceMock)
+	m, err := Create(ifaceMock, nil)
 	if err != nil {
 		t.Errorf("failed to create Manager: %v", err)
 		return
@@ -166,7 +169,7 @@ func TestAddUDPPacketHook(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			manager, err := Create(&IFaceMock{
 				SetFilterFunc: func(device.PacketFilter) error { return nil },
-			})
+			}, nil)
 			require.NoError(t, err)
 
 			manager.AddUDPPacketHook(tt.in, tt.ip, tt.dPort, tt.hook)
@@ -194,8 +197,8 @@ func TestAddUDPPacketHook(t *testing.T) {
 				t.Errorf("expected ip %s, got %s", tt.ip, addedRule.ip)
 				return
 			}
-			if tt.dPort != addedRule.dPort {
-				t.Errorf("expected dPort %d, got %d", tt.dPort, addedRule.dPort)
+			if addedRule.dPort == nil || !addedRule.dPort.Match(tt.dPort) {
+				t.Errorf("expected dPort %d, got %v", tt.dPort, addedRule.dPort)
 				return
 			}
 			if layers.LayerTypeUDP != addedRule.protoLayer {
@@ -215,7 +218,7 @@ func TestManagerReset(t *testing.T) {
 		SetFilterFunc: func(device.PacketFilter) error { return nil },
 	}
 
-	m, err := Create(ifaceMock)
+	m, err := Create(ifaceMock, nil)
 	if err != nil {
 		t.Errorf("failed to create Manager: %v", err)
 		return
@@ -249,7 +252,7 @@ func TestNotMatchByIP(t *testing.T) {
 		SetFilterFunc: func(device.PacketFilter) error { return nil },
 	}
 
-	m, err := Create(ifaceMock)
+	m, err := Create(ifaceMock, nil)
 	if err != nil {
 		t.Errorf("failed to create Manager: %v", err)
 		return
@@ -317,7 +320,7 @@ func TestRemovePacketHook(t *testing.T) {
 	}
 
 	// creating manager instance
-	manager, err := Create(iface)
+	manager, err := Create(iface, nil)
 	if err != nil {
 		t.Fatalf("Failed to create Manager: %s", err)
 	}
@@ -363,7 +366,7 @@ func TestRemovePacketHook(t *testing.T) {
 func TestProcessOutgoingHooks(t *testing.T) {
 	manager, err := Create(&IFaceMock{
 		SetFilterFunc: func(device.PacketFilter) error { return nil },
-	})
+	}, nil)
 	require.NoError(t, err)
 
 	manager.wgNetwork = &net.IPNet{
@@ -371,7 +374,7 @@ func TestProcessOutgoingHooks(t *testing.T) {
 		Mask: net.CIDRMask(16, 32),
 	}
 	manager.udpTracker.Close()
-	manager.udpTracker = conntrack.NewUDPTracker(100 * time.Millisecond)
+	manager.udpTracker = conntrack.NewUDPTracker(100*time.Millisecond, nil)
 	defer func() {
 		require.NoError(t, manager.Reset(nil))
 	}()
@@ -449,7 +452,7 @@ func TestUSPFilterCreatePerformance(t *testing.T) {
 			ifaceMock := &IFaceMock{
 				SetFilterFunc: func(device.PacketFilter) error { return nil },
 			}
-			manager, err := Create(ifaceMock)
+			manager, err := Create(ifaceMock, nil)
 			require.NoError(t, err)
 			time.Sleep(time.Second)
 
@@ -476,7 +479,7 @@ func TestUSPFilterCreatePerformance(t *testing.T) {
 func TestStatefulFirewall_UDPTracking(t *testing.T) {
 	manager, err := Create(&IFaceMock{
 		SetFilterFunc: func(device.PacketFilter) error { return nil },
-	})
+	}, nil)
 	require.NoError(t, err)
 
 	manager.wgNetwork = &net.IPNet{
@@ -485,7 +488,7 @@ func TestStatefulFirewall_UDPTracking(t *testing.T) {
 	}
 
 	manager.udpTracker.Close() // Close the existing tracker
-	manager.udpTracker = conntrack.NewUDPTracker(200 * time.Millisecond)
+	manager.udpTracker = conntrack.NewUDPTracker(200*time.Millisecond, nil)
 	manager.decoders = sync.Pool{
 		New: func() any {
 			d := &decoder{
@@ -682,3 +685,232 @@ func TestStatefulFirewall_UDPTracking(t *testing.T) {
 		})
 	}
 }
+
+func TestFlowEvents(t *testing.T) {
+	flowLogger := logger.New(0)
+	flowLogger.Enable()
+
+	manager, err := Create(&IFaceMock{
+		SetFilterFunc: func(device.PacketFilter) error { return nil },
+	}, flowLogger)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, manager.Reset(nil))
+	}()
+
+	manager.wgNetwork = &net.IPNet{
+		IP:   net.ParseIP("100.10.0.0"),
+		Mask: net.CIDRMask(16, 32),
+	}
+
+	peerIP := net.ParseIP("100.10.0.1")
+	localIP := net.ParseIP("100.10.0.100")
+
+	rules, err := manager.AddPeerFiltering(peerIP, fw.ProtocolTCP, nil, &fw.Port{Values: []int{22}}, fw.ActionAccept, "", "")
+	require.NoError(t, err)
+	acceptRuleID := rules[0].GetRuleID()
+
+	tcpPacket := func(dstPort uint16) []byte {
+		ipv4 := &layers.IPv4{
+			TTL:      64,
+			Version:  4,
+			SrcIP:    peerIP,
+			DstIP:    localIP,
+			Protocol: layers.IPProtocolTCP,
+		}
+		tcp := &layers.TCP{
+			SrcPort: 40000,
+			DstPort: layers.TCPPort(dstPort),
+			SYN:     true,
+		}
+		require.NoError(t, tcp.SetNetworkLayerForChecksum(ipv4))
+
+		buf := gopacket.NewSerializeBuffer()
+		opts := gopacket.SerializeOptions{ComputeChecksums: true, FixLengths: true}
+		require.NoError(t, gopacket.SerializeLayers(buf, opts, ipv4, tcp))
+		return buf.Bytes()
+	}
+
+	require.False(t, manager.DropIncoming(tcpPacket(22)), "packet matching the accept rule should pass")
+	require.True(t, manager.DropIncoming(tcpPacket(80)), "packet not matching any rule should be dropped")
+
+	events := flowLogger.GetEvents()
+	require.Len(t, events, 2)
+
+	start := events[0]
+	require.Equal(t, nftypes.TypeStart, start.Type)
+	require.Equal(t, nftypes.Ingress, start.Direction)
+	require.Equal(t, acceptRuleID, start.RuleID)
+	require.Equal(t, uint16(22), start.DestPort)
+
+	drop := events[1]
+	require.Equal(t, nftypes.TypeDrop, drop.Type)
+	require.Equal(t, nftypes.TCP, drop.Protocol)
+	require.Equal(t, netip.MustParseAddr("100.10.0.1"), drop.SourceIP)
+	require.Equal(t, netip.MustParseAddr("100.10.0.100"), drop.DestIP)
+	require.Equal(t, uint16(40000), drop.SourcePort)
+	require.Equal(t, uint16(80), drop.DestPort)
+	require.Empty(t, drop.RuleID, "default drop should not reference a rule")
+}
+
+func TestPeerFilteringPorts(t *testing.T) {
+	peerIP := net.ParseIP("100.10.0.1")
+	localIP := net.ParseIP("100.10.0.100")
+
+	udpPacket := func(t *testing.T, srcPort, dstPort uint16) []byte {
+		t.Helper()
+		ipv4 := &layers.IPv4{
+			TTL:      64,
+			Version:  4,
+			SrcIP:    peerIP,
+			DstIP:    localIP,
+			Protocol: layers.IPProtocolUDP,
+		}
+		udp := &layers.UDP{
+			SrcPort: layers.UDPPort(srcPort),
+			DstPort: layers.UDPPort(dstPort),
+		}
+		require.NoError(t, udp.SetNetworkLayerForChecksum(ipv4))
+
+		buf := gopacket.NewSerializeBuffer()
+		opts := gopacket.SerializeOptions{ComputeChecksums: true, FixLengths: true}
+		require.NoError(t, gopacket.SerializeLayers(buf, opts, ipv4, udp, gopacket.Payload("test")))
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name      string
+		sPort     *fw.Port
+		dPort     *fw.Port
+		allowed   []uint16
+		forbidden []uint16
+	}{
+		{
+			name:      "single destination port",
+			dPort:     &fw.Port{Values: []int{5000}},
+			allowed:   []uint16{5000},
+			forbidden: []uint16{4999, 5001},
+		},
+		{
+			name:      "destination port set",
+			dPort:     &fw.Port{Values: []int{5000, 5002, 6000}},
+			allowed:   []uint16{5000, 5002, 6000},
+			forbidden: []uint16{5001, 5999},
+		},
+		{
+			name:      "destination port range",
+			dPort:     &fw.Port{IsRange: true, Values: []int{30000, 40000}},
+			allowed:   []uint16{30000, 35000, 40000},
+			forbidden: []uint16{29999, 40001},
+		},
+		{
+			name:      "source port range",
+			sPort:     &fw.Port{IsRange: true, Values: []int{1000, 1010}},
+			allowed:   []uint16{},
+			forbidden: []uint16{5000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager, err := Create(&IFaceMock{
+				SetFilterFunc: func(device.PacketFilter) error { return nil },
+			}, nil)
+			require.NoError(t, err)
+			defer func() {
+				require.NoError(t, manager.Reset(nil))
+			}()
+
+			manager.wgNetwork = &net.IPNet{
+				IP:   net.ParseIP("100.10.0.0"),
+				Mask: net.CIDRMask(16, 32),
+			}
+
+			_, err = manager.AddPeerFiltering(peerIP, fw.ProtocolUDP, tt.sPort, tt.dPort, fw.ActionAccept, "", "")
+			require.NoError(t, err)
+
+			for _, port := range tt.allowed {
+				require.False(t, manager.DropIncoming(udpPacket(t, 50000, port)), "port %d should be allowed", port)
+			}
+			for _, port := range tt.forbidden {
+				require.True(t, manager.DropIncoming(udpPacket(t, 50000, port)), "port %d should be dropped", port)
+			}
+			if tt.sPort != nil {
+				require.False(t, manager.DropIncoming(udpPacket(t, 1005, 5000)), "source port in range should be allowed")
+			}
+		})
+	}
+
+	t.Run("invalid port range", func(t *testing.T) {
+		manager, err := Create(&IFaceMock{
+			SetFilterFunc: func(device.PacketFilter) error { return nil },
+		}, nil)
+		require.NoError(t, err)
+
+		_, err = manager.AddPeerFiltering(peerIP, fw.ProtocolTCP, nil, &fw.Port{IsRange: true, Values: []int{2000, 1000}}, fw.ActionAccept, "", "")
+		require.Error(t, err)
+	})
+}
+
+func TestRuleCounters(t *testing.T) {
+	manager, err := Create(&IFaceMock{
+		SetFilterFunc: func(device.PacketFilter) error { return nil },
+	}, nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, manager.Reset(nil))
+	}()
+
+	manager.wgNetwork = &net.IPNet{
+		IP:   net.ParseIP("100.10.0.0"),
+		Mask: net.CIDRMask(16, 32),
+	}
+	// every packet has to be evaluated against the rules
+	manager.stateful = false
+
+	peerIP := net.ParseIP("100.10.0.1")
+	localIP := net.ParseIP("100.10.0.100")
+
+	acceptRules, err := manager.AddPeerFiltering(peerIP, fw.ProtocolUDP, nil, &fw.Port{Values: []int{53}}, fw.ActionAccept, "", "policy1")
+	require.NoError(t, err)
+	dropRules, err := manager.AddPeerFiltering(peerIP, fw.ProtocolUDP, nil, &fw.Port{Values: []int{5353}}, fw.ActionDrop, "", "policy2")
+	require.NoError(t, err)
+
+	udpPacket := func(dstPort uint16) []byte {
+		ipv4 := &layers.IPv4{
+			TTL:      64,
+			Version:  4,
+			SrcIP:    peerIP,
+			DstIP:    localIP,
+			Protocol: layers.IPProtocolUDP,
+		}
+		udp := &layers.UDP{
+			SrcPort: 50000,
+			DstPort: layers.UDPPort(dstPort),
+		}
+		require.NoError(t, udp.SetNetworkLayerForChecksum(ipv4))
+
+		buf := gopacket.NewSerializeBuffer()
+		opts := gopacket.SerializeOptions{ComputeChecksums: true, FixLengths: true}
+		require.NoError(t, gopacket.SerializeLayers(buf, opts, ipv4, udp, gopacket.Payload("test")))
+		return buf.Bytes()
+	}
+
+	packet := udpPacket(53)
+	for i := 0; i < 3; i++ {
+		require.False(t, manager.DropIncoming(packet))
+	}
+	require.True(t, manager.DropIncoming(udpPacket(5353)))
+	require.True(t, manager.DropIncoming(udpPacket(8080)))
+
+	counters, err := manager.GetRuleCounters()
+	require.NoError(t, err)
+	require.Len(t, counters, 2)
+	require.Equal(t, fw.RuleCounter{Packets: 3, Bytes: uint64(3 * len(packet))}, counters[acceptRules[0].GetRuleID()])
+	require.Equal(t, uint64(1), counters[dropRules[0].GetRuleID()].Packets)
+
+	require.NoError(t, manager.DeletePeerRule(dropRules[0]))
+	counters, err = manager.GetRuleCounters()
+	require.NoError(t, err)
+	require.Len(t, counters, 1)
+}
@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"net"
 	"net/netip"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -26,6 +28,21 @@ var ErrSourceRangesEmpty = errors.New("sources range is empty")
 // Manager is a ACL rules manager
 type Manager interface {
 	ApplyFiltering(networkMap *mgmProto.NetworkMap)
+	GetRuleStats() ([]RuleStats, error)
+}
+
+// RuleStats describes a peer rule applied to the firewall and how often it matched.
+// The rules of the peers sharing an ipset are reported once with the comma separated peer IPs.
+type RuleStats struct {
+	ID        string
+	PolicyID  string
+	PeerIP    string
+	Direction mgmProto.RuleDirection
+	Action    mgmProto.RuleAction
+	Protocol  mgmProto.RuleProtocol
+	Port      string
+	Packets   uint64
+	Bytes     uint64
 }
 
 // DefaultManager uses firewall manager to handle
@@ -33,6 +50,7 @@ type DefaultManager struct {
 	firewall       firewall.Manager
 	ipsetCounter   int
 	peerRulesPairs map[id.RuleID][]firewall.Rule
+	peerRules      map[id.RuleID]*mgmProto.FirewallRule
 	routeRules     map[id.RuleID]struct{}
 	mutex          sync.Mutex
 }
@@ -41,6 +59,7 @@ func NewDefaultManager(fm firewall.Manager) *DefaultManager {
 	return &DefaultManager{
 		firewall:       fm,
 		peerRulesPairs: make(map[id.RuleID][]firewall.Rule),
+		peerRules:      make(map[id.RuleID]*mgmProto.FirewallRule),
 		routeRules:     make(map[id.RuleID]struct{}),
 	}
 }
@@ -133,6 +152,7 @@ func (d *DefaultManager) applyPeerACLs(networkMap *mgmProto.NetworkMap) {
 	}
 
 	newRulePairs := make(map[id.RuleID][]firewall.Rule)
+	newPeerRules := make(map[id.RuleID]*mgmProto.FirewallRule)
 	ipsetByRuleSelectors := make(map[string]string)
 
 	for _, r := range rules {
@@ -154,6 +174,7 @@ func (d *DefaultManager) applyPeerACLs(networkMap *mgmProto.NetworkMap) {
 		if len(rulePair) > 0 {
 			d.peerRulesPairs[pairID] = rulePair
 			newRulePairs[pairID] = rulePair
+			newPeerRules[pairID] = r
 		}
 	}
 
@@ -169,6 +190,85 @@ func (d *DefaultManager) applyPeerACLs(networkMap *mgmProto.NetworkMap) {
 		}
 	}
 	d.peerRulesPairs = newRulePairs
+	d.peerRules = newPeerRules
+}
+
+// GetRuleStats returns the applied peer rules with the hit counters reported by the firewall manager
+func (d *DefaultManager) GetRuleStats() ([]RuleStats, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.firewall == nil {
+		return nil, errors.New("firewall manager is not supported")
+	}
+
+	counters, err := d.firewall.GetRuleCounters()
+	if err != nil {
+		return nil, fmt.Errorf("get rule counters: %w", err)
+	}
+
+	// the peers of an ipset share the firewall rules, such pairs are reported as a single rule so the counters
+	// are not repeated for every peer IP
+	type ruleGroup struct {
+		stats   RuleStats
+		peerIPs []string
+	}
+	groups := make(map[string]*ruleGroup, len(d.peerRules))
+	for pairID, r := range d.peerRules {
+		rules := d.peerRulesPairs[pairID]
+		key := firewallRulesKey(rules)
+		if group, ok := groups[key]; ok {
+			group.peerIPs = append(group.peerIPs, r.PeerIP)
+			if string(pairID) < group.stats.ID {
+				group.stats.ID = string(pairID)
+			}
+			continue
+		}
+
+		group := &ruleGroup{
+			stats: RuleStats{
+				ID:        string(pairID),
+				PolicyID:  r.PolicyID,
+				Direction: r.Direction,
+				Action:    r.Action,
+				Protocol:  r.Protocol,
+				Port:      r.Port,
+			},
+			peerIPs: []string{r.PeerIP},
+		}
+		for _, rule := range rules {
+			counter := counters[rule.GetRuleID()]
+			group.stats.Packets += counter.Packets
+			group.stats.Bytes += counter.Bytes
+		}
+		groups[key] = group
+	}
+
+	stats := make([]RuleStats, 0, len(groups))
+	for _, group := range groups {
+		sort.Strings(group.peerIPs)
+		group.stats.PeerIP = strings.Join(group.peerIPs, ",")
+		stats = append(stats, group.stats)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].PolicyID != stats[j].PolicyID {
+			return stats[i].PolicyID < stats[j].PolicyID
+		}
+		return stats[i].ID < stats[j].ID
+	})
+
+	return stats, nil
+}
+
+// firewallRulesKey identifies the firewall rules backing a peer rule
+func firewallRulesKey(rules []firewall.Rule) string {
+	ids := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		ids = append(ids, rule.GetRuleID())
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
 }
 
 func (d *DefaultManager) applyRouteACLs(rules []*mgmProto.RouteFirewallRule) error {
@@ -268,7 +368,13 @@ func (d *DefaultManager) protoRuleToFirewallRule(
 	}
 
 	var port *firewall.Port
-	if r.Port != "" {
+	switch {
+	case r.PortInfo != nil:
+		port = convertPortInfo(r.PortInfo)
+		if port == nil {
+			return "", nil, fmt.Errorf("invalid port info, skipping firewall rule")
+		}
+	case r.Port != "":
 		value, err := strconv.Atoi(r.Port)
 		if err != nil {
 			return "", nil, fmt.Errorf("invalid port, skipping firewall rule")
@@ -278,7 +384,7 @@ func (d *DefaultManager) protoRuleToFirewallRule(
 		}
 	}
 
-	ruleID := d.getPeerRuleID(ip, protocol, int(r.Direction), port, action, "")
+	ruleID := d.getPeerRuleID(ip, protocol, int(r.Direction), port, action, r.PolicyID)
 	if rulesPair, ok := d.peerRulesPairs[ruleID]; ok {
 		return ruleID, rulesPair, nil
 	}
@@ -286,11 +392,11 @@ func (d *DefaultManager) protoRuleToFirewallRule(
 	var rules []firewall.Rule
 	switch r.Direction {
 	case mgmProto.RuleDirection_IN:
-		rules, err = d.addInRules(ip, protocol, port, action, ipsetName, "")
+		rules, err = d.addInRules(ip, protocol, port, action, ipsetName, r.PolicyID)
 	case mgmProto.RuleDirection_OUT:
 		// TODO: Remove this soon. Outbound rules are obsolete.
 		// We only maintain this for return traffic (inbound dir) which is now handled by the stateful firewall already
-		rules, err = d.addOutRules(ip, protocol, port, action, ipsetName, "")
+		rules, err = d.addOutRules(ip, protocol, port, action, ipsetName, r.PolicyID)
 	default:
 		return "", nil, fmt.Errorf("invalid direction, skipping firewall rule")
 	}
@@ -383,11 +489,11 @@ func (d *DefaultManager) squashAcceptRules(
 	// We summ amount of Peers IP for given protocol we found in original rules list.
 	// But we zeroed the IP's for protocol if:
 	// 1. Any of the rule has DROP action type.
-	// 2. Any of rule contains Port.
+	// 2. Any of rule contains Port or PortInfo.
 	//
 	// We zeroed this to notify squash function that this protocol can't be squashed.
 	addRuleToCalculationMap := func(i int, r *mgmProto.FirewallRule, protocols protoMatch) {
-		drop := r.Action == mgmProto.RuleAction_DROP || r.Port != ""
+		drop := r.Action == mgmProto.RuleAction_DROP || r.Port != "" || r.PortInfo != nil
 		if drop {
 			protocols[r.Protocol] = map[string]int{}
 			return
@@ -486,9 +592,10 @@ func (d *DefaultManager) squashAcceptRules(
 	return append(rules, squashedRules...), squashedProtocols
 }
 
-// getRuleGroupingSelector takes all rule properties except IP address to build selector
+// getRuleGroupingSelector takes all rule properties except IP address to build selector,
+// rules of different policies are kept in different sets to count their hits separately
 func (d *DefaultManager) getRuleGroupingSelector(rule *mgmProto.FirewallRule) string {
-	return fmt.Sprintf("%v:%v:%v:%s", strconv.Itoa(int(rule.Direction)), rule.Action, rule.Protocol, rule.Port)
+	return fmt.Sprintf("%v:%v:%v:%s:%s", strconv.Itoa(int(rule.Direction)), rule.Action, rule.Protocol, rule.Port, rule.PolicyID)
 }
 
 func (d *DefaultManager) rollBack(newRulePairs map[id.RuleID][]firewall.Rule) {
@@ -51,7 +51,7 @@ func TestDefaultManager(t *testing.T) {
 	}).AnyTimes()
 
 	// we receive one rule from the management so for testing purposes ignore it
-	fw, err := firewall.NewFirewall(ifaceMock, nil)
+	fw, err := firewall.NewFirewall(ifaceMock, nil, nil)
 	if err != nil {
 		t.Errorf("create firewall: %v", err)
 		return
@@ -344,7 +344,7 @@ func TestDefaultManagerEnableSSHRules(t *testing.T) {
 	}).AnyTimes()
 
 	// we receive one rule from the management so for testing purposes ignore it
-	fw, err := firewall.NewFirewall(ifaceMock, nil)
+	fw, err := firewall.NewFirewall(ifaceMock, nil, nil)
 	if err != nil {
 		t.Errorf("create firewall: %v", err)
 		return
@@ -361,3 +361,239 @@ func TestDefaultManagerEnableSSHRules(t *testing.T) {
 		return
 	}
 }
+
+func TestDefaultManagerPortRanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ifaceMock := mocks.NewMockIFaceMapper(ctrl)
+	ifaceMock.EXPECT().IsUserspaceBind().Return(true).AnyTimes()
+	ifaceMock.EXPECT().SetFilter(gomock.Any())
+	ip, network, err := net.ParseCIDR("172.0.0.1/32")
+	if err != nil {
+		t.Fatalf("failed to parse IP address: %v", err)
+	}
+
+	ifaceMock.EXPECT().Name().Return("lo").AnyTimes()
+	ifaceMock.EXPECT().Address().Return(iface.WGAddress{
+		IP:      ip,
+		Network: network,
+	}).AnyTimes()
+
+	fw, err := firewall.NewFirewall(ifaceMock, nil, nil)
+	if err != nil {
+		t.Fatalf("create firewall: %v", err)
+	}
+	defer func(fw manager.Manager) {
+		_ = fw.Reset(nil)
+	}(fw)
+	acl := NewDefaultManager(fw)
+
+	portRange := &mgmProto.PortInfo{
+		PortSelection: &mgmProto.PortInfo_Range_{
+			Range: &mgmProto.PortInfo_Range{Start: 30000, End: 40000},
+		},
+	}
+
+	networkMap := &mgmProto.NetworkMap{
+		FirewallRules: []*mgmProto.FirewallRule{
+			{
+				PeerIP:    "10.93.0.1",
+				Direction: mgmProto.RuleDirection_IN,
+				Action:    mgmProto.RuleAction_ACCEPT,
+				Protocol:  mgmProto.RuleProtocol_TCP,
+				Port:      "30000-40000",
+				PortInfo:  portRange,
+			},
+			{
+				PeerIP:    "10.93.0.2",
+				Direction: mgmProto.RuleDirection_IN,
+				Action:    mgmProto.RuleAction_ACCEPT,
+				Protocol:  mgmProto.RuleProtocol_TCP,
+				Port:      "30000-40000",
+				PortInfo:  portRange,
+			},
+		},
+	}
+
+	rules, _ := acl.squashAcceptRules(networkMap)
+	if len(rules) != 2 {
+		t.Errorf("rules with port ranges should not be squashed, got %d rules", len(rules))
+	}
+
+	acl.ApplyFiltering(networkMap)
+	if len(acl.peerRulesPairs) != 2 {
+		t.Errorf("port range rules not applied: %v", acl.peerRulesPairs)
+	}
+
+	for _, pair := range acl.peerRulesPairs {
+		if len(pair) != 1 {
+			t.Errorf("port range should result in a single firewall rule, got %d", len(pair))
+		}
+	}
+}
+
+func TestDefaultManagerRuleStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ifaceMock := mocks.NewMockIFaceMapper(ctrl)
+	ifaceMock.EXPECT().IsUserspaceBind().Return(true).AnyTimes()
+	ifaceMock.EXPECT().SetFilter(gomock.Any())
+	ip, network, err := net.ParseCIDR("172.0.0.1/32")
+	if err != nil {
+		t.Fatalf("failed to parse IP address: %v", err)
+	}
+
+	ifaceMock.EXPECT().Name().Return("lo").AnyTimes()
+	ifaceMock.EXPECT().Address().Return(iface.WGAddress{
+		IP:      ip,
+		Network: network,
+	}).AnyTimes()
+
+	fw, err := firewall.NewFirewall(ifaceMock, nil, nil)
+	if err != nil {
+		t.Fatalf("create firewall: %v", err)
+	}
+	defer func(fw manager.Manager) {
+		_ = fw.Reset(nil)
+	}(fw)
+	acl := NewDefaultManager(fw)
+
+	networkMap := &mgmProto.NetworkMap{
+		FirewallRules: []*mgmProto.FirewallRule{
+			{
+				PolicyID:  "web",
+				PeerIP:    "10.93.0.1",
+				Direction: mgmProto.RuleDirection_IN,
+				Action:    mgmProto.RuleAction_ACCEPT,
+				Protocol:  mgmProto.RuleProtocol_TCP,
+				Port:      "443",
+			},
+			{
+				PolicyID:  "admin",
+				PeerIP:    "10.93.0.1",
+				Direction: mgmProto.RuleDirection_IN,
+				Action:    mgmProto.RuleAction_ACCEPT,
+				Protocol:  mgmProto.RuleProtocol_TCP,
+				Port:      "443",
+			},
+			{
+				PolicyID:  "deny",
+				PeerIP:    "10.93.0.2",
+				Direction: mgmProto.RuleDirection_IN,
+				Action:    mgmProto.RuleAction_DROP,
+				Protocol:  mgmProto.RuleProtocol_UDP,
+				Port:      "53",
+			},
+		},
+	}
+
+	acl.ApplyFiltering(networkMap)
+
+	stats, err := acl.GetRuleStats()
+	if err != nil {
+		t.Fatalf("get rule stats: %v", err)
+	}
+	if len(stats) != 3 {
+		t.Fatalf("expected a rule per policy, got %d rules", len(stats))
+	}
+
+	expectedPolicies := []string{"admin", "deny", "web"}
+	for i, s := range stats {
+		if s.PolicyID != expectedPolicies[i] {
+			t.Errorf("expected policy %s, got %s", expectedPolicies[i], s.PolicyID)
+		}
+		if s.Packets != 0 {
+			t.Errorf("expected no hits for rule %s, got %d", s.ID, s.Packets)
+		}
+	}
+	if stats[1].Action != mgmProto.RuleAction_DROP || stats[1].Port != "53" {
+		t.Errorf("unexpected drop rule: %+v", stats[1])
+	}
+}
+
+// ipsetFirewall backs the peer rules with a rule per ipset like the kernel firewall managers
+type ipsetFirewall struct {
+	manager.Manager
+	counters map[string]manager.RuleCounter
+}
+
+type ipsetRule string
+
+func (r ipsetRule) GetRuleID() string {
+	return string(r)
+}
+
+func (f *ipsetFirewall) AddPeerFiltering(_ net.IP, _ manager.Protocol, _ *manager.Port, _ *manager.Port, _ manager.Action, ipsetName string, _ string) ([]manager.Rule, error) {
+	return []manager.Rule{ipsetRule(ipsetName)}, nil
+}
+
+func (f *ipsetFirewall) DeletePeerRule(manager.Rule) error {
+	return nil
+}
+
+func (f *ipsetFirewall) SetLegacyManagement(bool) error {
+	return nil
+}
+
+func (f *ipsetFirewall) Flush() error {
+	return nil
+}
+
+func (f *ipsetFirewall) GetRuleCounters() (map[string]manager.RuleCounter, error) {
+	return f.counters, nil
+}
+
+func TestDefaultManagerRuleStatsIpset(t *testing.T) {
+	fw := &ipsetFirewall{}
+	acl := NewDefaultManager(fw)
+
+	rule := func(peerIP, port string) *mgmProto.FirewallRule {
+		return &mgmProto.FirewallRule{
+			PolicyID:  "web",
+			PeerIP:    peerIP,
+			Direction: mgmProto.RuleDirection_IN,
+			Action:    mgmProto.RuleAction_ACCEPT,
+			Protocol:  mgmProto.RuleProtocol_TCP,
+			Port:      port,
+		}
+	}
+	acl.ApplyFiltering(&mgmProto.NetworkMap{
+		FirewallRules: []*mgmProto.FirewallRule{
+			rule("10.93.0.2", "443"),
+			rule("10.93.0.1", "443"),
+			rule("10.93.0.3", "22"),
+		},
+		RoutesFirewallRulesIsEmpty: true,
+	})
+
+	fw.counters = make(map[string]manager.RuleCounter)
+	for _, rules := range acl.peerRulesPairs {
+		for _, r := range rules {
+			fw.counters[r.GetRuleID()] = manager.RuleCounter{Packets: 10, Bytes: 1000}
+		}
+	}
+
+	stats, err := acl.GetRuleStats()
+	if err != nil {
+		t.Fatalf("get rule stats: %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("expected a rule per ipset, got %d rules: %+v", len(stats), stats)
+	}
+
+	peerIPs := map[string]RuleStats{}
+	for _, s := range stats {
+		peerIPs[s.PeerIP] = s
+	}
+	for _, peerIP := range []string{"10.93.0.1,10.93.0.2", "10.93.0.3"} {
+		s, ok := peerIPs[peerIP]
+		if !ok {
+			t.Fatalf("missing rule of peers %s: %+v", peerIP, stats)
+		}
+		if s.Packets != 10 || s.Bytes != 1000 {
+			t.Errorf("expected the ipset counters once for peers %s, got %d packets and %d bytes", peerIP, s.Packets, s.Bytes)
+		}
+	}
+}
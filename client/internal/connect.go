@@ -409,6 +409,7 @@ func createEngineConfig(key wgtypes.Key, config *Config, peerConfig *mgmProto.Pe
 		WgPort:               config.WgPort,
 		NetworkMonitor:       nm,
 		SSHKey:               []byte(config.SSHKey),
+		SSHKnownHostsPath:    ssh.DefaultKnownHostsPath(),
 		NATExternalIPs:       config.NATExternalIPs,
 		CustomDNSAddress:     config.CustomDNSAddress,
 		RosenpassEnabled:     config.RosenpassEnabled,
@@ -0,0 +1,277 @@
+package dns
+
+import (
+	"container/list"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/miekg/dns"
+	log "github.com/sirupsen/logrus"
+)
+
+// EnvDNSCacheSize sets the number of responses cached for each nameserver group, 0 disables the cache
+const EnvDNSCacheSize = "NB_DNS_CACHE_SIZE"
+
+// DefaultCacheSize is the number of responses cached for each nameserver group
+const DefaultCacheSize = 1000
+
+const (
+	// maxCacheTTL caps the time a response is cached regardless of the TTL of its records
+	maxCacheTTL = time.Hour
+	// maxNegativeCacheTTL caps the time a NXDOMAIN or an empty response is cached
+	maxNegativeCacheTTL = 5 * time.Minute
+)
+
+// cachingHandler is implemented by the handlers caching the responses
+type cachingHandler interface {
+	purgeCache()
+}
+
+// CacheStats are the counters of the upstream response caches
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int64
+}
+
+// cacheCounters are shared by the caches of all nameserver groups, so they survive configuration updates
+type cacheCounters struct {
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+	entries   atomic.Int64
+}
+
+func (c *cacheCounters) stats() CacheStats {
+	return CacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Entries:   c.entries.Load(),
+	}
+}
+
+type cacheKey struct {
+	name   string
+	qtype  uint16
+	qclass uint16
+}
+
+type cacheEntry struct {
+	key     cacheKey
+	msg     *dns.Msg
+	stored  time.Time
+	expires time.Time
+}
+
+// responseCache caches the upstream responses respecting their TTL, evicting the least recently used
+// responses when full. A nil cache caches nothing.
+type responseCache struct {
+	mu       sync.Mutex
+	maxSize  int
+	entries  map[cacheKey]*list.Element
+	lru      *list.List
+	counters *cacheCounters
+	now      func() time.Time
+}
+
+func newResponseCache(maxSize int, counters *cacheCounters) *responseCache {
+	if maxSize <= 0 {
+		return nil
+	}
+	return &responseCache{
+		maxSize:  maxSize,
+		entries:  make(map[cacheKey]*list.Element),
+		lru:      list.New(),
+		counters: counters,
+		now:      time.Now,
+	}
+}
+
+func cacheSizeFromEnv() int {
+	value := os.Getenv(EnvDNSCacheSize)
+	if value == "" {
+		return DefaultCacheSize
+	}
+	size, err := strconv.Atoi(value)
+	if err != nil || size < 0 {
+		log.Warnf("invalid %s value %q, using the default cache size %d", EnvDNSCacheSize, value, DefaultCacheSize)
+		return DefaultCacheSize
+	}
+	if size == 0 {
+		log.Infof("DNS response caching is disabled by %s", EnvDNSCacheSize)
+	}
+	return size
+}
+
+func newCacheKey(r *dns.Msg) (cacheKey, bool) {
+	if len(r.Question) != 1 {
+		return cacheKey{}, false
+	}
+	question := r.Question[0]
+	return cacheKey{
+		name:   strings.ToLower(question.Name),
+		qtype:  question.Qtype,
+		qclass: question.Qclass,
+	}, true
+}
+
+// get returns a copy of the cached response to the query with the TTLs decreased by the time it spent in the cache,
+// nil if there is no valid response cached
+func (c *responseCache) get(r *dns.Msg) *dns.Msg {
+	if c == nil {
+		return nil
+	}
+	key, ok := newCacheKey(r)
+	if !ok {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		c.counters.misses.Add(1)
+		return nil
+	}
+
+	entry := element.Value.(*cacheEntry)
+	now := c.now()
+	if !now.Before(entry.expires) {
+		c.remove(element)
+		c.counters.misses.Add(1)
+		return nil
+	}
+	c.lru.MoveToFront(element)
+	c.counters.hits.Add(1)
+
+	resp := entry.msg.Copy()
+	resp.Id = r.Id
+	resp.Question = r.Question
+	elapsed := uint32(now.Sub(entry.stored) / time.Second)
+	for _, section := range [][]dns.RR{resp.Answer, resp.Ns, resp.Extra} {
+		for _, rr := range section {
+			if rr.Header().Rrtype == dns.TypeOPT {
+				continue
+			}
+			if rr.Header().Ttl > elapsed {
+				rr.Header().Ttl -= elapsed
+			} else {
+				rr.Header().Ttl = 0
+			}
+		}
+	}
+	return resp
+}
+
+// set caches the response to the query if it is cacheable
+func (c *responseCache) set(r, resp *dns.Msg) {
+	if c == nil {
+		return
+	}
+	key, ok := newCacheKey(r)
+	if !ok {
+		return
+	}
+	ttl, ok := cacheTTL(resp)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	entry := &cacheEntry{
+		key:     key,
+		msg:     resp.Copy(),
+		stored:  now,
+		expires: now.Add(ttl),
+	}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.lru.MoveToFront(element)
+		return
+	}
+
+	for c.lru.Len() >= c.maxSize {
+		c.remove(c.lru.Back())
+		c.counters.evictions.Add(1)
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	c.counters.entries.Add(1)
+}
+
+// purge drops all cached responses
+func (c *responseCache) purge() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.counters.entries.Add(-int64(c.lru.Len()))
+	c.entries = make(map[cacheKey]*list.Element)
+	c.lru.Init()
+}
+
+func (c *responseCache) remove(element *list.Element) {
+	c.lru.Remove(element)
+	delete(c.entries, element.Value.(*cacheEntry).key)
+	c.counters.entries.Add(-1)
+}
+
+// cacheTTL returns for how long the response can be cached. Positive responses are cached for the lowest TTL of
+// their records, negative responses for the TTL of the SOA record in the authority section as described in RFC 2308.
+func cacheTTL(resp *dns.Msg) (time.Duration, bool) {
+	if resp.Truncated {
+		return 0, false
+	}
+
+	switch {
+	case resp.Rcode == dns.RcodeSuccess && len(resp.Answer) > 0:
+		ttl, ok := minTTL(resp)
+		if !ok || ttl == 0 {
+			return 0, false
+		}
+		return min(time.Duration(ttl)*time.Second, maxCacheTTL), true
+	case resp.Rcode == dns.RcodeSuccess || resp.Rcode == dns.RcodeNameError:
+		for _, rr := range resp.Ns {
+			soa, ok := rr.(*dns.SOA)
+			if !ok {
+				continue
+			}
+			ttl := min(soa.Hdr.Ttl, soa.Minttl)
+			if ttl == 0 {
+				return 0, false
+			}
+			return min(time.Duration(ttl)*time.Second, maxNegativeCacheTTL), true
+		}
+	}
+	return 0, false
+}
+
+func minTTL(resp *dns.Msg) (uint32, bool) {
+	var ttl uint32
+	found := false
+	for _, section := range [][]dns.RR{resp.Answer, resp.Ns, resp.Extra} {
+		for _, rr := range section {
+			if rr.Header().Rrtype == dns.TypeOPT {
+				continue
+			}
+			if !found || rr.Header().Ttl < ttl {
+				ttl = rr.Header().Ttl
+				found = true
+			}
+		}
+	}
+	return ttl, found
+}
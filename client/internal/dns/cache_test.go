@@ -0,0 +1,211 @@
+package dns
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestQuery(name string, qtype uint16) *dns.Msg {
+	r := new(dns.Msg)
+	r.SetQuestion(name, qtype)
+	return r
+}
+
+func newTestAnswer(t *testing.T, r *dns.Msg, records ...string) *dns.Msg {
+	t.Helper()
+	resp := new(dns.Msg)
+	resp.SetReply(r)
+	for _, record := range records {
+		rr, err := dns.NewRR(record)
+		require.NoError(t, err)
+		resp.Answer = append(resp.Answer, rr)
+	}
+	return resp
+}
+
+func newTestNegativeAnswer(t *testing.T, r *dns.Msg, rcode int, soa string) *dns.Msg {
+	t.Helper()
+	resp := new(dns.Msg)
+	resp.SetRcode(r, rcode)
+	if soa != "" {
+		rr, err := dns.NewRR(soa)
+		require.NoError(t, err)
+		resp.Ns = append(resp.Ns, rr)
+	}
+	return resp
+}
+
+func TestResponseCache_TTL(t *testing.T) {
+	now := time.Now()
+	cache := newResponseCache(10, &cacheCounters{})
+	cache.now = func() time.Time { return now }
+
+	r := newTestQuery("www.example.com.", dns.TypeA)
+	cache.set(r, newTestAnswer(t, r,
+		"www.example.com. 300 IN CNAME example.com.",
+		"example.com. 60 IN A 1.2.3.4",
+	))
+
+	now = now.Add(20 * time.Second)
+	query := newTestQuery("WWW.example.com.", dns.TypeA)
+	resp := cache.get(query)
+	require.NotNil(t, resp, "response should be cached")
+	assert.Equal(t, query.Id, resp.Id, "response should match the query id")
+	assert.Equal(t, "WWW.example.com.", resp.Question[0].Name, "response should match the query question")
+	require.Len(t, resp.Answer, 2)
+	assert.Equal(t, uint32(280), resp.Answer[0].Header().Ttl, "TTL should be decreased by the time spent in the cache")
+	assert.Equal(t, uint32(40), resp.Answer[1].Header().Ttl, "TTL should be decreased by the time spent in the cache")
+
+	assert.Nil(t, cache.get(newTestQuery("www.example.com.", dns.TypeAAAA)), "other query types should not match")
+
+	now = now.Add(40 * time.Second)
+	assert.Nil(t, cache.get(r), "response should expire with the lowest TTL")
+
+	stats := cache.counters.stats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(2), stats.Misses)
+	assert.Equal(t, int64(0), stats.Entries, "expired response should be removed")
+}
+
+func TestResponseCache_Cacheable(t *testing.T) {
+	soa := "example.com. 3600 IN SOA ns.example.com. admin.example.com. 1 7200 3600 1209600 30"
+
+	tests := []struct {
+		name     string
+		response func(r *dns.Msg) *dns.Msg
+		ttl      time.Duration
+	}{
+		{
+			name: "positive answer",
+			response: func(r *dns.Msg) *dns.Msg {
+				return newTestAnswer(t, r, "example.com. 120 IN A 1.2.3.4")
+			},
+			ttl: 120 * time.Second,
+		},
+		{
+			name: "positive answer capped",
+			response: func(r *dns.Msg) *dns.Msg {
+				return newTestAnswer(t, r, "example.com. 86400 IN A 1.2.3.4")
+			},
+			ttl: maxCacheTTL,
+		},
+		{
+			name: "NXDOMAIN with SOA",
+			response: func(r *dns.Msg) *dns.Msg {
+				return newTestNegativeAnswer(t, r, dns.RcodeNameError, soa)
+			},
+			ttl: 30 * time.Second,
+		},
+		{
+			name: "empty answer with SOA",
+			response: func(r *dns.Msg) *dns.Msg {
+				return newTestNegativeAnswer(t, r, dns.RcodeSuccess, soa)
+			},
+			ttl: 30 * time.Second,
+		},
+		{
+			name: "NXDOMAIN without SOA",
+			response: func(r *dns.Msg) *dns.Msg {
+				return newTestNegativeAnswer(t, r, dns.RcodeNameError, "")
+			},
+		},
+		{
+			name: "SERVFAIL",
+			response: func(r *dns.Msg) *dns.Msg {
+				return newTestNegativeAnswer(t, r, dns.RcodeServerFailure, soa)
+			},
+		},
+		{
+			name: "zero TTL",
+			response: func(r *dns.Msg) *dns.Msg {
+				return newTestAnswer(t, r, "example.com. 0 IN A 1.2.3.4")
+			},
+		},
+		{
+			name: "truncated",
+			response: func(r *dns.Msg) *dns.Msg {
+				resp := newTestAnswer(t, r, "example.com. 120 IN A 1.2.3.4")
+				resp.Truncated = true
+				return resp
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ttl, ok := cacheTTL(tc.response(newTestQuery("example.com.", dns.TypeA)))
+			assert.Equal(t, tc.ttl != 0, ok)
+			assert.Equal(t, tc.ttl, ttl)
+		})
+	}
+}
+
+func TestResponseCache_Eviction(t *testing.T) {
+	counters := &cacheCounters{}
+	cache := newResponseCache(2, counters)
+
+	first := newTestQuery("first.example.com.", dns.TypeA)
+	second := newTestQuery("second.example.com.", dns.TypeA)
+	third := newTestQuery("third.example.com.", dns.TypeA)
+
+	cache.set(first, newTestAnswer(t, first, "first.example.com. 60 IN A 1.1.1.1"))
+	cache.set(second, newTestAnswer(t, second, "second.example.com. 60 IN A 2.2.2.2"))
+	require.NotNil(t, cache.get(first))
+	cache.set(third, newTestAnswer(t, third, "third.example.com. 60 IN A 3.3.3.3"))
+
+	assert.NotNil(t, cache.get(first), "recently used response should be kept")
+	assert.Nil(t, cache.get(second), "least recently used response should be evicted")
+	assert.NotNil(t, cache.get(third))
+
+	stats := counters.stats()
+	assert.Equal(t, uint64(1), stats.Evictions)
+	assert.Equal(t, int64(2), stats.Entries)
+
+	cache.purge()
+	assert.Nil(t, cache.get(first), "purged response should not be returned")
+	assert.Equal(t, int64(0), counters.stats().Entries)
+}
+
+type countingUpstreamClient struct {
+	resp  *dns.Msg
+	calls int
+}
+
+func (c *countingUpstreamClient) exchange(_ context.Context, _ string, r *dns.Msg) (*dns.Msg, time.Duration, error) {
+	c.calls++
+	resp := c.resp.Copy()
+	resp.Id = r.Id
+	return resp, time.Millisecond, nil
+}
+
+func TestUpstreamResolver_Cache(t *testing.T) {
+	r := newTestQuery("example.com.", dns.TypeA)
+	client := &countingUpstreamClient{resp: newTestAnswer(t, r, "example.com. 60 IN A 1.2.3.4")}
+
+	resolver := newUpstreamResolverBase(context.Background(), nil)
+	resolver.upstreamClient = client
+	resolver.upstreamServers = []string{"10.0.0.1:53"}
+	resolver.cache = newResponseCache(10, &cacheCounters{})
+
+	for i := 0; i < 3; i++ {
+		var answer *dns.Msg
+		resolver.ServeDNS(&mockResponseWriter{
+			WriteMsgFunc: func(m *dns.Msg) error {
+				answer = m
+				return nil
+			},
+		}, newTestQuery("example.com.", dns.TypeA))
+		require.NotNil(t, answer)
+		require.Len(t, answer.Answer, 1)
+	}
+	assert.Equal(t, 1, client.calls, "repeated queries should be answered from the cache")
+
+	resolver.purgeCache()
+	resolver.ServeDNS(&mockResponseWriter{}, newTestQuery("example.com.", dns.TypeA))
+	assert.Equal(t, 2, client.calls, "purged cache should forward the query to the upstream")
+}
@@ -4,6 +4,7 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/miekg/dns"
 	log "github.com/sirupsen/logrus"
@@ -34,6 +35,7 @@ type HandlerEntry struct {
 type HandlerChain struct {
 	mu       sync.RWMutex
 	handlers []HandlerEntry
+	queryLog *QueryLog
 }
 
 // ResponseWriterChain wraps a dns.ResponseWriter to track if handler wants to continue chain
@@ -41,6 +43,10 @@ type ResponseWriterChain struct {
 	dns.ResponseWriter
 	origPattern    string
 	shouldContinue bool
+	answered       bool
+	rcode          int
+	upstream       string
+	cached         bool
 }
 
 func (w *ResponseWriterChain) WriteMsg(m *dns.Msg) error {
@@ -49,15 +55,32 @@ func (w *ResponseWriterChain) WriteMsg(m *dns.Msg) error {
 		w.shouldContinue = true
 		return nil
 	}
+	w.answered = true
+	w.rcode = m.Rcode
 	return w.ResponseWriter.WriteMsg(m)
 }
 
+// setUpstream records the upstream nameserver the query was forwarded to
+func (w *ResponseWriterChain) setUpstream(upstream string) {
+	w.upstream = upstream
+}
+
+// setCached records that the query was answered from the upstream response cache
+func (w *ResponseWriterChain) setCached() {
+	w.cached = true
+}
+
 func NewHandlerChain() *HandlerChain {
 	return &HandlerChain{
 		handlers: make([]HandlerEntry, 0),
 	}
 }
 
+// SetQueryLog sets the query log the handled queries are recorded to
+func (c *HandlerChain) SetQueryLog(queryLog *QueryLog) {
+	c.queryLog = queryLog
+}
+
 // GetOrigPattern returns the original pattern of the handler that wrote the response
 func (w *ResponseWriterChain) GetOrigPattern() string {
 	return w.origPattern
@@ -138,6 +161,18 @@ func (c *HandlerChain) RemoveHandler(pattern string, priority int) {
 	}
 }
 
+// purgeCaches drops the cached responses of all handlers in the chain
+func (c *HandlerChain) purgeCaches() {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, entry := range c.handlers {
+		if h, ok := entry.Handler.(cachingHandler); ok {
+			h.purgeCache()
+		}
+	}
+}
+
 // HasHandlers returns true if there are any handlers remaining for the given pattern
 func (c *HandlerChain) HasHandlers(pattern string) bool {
 	c.mu.RLock()
@@ -157,6 +192,7 @@ func (c *HandlerChain) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 		return
 	}
 
+	start := time.Now()
 	qname := strings.ToLower(r.Question[0].Name)
 	log.Tracef("handling DNS request for domain=%s", qname)
 
@@ -212,6 +248,8 @@ func (c *HandlerChain) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 			log.Tracef("handler requested continue to next handler")
 			continue
 		}
+
+		c.recordQuery(r, start, entry, chainWriter)
 		return
 	}
 
@@ -222,4 +260,27 @@ func (c *HandlerChain) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 	if err := w.WriteMsg(resp); err != nil {
 		log.Errorf("failed to write DNS response: %v", err)
 	}
+
+	if c.queryLog != nil {
+		logEntry := newQueryLogEntry(r, start)
+		logEntry.Handler = QueryHandlerNone
+		logEntry.Rcode = dns.RcodeToString[dns.RcodeNameError]
+		c.queryLog.record(logEntry)
+	}
+}
+
+func (c *HandlerChain) recordQuery(r *dns.Msg, start time.Time, entry HandlerEntry, w *ResponseWriterChain) {
+	if c.queryLog == nil {
+		return
+	}
+
+	logEntry := newQueryLogEntry(r, start)
+	logEntry.Handler = handlerKind(entry)
+	logEntry.Pattern = entry.OrigPattern
+	logEntry.Upstream = w.upstream
+	logEntry.Cached = w.cached
+	if w.answered {
+		logEntry.Rcode = dns.RcodeToString[w.rcode]
+	}
+	c.queryLog.record(logEntry)
 }
@@ -24,6 +24,10 @@ func (d *localResolver) MatchSubdomains() bool {
 func (d *localResolver) stop() {
 }
 
+func (d *localResolver) queryHandlerKind() string {
+	return QueryHandlerLocal
+}
+
 // String returns a string representation of the local resolver
 func (d *localResolver) String() string {
 	return fmt.Sprintf("local resolver [%d records]", len(d.registeredMap))
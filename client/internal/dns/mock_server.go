@@ -68,3 +68,13 @@ func (m *MockServer) SearchDomains() []string {
 // ProbeAvailability mocks implementation of ProbeAvailability from the Server interface
 func (m *MockServer) ProbeAvailability() {
 }
+
+// QueryLog mocks implementation of QueryLog from the Server interface
+func (m *MockServer) QueryLog() *QueryLog {
+	return nil
+}
+
+// CacheStats mocks implementation of CacheStats from the Server interface
+func (m *MockServer) CacheStats() CacheStats {
+	return CacheStats{}
+}
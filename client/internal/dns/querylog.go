@@ -0,0 +1,190 @@
+package dns
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+	log "github.com/sirupsen/logrus"
+)
+
+// EnvEnableQueryLog enables the recording of the DNS queries, the query counters are always kept
+const EnvEnableQueryLog = "NB_ENABLE_DNS_QUERY_LOG"
+
+// DefaultMaxQueryLogEntries is the number of the most recent DNS queries kept in the query log
+const DefaultMaxQueryLogEntries = 1000
+
+// Kinds of the handlers answering the DNS queries
+const (
+	QueryHandlerLocal           = "local"
+	QueryHandlerNameserverGroup = "nameserver-group"
+	QueryHandlerHost            = "host"
+	QueryHandlerRoute           = "route"
+	QueryHandlerOther           = "other"
+	QueryHandlerNone            = "none"
+)
+
+// queryHandler is implemented by the handlers that report their kind to the query log
+type queryHandler interface {
+	queryHandlerKind() string
+}
+
+// upstreamRecorder is implemented by the response writers that record the upstream a query was forwarded to
+type upstreamRecorder interface {
+	setUpstream(upstream string)
+	setCached()
+}
+
+// QueryLogEntry is a DNS query handled by the resolver
+type QueryLogEntry struct {
+	Timestamp time.Time
+	Name      string
+	Type      string
+	// Rcode is the response code of the answer, empty if no answer was sent
+	Rcode string
+	// Handler is the kind of the handler that answered the query
+	Handler string
+	// Pattern is the domain pattern the handler is registered for
+	Pattern string
+	// Upstream is the nameserver the query was forwarded to, empty for the queries answered locally
+	Upstream string
+	// Cached is set if the query was answered from the upstream response cache
+	Cached  bool
+	Latency time.Duration
+}
+
+// QueryStats are the counters of the DNS queries handled since the resolver started
+type QueryStats struct {
+	Total uint64
+	// Failed counts the queries that were left without an answer
+	Failed    uint64
+	ByHandler map[string]uint64
+	ByRcode   map[string]uint64
+}
+
+// QueryLog keeps the most recent DNS queries in a ring buffer along with counters of all queries
+type QueryLog struct {
+	mu      sync.Mutex
+	enabled bool
+	entries []QueryLogEntry
+	next    int
+	full    bool
+	stats   QueryStats
+}
+
+// NewQueryLog creates a query log keeping up to maxEntries queries. The queries are only counted if disabled.
+func NewQueryLog(maxEntries int, enabled bool) *QueryLog {
+	l := &QueryLog{
+		enabled: enabled,
+		stats: QueryStats{
+			ByHandler: make(map[string]uint64),
+			ByRcode:   make(map[string]uint64),
+		},
+	}
+	if enabled {
+		l.entries = make([]QueryLogEntry, maxEntries)
+	}
+	return l
+}
+
+// newQueryLogFromEnv creates the query log of the resolver, the queries are only counted unless EnvEnableQueryLog is set
+func newQueryLogFromEnv() *QueryLog {
+	if enabled, _ := strconv.ParseBool(os.Getenv(EnvEnableQueryLog)); enabled {
+		log.Infof("DNS query logging is enabled by %s", EnvEnableQueryLog)
+		return NewQueryLog(DefaultMaxQueryLogEntries, true)
+	}
+	return NewQueryLog(0, false)
+}
+
+// Enabled returns whether the queries are recorded
+func (l *QueryLog) Enabled() bool {
+	return l.enabled
+}
+
+func (l *QueryLog) record(entry QueryLogEntry) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stats.Total++
+	l.stats.ByHandler[entry.Handler]++
+	if entry.Rcode == "" {
+		l.stats.Failed++
+	} else {
+		l.stats.ByRcode[entry.Rcode]++
+	}
+
+	if len(l.entries) == 0 {
+		return
+	}
+	l.entries[l.next] = entry
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Entries returns the recorded queries, oldest first
+func (l *QueryLog) Entries() []QueryLogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.full {
+		entries := make([]QueryLogEntry, l.next)
+		copy(entries, l.entries[:l.next])
+		return entries
+	}
+
+	entries := make([]QueryLogEntry, 0, len(l.entries))
+	entries = append(entries, l.entries[l.next:]...)
+	return append(entries, l.entries[:l.next]...)
+}
+
+// Stats returns a copy of the query counters
+func (l *QueryLog) Stats() QueryStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := QueryStats{
+		Total:     l.stats.Total,
+		Failed:    l.stats.Failed,
+		ByHandler: make(map[string]uint64, len(l.stats.ByHandler)),
+		ByRcode:   make(map[string]uint64, len(l.stats.ByRcode)),
+	}
+	for k, v := range l.stats.ByHandler {
+		stats.ByHandler[k] = v
+	}
+	for k, v := range l.stats.ByRcode {
+		stats.ByRcode[k] = v
+	}
+	return stats
+}
+
+func handlerKind(entry HandlerEntry) string {
+	if h, ok := entry.Handler.(queryHandler); ok {
+		return h.queryHandlerKind()
+	}
+	if entry.Priority == PriorityDNSRoute {
+		return QueryHandlerRoute
+	}
+	return QueryHandlerOther
+}
+
+func newQueryLogEntry(r *dns.Msg, start time.Time) QueryLogEntry {
+	question := r.Question[0]
+	qtype, ok := dns.TypeToString[question.Qtype]
+	if !ok {
+		qtype = strconv.Itoa(int(question.Qtype))
+	}
+	return QueryLogEntry{
+		Timestamp: start,
+		Name:      question.Name,
+		Type:      qtype,
+		Latency:   time.Since(start),
+	}
+}
@@ -0,0 +1,118 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+)
+
+func TestQueryLog_Entries(t *testing.T) {
+	queryLog := NewQueryLog(3, true)
+	for i := 0; i < 5; i++ {
+		queryLog.record(QueryLogEntry{
+			Name:    fmt.Sprintf("peer%d.netbird.cloud.", i),
+			Handler: QueryHandlerLocal,
+			Rcode:   "NOERROR",
+		})
+	}
+
+	entries := queryLog.Entries()
+	require.Len(t, entries, 3, "only the most recent queries should be kept")
+	assert.Equal(t, "peer2.netbird.cloud.", entries[0].Name)
+	assert.Equal(t, "peer4.netbird.cloud.", entries[2].Name)
+
+	stats := queryLog.Stats()
+	assert.Equal(t, uint64(5), stats.Total, "all queries should be counted")
+	assert.Equal(t, uint64(5), stats.ByHandler[QueryHandlerLocal])
+	assert.Equal(t, uint64(5), stats.ByRcode["NOERROR"])
+}
+
+func TestQueryLog_Disabled(t *testing.T) {
+	queryLog := NewQueryLog(0, false)
+	queryLog.record(QueryLogEntry{Name: "example.com.", Handler: QueryHandlerHost})
+
+	assert.False(t, queryLog.Enabled())
+	assert.Empty(t, queryLog.Entries(), "queries should not be recorded if disabled")
+
+	stats := queryLog.Stats()
+	assert.Equal(t, uint64(1), stats.Total, "queries should be counted if disabled")
+	assert.Equal(t, uint64(1), stats.Failed, "a query without an answer should be counted as failed")
+}
+
+func TestHandlerChain_QueryLog(t *testing.T) {
+	local := &localResolver{registeredMap: make(registrationMap)}
+	err := local.registerRecord(nbdns.SimpleRecord{
+		Name:  "peera.netbird.cloud.",
+		Type:  int(dns.TypeA),
+		Class: nbdns.DefaultClass,
+		TTL:   300,
+		RData: "1.2.3.4",
+	})
+	require.NoError(t, err)
+
+	newUpstream := func(hostFallback bool) *upstreamResolverBase {
+		upstream := newUpstreamResolverBase(context.Background(), nil)
+		upstream.upstreamClient = &mockUpstreamResolver{
+			r:   &dns.Msg{MsgHdr: dns.MsgHdr{Response: true}},
+			rtt: time.Millisecond,
+		}
+		upstream.upstreamServers = []string{"10.0.0.1:53"}
+		upstream.hostFallback = hostFallback
+		return upstream
+	}
+
+	queryLog := NewQueryLog(10, true)
+	chain := NewHandlerChain()
+	chain.SetQueryLog(queryLog)
+	chain.AddHandler("netbird.cloud.", local, PriorityMatchDomain, nil)
+	chain.AddHandler("example.com.", newUpstream(false), PriorityMatchDomain, nil)
+	chain.AddHandler(".", newUpstream(true), PriorityDefault, nil)
+
+	for _, name := range []string{"peera.netbird.cloud.", "www.example.com.", "google.com."} {
+		r := new(dns.Msg)
+		r.SetQuestion(name, dns.TypeA)
+		chain.ServeDNS(&mockResponseWriter{}, r)
+	}
+
+	entries := queryLog.Entries()
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, "peera.netbird.cloud.", entries[0].Name)
+	assert.Equal(t, "A", entries[0].Type)
+	assert.Equal(t, "NOERROR", entries[0].Rcode)
+	assert.Equal(t, QueryHandlerLocal, entries[0].Handler)
+	assert.Equal(t, "netbird.cloud.", entries[0].Pattern)
+	assert.Empty(t, entries[0].Upstream)
+
+	assert.Equal(t, QueryHandlerNameserverGroup, entries[1].Handler)
+	assert.Equal(t, "example.com.", entries[1].Pattern)
+	assert.Equal(t, "10.0.0.1:53", entries[1].Upstream)
+
+	assert.Equal(t, QueryHandlerHost, entries[2].Handler)
+	assert.Equal(t, ".", entries[2].Pattern)
+
+	chain.RemoveHandler(".", PriorityDefault)
+	r := new(dns.Msg)
+	r.SetQuestion("google.com.", dns.TypeAAAA)
+	chain.ServeDNS(&mockResponseWriter{}, r)
+
+	entries = queryLog.Entries()
+	require.Len(t, entries, 4)
+	assert.Equal(t, QueryHandlerNone, entries[3].Handler)
+	assert.Equal(t, "NXDOMAIN", entries[3].Rcode)
+	assert.Equal(t, "AAAA", entries[3].Type)
+}
+
+func TestNewQueryLogFromEnv(t *testing.T) {
+	assert.False(t, newQueryLogFromEnv().Enabled(), "query logging should be disabled by default")
+
+	t.Setenv(EnvEnableQueryLog, "true")
+	assert.True(t, newQueryLogFromEnv().Enabled())
+}
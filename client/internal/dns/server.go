@@ -40,6 +40,8 @@ type Server interface {
 	OnUpdatedHostDNSServer(strings []string)
 	SearchDomains() []string
 	ProbeAvailability()
+	QueryLog() *QueryLog
+	CacheStats() CacheStats
 }
 
 type registeredHandlerMap map[string]handlerWithStop
@@ -60,6 +62,9 @@ type DefaultServer struct {
 	previousConfigHash uint64
 	currentConfig      HostDNSConfig
 	handlerChain       *HandlerChain
+	queryLog           *QueryLog
+	cacheSize          int
+	cacheCounters      *cacheCounters
 
 	// permanent related properties
 	permanent      bool
@@ -157,12 +162,18 @@ func newDefaultServer(
 	disableSys bool,
 ) *DefaultServer {
 	ctx, stop := context.WithCancel(ctx)
+	queryLog := newQueryLogFromEnv()
+	handlerChain := NewHandlerChain()
+	handlerChain.SetQueryLog(queryLog)
 	defaultServer := &DefaultServer{
 		ctx:               ctx,
 		ctxCancel:         stop,
 		disableSys:        disableSys,
 		service:           dnsService,
-		handlerChain:      NewHandlerChain(),
+		handlerChain:      handlerChain,
+		queryLog:          queryLog,
+		cacheSize:         cacheSizeFromEnv(),
+		cacheCounters:     &cacheCounters{},
 		dnsMuxMap:         make(registeredHandlerMap),
 		handlerPriorities: make(map[string]int),
 		localResolver: &localResolver{
@@ -330,6 +341,9 @@ func (s *DefaultServer) UpdateDNSServer(serial uint64, update nbdns.Config) erro
 		return nil
 	}
 
+	// the responses cached so far may be stale with the new nameservers, records and routes
+	s.handlerChain.purgeCaches()
+
 	if err := s.applyConfiguration(update); err != nil {
 		return fmt.Errorf("apply configuration: %w", err)
 	}
@@ -340,6 +354,16 @@ func (s *DefaultServer) UpdateDNSServer(serial uint64, update nbdns.Config) erro
 	return nil
 }
 
+// QueryLog returns the log of the DNS queries handled by the server
+func (s *DefaultServer) QueryLog() *QueryLog {
+	return s.queryLog
+}
+
+// CacheStats returns the counters of the upstream response caches
+func (s *DefaultServer) CacheStats() CacheStats {
+	return s.cacheCounters.stats()
+}
+
 func (s *DefaultServer) SearchDomains() []string {
 	var searchDomains []string
 
@@ -467,12 +491,17 @@ func (s *DefaultServer) buildUpstreamHandlerUpdate(nameServerGroups []*nbdns.Nam
 			return nil, fmt.Errorf("unable to create a new upstream resolver, error: %v", err)
 		}
 		for _, ns := range nsGroup.NameServers {
-			if ns.NSType != nbdns.UDPNameServerType {
-				log.Warnf("skipping nameserver %s with type %s, this peer supports only %s",
-					ns.IP.String(), ns.NSType.String(), nbdns.UDPNameServerType.String())
-				continue
+			switch ns.NSType {
+			case nbdns.UDPNameServerType:
+				handler.upstreamServers = append(handler.upstreamServers, getNSHostPort(ns))
+			case nbdns.TLSNameServerType, nbdns.HTTPSNameServerType:
+				if err := handler.addEncryptedUpstream(ns); err != nil {
+					log.Warnf("skipping nameserver %s: %v", ns.IP, err)
+				}
+			default:
+				log.Warnf("skipping nameserver %s with type %s, this peer supports only %s, %s and %s",
+					ns.IP.String(), ns.NSType.String(), nbdns.UDPNameServerType, nbdns.TLSNameServerType, nbdns.HTTPSNameServerType)
 			}
-			handler.upstreamServers = append(handler.upstreamServers, getNSHostPort(ns))
 		}
 
 		if len(handler.upstreamServers) == 0 {
@@ -481,6 +510,8 @@ func (s *DefaultServer) buildUpstreamHandlerUpdate(nameServerGroups []*nbdns.Nam
 			continue
 		}
 
+		handler.cache = newResponseCache(s.cacheSize, s.cacheCounters)
+
 		// when upstream fails to resolve domain several times over all it servers
 		// it will calls this hook to exclude self from the configuration and
 		// reapply DNS settings, but it not touch the original configuration and serial number
@@ -688,6 +719,8 @@ func (s *DefaultServer) addHostRootZone() {
 	}
 	handler.deactivate = func(error) {}
 	handler.reactivate = func() {}
+	handler.hostFallback = true
+	handler.cache = newResponseCache(s.cacheSize, s.cacheCounters)
 
 	s.registerHandler([]string{nbdns.RootZone}, handler, PriorityDefault)
 }
@@ -698,7 +731,7 @@ func (s *DefaultServer) updateNSGroupStates(groups []*nbdns.NameServerGroup) {
 	for _, group := range groups {
 		var servers []string
 		for _, ns := range group.NameServers {
-			servers = append(servers, fmt.Sprintf("%s:%d", ns.IP, ns.Port))
+			servers = append(servers, getNSAddress(ns))
 		}
 
 		state := peer.NSGroupState{
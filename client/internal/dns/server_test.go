@@ -849,7 +849,7 @@ func createWgInterfaceWithBind(t *testing.T) (*iface.WGIface, error) {
 		return nil, err
 	}
 
-	pf, err := uspfilter.Create(wgIface)
+	pf, err := uspfilter.Create(wgIface, nil)
 	if err != nil {
 		t.Fatalf("failed to create uspfilter: %v", err)
 		return nil, err
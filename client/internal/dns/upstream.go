@@ -15,6 +15,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netbirdio/netbird/client/internal/peer"
+	nbdns "github.com/netbirdio/netbird/dns"
 )
 
 const (
@@ -51,6 +52,11 @@ type upstreamResolverBase struct {
 	deactivate     func(error)
 	reactivate     func()
 	statusRecorder *peer.Status
+	// hostFallback is set for the resolver forwarding to the original nameservers of the host
+	hostFallback bool
+	cache        *responseCache
+	// encryptedClients are the clients of the DNS-over-TLS and DNS-over-HTTPS upstreams by their address
+	encryptedClients map[string]upstreamClient
 }
 
 func newUpstreamResolverBase(ctx context.Context, statusRecorder *peer.Status) *upstreamResolverBase {
@@ -75,9 +81,55 @@ func (u *upstreamResolverBase) MatchSubdomains() bool {
 	return true
 }
 
+func (u *upstreamResolverBase) queryHandlerKind() string {
+	if u.hostFallback {
+		return QueryHandlerHost
+	}
+	return QueryHandlerNameserverGroup
+}
+
 func (u *upstreamResolverBase) stop() {
 	log.Debugf("stopping serving DNS for upstreams %s", u.upstreamServers)
 	u.cancel()
+	u.cache.purge()
+	for _, client := range u.encryptedClients {
+		if c, ok := client.(interface{ close() }); ok {
+			c.close()
+		}
+	}
+}
+
+// addEncryptedUpstream adds a DNS-over-TLS or DNS-over-HTTPS nameserver to the upstreams
+func (u *upstreamResolverBase) addEncryptedUpstream(ns nbdns.NameServer) error {
+	dialer := defaultDialer
+	if d, ok := u.upstreamClient.(upstreamDialer); ok {
+		dialer = d.dialer
+	}
+
+	client, err := newEncryptedUpstreamClient(ns, dialer)
+	if err != nil {
+		return err
+	}
+
+	addr := getNSAddress(ns)
+	if u.encryptedClients == nil {
+		u.encryptedClients = make(map[string]upstreamClient)
+	}
+	u.encryptedClients[addr] = client
+	u.upstreamServers = append(u.upstreamServers, addr)
+	return nil
+}
+
+// clientFor returns the client to exchange the queries with the upstream
+func (u *upstreamResolverBase) clientFor(upstream string) upstreamClient {
+	if client, ok := u.encryptedClients[upstream]; ok {
+		return client
+	}
+	return u.upstreamClient
+}
+
+func (u *upstreamResolverBase) purgeCache() {
+	u.cache.purge()
 }
 
 // ServeDNS handles a DNS request
@@ -100,6 +152,16 @@ func (u *upstreamResolverBase) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 	default:
 	}
 
+	if cached := u.cache.get(r); cached != nil {
+		if recorder, ok := w.(upstreamRecorder); ok {
+			recorder.setCached()
+		}
+		if err := w.WriteMsg(cached); err != nil {
+			log.WithError(err).Error("got an error while writing the cached upstream response")
+		}
+		return
+	}
+
 	for _, upstream := range u.upstreamServers {
 		var rm *dns.Msg
 		var t time.Duration
@@ -107,7 +169,7 @@ func (u *upstreamResolverBase) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 		func() {
 			ctx, cancel := context.WithTimeout(u.ctx, u.upstreamTimeout)
 			defer cancel()
-			rm, t, err = u.upstreamClient.exchange(ctx, upstream, r)
+			rm, t, err = u.clientFor(upstream).exchange(ctx, upstream, r)
 		}()
 
 		if err != nil {
@@ -137,6 +199,11 @@ func (u *upstreamResolverBase) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 		u.successCount.Add(1)
 		log.Tracef("took %s to query the upstream %s", t, upstream)
 
+		u.cache.set(r, rm)
+
+		if recorder, ok := w.(upstreamRecorder); ok {
+			recorder.setUpstream(upstream)
+		}
 		err = w.WriteMsg(rm)
 		if err != nil {
 			log.WithError(err).Error("got an error while writing the upstream resolver response")
@@ -294,6 +361,6 @@ func (u *upstreamResolverBase) testNameserver(server string, timeout time.Durati
 
 	r := new(dns.Msg).SetQuestion(testRecord, dns.TypeSOA)
 
-	_, _, err := u.upstreamClient.exchange(ctx, server, r)
+	_, _, err := u.clientFor(server).exchange(ctx, server, r)
 	return err
 }
@@ -58,22 +58,33 @@ func (u *upstreamResolver) exchangeWithoutVPN(ctx context.Context, upstream stri
 	if deadline, ok := ctx.Deadline(); ok {
 		timeout = time.Until(deadline)
 	}
-	dialTimeout := timeout
 
+	upstreamExchangeClient := &dns.Client{
+		Dialer: protectedDialer(timeout),
+	}
+
+	return upstreamExchangeClient.Exchange(r, upstream)
+}
+
+// dialer returns the dialer of the encrypted upstreams, the local resolvers are reached outside the VPN like the
+// plain ones
+func (u *upstreamResolver) dialer(upstream string) (*net.Dialer, error) {
+	if u.isLocalResolver(upstream) {
+		return protectedDialer(0), nil
+	}
+	return &net.Dialer{}, nil
+}
+
+// protectedDialer returns a dialer with the sockets protected by the Android SDK
+func protectedDialer(timeout time.Duration) *net.Dialer {
 	nbDialer := nbnet.NewDialer()
 
-	dialer := &net.Dialer{
+	return &net.Dialer{
 		Control: func(network, address string, c syscall.RawConn) error {
 			return nbDialer.Control(network, address, c)
 		},
-		Timeout: dialTimeout,
+		Timeout: timeout,
 	}
-
-	upstreamExchangeClient := &dns.Client{
-		Dialer: dialer,
-	}
-
-	return upstreamExchangeClient.Exchange(r, upstream)
 }
 
 func (u *upstreamResolver) isLocalResolver(upstream string) bool {
@@ -0,0 +1,175 @@
+package dns
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"time"
+
+	"github.com/miekg/dns"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+)
+
+const dnsMessageContentType = "application/dns-message"
+
+// dialerFunc returns the dialer for the connections to the nameserver address, it lets the platforms route the
+// encrypted upstreams the same way as the plain ones
+type dialerFunc func(upstream string) (*net.Dialer, error)
+
+// upstreamDialer is implemented by the platform upstream clients that need a custom dialer for the nameservers
+type upstreamDialer interface {
+	dialer(upstream string) (*net.Dialer, error)
+}
+
+func defaultDialer(string) (*net.Dialer, error) {
+	return &net.Dialer{}, nil
+}
+
+// tlsUpstreamClient exchanges the queries with a DNS-over-TLS nameserver as described in RFC 7858
+type tlsUpstreamClient struct {
+	addr      string
+	tlsConfig *tls.Config
+	dialer    dialerFunc
+}
+
+func newTLSUpstreamClient(ns nbdns.NameServer, dialer dialerFunc) *tlsUpstreamClient {
+	return &tlsUpstreamClient{
+		addr:      netip.AddrPortFrom(ns.IP, uint16(ns.Port)).String(),
+		tlsConfig: &tls.Config{ServerName: tlsServerName(ns), MinVersion: tls.VersionTLS12},
+		dialer:    dialer,
+	}
+}
+
+func (c *tlsUpstreamClient) exchange(ctx context.Context, _ string, r *dns.Msg) (*dns.Msg, time.Duration, error) {
+	dialer, err := c.dialer(c.addr)
+	if err != nil {
+		return nil, 0, fmt.Errorf("create dialer: %w", err)
+	}
+
+	client := &dns.Client{
+		Net:       "tcp-tls",
+		TLSConfig: c.tlsConfig,
+		Dialer:    dialer,
+	}
+	return client.ExchangeContext(ctx, r, c.addr)
+}
+
+// httpsUpstreamClient exchanges the queries with a DNS-over-HTTPS nameserver as described in RFC 8484.
+// The connections are always made to the IP of the nameserver, the server name is only used for the certificate
+// verification and the HTTP host.
+type httpsUpstreamClient struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPSUpstreamClient(ns nbdns.NameServer, dialer dialerFunc) *httpsUpstreamClient {
+	addr := netip.AddrPortFrom(ns.IP, uint16(ns.Port)).String()
+
+	host := addr
+	if ns.ServerName != "" {
+		host = net.JoinHostPort(ns.ServerName, fmt.Sprint(ns.Port))
+	}
+	path := ns.Path
+	if path == "" {
+		path = nbdns.DefaultHTTPSPath
+	}
+
+	return &httpsUpstreamClient{
+		url: (&url.URL{Scheme: "https", Host: host, Path: path}).String(),
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+					d, err := dialer(addr)
+					if err != nil {
+						return nil, fmt.Errorf("create dialer: %w", err)
+					}
+					return d.DialContext(ctx, network, addr)
+				},
+				TLSClientConfig:   &tls.Config{ServerName: tlsServerName(ns), MinVersion: tls.VersionTLS12},
+				ForceAttemptHTTP2: true,
+				IdleConnTimeout:   reactivatePeriod,
+			},
+		},
+	}
+}
+
+func (c *httpsUpstreamClient) exchange(ctx context.Context, _ string, r *dns.Msg) (*dns.Msg, time.Duration, error) {
+	start := time.Now()
+
+	// the message ID should be zero for a better cache friendliness of the HTTP responses
+	query := r.Copy()
+	query.Id = 0
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, 0, fmt.Errorf("pack query: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(packed))
+	if err != nil {
+		return nil, 0, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", dnsMessageContentType)
+	req.Header.Set("Accept", dnsMessageContentType)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("unexpected response status %s from %s", resp.Status, c.url)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, dns.MaxMsgSize))
+	if err != nil {
+		return nil, 0, fmt.Errorf("read response: %w", err)
+	}
+
+	rm := new(dns.Msg)
+	if err := rm.Unpack(body); err != nil {
+		return nil, 0, fmt.Errorf("unpack response: %w", err)
+	}
+	rm.Id = r.Id
+
+	return rm, time.Since(start), nil
+}
+
+func (c *httpsUpstreamClient) close() {
+	c.client.CloseIdleConnections()
+}
+
+func tlsServerName(ns nbdns.NameServer) string {
+	if ns.ServerName != "" {
+		return ns.ServerName
+	}
+	return ns.IP.String()
+}
+
+// newEncryptedUpstreamClient returns the client of a DNS-over-TLS or DNS-over-HTTPS nameserver
+func newEncryptedUpstreamClient(ns nbdns.NameServer, dialer dialerFunc) (upstreamClient, error) {
+	switch ns.NSType {
+	case nbdns.TLSNameServerType:
+		return newTLSUpstreamClient(ns, dialer), nil
+	case nbdns.HTTPSNameServerType:
+		return newHTTPSUpstreamClient(ns, dialer), nil
+	default:
+		return nil, fmt.Errorf("nameserver type %s is not encrypted", ns.NSType)
+	}
+}
+
+// getNSAddress returns the address the nameserver is identified by in the upstream list and the status,
+// the encrypted nameservers are prefixed with their type
+func getNSAddress(ns nbdns.NameServer) string {
+	if !ns.IsEncrypted() {
+		return getNSHostPort(ns)
+	}
+	return fmt.Sprintf("%s://%s", ns.NSType, netip.AddrPortFrom(ns.IP, uint16(ns.Port)))
+}
@@ -0,0 +1,150 @@
+package dns
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+)
+
+func testAnswer(w dns.ResponseWriter, r *dns.Msg) {
+	resp := new(dns.Msg)
+	resp.SetReply(r)
+	rr, _ := dns.NewRR(r.Question[0].Name + " 60 IN A 1.2.3.4")
+	resp.Answer = append(resp.Answer, rr)
+	_ = w.WriteMsg(resp)
+}
+
+func testNameServer(t *testing.T, nsType nbdns.NameServerType, addr string) nbdns.NameServer {
+	t.Helper()
+	addrPort, err := netip.ParseAddrPort(addr)
+	require.NoError(t, err)
+	return nbdns.NameServer{IP: addrPort.Addr(), Port: int(addrPort.Port()), NSType: nsType}
+}
+
+func certPool(srv *httptest.Server) *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	return pool
+}
+
+func TestTLSUpstreamClient(t *testing.T) {
+	// the test server is only used for its certificate valid for 127.0.0.1 and example.com
+	certSrv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer certSrv.Close()
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: certSrv.TLS.Certificates})
+	require.NoError(t, err)
+
+	server := &dns.Server{Listener: listener, Handler: dns.HandlerFunc(testAnswer)}
+	go func() {
+		_ = server.ActivateAndServe()
+	}()
+	defer func() {
+		_ = server.Shutdown()
+	}()
+
+	ns := testNameServer(t, nbdns.TLSNameServerType, listener.Addr().String())
+	ns.ServerName = "example.com"
+	var dialed []string
+	client := newTLSUpstreamClient(ns, func(upstream string) (*net.Dialer, error) {
+		dialed = append(dialed, upstream)
+		return &net.Dialer{}, nil
+	})
+	client.tlsConfig.RootCAs = certPool(certSrv)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	r := new(dns.Msg).SetQuestion("netbird.io.", dns.TypeA)
+	resp, _, err := client.exchange(ctx, getNSAddress(ns), r)
+	require.NoError(t, err)
+	require.Len(t, resp.Answer, 1)
+	assert.Equal(t, "1.2.3.4", resp.Answer[0].(*dns.A).A.String())
+	assert.Equal(t, []string{listener.Addr().String()}, dialed, "the connection should use the platform dialer")
+
+	ns.ServerName = "netbird.io"
+	client = newTLSUpstreamClient(ns, defaultDialer)
+	client.tlsConfig.RootCAs = certPool(certSrv)
+	_, _, err = client.exchange(ctx, getNSAddress(ns), r)
+	assert.Error(t, err, "certificate not valid for the server name should be rejected")
+}
+
+func TestHTTPSUpstreamClient(t *testing.T) {
+	var path, contentType string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		path = req.URL.Path
+		contentType = req.Header.Get("Content-Type")
+
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r := new(dns.Msg)
+		if err := r.Unpack(body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		resp := new(dns.Msg)
+		resp.SetReply(r)
+		rr, _ := dns.NewRR(r.Question[0].Name + " 60 IN A 1.2.3.4")
+		resp.Answer = append(resp.Answer, rr)
+		packed, _ := resp.Pack()
+		w.Header().Set("Content-Type", dnsMessageContentType)
+		_, _ = w.Write(packed)
+	}))
+	defer srv.Close()
+
+	ns := testNameServer(t, nbdns.HTTPSNameServerType, srv.Listener.Addr().String())
+	client := newHTTPSUpstreamClient(ns, defaultDialer)
+	client.client.Transport.(*http.Transport).TLSClientConfig.RootCAs = certPool(srv)
+	defer client.close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	r := new(dns.Msg).SetQuestion("netbird.io.", dns.TypeA)
+	resp, _, err := client.exchange(ctx, getNSAddress(ns), r)
+	require.NoError(t, err)
+	assert.Equal(t, r.Id, resp.Id, "response should match the query id")
+	require.Len(t, resp.Answer, 1)
+	assert.Equal(t, "1.2.3.4", resp.Answer[0].(*dns.A).A.String())
+	assert.Equal(t, nbdns.DefaultHTTPSPath, path)
+	assert.Equal(t, dnsMessageContentType, contentType)
+}
+
+func TestUpstreamResolver_EncryptedUpstreams(t *testing.T) {
+	resolver := newUpstreamResolverBase(context.Background(), nil)
+	resolver.upstreamClient = &mockUpstreamResolver{}
+
+	err := resolver.addEncryptedUpstream(nbdns.NameServer{
+		IP:     netip.MustParseAddr("1.1.1.1"),
+		NSType: nbdns.TLSNameServerType,
+		Port:   853,
+	})
+	require.NoError(t, err)
+	err = resolver.addEncryptedUpstream(nbdns.NameServer{
+		IP:     netip.MustParseAddr("1.1.1.1"),
+		NSType: nbdns.UDPNameServerType,
+		Port:   53,
+	})
+	require.Error(t, err, "plain nameservers should not be added as encrypted upstreams")
+
+	assert.Equal(t, []string{"tls://1.1.1.1:853"}, resolver.upstreamServers)
+	assert.IsType(t, &tlsUpstreamClient{}, resolver.clientFor("tls://1.1.1.1:853"))
+	assert.IsType(t, &mockUpstreamResolver{}, resolver.clientFor("1.1.1.1:53"))
+}
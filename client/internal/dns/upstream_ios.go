@@ -57,8 +57,7 @@ func (u *upstreamResolverIOS) exchange(ctx context.Context, upstream string, r *
 	}
 	client.DialTimeout = timeout
 
-	upstreamIP := net.ParseIP(upstreamHost)
-	if u.lNet.Contains(upstreamIP) || net.IP.IsPrivate(upstreamIP) {
+	if u.isPrivate(upstreamHost) {
 		log.Debugf("using private client to query upstream: %s", upstream)
 		client, err = GetClientPrivate(u.lIP, u.interfaceName, timeout)
 		if err != nil {
@@ -70,9 +69,44 @@ func (u *upstreamResolverIOS) exchange(ctx context.Context, upstream string, r *
 	return client.Exchange(r, upstream)
 }
 
+// isPrivate returns true if the upstream host is reached through the Netbird interface
+func (u *upstreamResolverIOS) isPrivate(upstreamHost string) bool {
+	upstreamIP := net.ParseIP(upstreamHost)
+	return u.lNet.Contains(upstreamIP) || net.IP.IsPrivate(upstreamIP)
+}
+
+// dialer returns the dialer of the encrypted upstreams, the private upstreams are reached through the Netbird
+// interface like the plain ones
+func (u *upstreamResolverIOS) dialer(upstream string) (*net.Dialer, error) {
+	upstreamHost, _, err := net.SplitHostPort(upstream)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing upstream host: %s", err)
+	}
+	if !u.isPrivate(upstreamHost) {
+		return &net.Dialer{}, nil
+	}
+
+	log.Debugf("using private dialer to connect to upstream: %s", upstream)
+	return newPrivateDialer(&net.TCPAddr{IP: u.lIP}, u.interfaceName, 0)
+}
+
 // GetClientPrivate returns a new DNS client bound to the local IP address of the Netbird interface
 // This method is needed for iOS
 func GetClientPrivate(ip net.IP, interfaceName string, dialTimeout time.Duration) (*dns.Client, error) {
+	// Let the OS pick a free port
+	dialer, err := newPrivateDialer(&net.UDPAddr{IP: ip}, interfaceName, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &dns.Client{
+		Dialer: dialer,
+	}
+	return client, nil
+}
+
+// newPrivateDialer returns a dialer bound to the local address and the interface
+func newPrivateDialer(localAddr net.Addr, interfaceName string, dialTimeout time.Duration) (*net.Dialer, error) {
 	index, err := getInterfaceIndex(interfaceName)
 	if err != nil {
 		log.Debugf("unable to get interface index for %s: %s", interfaceName, err)
@@ -80,11 +114,8 @@ func GetClientPrivate(ip net.IP, interfaceName string, dialTimeout time.Duration
 	}
 
 	dialer := &net.Dialer{
-		LocalAddr: &net.UDPAddr{
-			IP:   ip,
-			Port: 0, // Let the OS pick a free port
-		},
-		Timeout: dialTimeout,
+		LocalAddr: localAddr,
+		Timeout:   dialTimeout,
 		Control: func(network, address string, c syscall.RawConn) error {
 			var operr error
 			fn := func(s uintptr) {
@@ -102,10 +133,7 @@ func GetClientPrivate(ip net.IP, interfaceName string, dialTimeout time.Duration
 			return operr
 		},
 	}
-	client := &dns.Client{
-		Dialer: dialer,
-	}
-	return client, nil
+	return dialer, nil
 }
 
 func getInterfaceIndex(interfaceName string) (int, error) {
@@ -4,22 +4,40 @@ import (
 	"context"
 	"errors"
 	"net"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/miekg/dns"
 	log "github.com/sirupsen/logrus"
+)
+
+const (
+	errResolveFailed = "failed to resolve query for domain=%s: %v"
 
-	nbdns "github.com/netbirdio/netbird/dns"
+	// upstreamTimeout is the time a query is resolved by the system resolver for at most
+	upstreamTimeout = 15 * time.Second
+	// maxUDPSize is the EDNS0 buffer size advertised by the forwarder
+	maxUDPSize = 4096
 )
 
-const errResolveFailed = "failed to resolve query for domain=%s: %v"
+// resolver resolves the queries of the forwarded domains, it is the system resolver by default
+type resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+	LookupCNAME(ctx context.Context, host string) (string, error)
+}
 
 type DNSForwarder struct {
 	listenAddress string
 	ttl           uint32
-	domains       []string
+	resolver      resolver
+
+	mutex   sync.RWMutex
+	domains []string
 
 	dnsServer *dns.Server
-	mux       *dns.ServeMux
+	tcpServer *dns.Server
 }
 
 func NewDNSForwarder(listenAddress string, ttl uint32) *DNSForwarder {
@@ -27,45 +45,81 @@ func NewDNSForwarder(listenAddress string, ttl uint32) *DNSForwarder {
 	return &DNSForwarder{
 		listenAddress: listenAddress,
 		ttl:           ttl,
+		resolver:      net.DefaultResolver,
 	}
 }
 
+// Listen serves the queries on UDP and TCP until the forwarder is closed or one of the listeners fails
 func (f *DNSForwarder) Listen(domains []string) error {
 	log.Infof("listen DNS forwarder on address=%s", f.listenAddress)
-	mux := dns.NewServeMux()
+	handler := dns.HandlerFunc(f.handleDNSQuery)
 
-	dnsServer := &dns.Server{
+	f.dnsServer = &dns.Server{
 		Addr:    f.listenAddress,
 		Net:     "udp",
-		Handler: mux,
+		Handler: handler,
+	}
+	f.tcpServer = &dns.Server{
+		Addr:    f.listenAddress,
+		Net:     "tcp",
+		Handler: handler,
 	}
-	f.dnsServer = dnsServer
-	f.mux = mux
 
 	f.UpdateDomains(domains)
 
-	return dnsServer.ListenAndServe()
+	errCh := make(chan error, 2)
+	go func() {
+		errCh <- f.dnsServer.ListenAndServe()
+	}()
+	go func() {
+		errCh <- f.tcpServer.ListenAndServe()
+	}()
+
+	return <-errCh
 }
 
+// UpdateDomains replaces the forwarded domains. A domain with the *. prefix matches all of its subdomains,
+// other domains match only themselves.
 func (f *DNSForwarder) UpdateDomains(domains []string) {
-	log.Debugf("Updating domains from %v to %v", f.domains, domains)
-
-	for _, d := range f.domains {
-		f.mux.HandleRemove(d)
-	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
 
 	newDomains := filterDomains(domains)
-	for _, d := range newDomains {
-		f.mux.HandleFunc(d, f.handleDNSQuery)
-	}
+	log.Debugf("Updating domains from %v to %v", f.domains, newDomains)
 	f.domains = newDomains
 }
 
 func (f *DNSForwarder) Close(ctx context.Context) error {
-	if f.dnsServer == nil {
-		return nil
+	var errs []error
+	for _, server := range []*dns.Server{f.dnsServer, f.tcpServer} {
+		if server == nil {
+			continue
+		}
+		if err := server.ShutdownContext(ctx); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	return f.dnsServer.ShutdownContext(ctx)
+	return errors.Join(errs...)
+}
+
+func (f *DNSForwarder) matchDomain(name string) bool {
+	name = strings.ToLower(dns.Fqdn(name))
+
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	for _, d := range f.domains {
+		if wildcard, ok := strings.CutPrefix(d, "*"); ok {
+			if strings.HasSuffix(name, wildcard) {
+				return true
+			}
+			continue
+		}
+		if name == d {
+			return true
+		}
+	}
+	return false
 }
 
 func (f *DNSForwarder) handleDNSQuery(w dns.ResponseWriter, query *dns.Msg) {
@@ -78,66 +132,150 @@ func (f *DNSForwarder) handleDNSQuery(w dns.ResponseWriter, query *dns.Msg) {
 	question := query.Question[0]
 	domain := question.Name
 
-	resp := query.SetReply(query)
+	resp := new(dns.Msg)
+	resp.SetReply(query)
 
-	ips, err := net.LookupIP(domain)
-	if err != nil {
-		var dnsErr *net.DNSError
+	if !f.matchDomain(domain) {
+		log.Tracef("refusing DNS request for not forwarded domain=%s", domain)
+		resp.Rcode = dns.RcodeRefused
+		f.writeResponse(w, query, resp)
+		return
+	}
 
-		switch {
-		case errors.As(err, &dnsErr):
-			resp.Rcode = dns.RcodeServerFailure
-			if dnsErr.IsNotFound {
-				// Pass through NXDOMAIN
-				resp.Rcode = dns.RcodeNameError
-			}
+	ctx, cancel := context.WithTimeout(context.Background(), upstreamTimeout)
+	defer cancel()
+
+	switch question.Qtype {
+	case dns.TypeA, dns.TypeAAAA:
+		resp.Answer, resp.Rcode = f.resolveAddresses(ctx, domain, question.Qtype)
+	case dns.TypeCNAME:
+		resp.Answer, resp.Rcode = f.resolveCNAME(ctx, domain)
+	default:
+		// the forwarder only serves the address records, other types are answered without records
+		log.Tracef("no records of type=%v for domain=%s", question.Qtype, domain)
+	}
+
+	f.writeResponse(w, query, resp)
+}
 
-			if dnsErr.Server != "" {
-				log.Warnf("failed to resolve query for domain=%s server=%s: %v", domain, dnsErr.Server, err)
-			} else {
-				log.Warnf(errResolveFailed, domain, err)
+// resolveAddresses resolves the A or AAAA records of the domain. If the domain is an alias, the answer starts with
+// the CNAME record pointing to the canonical name the addresses belong to.
+func (f *DNSForwarder) resolveAddresses(ctx context.Context, domain string, qtype uint16) ([]dns.RR, int) {
+	network, otherNetwork := "ip4", "ip6"
+	if qtype == dns.TypeAAAA {
+		network, otherNetwork = "ip6", "ip4"
+	}
+
+	addrs, err := f.resolver.LookupNetIP(ctx, network, domain)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			// the resolver doesn't tell apart a missing domain from a domain without records of the family
+			if _, otherErr := f.resolver.LookupNetIP(ctx, otherNetwork, domain); otherErr == nil {
+				return nil, dns.RcodeSuccess
 			}
-		default:
-			resp.Rcode = dns.RcodeServerFailure
-			log.Warnf(errResolveFailed, domain, err)
 		}
+		return nil, f.errorRcode(domain, err)
+	}
 
-		if err := w.WriteMsg(resp); err != nil {
-			log.Errorf("failed to write failure DNS response: %v", err)
+	var answer []dns.RR
+	name := domain
+	if cname := f.lookupCNAME(ctx, domain); cname != "" {
+		answer = append(answer, f.cnameRecord(domain, cname))
+		name = cname
+	}
+
+	for _, addr := range addrs {
+		addr = addr.Unmap()
+		switch {
+		case qtype == dns.TypeA && addr.Is4():
+			log.Tracef("resolved domain=%s to IPv4=%s", domain, addr)
+			answer = append(answer, &dns.A{
+				Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: f.ttl},
+				A:   addr.AsSlice(),
+			})
+		case qtype == dns.TypeAAAA && addr.Is6():
+			log.Tracef("resolved domain=%s to IPv6=%s", domain, addr)
+			answer = append(answer, &dns.AAAA{
+				Hdr:  dns.RR_Header{Name: name, Rrtype: dns.TypeAAAA, Class: dns.ClassINET, Ttl: f.ttl},
+				AAAA: addr.AsSlice(),
+			})
 		}
-		return
 	}
 
-	for _, ip := range ips {
-		var respRecord dns.RR
-		if ip.To4() == nil {
-			log.Tracef("resolved domain=%s to IPv6=%s", domain, ip)
-			rr := dns.AAAA{
-				AAAA: ip,
-				Hdr: dns.RR_Header{
-					Name:   domain,
-					Rrtype: dns.TypeAAAA,
-					Class:  dns.ClassINET,
-					Ttl:    f.ttl,
-				},
-			}
-			respRecord = &rr
-		} else {
-			log.Tracef("resolved domain=%s to IPv4=%s", domain, ip)
-			rr := dns.A{
-				A: ip,
-				Hdr: dns.RR_Header{
-					Name:   domain,
-					Rrtype: dns.TypeA,
-					Class:  dns.ClassINET,
-					Ttl:    f.ttl,
-				},
-			}
-			respRecord = &rr
+	return answer, dns.RcodeSuccess
+}
+
+func (f *DNSForwarder) resolveCNAME(ctx context.Context, domain string) ([]dns.RR, int) {
+	cname, err := f.resolver.LookupCNAME(ctx, domain)
+	if err != nil {
+		return nil, f.errorRcode(domain, err)
+	}
+	if strings.EqualFold(dns.Fqdn(cname), dns.Fqdn(domain)) {
+		return nil, dns.RcodeSuccess
+	}
+	return []dns.RR{f.cnameRecord(domain, cname)}, dns.RcodeSuccess
+}
+
+// lookupCNAME returns the canonical name of the domain, empty if the domain is not an alias or the lookup failed
+func (f *DNSForwarder) lookupCNAME(ctx context.Context, domain string) string {
+	cname, err := f.resolver.LookupCNAME(ctx, domain)
+	if err != nil {
+		log.Tracef("failed to resolve CNAME for domain=%s: %v", domain, err)
+		return ""
+	}
+	cname = dns.Fqdn(cname)
+	if strings.EqualFold(cname, dns.Fqdn(domain)) {
+		return ""
+	}
+	return cname
+}
+
+func (f *DNSForwarder) cnameRecord(domain, cname string) dns.RR {
+	return &dns.CNAME{
+		Hdr:    dns.RR_Header{Name: domain, Rrtype: dns.TypeCNAME, Class: dns.ClassINET, Ttl: f.ttl},
+		Target: dns.Fqdn(cname),
+	}
+}
+
+func (f *DNSForwarder) errorRcode(domain string, err error) int {
+	var dnsErr *net.DNSError
+	if !errors.As(err, &dnsErr) {
+		log.Warnf(errResolveFailed, domain, err)
+		return dns.RcodeServerFailure
+	}
+
+	if dnsErr.Server != "" {
+		log.Warnf("failed to resolve query for domain=%s server=%s: %v", domain, dnsErr.Server, err)
+	} else {
+		log.Warnf(errResolveFailed, domain, err)
+	}
+
+	if dnsErr.IsNotFound {
+		// Pass through NXDOMAIN
+		return dns.RcodeNameError
+	}
+	return dns.RcodeServerFailure
+}
+
+// writeResponse writes the response fitting the size the client can receive. UDP responses are limited to the
+// EDNS0 buffer size of the query or 512 bytes without EDNS0, larger responses are truncated to make the client
+// retry over TCP.
+func (f *DNSForwarder) writeResponse(w dns.ResponseWriter, query, resp *dns.Msg) {
+	size := dns.MaxMsgSize
+	_, isTCP := w.RemoteAddr().(*net.TCPAddr)
+
+	if opt := query.IsEdns0(); opt != nil {
+		resp.SetEdns0(maxUDPSize, opt.Do())
+		if !isTCP {
+			size = max(int(opt.UDPSize()), dns.MinMsgSize)
 		}
-		resp.Answer = append(resp.Answer, respRecord)
+	} else if !isTCP {
+		size = dns.MinMsgSize
 	}
 
+	resp.Truncate(size)
+
 	if err := w.WriteMsg(resp); err != nil {
 		log.Errorf("failed to write DNS response: %v", err)
 	}
@@ -151,7 +289,7 @@ func filterDomains(domains []string) []string {
 			log.Warn("empty domain in DNS forwarder")
 			continue
 		}
-		newDomains = append(newDomains, nbdns.NormalizeZone(d))
+		newDomains = append(newDomains, strings.ToLower(dns.Fqdn(d)))
 	}
 	return newDomains
 }
@@ -0,0 +1,256 @@
+package dnsfwd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockResolver struct {
+	addrs  map[string][]netip.Addr
+	cnames map[string]string
+}
+
+func (m *mockResolver) LookupNetIP(_ context.Context, network, host string) ([]netip.Addr, error) {
+	if cname, ok := m.cnames[host]; ok {
+		host = cname
+	}
+	var addrs []netip.Addr
+	for _, addr := range m.addrs[host] {
+		if network == "ip4" && addr.Is4() || network == "ip6" && addr.Is6() {
+			addrs = append(addrs, addr)
+		}
+	}
+	if len(addrs) == 0 {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addrs, nil
+}
+
+func (m *mockResolver) LookupCNAME(_ context.Context, host string) (string, error) {
+	if cname, ok := m.cnames[host]; ok {
+		return cname, nil
+	}
+	if _, ok := m.addrs[host]; ok {
+		return host, nil
+	}
+	return "", &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+type mockResponseWriter struct {
+	remoteAddr net.Addr
+	msg        *dns.Msg
+}
+
+func (w *mockResponseWriter) LocalAddr() net.Addr       { return nil }
+func (w *mockResponseWriter) RemoteAddr() net.Addr      { return w.remoteAddr }
+func (w *mockResponseWriter) WriteMsg(m *dns.Msg) error { w.msg = m; return nil }
+func (w *mockResponseWriter) Write([]byte) (int, error) { return 0, nil }
+func (w *mockResponseWriter) Close() error              { return nil }
+func (w *mockResponseWriter) TsigStatus() error         { return nil }
+func (w *mockResponseWriter) TsigTimersOnly(bool)       {}
+func (w *mockResponseWriter) Hijack()                   {}
+
+func newTestForwarder(domains []string) *DNSForwarder {
+	f := NewDNSForwarder("127.0.0.1:0", 60)
+	f.resolver = &mockResolver{
+		addrs: map[string][]netip.Addr{
+			"example.com.":     {netip.MustParseAddr("1.1.1.1"), netip.MustParseAddr("2001:db8::1")},
+			"app.example.com.": {netip.MustParseAddr("1.1.1.2")},
+			"cdn.provider.net.": {
+				netip.MustParseAddr("2.2.2.2"),
+				netip.MustParseAddr("2.2.2.3"),
+			},
+			"v4only.example.com.": {netip.MustParseAddr("1.1.1.3")},
+		},
+		cnames: map[string]string{
+			"www.example.com.": "cdn.provider.net.",
+		},
+	}
+	f.UpdateDomains(domains)
+	return f
+}
+
+func query(t *testing.T, f *DNSForwarder, remoteAddr net.Addr, r *dns.Msg) *dns.Msg {
+	t.Helper()
+	w := &mockResponseWriter{remoteAddr: remoteAddr}
+	f.handleDNSQuery(w, r)
+	require.NotNil(t, w.msg, "forwarder should always write a response")
+	return w.msg
+}
+
+func TestDNSForwarder_MatchDomain(t *testing.T) {
+	f := newTestForwarder([]string{"Example.com", "*.internal.io", ""})
+
+	tests := []struct {
+		name     string
+		expected bool
+	}{
+		{name: "example.com.", expected: true},
+		{name: "EXAMPLE.com", expected: true},
+		{name: "app.example.com.", expected: false},
+		{name: "db.internal.io.", expected: true},
+		{name: "a.b.internal.io.", expected: true},
+		{name: "internal.io.", expected: false},
+		{name: "notinternal.io.", expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, f.matchDomain(tc.name))
+		})
+	}
+}
+
+func TestDNSForwarder_HandleDNSQuery(t *testing.T) {
+	udpAddr := &net.UDPAddr{IP: net.IPv4(100, 64, 0, 1), Port: 40000}
+	f := newTestForwarder([]string{"*.example.com", "example.com"})
+
+	tests := []struct {
+		name          string
+		domain        string
+		qtype         uint16
+		expectedRcode int
+		expectedRRs   []string
+	}{
+		{
+			name:          "A record",
+			domain:        "example.com.",
+			qtype:         dns.TypeA,
+			expectedRcode: dns.RcodeSuccess,
+			expectedRRs:   []string{"example.com.\t60\tIN\tA\t1.1.1.1"},
+		},
+		{
+			name:          "AAAA record",
+			domain:        "example.com.",
+			qtype:         dns.TypeAAAA,
+			expectedRcode: dns.RcodeSuccess,
+			expectedRRs:   []string{"example.com.\t60\tIN\tAAAA\t2001:db8::1"},
+		},
+		{
+			name:          "A records through CNAME",
+			domain:        "www.example.com.",
+			qtype:         dns.TypeA,
+			expectedRcode: dns.RcodeSuccess,
+			expectedRRs: []string{
+				"www.example.com.\t60\tIN\tCNAME\tcdn.provider.net.",
+				"cdn.provider.net.\t60\tIN\tA\t2.2.2.2",
+				"cdn.provider.net.\t60\tIN\tA\t2.2.2.3",
+			},
+		},
+		{
+			name:          "CNAME record",
+			domain:        "www.example.com.",
+			qtype:         dns.TypeCNAME,
+			expectedRcode: dns.RcodeSuccess,
+			expectedRRs:   []string{"www.example.com.\t60\tIN\tCNAME\tcdn.provider.net."},
+		},
+		{
+			name:          "No records of the family",
+			domain:        "v4only.example.com.",
+			qtype:         dns.TypeAAAA,
+			expectedRcode: dns.RcodeSuccess,
+		},
+		{
+			name:          "Unsupported type",
+			domain:        "example.com.",
+			qtype:         dns.TypeMX,
+			expectedRcode: dns.RcodeSuccess,
+		},
+		{
+			name:          "Not existing domain",
+			domain:        "missing.example.com.",
+			qtype:         dns.TypeA,
+			expectedRcode: dns.RcodeNameError,
+		},
+		{
+			name:          "Not forwarded domain",
+			domain:        "netbird.io.",
+			qtype:         dns.TypeA,
+			expectedRcode: dns.RcodeRefused,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := query(t, f, udpAddr, new(dns.Msg).SetQuestion(tc.domain, tc.qtype))
+			assert.Equal(t, tc.expectedRcode, resp.Rcode)
+
+			var rrs []string
+			for _, rr := range resp.Answer {
+				rrs = append(rrs, rr.String())
+			}
+			assert.Equal(t, tc.expectedRRs, rrs)
+		})
+	}
+}
+
+func TestDNSForwarder_Truncation(t *testing.T) {
+	f := newTestForwarder([]string{"large.example.com"})
+	var addrs []netip.Addr
+	for i := 0; i < 100; i++ {
+		addrs = append(addrs, netip.MustParseAddr(fmt.Sprintf("10.0.0.%d", i)))
+	}
+	f.resolver.(*mockResolver).addrs["large.example.com."] = addrs
+
+	udpAddr := &net.UDPAddr{IP: net.IPv4(100, 64, 0, 1), Port: 40000}
+	tcpAddr := &net.TCPAddr{IP: net.IPv4(100, 64, 0, 1), Port: 40000}
+
+	resp := query(t, f, udpAddr, new(dns.Msg).SetQuestion("large.example.com.", dns.TypeA))
+	assert.True(t, resp.Truncated, "UDP response without EDNS0 should be truncated")
+	assert.LessOrEqual(t, resp.Len(), dns.MinMsgSize)
+	assert.Nil(t, resp.IsEdns0(), "response should not have EDNS0 if the query didn't")
+
+	r := new(dns.Msg).SetQuestion("large.example.com.", dns.TypeA)
+	r.SetEdns0(4096, false)
+	resp = query(t, f, udpAddr, r)
+	assert.False(t, resp.Truncated, "response fitting the EDNS0 buffer size should not be truncated")
+	assert.Len(t, resp.Answer, len(addrs))
+	assert.NotNil(t, resp.IsEdns0(), "response should have EDNS0 if the query had")
+
+	resp = query(t, f, tcpAddr, new(dns.Msg).SetQuestion("large.example.com.", dns.TypeA))
+	assert.False(t, resp.Truncated, "TCP response should not be truncated")
+	assert.Len(t, resp.Answer, len(addrs))
+}
+
+func TestDNSForwarder_ListenUDPAndTCP(t *testing.T) {
+	f := newTestForwarder([]string{"example.com"})
+
+	// reserve a port free for both UDP and TCP
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	f.listenAddress = tcpListener.Addr().String()
+	require.NoError(t, tcpListener.Close())
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- f.Listen([]string{"example.com"})
+	}()
+	defer func() {
+		assert.NoError(t, f.Close(context.Background()))
+	}()
+
+	for _, network := range []string{"udp", "tcp"} {
+		client := &dns.Client{Net: network}
+		var resp *dns.Msg
+		require.Eventually(t, func() bool {
+			resp, _, err = client.Exchange(new(dns.Msg).SetQuestion("example.com.", dns.TypeA), f.listenAddress)
+			return err == nil
+		}, 5*time.Second, 100*time.Millisecond, "forwarder should answer over %s", network)
+		require.Len(t, resp.Answer, 1)
+		assert.Equal(t, "1.1.1.1", resp.Answer[0].(*dns.A).A.String())
+	}
+
+	select {
+	case err := <-errCh:
+		t.Fatalf("forwarder stopped listening: %v", err)
+	default:
+	}
+}
@@ -88,12 +88,15 @@ func (h *Manager) allowDNSFirewall() error {
 		return nil
 	}
 
-	dnsRules, err := h.firewall.AddPeerFiltering(net.IP{0, 0, 0, 0}, firewall.ProtocolUDP, nil, dport, firewall.ActionAccept, "", "")
-	if err != nil {
-		log.Errorf("failed to add allow DNS router rules, err: %v", err)
-		return err
+	// the forwarder answers over TCP the queries truncated over UDP
+	for _, protocol := range []firewall.Protocol{firewall.ProtocolUDP, firewall.ProtocolTCP} {
+		dnsRules, err := h.firewall.AddPeerFiltering(net.IP{0, 0, 0, 0}, protocol, nil, dport, firewall.ActionAccept, "", "")
+		if err != nil {
+			log.Errorf("failed to add allow DNS router rules, err: %v", err)
+			return err
+		}
+		h.fwRules = append(h.fwRules, dnsRules...)
 	}
-	h.fwRules = dnsRules
 
 	return nil
 }
@@ -33,6 +33,8 @@ import (
 	"github.com/netbirdio/netbird/client/internal/acl"
 	"github.com/netbirdio/netbird/client/internal/dns"
 	"github.com/netbirdio/netbird/client/internal/dnsfwd"
+	"github.com/netbirdio/netbird/client/internal/netflow"
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
 	"github.com/netbirdio/netbird/client/internal/networkmonitor"
 	"github.com/netbirdio/netbird/client/internal/peer"
 	"github.com/netbirdio/netbird/client/internal/peer/guard"
@@ -42,6 +44,7 @@ import (
 	"github.com/netbirdio/netbird/client/internal/rosenpass"
 	"github.com/netbirdio/netbird/client/internal/routemanager"
 	"github.com/netbirdio/netbird/client/internal/routemanager/systemops"
+	"github.com/netbirdio/netbird/client/internal/sshaudit"
 	"github.com/netbirdio/netbird/client/internal/statemanager"
 	semaphoregroup "github.com/netbirdio/netbird/util/semaphore-group"
 
@@ -101,6 +104,10 @@ type EngineConfig struct {
 	// SSHKey is a private SSH key in a PEM format
 	SSHKey []byte
 
+	// SSHKnownHostsPath is the file the SSH host keys of the remote peers are cached in for host key verification,
+	// the keys are not cached if empty
+	SSHKnownHostsPath string
+
 	NATExternalIPs []string
 
 	CustomDNSAddress string
@@ -172,6 +179,9 @@ type Engine struct {
 	routeManager  routemanager.Manager
 	acl           acl.Manager
 	dnsForwardMgr *dnsfwd.Manager
+	flowManager   *netflow.Manager
+
+	sshSessionReporter *sshaudit.Reporter
 
 	dnsServer dns.Server
 
@@ -254,6 +264,9 @@ func NewEngineWithProbes(
 		probes:         probes,
 		checks:         checks,
 		connSemaphore:  semaphoregroup.NewSemaphoreGroup(connInitLimit),
+		flowManager:    netflow.NewManager(mgmClient),
+
+		sshSessionReporter: sshaudit.NewReporter(mgmClient, sshaudit.DefaultMaxEvents),
 	}
 	if runtime.GOOS == "ios" {
 		if !fileExists(mobileDep.StateFilePath) {
@@ -306,6 +319,9 @@ func (e *Engine) Stop() error {
 		e.srWatcher.Close()
 	}
 
+	e.flowManager.Close()
+	e.sshSessionReporter.Close()
+
 	e.statusRecorder.ReplaceOfflinePeers([]peer.State{})
 	e.statusRecorder.UpdateDNSStates([]peer.NSGroupState{})
 	e.statusRecorder.UpdateRelayStates([]relay.ProbeResult{})
@@ -465,7 +481,7 @@ func (e *Engine) createFirewall() error {
 	}
 
 	var err error
-	e.firewall, err = firewall.NewFirewall(e.wgInterface, e.stateManager)
+	e.firewall, err = firewall.NewFirewall(e.wgInterface, e.stateManager, e.flowManager.GetLogger())
 	if err != nil || e.firewall == nil {
 		log.Errorf("failed creating firewall manager: %s", err)
 		return nil
@@ -677,6 +693,8 @@ func (e *Engine) handleSync(update *mgmProto.SyncResponse) error {
 			e.relayManager.UpdateServerURLs(nil)
 		}
 
+		e.flowManager.Update(toFlowConfig(wCfg.GetFlow()))
+
 		// todo update signal
 	}
 
@@ -703,6 +721,16 @@ func (e *Engine) handleSync(update *mgmProto.SyncResponse) error {
 	return nil
 }
 
+func toFlowConfig(flow *mgmProto.FlowConfig) *nftypes.FlowConfig {
+	if flow == nil {
+		return nil
+	}
+	return &nftypes.FlowConfig{
+		Enabled:  flow.GetEnabled(),
+		Interval: flow.GetInterval().AsDuration(),
+	}
+}
+
 // updateChecksIfNew updates checks if there are changes and sync new meta with management
 func (e *Engine) updateChecksIfNew(checks []*mgmProto.Checks) error {
 	// if checks are equal, we skip the update
@@ -753,6 +781,7 @@ func (e *Engine) updateSSH(sshConf *mgmProto.SSHConfig) error {
 				if err != nil {
 					return fmt.Errorf("create ssh server: %w", err)
 				}
+				e.sshServer.SetSessionListener(e.sshSessionReporter.Report)
 				go func() {
 					// blocking
 					err = e.sshServer.Start()
@@ -768,6 +797,11 @@ func (e *Engine) updateSSH(sshConf *mgmProto.SSHConfig) error {
 			} else {
 				log.Debugf("SSH server is already running")
 			}
+			e.sshServer.UpdateFeatures(nbssh.Features{
+				PortForwarding: sshConf.GetPortForwardingEnabled(),
+				SFTP:           sshConf.GetSftpEnabled(),
+				Exec:           sshConf.GetExecEnabled(),
+			})
 		} else if !isNil(e.sshServer) {
 			// Disable SSH server request, so stop it if it was running
 			err := e.sshServer.Stop()
@@ -918,6 +952,10 @@ func (e *Engine) updateNetworkMap(networkMap *mgmProto.NetworkMap) error {
 		if err != nil {
 			return err
 		}
+
+		if err := e.updateSSHKnownHosts(nil); err != nil {
+			log.Warnf("failed to update SSH known hosts: %v", err)
+		}
 	} else {
 		err := e.removePeers(networkMap.GetRemotePeers())
 		if err != nil {
@@ -940,13 +978,17 @@ func (e *Engine) updateNetworkMap(networkMap *mgmProto.NetworkMap) error {
 		if !isNil(e.sshServer) {
 			for _, config := range networkMap.GetRemotePeers() {
 				if config.GetSshConfig() != nil && config.GetSshConfig().GetSshPubKey() != nil {
-					err := e.sshServer.AddAuthorizedKey(config.WgPubKey, string(config.GetSshConfig().GetSshPubKey()))
+					err := e.sshServer.AddAuthorizedKey(config.WgPubKey, string(config.GetSshConfig().GetSshPubKey()), config.GetSshConfig().GetLocalUsers())
 					if err != nil {
 						log.Warnf("failed adding authorized key to SSH DefaultServer %v", err)
 					}
 				}
 			}
 		}
+
+		if err := e.updateSSHKnownHosts(networkMap.GetRemotePeers()); err != nil {
+			log.Warnf("failed to update SSH known hosts: %v", err)
+		}
 	}
 
 	protoDNSConfig := networkMap.GetDNSConfig()
@@ -1055,9 +1097,11 @@ func toDNSConfig(protoDNSConfig *mgmProto.DNSConfig) nbdns.Config {
 		}
 		for _, ns := range nsGroup.GetNameServers() {
 			dnsNS := nbdns.NameServer{
-				IP:     netip.MustParseAddr(ns.GetIP()),
-				NSType: nbdns.NameServerType(ns.GetNSType()),
-				Port:   int(ns.GetPort()),
+				IP:         netip.MustParseAddr(ns.GetIP()),
+				NSType:     nbdns.NameServerType(ns.GetNSType()),
+				Port:       int(ns.GetPort()),
+				ServerName: ns.GetServerName(),
+				Path:       ns.GetPath(),
 			}
 			dnsNSGroup.NameServers = append(dnsNSGroup.NameServers, dnsNS)
 		}
@@ -1450,6 +1494,67 @@ func (e *Engine) GetRouteManager() routemanager.Manager {
 	return e.routeManager
 }
 
+// GetFlowLogger returns the logger recording the flow events of the packet filter
+func (e *Engine) GetFlowLogger() nftypes.FlowLogger {
+	return e.flowManager.GetLogger()
+}
+
+// updateSSHKnownHosts caches the SSH host keys published by management for the remote peers,
+// they are used by the SSH client to verify the remote peer
+func (e *Engine) updateSSHKnownHosts(remotePeers []*mgmProto.RemotePeerConfig) error {
+	if e.config.SSHKnownHostsPath == "" {
+		return nil
+	}
+
+	hostKeys := make([]nbssh.HostKey, 0, len(remotePeers))
+	for _, peerConfig := range remotePeers {
+		pubKey := peerConfig.GetSshConfig().GetSshPubKey()
+		if len(pubKey) == 0 {
+			continue
+		}
+
+		var hosts []string
+		for _, allowedIP := range peerConfig.GetAllowedIps() {
+			prefix, err := netip.ParsePrefix(allowedIP)
+			if err != nil || !prefix.IsSingleIP() {
+				continue
+			}
+			hosts = append(hosts, prefix.Addr().String())
+		}
+		if fqdn := strings.TrimSuffix(peerConfig.GetFqdn(), "."); fqdn != "" {
+			hosts = append(hosts, fqdn)
+		}
+
+		hostKeys = append(hostKeys, nbssh.HostKey{Hosts: hosts, Key: pubKey})
+	}
+
+	return nbssh.WriteKnownHosts(e.ctx, e.config.SSHKnownHostsPath, hostKeys)
+}
+
+// GetFirewallRuleStats returns the peer firewall rules with the number of packets they matched
+func (e *Engine) GetFirewallRuleStats() ([]acl.RuleStats, error) {
+	if e.acl == nil {
+		return nil, errors.New("firewall is not initialized")
+	}
+	return e.acl.GetRuleStats()
+}
+
+// GetDNSQueryLog returns the log of the DNS queries handled by the client resolver
+func (e *Engine) GetDNSQueryLog() (*dns.QueryLog, error) {
+	if e.dnsServer == nil || e.dnsServer.QueryLog() == nil {
+		return nil, errors.New("DNS server is not initialized")
+	}
+	return e.dnsServer.QueryLog(), nil
+}
+
+// GetDNSCacheStats returns the counters of the upstream response caches of the client resolver
+func (e *Engine) GetDNSCacheStats() (dns.CacheStats, error) {
+	if e.dnsServer == nil {
+		return dns.CacheStats{}, errors.New("DNS server is not initialized")
+	}
+	return e.dnsServer.CacheStats(), nil
+}
+
 func findIPFromInterfaceName(ifaceName string) (net.IP, error) {
 	iface, err := net.InterfaceByName(ifaceName)
 	if err != nil {
@@ -1712,18 +1817,35 @@ func (e *Engine) updateDNSForwarder(enabled bool, domains []string) {
 // isChecksEqual checks if two slices of checks are equal.
 func isChecksEqual(checks []*mgmProto.Checks, oChecks []*mgmProto.Checks) bool {
 	for _, check := range checks {
-		sort.Slice(check.Files, func(i, j int) bool {
-			return check.Files[i] < check.Files[j]
-		})
+		sort.Strings(check.Files)
+		sort.Strings(check.Services)
+		sortPorts(check.Ports)
 	}
 	for _, oCheck := range oChecks {
-		sort.Slice(oCheck.Files, func(i, j int) bool {
-			return oCheck.Files[i] < oCheck.Files[j]
-		})
+		sort.Strings(oCheck.Files)
+		sort.Strings(oCheck.Services)
+		sortPorts(oCheck.Ports)
 	}
 
 	return slices.EqualFunc(checks, oChecks, func(checks, oChecks *mgmProto.Checks) bool {
-		return slices.Equal(checks.Files, oChecks.Files)
+		return checks.HostSecurity == oChecks.HostSecurity &&
+			slices.Equal(checks.Files, oChecks.Files) &&
+			slices.Equal(checks.Services, oChecks.Services) &&
+			slices.EqualFunc(checks.Ports, oChecks.Ports, func(port, oPort *mgmProto.Port) bool {
+				return proto.Equal(port, oPort)
+			})
+	})
+}
+
+func sortPorts(ports []*mgmProto.Port) {
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Port != ports[j].Port {
+			return ports[i].Port < ports[j].Port
+		}
+		if ports[i].Protocol != ports[j].Protocol {
+			return ports[i].Protocol < ports[j].Protocol
+		}
+		return !ports[i].Listening && ports[j].Listening
 	})
 }
 
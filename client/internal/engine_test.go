@@ -6,6 +6,7 @@ import (
 	"net"
 	"net/netip"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"sync"
@@ -18,6 +19,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
+	gossh "golang.org/x/crypto/ssh"
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
@@ -122,7 +124,7 @@ func TestEngine_SSH(t *testing.T) {
 				<-ctx.Done()
 				return ctx.Err()
 			},
-			AddAuthorizedKeyFunc: func(peer, newKey string) error {
+			AddAuthorizedKeyFunc: func(peer, newKey string, _ []string) error {
 				sshKeysAdded = append(sshKeysAdded, newKey)
 				return nil
 			},
@@ -218,6 +220,43 @@ func TestEngine_SSH(t *testing.T) {
 
 }
 
+func TestEngine_UpdateSSHKnownHosts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ssh.KnownHostsFile)
+	engine := &Engine{
+		ctx:    context.Background(),
+		config: &EngineConfig{SSHKnownHostsPath: path},
+	}
+
+	privateKey, err := ssh.GeneratePrivateKey(ssh.ED25519)
+	require.NoError(t, err)
+	publicKey, err := ssh.GeneratePublicKey(privateKey)
+	require.NoError(t, err)
+	hostKey, _, _, _, err := gossh.ParseAuthorizedKey(publicKey)
+	require.NoError(t, err)
+
+	err = engine.updateSSHKnownHosts([]*mgmtProto.RemotePeerConfig{
+		{
+			WgPubKey:   "RRHf3Ma6z6mdLbriAJbqhX7+nM/B71lgw2+91q3LfhU=",
+			AllowedIps: []string{"100.64.0.10/32"},
+			Fqdn:       "peer-a.netbird.cloud.",
+			SshConfig:  &mgmtProto.SSHConfig{SshPubKey: publicKey},
+		},
+		{
+			WgPubKey:   "LLHf3Ma6z6mdLbriAJbqhX7+nM/B71lgw2+91q3LfhU=",
+			AllowedIps: []string{"100.64.0.11/32"},
+		},
+	})
+	require.NoError(t, err)
+
+	callback, err := ssh.NewKnownHostsCallback(path)
+	require.NoError(t, err)
+
+	remote := &net.TCPAddr{IP: net.ParseIP("100.64.0.10"), Port: ssh.DefaultSSHPort}
+	assert.NoError(t, callback("peer-a.netbird.cloud:44338", remote, hostKey))
+	assert.Error(t, callback("100.64.0.11:44338", &net.TCPAddr{IP: net.ParseIP("100.64.0.11")}, hostKey),
+		"peers without a published key should not be trusted")
+}
+
 func TestEngine_UpdateNetworkMap(t *testing.T) {
 	// test setup
 	key, err := wgtypes.GeneratePrivateKey()
@@ -1101,6 +1140,64 @@ func Test_CheckFilesEqual(t *testing.T) {
 			},
 			expectedBool: false,
 		},
+		{
+			name: "Equal Services In Different Order Should Return True",
+			inputChecks1: []*mgmtProto.Checks{
+				{
+					Services: []string{"sshd", "falcon-sensor"},
+					Ports:    []*mgmtProto.Port{{Port: 3389, Protocol: "tcp"}},
+				},
+			},
+			inputChecks2: []*mgmtProto.Checks{
+				{
+					Services: []string{"falcon-sensor", "sshd"},
+					Ports:    []*mgmtProto.Port{{Port: 3389, Protocol: "tcp"}},
+				},
+			},
+			expectedBool: true,
+		},
+		{
+			name: "Equal Ports In Different Order Should Return True",
+			inputChecks1: []*mgmtProto.Checks{
+				{
+					Ports: []*mgmtProto.Port{{Port: 3389, Protocol: "tcp"}, {Port: 22, Protocol: "tcp"}, {Port: 22, Protocol: "udp"}},
+				},
+			},
+			inputChecks2: []*mgmtProto.Checks{
+				{
+					Ports: []*mgmtProto.Port{{Port: 22, Protocol: "udp"}, {Port: 3389, Protocol: "tcp"}, {Port: 22, Protocol: "tcp"}},
+				},
+			},
+			expectedBool: true,
+		},
+		{
+			name: "Unequal Ports Should Return False",
+			inputChecks1: []*mgmtProto.Checks{
+				{
+					Ports: []*mgmtProto.Port{{Port: 3389, Protocol: "tcp"}},
+				},
+			},
+			inputChecks2: []*mgmtProto.Checks{
+				{
+					Ports: []*mgmtProto.Port{{Port: 3389, Protocol: "udp"}},
+				},
+			},
+			expectedBool: false,
+		},
+		{
+			name: "Unequal Host Security Should Return False",
+			inputChecks1: []*mgmtProto.Checks{
+				{
+					HostSecurity: true,
+				},
+			},
+			inputChecks2: []*mgmtProto.Checks{
+				{
+					Files: []string{},
+				},
+			},
+			expectedBool: false,
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+// DefaultMaxEvents is the number of events kept in memory, the oldest events are dropped first
+const DefaultMaxEvents = 10000
+
+var _ types.FlowLogger = (*Logger)(nil)
+
+// Logger is an in-memory flow logger keeping up to maxEvents events
+type Logger struct {
+	enabled   atomic.Bool
+	maxEvents int
+
+	mu     sync.Mutex
+	events []*types.Event
+}
+
+// New creates a flow logger, a non-positive maxEvents means DefaultMaxEvents
+func New(maxEvents int) *Logger {
+	if maxEvents <= 0 {
+		maxEvents = DefaultMaxEvents
+	}
+	return &Logger{maxEvents: maxEvents}
+}
+
+// StoreEvent stores a flow event if the logger is enabled
+func (l *Logger) StoreEvent(flowEvent types.EventFields) {
+	if !l.enabled.Load() {
+		return
+	}
+
+	event := &types.Event{
+		ID:          uuid.New(),
+		Timestamp:   time.Now().UTC(),
+		EventFields: flowEvent,
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.events) >= l.maxEvents {
+		// drop the oldest event, the underlying array is reallocated by append once in a while
+		l.events[0] = nil
+		l.events = l.events[1:]
+	}
+	l.events = append(l.events, event)
+}
+
+// GetEvents returns a copy of the stored events
+func (l *Logger) GetEvents() []*types.Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	events := make([]*types.Event, len(l.events))
+	copy(events, l.events)
+	return events
+}
+
+// DeleteEvents removes the events with the given IDs
+func (l *Logger) DeleteEvents(ids []uuid.UUID) {
+	if len(ids) == 0 {
+		return
+	}
+
+	toDelete := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		toDelete[id] = struct{}{}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	events := make([]*types.Event, 0, len(l.events))
+	for _, event := range l.events {
+		if _, ok := toDelete[event.ID]; !ok {
+			events = append(events, event)
+		}
+	}
+	l.events = events
+}
+
+// Enable starts storing the events
+func (l *Logger) Enable() {
+	l.enabled.Store(true)
+}
+
+// Disable stops storing the events and removes the stored ones
+func (l *Logger) Disable() {
+	l.enabled.Store(false)
+
+	l.mu.Lock()
+	l.events = nil
+	l.mu.Unlock()
+}
+
+// Enabled returns whether the events are stored
+func (l *Logger) Enabled() bool {
+	return l.enabled.Load()
+}
+
+// Close disables the logger and removes the stored events
+func (l *Logger) Close() {
+	l.Disable()
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/client/internal/netflow/types"
+)
+
+func TestLogger_StoreEvent(t *testing.T) {
+	l := New(3)
+
+	l.StoreEvent(types.EventFields{Type: types.TypeStart})
+	assert.Empty(t, l.GetEvents(), "disabled logger should not store events")
+
+	l.Enable()
+	for i := 0; i < 5; i++ {
+		l.StoreEvent(types.EventFields{Type: types.TypeStart, SourcePort: uint16(i)})
+	}
+
+	events := l.GetEvents()
+	require.Len(t, events, 3, "oldest events should be dropped")
+	for i, event := range events {
+		assert.Equal(t, uint16(i+2), event.SourcePort)
+		assert.NotEqual(t, uuid.Nil, event.ID)
+		assert.False(t, event.Timestamp.IsZero())
+	}
+}
+
+func TestLogger_DeleteEvents(t *testing.T) {
+	l := New(0)
+	l.Enable()
+
+	for i := 0; i < 3; i++ {
+		l.StoreEvent(types.EventFields{SourcePort: uint16(i)})
+	}
+
+	events := l.GetEvents()
+	l.DeleteEvents([]uuid.UUID{events[0].ID, events[2].ID})
+
+	remaining := l.GetEvents()
+	require.Len(t, remaining, 1)
+	assert.Equal(t, events[1].ID, remaining[0].ID)
+
+	l.Disable()
+	assert.Empty(t, l.GetEvents(), "disabling should remove the stored events")
+	assert.False(t, l.Enabled())
+}
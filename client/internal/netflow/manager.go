@@ -0,0 +1,206 @@
+package netflow
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/netbirdio/netbird/client/internal/netflow/logger"
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+	mgmProto "github.com/netbirdio/netbird/management/proto"
+)
+
+// EnvEnableFlowLog enables the local recording of the flow events when the management service doesn't enable it
+const EnvEnableFlowLog = "NB_ENABLE_FLOW_LOG"
+
+// DefaultUploadInterval is used when the management service doesn't provide an interval
+const DefaultUploadInterval = time.Minute
+
+// Sender uploads flow events to the management service
+type Sender interface {
+	LogFlows(events []*mgmProto.FlowEvent) error
+}
+
+// Manager records the flow events of the packet filter and uploads them to the management service if enabled
+type Manager struct {
+	mux        sync.Mutex
+	logger     nftypes.FlowLogger
+	sender     Sender
+	flowConfig *nftypes.FlowConfig
+	// localLog keeps the events recorded locally regardless of the management config
+	localLog bool
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewManager creates a flow manager. The flow events are recorded only when the management service enables the flow
+// logging or when EnvEnableFlowLog is set.
+func NewManager(sender Sender) *Manager {
+	m := &Manager{
+		logger: logger.New(logger.DefaultMaxEvents),
+		sender: sender,
+	}
+
+	if enabled, _ := strconv.ParseBool(os.Getenv(EnvEnableFlowLog)); enabled {
+		log.Infof("flow logging is enabled by %s", EnvEnableFlowLog)
+		m.localLog = true
+		m.logger.Enable()
+	}
+
+	return m
+}
+
+// GetLogger returns the flow logger used by the packet filter
+func (m *Manager) GetLogger() nftypes.FlowLogger {
+	return m.logger
+}
+
+// Update starts or stops the recording and the upload of the flow events according to the management config
+func (m *Manager) Update(update *nftypes.FlowConfig) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if update == nil {
+		update = &nftypes.FlowConfig{}
+	}
+	if update.Interval <= 0 {
+		update.Interval = DefaultUploadInterval
+	}
+
+	if m.flowConfig != nil && *m.flowConfig == *update {
+		return
+	}
+	m.flowConfig = update
+
+	m.stopUpload()
+
+	switch {
+	case update.Enabled || m.localLog:
+		m.logger.Enable()
+	case m.logger.Enabled():
+		log.Infof("flow logging is disabled by management")
+		m.logger.Disable()
+	}
+
+	if !update.Enabled || m.sender == nil {
+		return
+	}
+
+	log.Infof("uploading flow events to management every %s", update.Interval)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.uploadLoop(ctx, update.Interval)
+	}()
+}
+
+// Close stops the upload and the flow logger
+func (m *Manager) Close() {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	m.stopUpload()
+	m.logger.Close()
+}
+
+func (m *Manager) stopUpload() {
+	if m.cancel == nil {
+		return
+	}
+	m.cancel()
+	m.cancel = nil
+	m.wg.Wait()
+}
+
+func (m *Manager) uploadLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.upload(); err != nil {
+				log.Warnf("failed to upload flow events: %v", err)
+			}
+		}
+	}
+}
+
+func (m *Manager) upload() error {
+	events := m.logger.GetEvents()
+	if len(events) == 0 {
+		return nil
+	}
+
+	protoEvents := make([]*mgmProto.FlowEvent, 0, len(events))
+	ids := make([]uuid.UUID, 0, len(events))
+	for _, event := range events {
+		protoEvents = append(protoEvents, toProtoEvent(event))
+		ids = append(ids, event.ID)
+	}
+
+	if err := m.sender.LogFlows(protoEvents); err != nil {
+		return err
+	}
+
+	m.logger.DeleteEvents(ids)
+	return nil
+}
+
+func toProtoEvent(event *nftypes.Event) *mgmProto.FlowEvent {
+	protoEvent := &mgmProto.FlowEvent{
+		EventId:    event.ID[:],
+		Timestamp:  timestamppb.New(event.Timestamp),
+		FlowId:     event.FlowID[:],
+		Type:       toProtoType(event.Type),
+		RuleId:     event.RuleID,
+		Direction:  toProtoDirection(event.Direction),
+		Protocol:   uint32(event.Protocol),
+		SourcePort: uint32(event.SourcePort),
+		DestPort:   uint32(event.DestPort),
+		IcmpType:   uint32(event.ICMPType),
+		IcmpCode:   uint32(event.ICMPCode),
+		RxPackets:  event.RxPackets,
+		TxPackets:  event.TxPackets,
+		RxBytes:    event.RxBytes,
+		TxBytes:    event.TxBytes,
+	}
+	if event.SourceIP.IsValid() {
+		protoEvent.SourceIp = event.SourceIP.AsSlice()
+	}
+	if event.DestIP.IsValid() {
+		protoEvent.DestIp = event.DestIP.AsSlice()
+	}
+	return protoEvent
+}
+
+func toProtoType(t nftypes.Type) mgmProto.FlowEventType {
+	switch t {
+	case nftypes.TypeStart:
+		return mgmProto.FlowEventType_FLOW_START
+	case nftypes.TypeEnd:
+		return mgmProto.FlowEventType_FLOW_END
+	case nftypes.TypeDrop:
+		return mgmProto.FlowEventType_FLOW_DROP
+	default:
+		return mgmProto.FlowEventType_FLOW_UNKNOWN
+	}
+}
+
+func toProtoDirection(d nftypes.Direction) mgmProto.RuleDirection {
+	if d == nftypes.Egress {
+		return mgmProto.RuleDirection_OUT
+	}
+	return mgmProto.RuleDirection_IN
+}
@@ -0,0 +1,96 @@
+package netflow
+
+import (
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+	mgmProto "github.com/netbirdio/netbird/management/proto"
+)
+
+type mockSender struct {
+	mu     sync.Mutex
+	events []*mgmProto.FlowEvent
+}
+
+func (s *mockSender) LogFlows(events []*mgmProto.FlowEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func (s *mockSender) received() []*mgmProto.FlowEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.events
+}
+
+func TestManager_Upload(t *testing.T) {
+	sender := &mockSender{}
+	m := NewManager(sender)
+	defer m.Close()
+
+	m.GetLogger().StoreEvent(nftypes.EventFields{Type: nftypes.TypeStart})
+	assert.False(t, m.GetLogger().Enabled(), "flow logging should be disabled until enabled by management")
+	assert.Empty(t, m.GetLogger().GetEvents())
+
+	m.Update(&nftypes.FlowConfig{Enabled: true, Interval: 10 * time.Millisecond})
+	require.True(t, m.GetLogger().Enabled())
+
+	flowID := uuid.New()
+	m.GetLogger().StoreEvent(nftypes.EventFields{
+		FlowID:     flowID,
+		Type:       nftypes.TypeEnd,
+		RuleID:     "rule",
+		Direction:  nftypes.Egress,
+		Protocol:   nftypes.TCP,
+		SourceIP:   netip.MustParseAddr("100.64.0.1"),
+		DestIP:     netip.MustParseAddr("100.64.0.2"),
+		SourcePort: 40000,
+		DestPort:   443,
+		TxBytes:    100,
+	})
+
+	require.Eventually(t, func() bool {
+		return len(sender.received()) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	event := sender.received()[0]
+	assert.Equal(t, flowID[:], event.FlowId)
+	assert.Equal(t, mgmProto.FlowEventType_FLOW_END, event.Type)
+	assert.Equal(t, mgmProto.RuleDirection_OUT, event.Direction)
+	assert.Equal(t, uint32(6), event.Protocol)
+	assert.Equal(t, []byte{100, 64, 0, 2}, event.DestIp)
+	assert.Equal(t, uint32(443), event.DestPort)
+	assert.Equal(t, uint64(100), event.TxBytes)
+
+	require.Eventually(t, func() bool {
+		return len(m.GetLogger().GetEvents()) == 0
+	}, time.Second, 10*time.Millisecond, "uploaded events should be deleted")
+
+	m.Update(nil)
+	m.GetLogger().StoreEvent(nftypes.EventFields{Type: nftypes.TypeStart})
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, sender.received(), 1, "events should not be uploaded when disabled")
+	assert.Empty(t, m.GetLogger().GetEvents(), "events should not be recorded when disabled")
+}
+
+func TestManager_LocalFlowLog(t *testing.T) {
+	t.Setenv(EnvEnableFlowLog, "true")
+
+	m := NewManager(&mockSender{})
+	defer m.Close()
+
+	m.Update(nil)
+	require.True(t, m.GetLogger().Enabled(), "flow logging enabled locally should not be disabled by management")
+
+	m.GetLogger().StoreEvent(nftypes.EventFields{Type: nftypes.TypeStart})
+	assert.Len(t, m.GetLogger().GetEvents(), 1)
+}
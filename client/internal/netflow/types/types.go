@@ -0,0 +1,133 @@
+package types
+
+import (
+	"net/netip"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Type is the type of flow event
+type Type int
+
+const (
+	TypeUnknown Type = iota
+	// TypeStart is emitted when a new connection is tracked
+	TypeStart
+	// TypeEnd is emitted when a tracked connection is closed or timed out
+	TypeEnd
+	// TypeDrop is emitted when a packet is dropped by the packet filter
+	TypeDrop
+)
+
+func (t Type) String() string {
+	switch t {
+	case TypeStart:
+		return "start"
+	case TypeEnd:
+		return "end"
+	case TypeDrop:
+		return "drop"
+	default:
+		return "unknown"
+	}
+}
+
+// Direction is the direction of a flow from the point of view of the local peer
+type Direction int
+
+const (
+	DirectionUnknown Direction = iota
+	// Ingress flows are initiated by a remote peer
+	Ingress
+	// Egress flows are initiated by the local peer
+	Egress
+)
+
+func (d Direction) String() string {
+	switch d {
+	case Ingress:
+		return "ingress"
+	case Egress:
+		return "egress"
+	default:
+		return "unknown"
+	}
+}
+
+// Protocol is the IANA protocol number of a flow
+type Protocol uint8
+
+const (
+	ProtocolUnknown Protocol = 0
+	ICMP            Protocol = 1
+	TCP             Protocol = 6
+	UDP             Protocol = 17
+	ICMPv6          Protocol = 58
+)
+
+func (p Protocol) String() string {
+	switch p {
+	case ICMP:
+		return "icmp"
+	case TCP:
+		return "tcp"
+	case UDP:
+		return "udp"
+	case ICMPv6:
+		return "icmpv6"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is a flow event stored by the flow logger
+type Event struct {
+	ID        uuid.UUID
+	Timestamp time.Time
+	EventFields
+}
+
+// EventFields describes a flow. The counters are set on end events only,
+// Rx counts the packets received by the local peer and Tx the packets sent by it.
+type EventFields struct {
+	FlowID     uuid.UUID
+	Type       Type
+	RuleID     string
+	Direction  Direction
+	Protocol   Protocol
+	SourceIP   netip.Addr
+	DestIP     netip.Addr
+	SourcePort uint16
+	DestPort   uint16
+	ICMPType   uint8
+	ICMPCode   uint8
+	RxPackets  uint64
+	TxPackets  uint64
+	RxBytes    uint64
+	TxBytes    uint64
+}
+
+// FlowLogger stores the flow events emitted by the packet filter
+type FlowLogger interface {
+	// StoreEvent stores a flow event if the logger is enabled
+	StoreEvent(flowEvent EventFields)
+	// GetEvents returns the stored events ordered by their time
+	GetEvents() []*Event
+	// DeleteEvents removes the events with the given IDs
+	DeleteEvents(ids []uuid.UUID)
+	// Enable starts storing the events
+	Enable()
+	// Disable stops storing the events and removes the stored ones
+	Disable()
+	// Enabled returns whether the events are stored
+	Enabled() bool
+	// Close stops the logger
+	Close()
+}
+
+// FlowConfig is the configuration of the flow events upload to the management service
+type FlowConfig struct {
+	Enabled  bool
+	Interval time.Duration
+}
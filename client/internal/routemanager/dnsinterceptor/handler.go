@@ -161,12 +161,8 @@ func (d *DnsInterceptor) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 		return
 	}
 
-	client := &dns.Client{
-		Timeout: 5 * time.Second,
-		Net:     "udp",
-	}
 	upstream := fmt.Sprintf("%s:%d", upstreamIP, dnsfwd.ListenPort)
-	reply, _, err := client.ExchangeContext(context.Background(), r, upstream)
+	reply, err := exchange(r, upstream)
 
 	var answer []dns.RR
 	if reply != nil {
@@ -188,6 +184,28 @@ func (d *DnsInterceptor) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 	}
 }
 
+// exchange sends the query to the forwarder of the routing peer over UDP and retries over TCP
+// if the response doesn't fit in a UDP message. The truncated response is returned if the TCP retry fails.
+func exchange(r *dns.Msg, upstream string) (*dns.Msg, error) {
+	client := &dns.Client{
+		Timeout: 5 * time.Second,
+		Net:     "udp",
+	}
+	reply, _, err := client.ExchangeContext(context.Background(), r, upstream)
+	if err != nil || !reply.Truncated {
+		return reply, err
+	}
+
+	log.Tracef("DNS response for domain=%s from %s truncated, retrying over TCP", r.Question[0].Name, upstream)
+	client.Net = "tcp"
+	tcpReply, _, err := client.ExchangeContext(context.Background(), r, upstream)
+	if err != nil {
+		log.Debugf("failed to retry DNS request for domain=%s with %s over TCP, using the truncated response: %v", r.Question[0].Name, upstream, err)
+		return reply, nil
+	}
+	return tcpReply, nil
+}
+
 // continueToNextHandler signals the handler chain to try the next handler
 func (d *DnsInterceptor) continueToNextHandler(w dns.ResponseWriter, r *dns.Msg, reason string) {
 	log.Tracef("continuing to next handler for domain=%s reason=%s", r.Question[0].Name, reason)
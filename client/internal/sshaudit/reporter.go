@@ -0,0 +1,150 @@
+// Package sshaudit reports the sessions of the embedded SSH server to the management service
+package sshaudit
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	nbssh "github.com/netbirdio/netbird/client/ssh"
+	mgmProto "github.com/netbirdio/netbird/management/proto"
+)
+
+const (
+	// DefaultMaxEvents is the number of events kept while the management service is unreachable, the oldest events
+	// are dropped first
+	DefaultMaxEvents = 1000
+	// retryInterval is the period of the upload retries of the pending events
+	retryInterval = 10 * time.Second
+)
+
+// Sender uploads the SSH session events to the management service
+type Sender interface {
+	LogSSHSessions(events []*mgmProto.SSHSessionEvent) error
+}
+
+// Reporter queues the SSH session events and uploads them to the management service, the events failed to upload are
+// retried until they succeed or the reporter is closed
+type Reporter struct {
+	sender    Sender
+	maxEvents int
+
+	mu      sync.Mutex
+	pending []*mgmProto.SSHSessionEvent
+
+	notify    chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewReporter creates a reporter uploading the events with the sender, a non-positive maxEvents means DefaultMaxEvents
+func NewReporter(sender Sender, maxEvents int) *Reporter {
+	if maxEvents <= 0 {
+		maxEvents = DefaultMaxEvents
+	}
+
+	r := &Reporter{
+		sender:    sender,
+		maxEvents: maxEvents,
+		notify:    make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.uploadLoop()
+	}()
+	return r
+}
+
+// Report queues the start or the end of a session on the SSH server for the upload
+func (r *Reporter) Report(event nbssh.SessionEvent) {
+	r.mu.Lock()
+	r.pending = append(r.pending, toProtoEvent(event))
+	r.trim()
+	r.mu.Unlock()
+
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Close stops the upload, the pending events are dropped
+func (r *Reporter) Close() {
+	r.closeOnce.Do(func() {
+		close(r.done)
+	})
+	r.wg.Wait()
+}
+
+func (r *Reporter) uploadLoop() {
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-r.notify:
+		case <-ticker.C:
+		}
+
+		if err := r.upload(); err != nil {
+			log.Warnf("failed to report SSH sessions to management, retrying in %s: %v", retryInterval, err)
+		}
+	}
+}
+
+func (r *Reporter) upload() error {
+	r.mu.Lock()
+	events := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	if err := r.sender.LogSSHSessions(events); err != nil {
+		// put the events back in front of the ones reported meanwhile
+		r.mu.Lock()
+		r.pending = append(events, r.pending...)
+		r.trim()
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// trim drops the oldest events above maxEvents, it must be called with the lock held
+func (r *Reporter) trim() {
+	if dropped := len(r.pending) - r.maxEvents; dropped > 0 {
+		log.Warnf("dropping %d SSH session events, the management service is unreachable", dropped)
+		r.pending = r.pending[dropped:]
+	}
+}
+
+func toProtoEvent(event nbssh.SessionEvent) *mgmProto.SSHSessionEvent {
+	eventType := mgmProto.SSHSessionEventType_SSH_SESSION_START
+	if event.Ended {
+		eventType = mgmProto.SSHSessionEventType_SSH_SESSION_END
+	}
+
+	sessionEvent := &mgmProto.SSHSessionEvent{
+		SessionId: event.ID,
+		Type:      eventType,
+		Timestamp: timestamppb.New(event.Timestamp),
+		PeerKey:   event.Peer,
+		LocalUser: event.LocalUser,
+	}
+	if event.Ended {
+		sessionEvent.Duration = durationpb.New(event.Duration)
+	}
+	return sessionEvent
+}
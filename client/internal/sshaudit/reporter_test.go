@@ -0,0 +1,93 @@
+package sshaudit
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nbssh "github.com/netbirdio/netbird/client/ssh"
+	mgmProto "github.com/netbirdio/netbird/management/proto"
+)
+
+type mockSender struct {
+	mu     sync.Mutex
+	fail   bool
+	events []*mgmProto.SSHSessionEvent
+}
+
+func (s *mockSender) LogSSHSessions(events []*mgmProto.SSHSessionEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail {
+		return errors.New("management is unreachable")
+	}
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func (s *mockSender) setFail(fail bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fail = fail
+}
+
+func (s *mockSender) received() []*mgmProto.SSHSessionEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.events
+}
+
+func TestReporter_Report(t *testing.T) {
+	sender := &mockSender{}
+	r := NewReporter(sender, 0)
+	defer r.Close()
+
+	started := time.Now().Add(-time.Minute)
+	r.Report(nbssh.SessionEvent{ID: "session", Timestamp: started, Peer: "peer", LocalUser: "root"})
+	r.Report(nbssh.SessionEvent{ID: "session", Ended: true, Timestamp: started.Add(time.Minute), Peer: "peer", LocalUser: "root", Duration: time.Minute})
+
+	require.Eventually(t, func() bool {
+		return len(sender.received()) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	start, end := sender.received()[0], sender.received()[1]
+	assert.Equal(t, mgmProto.SSHSessionEventType_SSH_SESSION_START, start.Type)
+	assert.True(t, started.Equal(start.Timestamp.AsTime()), "the event should keep the session timestamp")
+	assert.Equal(t, "root", start.LocalUser)
+	assert.Nil(t, start.Duration)
+	assert.Equal(t, mgmProto.SSHSessionEventType_SSH_SESSION_END, end.Type)
+	assert.Equal(t, time.Minute, end.Duration.AsDuration())
+}
+
+func TestReporter_Retry(t *testing.T) {
+	sender := &mockSender{fail: true}
+	r := NewReporter(sender, 2)
+	defer r.Close()
+
+	r.Report(nbssh.SessionEvent{ID: "dropped"})
+	r.Report(nbssh.SessionEvent{ID: "first"})
+	r.Report(nbssh.SessionEvent{ID: "second"})
+
+	// the failed uploads put the events back to the queue
+	require.Eventually(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return len(r.pending) == 2
+	}, time.Second, 10*time.Millisecond, "the oldest event should be dropped above the limit")
+	assert.Empty(t, sender.received())
+
+	sender.setFail(false)
+	require.Eventually(t, func() bool {
+		_ = r.upload()
+		return len(sender.received()) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	received := sender.received()
+	require.Len(t, received, 2)
+	assert.Equal(t, "first", received[0].SessionId)
+	assert.Equal(t, "second", received[1].SessionId)
+}
@@ -814,6 +814,9 @@ func anonymizeServers(servers []*mgmProto.NameServer, anonymizer *anonymize.Anon
 		if addr, err := netip.ParseAddr(server.IP); err == nil {
 			server.IP = anonymizer.AnonymizeIP(addr).String()
 		}
+		if server.ServerName != "" {
+			server.ServerName = anonymizer.AnonymizeDomain(server.ServerName)
+		}
 	}
 }
 
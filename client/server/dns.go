@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/netbirdio/netbird/client/internal/dns"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// GetDNSQueries returns the DNS queries recently handled by the client resolver
+func (s *Server) GetDNSQueries(_ context.Context, _ *proto.GetDNSQueriesRequest) (*proto.GetDNSQueriesResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.connectClient == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "client is not connected")
+	}
+
+	engine := s.connectClient.Engine()
+	if engine == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "engine is not initialized")
+	}
+
+	queryLog, err := engine.GetDNSQueryLog()
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "get DNS queries: %v", err)
+	}
+
+	entries := queryLog.Entries()
+	resp := &proto.GetDNSQueriesResponse{
+		Queries: make([]*proto.DNSQuery, 0, len(entries)),
+		Stats:   toProtoDNSQueryStats(queryLog.Stats()),
+		Enabled: queryLog.Enabled(),
+	}
+	for _, entry := range entries {
+		resp.Queries = append(resp.Queries, toProtoDNSQuery(entry))
+	}
+
+	return resp, nil
+}
+
+// dnsQueryStats returns the DNS query counters of the running engine, nil if the client is not connected
+func (s *Server) dnsQueryStats() *proto.DNSQueryStats {
+	if s.connectClient == nil {
+		return nil
+	}
+	engine := s.connectClient.Engine()
+	if engine == nil {
+		return nil
+	}
+	queryLog, err := engine.GetDNSQueryLog()
+	if err != nil {
+		return nil
+	}
+	return toProtoDNSQueryStats(queryLog.Stats())
+}
+
+// dnsCacheStats returns the DNS cache counters of the running engine, nil if the client is not connected
+func (s *Server) dnsCacheStats() *proto.DNSCacheStats {
+	if s.connectClient == nil {
+		return nil
+	}
+	engine := s.connectClient.Engine()
+	if engine == nil {
+		return nil
+	}
+	stats, err := engine.GetDNSCacheStats()
+	if err != nil {
+		return nil
+	}
+	return &proto.DNSCacheStats{
+		Hits:      stats.Hits,
+		Misses:    stats.Misses,
+		Evictions: stats.Evictions,
+		Entries:   stats.Entries,
+	}
+}
+
+func toProtoDNSQuery(entry dns.QueryLogEntry) *proto.DNSQuery {
+	return &proto.DNSQuery{
+		Timestamp: timestamppb.New(entry.Timestamp),
+		Name:      entry.Name,
+		Type:      entry.Type,
+		Rcode:     entry.Rcode,
+		Handler:   entry.Handler,
+		Pattern:   entry.Pattern,
+		Upstream:  entry.Upstream,
+		Latency:   durationpb.New(entry.Latency),
+		Cached:    entry.Cached,
+	}
+}
+
+func toProtoDNSQueryStats(stats dns.QueryStats) *proto.DNSQueryStats {
+	return &proto.DNSQueryStats{
+		Total:     stats.Total,
+		Failed:    stats.Failed,
+		ByHandler: stats.ByHandler,
+		ByRcode:   stats.ByRcode,
+	}
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/netbirdio/netbird/client/internal/acl"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// ListFirewallRules returns the peer firewall rules with their hit counters
+func (s *Server) ListFirewallRules(_ context.Context, _ *proto.ListFirewallRulesRequest) (*proto.ListFirewallRulesResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.connectClient == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "client is not connected")
+	}
+
+	engine := s.connectClient.Engine()
+	if engine == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "engine is not initialized")
+	}
+
+	stats, err := engine.GetFirewallRuleStats()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get firewall rules: %v", err)
+	}
+
+	resp := &proto.ListFirewallRulesResponse{
+		Rules: make([]*proto.FirewallRule, 0, len(stats)),
+	}
+	for _, s := range stats {
+		resp.Rules = append(resp.Rules, toProtoFirewallRule(s))
+	}
+
+	return resp, nil
+}
+
+func toProtoFirewallRule(s acl.RuleStats) *proto.FirewallRule {
+	return &proto.FirewallRule{
+		Id:        s.ID,
+		PolicyId:  s.PolicyID,
+		PeerIp:    s.PeerIP,
+		Direction: strings.ToLower(s.Direction.String()),
+		Action:    strings.ToLower(s.Action.String()),
+		Protocol:  strings.ToLower(s.Protocol.String()),
+		Port:      s.Port,
+		Packets:   s.Packets,
+		Bytes:     s.Bytes,
+	}
+}
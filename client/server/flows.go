@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	nftypes "github.com/netbirdio/netbird/client/internal/netflow/types"
+	"github.com/netbirdio/netbird/client/proto"
+)
+
+// GetFlows returns the connection flow events recorded by the packet filter
+func (s *Server) GetFlows(_ context.Context, _ *proto.GetFlowsRequest) (*proto.GetFlowsResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.connectClient == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "client is not connected")
+	}
+
+	engine := s.connectClient.Engine()
+	if engine == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "engine is not initialized")
+	}
+
+	flowLogger := engine.GetFlowLogger()
+	events := flowLogger.GetEvents()
+
+	resp := &proto.GetFlowsResponse{
+		Events:  make([]*proto.FlowEvent, 0, len(events)),
+		Enabled: flowLogger.Enabled(),
+	}
+	for _, event := range events {
+		resp.Events = append(resp.Events, toProtoFlowEvent(event))
+	}
+
+	return resp, nil
+}
+
+func toProtoFlowEvent(event *nftypes.Event) *proto.FlowEvent {
+	protoEvent := &proto.FlowEvent{
+		EventId:    event.ID.String(),
+		Timestamp:  timestamppb.New(event.Timestamp),
+		FlowId:     event.FlowID.String(),
+		Type:       event.Type.String(),
+		RuleId:     event.RuleID,
+		Direction:  event.Direction.String(),
+		Protocol:   event.Protocol.String(),
+		SourcePort: uint32(event.SourcePort),
+		DestPort:   uint32(event.DestPort),
+		IcmpType:   uint32(event.ICMPType),
+		IcmpCode:   uint32(event.ICMPCode),
+		RxPackets:  event.RxPackets,
+		TxPackets:  event.TxPackets,
+		RxBytes:    event.RxBytes,
+		TxBytes:    event.TxBytes,
+	}
+	if event.SourceIP.IsValid() {
+		protoEvent.SourceIp = event.SourceIP.String()
+	}
+	if event.DestIP.IsValid() {
+		protoEvent.DestIp = event.DestIP.String()
+	}
+	return protoEvent
+}
@@ -700,6 +700,8 @@ func (s *Server) Status(
 
 		fullStatus := s.statusRecorder.GetFullStatus()
 		pbFullStatus := toProtoFullStatus(fullStatus)
+		pbFullStatus.DnsQueryStats = s.dnsQueryStats()
+		pbFullStatus.DnsCacheStats = s.dnsCacheStats()
 		statusResponse.FullStatus = pbFullStatus
 	}
 
@@ -1,11 +1,16 @@
 package ssh
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"time"
 
+	"github.com/pkg/sftp"
+	log "github.com/sirupsen/logrus"
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/term"
 )
@@ -84,8 +89,115 @@ func (c *Client) OpenTerminal() error {
 	return nil
 }
 
+// Exec runs the command on the remote host without a terminal.
+// A non-zero exit status of the command is returned as *ssh.ExitError.
+func (c *Client) Exec(command string, stdin io.Reader, stdout, stderr io.Writer) error {
+	session, err := c.client.NewSession()
+	if err != nil {
+		return fmt.Errorf("failed to open new session: %v", err)
+	}
+	defer func() {
+		_ = session.Close()
+	}()
+
+	session.Stdin = stdin
+	session.Stdout = stdout
+	session.Stderr = stderr
+
+	return session.Run(command)
+}
+
+// ForwardLocal listens on localAddr and forwards the accepted connections to remoteAddr through the remote host
+// until the context is done
+func (c *Client) ForwardLocal(ctx context.Context, localAddr, remoteAddr string) error {
+	listener, err := net.Listen("tcp", localAddr)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", localAddr, err)
+	}
+
+	return serveForward(ctx, listener, func() (net.Conn, error) {
+		return c.client.Dial("tcp", remoteAddr)
+	})
+}
+
+// ForwardRemote requests the remote host to listen on remoteAddr and forwards the connections it accepts
+// to localAddr until the context is done
+func (c *Client) ForwardRemote(ctx context.Context, remoteAddr, localAddr string) error {
+	listener, err := c.client.Listen("tcp", remoteAddr)
+	if err != nil {
+		return fmt.Errorf("request remote listener on %s: %w", remoteAddr, err)
+	}
+
+	return serveForward(ctx, listener, func() (net.Conn, error) {
+		return net.Dial("tcp", localAddr)
+	})
+}
+
+// NewSFTPClient opens an SFTP session with the remote host, the session has to be closed by the caller
+func (c *Client) NewSFTPClient() (*sftp.Client, error) {
+	client, err := sftp.NewClient(c.client)
+	if err != nil {
+		return nil, fmt.Errorf("start sftp session: %w", err)
+	}
+	return client, nil
+}
+
+func serveForward(ctx context.Context, listener net.Listener, dial func() (net.Conn, error)) error {
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("accept forwarded connection: %w", err)
+		}
+		go forwardConn(conn, dial)
+	}
+}
+
+func forwardConn(conn net.Conn, dial func() (net.Conn, error)) {
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	target, err := dial()
+	if err != nil {
+		log.Warnf("failed to forward connection from %s: %v", conn.RemoteAddr(), err)
+		return
+	}
+	defer func() {
+		_ = target.Close()
+	}()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(target, conn)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(conn, target)
+		done <- struct{}{}
+	}()
+	<-done
+}
+
+// ExitStatus reports whether err is the non-zero exit status of a remote command and returns the status
+func ExitStatus(err error) (int, bool) {
+	var exitErr *ssh.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitStatus(), true
+	}
+	return 0, false
+}
+
 // DialWithKey connects to the remote SSH server with a provided private key file (PEM).
-func DialWithKey(addr, user string, privateKey []byte) (*Client, error) {
+// The host key of the remote server is verified with the given callback.
+func DialWithKey(addr, user string, privateKey []byte, hostKeyCallback ssh.HostKeyCallback) (*Client, error) {
 
 	signer, err := ssh.ParsePrivateKey(privateKey)
 	if err != nil {
@@ -98,7 +210,7 @@ func DialWithKey(addr, user string, privateKey []byte) (*Client, error) {
 		Auth: []ssh.AuthMethod{
 			ssh.PublicKeys(signer),
 		},
-		HostKeyCallback: ssh.HostKeyCallback(func(hostname string, remote net.Addr, key ssh.PublicKey) error { return nil }),
+		HostKeyCallback: hostKeyCallback,
 	}
 
 	return Dial("tcp", addr, config)
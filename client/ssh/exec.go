@@ -0,0 +1,196 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/user"
+
+	"github.com/creack/pty"
+	"github.com/gliderlabs/ssh"
+	"github.com/pkg/sftp"
+	log "github.com/sirupsen/logrus"
+)
+
+// SFTPServerCommand is the hidden netbird command serving SFTP on stdin and stdout,
+// it is used to run the SFTP server with the permissions of the local user
+const SFTPServerCommand = "sftp-server"
+
+// execHandler runs the command of the session as the local user
+func (srv *DefaultServer) execHandler(session ssh.Session, localUser *user.User) {
+	if !srv.getFeatures().Exec {
+		log.Infof("denied command execution for %s from %s, command execution is disabled", session.User(), session.RemoteAddr())
+		_, _ = io.WriteString(session.Stderr(), "command execution is disabled on this peer\n")
+		_ = session.Exit(1)
+		return
+	}
+
+	shell := getUserShell(localUser.Uid)
+	cmd, err := userCommand(localUser, shell, "-c", session.RawCommand())
+	if err != nil {
+		log.Warnf("failed to prepare command for %s from %s: %v", session.User(), session.RemoteAddr(), err)
+		_, _ = fmt.Fprintf(session.Stderr(), "failed to run command: %v\n", err)
+		_ = session.Exit(1)
+		return
+	}
+	cmd.Dir = localUser.HomeDir
+	cmd.Env = prepareUserEnv(localUser, shell)
+	for _, v := range session.Environ() {
+		if acceptEnv(v) {
+			cmd.Env = append(cmd.Env, v)
+		}
+	}
+
+	log.Infof("executing command for %s from %s: %s", session.User(), session.RemoteAddr(), session.RawCommand())
+
+	if ptyReq, winCh, isPty := session.Pty(); isPty {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("TERM=%s", ptyReq.Term))
+		err = runWithPty(session, cmd, winCh)
+	} else {
+		err = runWithPipes(session, cmd)
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		_ = session.Exit(exitErr.ExitCode())
+	case err != nil:
+		log.Debugf("command for %s from %s failed: %v", session.User(), session.RemoteAddr(), err)
+		_, _ = fmt.Fprintf(session.Stderr(), "failed to run command: %v\n", err)
+		_ = session.Exit(1)
+	default:
+		_ = session.Exit(0)
+	}
+}
+
+func runWithPipes(session ssh.Session, cmd *exec.Cmd) error {
+	// the input is copied manually as the session may never reach EOF and Wait would block on it
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("create stdin pipe: %w", err)
+	}
+	cmd.Stdout = session
+	cmd.Stderr = session.Stderr()
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start command: %w", err)
+	}
+	killOnDone(session, cmd)
+
+	go func() {
+		_, _ = io.Copy(stdin, session)
+		_ = stdin.Close()
+	}()
+
+	return cmd.Wait()
+}
+
+func runWithPty(session ssh.Session, cmd *exec.Cmd, winCh <-chan ssh.Window) error {
+	file, err := pty.Start(cmd)
+	if err != nil {
+		return fmt.Errorf("start command: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	killOnDone(session, cmd)
+
+	go func() {
+		for win := range winCh {
+			setWinSize(file, win.Width, win.Height)
+		}
+	}()
+	go func() {
+		_, _ = io.Copy(file, session)
+	}()
+	// returns once the command exits and the pty is closed
+	_, _ = io.Copy(session, file)
+
+	return cmd.Wait()
+}
+
+// killOnDone kills the process of the command when the session is closed
+func killOnDone(session ssh.Session, cmd *exec.Cmd) {
+	go func() {
+		<-session.Context().Done()
+		if cmd.ProcessState != nil || cmd.Process == nil {
+			return
+		}
+		if err := cmd.Process.Kill(); err != nil {
+			log.Debugf("failed killing SSH process %v", err)
+		}
+	}()
+}
+
+// sftpHandler serves the sftp subsystem with the permissions of the local user
+func (srv *DefaultServer) sftpHandler(session ssh.Session) {
+	if !srv.getFeatures().SFTP {
+		log.Infof("denied SFTP for %s from %s, SFTP is disabled", session.User(), session.RemoteAddr())
+		_ = session.Exit(1)
+		return
+	}
+
+	localUser, err := userNameLookup(session.User())
+	if err != nil {
+		log.Warnf("failed SFTP session from %v, user %s: %v", session.RemoteAddr(), session.User(), err)
+		_ = session.Exit(1)
+		return
+	}
+
+	log.Infof("starting SFTP session for %s from %s", session.User(), session.RemoteAddr())
+
+	if isCurrentUser(localUser) {
+		err = ServeSFTP(session, session, localUser.HomeDir)
+	} else {
+		err = srv.runSFTPServer(session, localUser)
+	}
+	if err != nil {
+		log.Debugf("SFTP session for %s from %s ended with error: %v", session.User(), session.RemoteAddr(), err)
+		_ = session.Exit(1)
+		return
+	}
+	_ = session.Exit(0)
+}
+
+// runSFTPServer runs the netbird binary in SFTP server mode as the local user so that the file
+// operations are subject to the permissions of that user
+func (srv *DefaultServer) runSFTPServer(session ssh.Session, localUser *user.User) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("get executable: %w", err)
+	}
+
+	cmd, err := userCommand(localUser, executable, SFTPServerCommand)
+	if err != nil {
+		return err
+	}
+	cmd.Dir = localUser.HomeDir
+	cmd.Env = prepareUserEnv(localUser, getUserShell(localUser.Uid))
+
+	return runWithPipes(session, cmd)
+}
+
+// ServeSFTP serves SFTP requests read from in until the client disconnects, relative paths are resolved from workDir
+func ServeSFTP(in io.Reader, out io.WriteCloser, workDir string) error {
+	server, err := sftp.NewServer(&readWriteCloser{Reader: in, WriteCloser: out}, sftp.WithServerWorkingDirectory(workDir))
+	if err != nil {
+		return fmt.Errorf("create sftp server: %w", err)
+	}
+
+	if err := server.Serve(); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+type readWriteCloser struct {
+	io.Reader
+	io.WriteCloser
+}
+
+func isCurrentUser(u *user.User) bool {
+	current, err := user.Current()
+	return err == nil && current.Uid == u.Uid
+}
@@ -0,0 +1,191 @@
+//go:build !windows
+
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func startTestServer(t *testing.T, features Features) *Client {
+	t.Helper()
+
+	server, clientKey := newTestServer(t, func(server *DefaultServer) {
+		server.UpdateFeatures(features)
+	})
+
+	current, err := user.Current()
+	require.NoError(t, err)
+	client, err := dialTestServer(t, server, clientKey, current.Username)
+	require.NoError(t, err)
+
+	return client
+}
+
+// newTestServer starts a server authorizing the key of remotePeer and returns the private key of the peer
+func newTestServer(t *testing.T, configure func(server *DefaultServer)) (*DefaultServer, []byte) {
+	t.Helper()
+
+	hostKey, err := GeneratePrivateKey(ED25519)
+	require.NoError(t, err)
+	server, err := newDefaultServer(hostKey, "127.0.0.1:0")
+	require.NoError(t, err)
+
+	clientKey, err := GeneratePrivateKey(ED25519)
+	require.NoError(t, err)
+	clientPubKey, err := GeneratePublicKey(clientKey)
+	require.NoError(t, err)
+	require.NoError(t, server.AddAuthorizedKey("remotePeer", string(clientPubKey), nil))
+	configure(server)
+
+	go func() {
+		_ = server.Start()
+	}()
+	t.Cleanup(func() {
+		_ = server.Stop()
+	})
+
+	return server, clientKey
+}
+
+func dialTestServer(t *testing.T, server *DefaultServer, clientKey []byte, username string) (*Client, error) {
+	t.Helper()
+
+	signer, err := ssh.ParsePrivateKey(clientKey)
+	require.NoError(t, err)
+
+	client, err := Dial("tcp", server.listener.Addr().String(), &ssh.ClientConfig{
+		User:            username,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         5 * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	return client, nil
+}
+
+func TestServer_Exec(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		client := startTestServer(t, Features{})
+
+		var stdout, stderr bytes.Buffer
+		err := client.Exec("echo hello", nil, &stdout, &stderr)
+		status, ok := ExitStatus(err)
+		require.True(t, ok, "expected exit error, got %v", err)
+		assert.Equal(t, 1, status)
+		assert.Empty(t, stdout.String())
+		assert.Contains(t, stderr.String(), "command execution is disabled")
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		client := startTestServer(t, Features{Exec: true})
+
+		var stdout bytes.Buffer
+		require.NoError(t, client.Exec("echo hello", nil, &stdout, io.Discard))
+		assert.Equal(t, "hello\n", stdout.String())
+
+		err := client.Exec("exit 3", nil, io.Discard, io.Discard)
+		status, ok := ExitStatus(err)
+		require.True(t, ok, "expected exit error, got %v", err)
+		assert.Equal(t, 3, status)
+	})
+}
+
+func TestServer_LocalPortForwarding(t *testing.T) {
+	echo, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer echo.Close()
+	go func() {
+		for {
+			conn, err := echo.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	t.Run("disabled", func(t *testing.T) {
+		client := startTestServer(t, Features{})
+
+		_, err := client.client.Dial("tcp", echo.Addr().String())
+		require.Error(t, err)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		client := startTestServer(t, Features{PortForwarding: true})
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		localAddr := listener.Addr().String()
+		require.NoError(t, listener.Close())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			_ = client.ForwardLocal(ctx, localAddr, echo.Addr().String())
+		}()
+
+		var conn net.Conn
+		require.Eventually(t, func() bool {
+			conn, err = net.Dial("tcp", localAddr)
+			return err == nil
+		}, 5*time.Second, 50*time.Millisecond)
+		defer conn.Close()
+
+		_, err = conn.Write([]byte("ping"))
+		require.NoError(t, err)
+		buf := make([]byte, 4)
+		_, err = io.ReadFull(conn, buf)
+		require.NoError(t, err)
+		assert.Equal(t, "ping", string(buf))
+	})
+}
+
+func TestServer_SFTP(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		client := startTestServer(t, Features{})
+
+		_, err := client.NewSFTPClient()
+		require.Error(t, err)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		client := startTestServer(t, Features{SFTP: true})
+
+		sftpClient, err := client.NewSFTPClient()
+		require.NoError(t, err)
+		defer sftpClient.Close()
+
+		path := filepath.Join(t.TempDir(), "file.txt")
+		f, err := sftpClient.Create(path)
+		require.NoError(t, err)
+		_, err = f.Write([]byte("content"))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "content", string(data))
+	})
+}
@@ -0,0 +1,46 @@
+//go:build !windows
+
+package ssh
+
+import (
+	"fmt"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// userCommand returns a command running with the credentials of the local user
+func userCommand(localUser *user.User, name string, args ...string) (*exec.Cmd, error) {
+	cmd := exec.Command(name, args...)
+	if isCurrentUser(localUser) {
+		return cmd, nil
+	}
+
+	uid, err := strconv.ParseUint(localUser.Uid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("parse uid of %s: %w", localUser.Username, err)
+	}
+	gid, err := strconv.ParseUint(localUser.Gid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("parse gid of %s: %w", localUser.Username, err)
+	}
+
+	var groups []uint32
+	if groupIDs, err := localUser.GroupIds(); err == nil {
+		for _, id := range groupIDs {
+			if g, err := strconv.ParseUint(id, 10, 32); err == nil {
+				groups = append(groups, uint32(g))
+			}
+		}
+	}
+
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Credential: &syscall.Credential{
+			Uid:    uint32(uid),
+			Gid:    uint32(gid),
+			Groups: groups,
+		},
+	}
+	return cmd, nil
+}
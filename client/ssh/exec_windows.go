@@ -0,0 +1,15 @@
+package ssh
+
+import (
+	"fmt"
+	"os/exec"
+	"os/user"
+)
+
+// userCommand returns a command running as the local user, switching users is not supported on Windows
+func userCommand(localUser *user.User, name string, args ...string) (*exec.Cmd, error) {
+	if !isCurrentUser(localUser) {
+		return nil, fmt.Errorf("running commands as user %s is not supported on Windows", localUser.Username)
+	}
+	return exec.Command(name, args...), nil
+}
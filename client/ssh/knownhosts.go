@@ -0,0 +1,126 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/netbirdio/netbird/client/configs"
+	"github.com/netbirdio/netbird/util"
+)
+
+// KnownHostsFile is the name of the file in the state directory that caches the SSH host keys of the peers
+const KnownHostsFile = "ssh_known_hosts"
+
+// HostKey is the SSH host key of a peer along with the names and addresses it is reachable at
+type HostKey struct {
+	Hosts []string
+	// Key is the public key in the authorized_keys format as distributed by the management service
+	Key []byte
+}
+
+// DefaultKnownHostsPath returns the path to the known hosts file in the state directory,
+// it is empty on platforms without a state directory
+func DefaultKnownHostsPath() string {
+	if configs.StateDir == "" {
+		return ""
+	}
+	return filepath.Join(configs.StateDir, KnownHostsFile)
+}
+
+// WriteKnownHosts replaces the content of the known hosts file with the given host keys.
+// The file is not touched if its content is unchanged.
+func WriteKnownHosts(ctx context.Context, path string, hostKeys []HostKey) error {
+	lines := make([]string, 0, len(hostKeys))
+	for _, hostKey := range hostKeys {
+		if len(hostKey.Hosts) == 0 {
+			continue
+		}
+		key, _, _, _, err := ssh.ParseAuthorizedKey(hostKey.Key)
+		if err != nil {
+			// an invalid key of a peer must not keep the outdated keys of the others
+			log.Warnf("skipping invalid SSH host key of %s: %v", hostKey.Hosts[0], err)
+			continue
+		}
+		lines = append(lines, knownhosts.Line(hostKey.Hosts, key))
+	}
+	sort.Strings(lines)
+
+	content := []byte(strings.Join(lines, "\n") + "\n")
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, content) {
+		return nil
+	}
+
+	if err := util.WriteBytesWithRestrictedPermission(ctx, path, content); err != nil {
+		return fmt.Errorf("write known hosts: %w", err)
+	}
+	return nil
+}
+
+// NewKnownHostsCallback returns a host key callback that verifies the remote host key against the keys in the
+// known hosts file. The key is looked up by the host name first and by the remote address otherwise,
+// connections to hosts without a known key are refused.
+func NewKnownHostsCallback(path string) (ssh.HostKeyCallback, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read known hosts: %w", err)
+	}
+
+	keys, err := parseKnownHosts(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse known hosts %s: %w", path, err)
+	}
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		host := hostname
+		if h, _, err := net.SplitHostPort(hostname); err == nil {
+			host = h
+		}
+
+		expected, ok := keys[host]
+		if !ok {
+			if tcpAddr, isTCP := remote.(*net.TCPAddr); isTCP {
+				expected, ok = keys[tcpAddr.IP.String()]
+			}
+		}
+		if !ok {
+			return fmt.Errorf("no host key known for %s, the host is not a peer of this network or the peer has SSH disabled", host)
+		}
+
+		if !bytes.Equal(expected.Marshal(), key.Marshal()) {
+			return fmt.Errorf("host key verification failed for %s: the remote host presented key %s but the management service "+
+				"published %s, someone could be intercepting the connection (man-in-the-middle attack)",
+				host, ssh.FingerprintSHA256(key), ssh.FingerprintSHA256(expected))
+		}
+		return nil
+	}, nil
+}
+
+func parseKnownHosts(data []byte) (map[string]ssh.PublicKey, error) {
+	keys := make(map[string]ssh.PublicKey)
+	for len(data) > 0 {
+		_, hosts, key, _, rest, err := ssh.ParseKnownHosts(data)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, host := range hosts {
+			keys[host] = key
+		}
+		data = rest
+	}
+	return keys, nil
+}
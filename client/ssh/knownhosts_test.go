@@ -0,0 +1,96 @@
+package ssh
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func generateHostKey(t *testing.T) (ssh.PublicKey, []byte) {
+	t.Helper()
+
+	privateKey, err := GeneratePrivateKey(ED25519)
+	require.NoError(t, err)
+	publicKey, err := GeneratePublicKey(privateKey)
+	require.NoError(t, err)
+	parsed, _, _, _, err := ssh.ParseAuthorizedKey(publicKey)
+	require.NoError(t, err)
+
+	return parsed, publicKey
+}
+
+func TestKnownHostsCallback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), KnownHostsFile)
+
+	peerA, peerAKey := generateHostKey(t)
+	peerB, peerBKey := generateHostKey(t)
+	other, _ := generateHostKey(t)
+
+	err := WriteKnownHosts(context.Background(), path, []HostKey{
+		{Hosts: []string{"100.64.0.1", "peer-a.netbird.cloud"}, Key: peerAKey},
+		{Hosts: []string{"100.64.0.2"}, Key: peerBKey},
+	})
+	require.NoError(t, err)
+
+	callback, err := NewKnownHostsCallback(path)
+	require.NoError(t, err)
+
+	remoteA := &net.TCPAddr{IP: net.ParseIP("100.64.0.1"), Port: DefaultSSHPort}
+	remoteB := &net.TCPAddr{IP: net.ParseIP("100.64.0.2"), Port: DefaultSSHPort}
+	unknown := &net.TCPAddr{IP: net.ParseIP("100.64.0.3"), Port: DefaultSSHPort}
+
+	assert.NoError(t, callback("100.64.0.1:44338", remoteA, peerA), "key published for the IP should be accepted")
+	assert.NoError(t, callback("peer-a.netbird.cloud:44338", remoteA, peerA), "key published for the FQDN should be accepted")
+	assert.NoError(t, callback("peer-b:44338", remoteB, peerB), "key should be looked up by the remote address")
+
+	assert.ErrorContains(t, callback("100.64.0.1:44338", remoteA, other), "host key verification failed")
+	assert.ErrorContains(t, callback("peer-a.netbird.cloud:44338", remoteB, peerB), "host key verification failed",
+		"key of the host name should take precedence over the remote address")
+	assert.ErrorContains(t, callback("100.64.0.3:44338", unknown, other), "no host key known")
+}
+
+func TestWriteKnownHosts_Replace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), KnownHostsFile)
+
+	peerA, peerAKey := generateHostKey(t)
+	_, peerBKey := generateHostKey(t)
+
+	require.NoError(t, WriteKnownHosts(context.Background(), path, []HostKey{
+		{Hosts: []string{"100.64.0.1"}, Key: peerAKey},
+		{Hosts: []string{"100.64.0.2"}, Key: peerBKey},
+	}))
+	require.NoError(t, WriteKnownHosts(context.Background(), path, []HostKey{
+		{Hosts: []string{"100.64.0.1"}, Key: peerAKey},
+	}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	keys, err := parseKnownHosts(data)
+	require.NoError(t, err)
+	require.Len(t, keys, 1, "keys of removed peers should not be kept")
+	assert.Equal(t, peerA.Marshal(), keys["100.64.0.1"].Marshal())
+
+	require.NoError(t, WriteKnownHosts(context.Background(), path, []HostKey{
+		{Hosts: []string{"100.64.0.1"}, Key: []byte("invalid")},
+		{Hosts: []string{"100.64.0.2"}, Key: peerBKey},
+	}))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	keys, err = parseKnownHosts(data)
+	require.NoError(t, err)
+	require.Len(t, keys, 1, "invalid key should be skipped")
+	assert.Contains(t, keys, "100.64.0.2", "valid keys should be written next to an invalid one")
+
+	require.NoError(t, WriteKnownHosts(context.Background(), path, nil))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	keys, err = parseKnownHosts(data)
+	require.NoError(t, err)
+	assert.Empty(t, keys, "keys should be removed without remote peers")
+}
@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"os/user"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"github.com/creack/pty"
 	"github.com/gliderlabs/ssh"
 	log "github.com/sirupsen/logrus"
+	gossh "golang.org/x/crypto/ssh"
 )
 
 // DefaultSSHPort is the default SSH port of the NetBird's embedded SSH server
@@ -39,18 +41,40 @@ type Server interface {
 	Start() error
 	// RemoveAuthorizedKey removes SSH key of a given peer from the authorized keys
 	RemoveAuthorizedKey(peer string)
-	// AddAuthorizedKey add a given peer key to server authorized keys
-	AddAuthorizedKey(peer, newKey string) error
+	// AddAuthorizedKey add a given peer key to server authorized keys and restricts the local accounts the peer may
+	// log in as, any account is allowed if localUsers is empty
+	AddAuthorizedKey(peer, newKey string, localUsers []string) error
+	// UpdateFeatures enables or disables the optional features of the server
+	UpdateFeatures(features Features)
+	// SetSessionListener sets the listener notified about the start and the end of the SSH sessions
+	SetSessionListener(listener SessionListener)
+}
+
+// Features are the optional SSH server features, they are all disabled by default
+type Features struct {
+	// PortForwarding allows local (direct-tcpip) and remote (tcpip-forward) TCP port forwarding,
+	// the remote forwarded ports are bound to the loopback interface only
+	PortForwarding bool
+	// SFTP enables the sftp subsystem
+	SFTP bool
+	// Exec allows non-interactive command execution
+	Exec bool
 }
 
 // DefaultServer is the embedded NetBird SSH server
 type DefaultServer struct {
 	listener net.Listener
+	server   *ssh.Server
 	// authorizedKeys is ssh pub key indexed by peer WireGuard public key
 	authorizedKeys map[string]ssh.PublicKey
-	mu             sync.Mutex
-	hostKeyPEM     []byte
-	sessions       []ssh.Session
+	// localUsers are the local accounts a peer may log in as indexed by peer WireGuard public key,
+	// peers without an entry may log in as any account
+	localUsers      map[string][]string
+	mu              sync.Mutex
+	hostKeyPEM      []byte
+	sessions        []ssh.Session
+	features        Features
+	sessionListener SessionListener
 }
 
 // newDefaultServer creates new server with provided host key
@@ -60,7 +84,7 @@ func newDefaultServer(hostKeyPEM []byte, addr string) (*DefaultServer, error) {
 		return nil, err
 	}
 	allowedKeys := make(map[string]ssh.PublicKey)
-	return &DefaultServer{listener: ln, mu: sync.Mutex{}, hostKeyPEM: hostKeyPEM, authorizedKeys: allowedKeys, sessions: make([]ssh.Session, 0)}, nil
+	return &DefaultServer{listener: ln, mu: sync.Mutex{}, hostKeyPEM: hostKeyPEM, authorizedKeys: allowedKeys, localUsers: make(map[string][]string), sessions: make([]ssh.Session, 0)}, nil
 }
 
 // RemoveAuthorizedKey removes SSH key of a given peer from the authorized keys
@@ -69,27 +93,56 @@ func (srv *DefaultServer) RemoveAuthorizedKey(peer string) {
 	defer srv.mu.Unlock()
 
 	delete(srv.authorizedKeys, peer)
+	delete(srv.localUsers, peer)
 }
 
-// AddAuthorizedKey add a given peer key to server authorized keys
-func (srv *DefaultServer) AddAuthorizedKey(peer, newKey string) error {
-	srv.mu.Lock()
-	defer srv.mu.Unlock()
-
+// AddAuthorizedKey add a given peer key to server authorized keys and restricts the local accounts the peer may log in
+// as, any account is allowed if localUsers is empty. The key and the accounts are updated together, the accounts apply
+// to new connections only.
+func (srv *DefaultServer) AddAuthorizedKey(peer, newKey string, localUsers []string) error {
 	parsedKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(newKey))
 	if err != nil {
 		return err
 	}
 
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
 	srv.authorizedKeys[peer] = parsedKey
+	if len(localUsers) == 0 {
+		delete(srv.localUsers, peer)
+	} else {
+		srv.localUsers[peer] = localUsers
+	}
 	return nil
 }
 
+// UpdateFeatures enables or disables the optional features of the server, it applies to new requests only
+func (srv *DefaultServer) UpdateFeatures(features Features) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	srv.features = features
+}
+
+func (srv *DefaultServer) getFeatures() Features {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	return srv.features
+}
+
 // Stop stops SSH server.
 func (srv *DefaultServer) Stop() error {
 	srv.mu.Lock()
 	defer srv.mu.Unlock()
-	err := srv.listener.Close()
+
+	// closing the server also closes the listener and the active connections including the forwarded ones
+	closer := io.Closer(srv.listener)
+	if srv.server != nil {
+		closer = srv.server
+	}
+	err := closer.Close()
 	if err != nil {
 		return err
 	}
@@ -105,15 +158,31 @@ func (srv *DefaultServer) Stop() error {
 
 func (srv *DefaultServer) publicKeyHandler(ctx ssh.Context, key ssh.PublicKey) bool {
 	srv.mu.Lock()
-	defer srv.mu.Unlock()
+	peer, ok := srv.authorizedPeer(ctx.User(), key)
+	srv.mu.Unlock()
+
+	if ok {
+		ctx.SetValue(peerContextKey, peer)
+	}
+	return ok
+}
+
+// authorizedPeer returns the peer owning the key if it may log in as the local user
+func (srv *DefaultServer) authorizedPeer(localUser string, key ssh.PublicKey) (string, bool) {
+	for peer, allowed := range srv.authorizedKeys {
+		if !ssh.KeysEqual(allowed, key) {
+			continue
+		}
 
-	for _, allowed := range srv.authorizedKeys {
-		if ssh.KeysEqual(allowed, key) {
-			return true
+		localUsers, restricted := srv.localUsers[peer]
+		if restricted && !slices.Contains(localUsers, localUser) {
+			log.Warnf("denied SSH login of peer %s as %s, the peer may log in as %s only", peer, localUser, strings.Join(localUsers, ", "))
+			return "", false
 		}
+		return peer, true
 	}
 
-	return false
+	return "", false
 }
 
 func prepareUserEnv(user *user.User, shell string) []string {
@@ -158,6 +227,11 @@ func (srv *DefaultServer) sessionHandler(session ssh.Session) {
 		return
 	}
 
+	if session.RawCommand() != "" {
+		srv.execHandler(session, localUser)
+		return
+	}
+
 	ptyReq, winCh, isPty := session.Pty()
 	if isPty {
 		loginCmd, loginArgs, err := getLoginCmd(localUser.Username, session.RemoteAddr())
@@ -251,9 +325,34 @@ func (srv *DefaultServer) stdInOut(file *os.File, session ssh.Session) {
 func (srv *DefaultServer) Start() error {
 	log.Infof("starting SSH server on addr: %s", srv.listener.Addr().String())
 
-	publicKeyOption := ssh.PublicKeyAuth(srv.publicKeyHandler)
-	hostKeyPEM := ssh.HostKeyPEM(srv.hostKeyPEM)
-	err := ssh.Serve(srv.listener, srv.sessionHandler, publicKeyOption, hostKeyPEM)
+	forwardHandler := &ssh.ForwardedTCPHandler{}
+	server := &ssh.Server{
+		Handler:                       srv.sessionHandler,
+		ServerConfigCallback:          srv.serverConfig,
+		PublicKeyHandler:              srv.publicKeyHandler,
+		LocalPortForwardingCallback:   srv.localPortForwardingCallback,
+		ReversePortForwardingCallback: srv.reversePortForwardingCallback,
+		ChannelHandlers: map[string]ssh.ChannelHandler{
+			"session":      ssh.DefaultSessionHandler,
+			"direct-tcpip": ssh.DirectTCPIPHandler,
+		},
+		RequestHandlers: map[string]ssh.RequestHandler{
+			"tcpip-forward":        loopbackForwardHandler(forwardHandler),
+			"cancel-tcpip-forward": loopbackForwardHandler(forwardHandler),
+		},
+		SubsystemHandlers: map[string]ssh.SubsystemHandler{
+			"sftp": srv.sftpHandler,
+		},
+	}
+	if err := server.SetOption(ssh.HostKeyPEM(srv.hostKeyPEM)); err != nil {
+		return fmt.Errorf("set host key: %w", err)
+	}
+
+	srv.mu.Lock()
+	srv.server = server
+	srv.mu.Unlock()
+
+	err := server.Serve(srv.listener)
 	if err != nil {
 		return err
 	}
@@ -261,6 +360,61 @@ func (srv *DefaultServer) Start() error {
 	return nil
 }
 
+func (srv *DefaultServer) localPortForwardingCallback(ctx ssh.Context, host string, port uint32) bool {
+	if !srv.getFeatures().PortForwarding {
+		log.Infof("denied local port forwarding to %s:%d for %s from %s, port forwarding is disabled", host, port, ctx.User(), ctx.RemoteAddr())
+		return false
+	}
+	log.Infof("local port forwarding to %s:%d for %s from %s", host, port, ctx.User(), ctx.RemoteAddr())
+	return true
+}
+
+func (srv *DefaultServer) reversePortForwardingCallback(ctx ssh.Context, host string, port uint32) bool {
+	if !srv.getFeatures().PortForwarding {
+		log.Infof("denied remote port forwarding from %s:%d for %s from %s, port forwarding is disabled", host, port, ctx.User(), ctx.RemoteAddr())
+		return false
+	}
+	log.Infof("remote port forwarding from %s:%d for %s from %s", host, port, ctx.User(), ctx.RemoteAddr())
+	return true
+}
+
+// remoteForwardRequest is the payload of the tcpip-forward and cancel-tcpip-forward requests (RFC 4254 7.1)
+type remoteForwardRequest struct {
+	BindAddr string
+	BindPort uint32
+}
+
+// loopbackForwardHandler binds the remote forwarded ports to the loopback interface only, like GatewayPorts no of
+// OpenSSH, so the forwarded ports are not exposed to the network of the peer
+func loopbackForwardHandler(handler *ssh.ForwardedTCPHandler) ssh.RequestHandler {
+	return func(ctx ssh.Context, srv *ssh.Server, req *gossh.Request) (bool, []byte) {
+		var payload remoteForwardRequest
+		if err := gossh.Unmarshal(req.Payload, &payload); err != nil {
+			return false, nil
+		}
+
+		if bindAddr := loopbackBindAddr(payload.BindAddr); bindAddr != payload.BindAddr {
+			log.Debugf("binding remote forwarded port %d of %s to %s instead of %q", payload.BindPort, ctx.User(), bindAddr, payload.BindAddr)
+			payload.BindAddr = bindAddr
+			rewritten := *req
+			rewritten.Payload = gossh.Marshal(&payload)
+			req = &rewritten
+		}
+		return handler.HandleSSHRequest(ctx, srv, req)
+	}
+}
+
+// loopbackBindAddr returns the bind address if it is a loopback address, otherwise the IPv4 loopback address
+func loopbackBindAddr(addr string) string {
+	if addr == "localhost" {
+		return addr
+	}
+	if ip := net.ParseIP(addr); ip != nil && ip.IsLoopback() {
+		return addr
+	}
+	return "127.0.0.1"
+}
+
 func getUserShell(userID string) string {
 	if runtime.GOOS == "linux" {
 		output, _ := exec.Command("getent", "passwd", userID).Output()
@@ -7,8 +7,20 @@ type MockServer struct {
 	Ctx                     context.Context
 	StopFunc                func() error
 	StartFunc               func() error
-	AddAuthorizedKeyFunc    func(peer, newKey string) error
+	AddAuthorizedKeyFunc    func(peer, newKey string, localUsers []string) error
 	RemoveAuthorizedKeyFunc func(peer string)
+	UpdateFeaturesFunc      func(features Features)
+}
+
+// SetSessionListener does nothing as the mock server has no sessions
+func (srv *MockServer) SetSessionListener(SessionListener) {}
+
+// UpdateFeatures enables or disables the optional features of the server
+func (srv *MockServer) UpdateFeatures(features Features) {
+	if srv.UpdateFeaturesFunc == nil {
+		return
+	}
+	srv.UpdateFeaturesFunc(features)
 }
 
 // RemoveAuthorizedKey removes SSH key of a given peer from the authorized keys
@@ -20,11 +32,11 @@ func (srv *MockServer) RemoveAuthorizedKey(peer string) {
 }
 
 // AddAuthorizedKey add a given peer key to server authorized keys
-func (srv *MockServer) AddAuthorizedKey(peer, newKey string) error {
+func (srv *MockServer) AddAuthorizedKey(peer, newKey string, localUsers []string) error {
 	if srv.AddAuthorizedKeyFunc == nil {
 		return nil
 	}
-	return srv.AddAuthorizedKeyFunc(peer, newKey)
+	return srv.AddAuthorizedKeyFunc(peer, newKey, localUsers)
 }
 
 // Stop stops SSH server.
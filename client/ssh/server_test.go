@@ -1,11 +1,16 @@
 package ssh
 
 import (
+	"context"
 	"fmt"
-	"github.com/stretchr/testify/assert"
-	"golang.org/x/crypto/ssh"
+	"net"
 	"strings"
+	"sync"
 	"testing"
+
+	gliderssh "github.com/gliderlabs/ssh"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
 )
 
 func TestServer_AddAuthorizedKey(t *testing.T) {
@@ -31,7 +36,7 @@ func TestServer_AddAuthorizedKey(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		err = server.AddAuthorizedKey(peer, string(remotePubKey))
+		err = server.AddAuthorizedKey(peer, string(remotePubKey), nil)
 		if err != nil {
 			t.Error(err)
 		}
@@ -67,7 +72,7 @@ func TestServer_RemoveAuthorizedKey(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	err = server.AddAuthorizedKey("remotePeer", string(remotePubKey))
+	err = server.AddAuthorizedKey("remotePeer", string(remotePubKey), nil)
 	if err != nil {
 		t.Error(err)
 	}
@@ -105,7 +110,7 @@ func TestServer_PubKeyHandler(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		err = server.AddAuthorizedKey(peer, string(remotePubKey))
+		err = server.AddAuthorizedKey(peer, string(remotePubKey), nil)
 		if err != nil {
 			t.Error(err)
 		}
@@ -113,9 +118,53 @@ func TestServer_PubKeyHandler(t *testing.T) {
 	}
 
 	for _, key := range keys {
-		accepted := server.publicKeyHandler(nil, key)
+		accepted := server.publicKeyHandler(newTestContext("root"), key)
 
 		assert.Truef(t, accepted, "expecting SSH connection to be accepted for a given SSH key %s", string(ssh.MarshalAuthorizedKey(key)))
 	}
 
 }
+
+// testContext is a minimal connection context for calling the handlers directly
+type testContext struct {
+	context.Context
+	sync.Mutex
+	user   string
+	values map[any]any
+}
+
+func newTestContext(user string) *testContext {
+	return &testContext{Context: context.Background(), user: user, values: make(map[any]any)}
+}
+
+func (c *testContext) User() string                        { return c.user }
+func (c *testContext) SessionID() string                   { return "" }
+func (c *testContext) ClientVersion() string               { return "" }
+func (c *testContext) ServerVersion() string               { return "" }
+func (c *testContext) RemoteAddr() net.Addr                { return nil }
+func (c *testContext) LocalAddr() net.Addr                 { return nil }
+func (c *testContext) Permissions() *gliderssh.Permissions { return &gliderssh.Permissions{} }
+func (c *testContext) SetValue(key, value any)             { c.values[key] = value }
+
+func (c *testContext) Value(key any) any {
+	if v, ok := c.values[key]; ok {
+		return v
+	}
+	return c.Context.Value(key)
+}
+
+func TestLoopbackBindAddr(t *testing.T) {
+	tests := map[string]string{
+		"":            "127.0.0.1",
+		"0.0.0.0":     "127.0.0.1",
+		"::":          "127.0.0.1",
+		"100.64.0.1":  "127.0.0.1",
+		"example.com": "127.0.0.1",
+		"localhost":   "localhost",
+		"127.0.0.1":   "127.0.0.1",
+		"::1":         "::1",
+	}
+	for addr, expected := range tests {
+		assert.Equal(t, expected, loopbackBindAddr(addr), "bind address %q", addr)
+	}
+}
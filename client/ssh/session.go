@@ -0,0 +1,98 @@
+package ssh
+
+import (
+	"time"
+
+	"github.com/gliderlabs/ssh"
+	log "github.com/sirupsen/logrus"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+type contextKey string
+
+const (
+	// peerContextKey holds the WireGuard public key of the peer owning the offered key in the connection context
+	peerContextKey contextKey = "netbird-peer"
+	// sessionContextKey marks the connections with a tracked session
+	sessionContextKey contextKey = "netbird-session"
+)
+
+// SessionEvent is the start or the end of an SSH session. A session lasts as long as the connection of the peer,
+// it includes the terminal, command, SFTP and port forwarding requests of the connection.
+type SessionEvent struct {
+	ID string
+	// Ended is true for the end of the session and false for its start
+	Ended     bool
+	Timestamp time.Time
+	// Peer is the WireGuard public key of the remote peer
+	Peer string
+	// LocalUser is the local account the session is logged in as
+	LocalUser string
+	// Duration of the session, set for the end events
+	Duration time.Duration
+}
+
+// SessionListener is notified about the start and the end of the SSH sessions
+type SessionListener func(event SessionEvent)
+
+// SetSessionListener sets the listener notified about the start and the end of the SSH sessions
+func (srv *DefaultServer) SetSessionListener(listener SessionListener) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	srv.sessionListener = listener
+}
+
+// serverConfig returns the per-connection server config. The session of the peer is tracked once the authentication
+// succeeded, the public key handler alone is not sufficient as it is also called for keys the client does not sign with.
+func (srv *DefaultServer) serverConfig(ctx ssh.Context) *gossh.ServerConfig {
+	return &gossh.ServerConfig{
+		AuthLogCallback: func(_ gossh.ConnMetadata, method string, err error) {
+			if err != nil || method != "publickey" {
+				return
+			}
+			if peer, ok := ctx.Value(peerContextKey).(string); ok {
+				srv.trackSession(ctx, peer)
+			}
+		},
+	}
+}
+
+// trackSession notifies the session listener about the start of the session of an authenticated peer
+// and about its end once the connection is closed
+func (srv *DefaultServer) trackSession(ctx ssh.Context, peer string) {
+	if ctx.Value(sessionContextKey) != nil {
+		return
+	}
+	ctx.SetValue(sessionContextKey, true)
+
+	start := SessionEvent{
+		ID:        ctx.SessionID(),
+		Timestamp: time.Now(),
+		Peer:      peer,
+		LocalUser: ctx.User(),
+	}
+	log.Infof("SSH session %s started for %s from %s", start.ID, start.LocalUser, ctx.RemoteAddr())
+	srv.notifySession(start)
+
+	go func() {
+		<-ctx.Done()
+
+		end := start
+		end.Ended = true
+		end.Timestamp = time.Now()
+		end.Duration = end.Timestamp.Sub(start.Timestamp)
+		log.Infof("SSH session %s ended for %s from %s after %s", end.ID, end.LocalUser, ctx.RemoteAddr(), end.Duration.Round(time.Second))
+		srv.notifySession(end)
+	}()
+}
+
+func (srv *DefaultServer) notifySession(event SessionEvent) {
+	srv.mu.Lock()
+	listener := srv.sessionListener
+	srv.mu.Unlock()
+
+	if listener != nil {
+		listener(event)
+	}
+}
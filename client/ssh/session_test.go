@@ -0,0 +1,72 @@
+//go:build !windows
+
+package ssh
+
+import (
+	"os/user"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_LocalUsers(t *testing.T) {
+	current, err := user.Current()
+	require.NoError(t, err)
+
+	server, clientKey := newTestServer(t, func(*DefaultServer) {})
+	clientPubKey, err := GeneratePublicKey(clientKey)
+	require.NoError(t, err)
+
+	require.NoError(t, server.AddAuthorizedKey("remotePeer", string(clientPubKey), []string{"deploy"}))
+	_, err = dialTestServer(t, server, clientKey, current.Username)
+	require.Error(t, err, "the peer should not be able to log in as an account it is not mapped to")
+
+	require.NoError(t, server.AddAuthorizedKey("remotePeer", string(clientPubKey), []string{"deploy", current.Username}))
+	_, err = dialTestServer(t, server, clientKey, current.Username)
+	require.NoError(t, err)
+
+	require.NoError(t, server.AddAuthorizedKey("remotePeer", string(clientPubKey), nil))
+	_, err = dialTestServer(t, server, clientKey, "any")
+	require.NoError(t, err, "the peer should be able to log in as any account without a mapping")
+}
+
+func TestServer_SessionEvents(t *testing.T) {
+	current, err := user.Current()
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var events []SessionEvent
+	server, clientKey := newTestServer(t, func(server *DefaultServer) {
+		server.SetSessionListener(func(event SessionEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, event)
+		})
+	})
+
+	client, err := dialTestServer(t, server, clientKey, current.Username)
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, client.Close())
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.False(t, events[0].Ended)
+	assert.True(t, events[1].Ended)
+	for _, event := range events {
+		assert.Equal(t, "remotePeer", event.Peer)
+		assert.Equal(t, current.Username, event.LocalUser)
+		assert.NotEmpty(t, event.ID)
+	}
+	assert.Equal(t, events[0].ID, events[1].ID)
+	assert.Positive(t, events[1].Duration)
+}
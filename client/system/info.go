@@ -40,6 +40,17 @@ type File struct {
 	ProcessIsRunning bool
 }
 
+type Service struct {
+	Name    string
+	Running bool
+}
+
+type Port struct {
+	Port      uint16
+	Protocol  string
+	Listening bool
+}
+
 // Info is an object that contains machine information
 // Most of the code is taken from https://github.com/matishsiao/goInfo
 type Info struct {
@@ -58,7 +69,11 @@ type Info struct {
 	SystemProductName  string
 	SystemManufacturer string
 	Environment        Environment
-	Files              []File // for posture checks
+	Files              []File    // for posture checks
+	Services           []Service // for posture checks
+	Ports              []Port    // for posture checks
+	DiskEncrypted      bool      // for posture checks
+	FirewallEnabled    bool      // for posture checks
 }
 
 // StaticInfo is an object that contains machine information that does not change
@@ -152,8 +167,16 @@ func isDuplicated(addresses []NetworkAddress, addr NetworkAddress) bool {
 // GetInfoWithChecks retrieves and parses the system information with applied checks.
 func GetInfoWithChecks(ctx context.Context, checks []*proto.Checks) (*Info, error) {
 	processCheckPaths := make([]string, 0)
+	serviceCheckNames := make([]string, 0)
+	portChecks := make([]Port, 0)
+	hostSecurityCheck := false
 	for _, check := range checks {
+		hostSecurityCheck = hostSecurityCheck || check.GetHostSecurity()
 		processCheckPaths = append(processCheckPaths, check.GetFiles()...)
+		serviceCheckNames = append(serviceCheckNames, check.GetServices()...)
+		for _, port := range check.GetPorts() {
+			portChecks = append(portChecks, Port{Port: uint16(port.GetPort()), Protocol: port.GetProtocol()})
+		}
 	}
 
 	files, err := checkFileAndProcess(processCheckPaths)
@@ -161,8 +184,24 @@ func GetInfoWithChecks(ctx context.Context, checks []*proto.Checks) (*Info, erro
 		return nil, err
 	}
 
+	services, err := checkServices(ctx, serviceCheckNames)
+	if err != nil {
+		return nil, err
+	}
+
+	ports, err := checkListeningPorts(portChecks)
+	if err != nil {
+		return nil, err
+	}
+
 	info := GetInfo(ctx)
 	info.Files = files
+	info.Services = services
+	info.Ports = ports
+
+	if hostSecurityCheck {
+		info.DiskEncrypted, info.FirewallEnabled = checkHostSecurity(ctx)
+	}
 
 	return info, nil
 }
@@ -52,6 +52,21 @@ func checkFileAndProcess(paths []string) ([]File, error) {
 	return []File{}, nil
 }
 
+// checkServices checks if the system services are running.
+func checkServices(_ context.Context, _ []string) ([]Service, error) {
+	return []Service{}, nil
+}
+
+// checkListeningPorts checks if a socket is listening on the given ports.
+func checkListeningPorts(_ []Port) ([]Port, error) {
+	return []Port{}, nil
+}
+
+// checkHostSecurity returns whether the disk is encrypted and a firewall is enabled.
+func checkHostSecurity(_ context.Context) (bool, bool) {
+	return false, false
+}
+
 func serial() string {
 	// try to fetch serial ID using different properties
 	properties := []string{"ril.serialnumber", "ro.serialno", "ro.boot.serialno", "sys.serialnumber"}
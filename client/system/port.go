@@ -0,0 +1,38 @@
+//go:build windows || (linux && !android) || (darwin && !ios) || freebsd
+
+package system
+
+import (
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+// checkListeningPorts checks if a socket is listening on the given ports.
+func checkListeningPorts(ports []Port) ([]Port, error) {
+	result := make([]Port, len(ports))
+	if len(ports) == 0 {
+		return result, nil
+	}
+
+	listening := make(map[Port]bool)
+	for _, protocol := range []string{"tcp", "udp"} {
+		connections, err := net.Connections(protocol)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, conn := range connections {
+			// UDP sockets have no state, a socket without a remote address is receiving from any peer
+			if protocol == "tcp" && conn.Status != "LISTEN" || protocol == "udp" && conn.Raddr.Port != 0 {
+				continue
+			}
+			listening[Port{Port: uint16(conn.Laddr.Port), Protocol: protocol}] = true
+		}
+	}
+
+	for i, port := range ports {
+		port.Listening = listening[Port{Port: port.Port, Protocol: port.Protocol}]
+		result[i] = port
+	}
+
+	return result, nil
+}
@@ -0,0 +1,29 @@
+//go:build windows || (linux && !android) || (darwin && !ios) || freebsd
+
+package system
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckListeningPorts(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	port := uint16(listener.Addr().(*net.TCPAddr).Port)
+
+	ports, err := checkListeningPorts([]Port{
+		{Port: port, Protocol: "tcp"},
+		{Port: port, Protocol: "udp"},
+	})
+	require.NoError(t, err)
+	require.Len(t, ports, 2)
+
+	assert.True(t, ports[0].Listening, "tcp listener should be reported")
+	assert.False(t, ports[1].Listening, "udp port shouldn't be reported for a tcp listener")
+}
@@ -0,0 +1,20 @@
+//go:build !ios
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+)
+
+// checkHostSecurity returns whether the disk is encrypted and the application firewall is enabled.
+// The disk encryption isn't collected on macOS yet.
+func checkHostSecurity(ctx context.Context) (bool, bool) {
+	out, err := exec.CommandContext(ctx, "/usr/libexec/ApplicationFirewall/socketfilterfw", "--getglobalstate").Output()
+	if err != nil {
+		return false, false
+	}
+
+	return false, bytes.Contains(out, []byte("enabled"))
+}
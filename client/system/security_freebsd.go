@@ -0,0 +1,13 @@
+//go:build freebsd
+
+package system
+
+import (
+	"context"
+)
+
+// checkHostSecurity returns whether the disk is encrypted and a firewall is enabled.
+// The host security state isn't collected on FreeBSD yet.
+func checkHostSecurity(_ context.Context) (bool, bool) {
+	return false, false
+}
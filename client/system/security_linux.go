@@ -0,0 +1,148 @@
+//go:build !android
+
+package system
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	mountInfoPath = "/proc/self/mountinfo"
+	sysBlockPath  = "/sys/class/block"
+	ufwConfigPath = "/etc/ufw/ufw.conf"
+
+	// luksUUIDPrefix is the prefix of the device mapper UUID of dm-crypt devices
+	luksUUIDPrefix = "CRYPT-"
+	// maxDeviceDepth limits the walk through stacked block devices, e.g. LVM on LUKS on RAID
+	maxDeviceDepth = 8
+)
+
+// checkHostSecurity returns whether the disk of the root filesystem is encrypted with LUKS and a firewall is enabled.
+func checkHostSecurity(ctx context.Context) (bool, bool) {
+	diskEncrypted, err := isRootDiskEncrypted()
+	if err != nil {
+		log.Debugf("failed to check the disk encryption: %v", err)
+	}
+
+	return diskEncrypted, isFirewallEnabled(ctx)
+}
+
+// isRootDiskEncrypted checks if the block device of the root filesystem is on top of a dm-crypt device
+func isRootDiskEncrypted() (bool, error) {
+	file, err := os.Open(mountInfoPath)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	source, err := rootMountSource(file)
+	if err != nil {
+		return false, err
+	}
+
+	if !strings.HasPrefix(source, "/dev/") {
+		return false, nil
+	}
+
+	device, err := filepath.EvalSymlinks(source)
+	if err != nil {
+		return false, fmt.Errorf("resolve root device %s: %w", source, err)
+	}
+
+	return isBlockDeviceEncrypted(sysBlockPath, filepath.Base(device), 0), nil
+}
+
+// rootMountSource returns the mount source of the root filesystem from the mountinfo
+func rootMountSource(r io.Reader) (string, error) {
+	var source string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		// the optional fields are terminated by a single hyphen followed by the filesystem type and the mount source
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || fields[4] != "/" {
+			continue
+		}
+		for i := 5; i < len(fields)-2; i++ {
+			if fields[i] == "-" {
+				// the last mount on the root path hides the previous ones
+				source = fields[i+2]
+				break
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	if source == "" {
+		return "", errors.New("root filesystem not found in mountinfo")
+	}
+	return source, nil
+}
+
+// isBlockDeviceEncrypted checks if the block device or any of the devices it is built on is a dm-crypt device
+func isBlockDeviceEncrypted(sysBlock, name string, depth int) bool {
+	if depth > maxDeviceDepth {
+		return false
+	}
+
+	uuid, err := os.ReadFile(filepath.Join(sysBlock, name, "dm", "uuid"))
+	if err == nil && strings.HasPrefix(string(uuid), luksUUIDPrefix) {
+		return true
+	}
+
+	slaves, err := os.ReadDir(filepath.Join(sysBlock, name, "slaves"))
+	if err != nil {
+		return false
+	}
+	for _, slave := range slaves {
+		if isBlockDeviceEncrypted(sysBlock, slave.Name(), depth+1) {
+			return true
+		}
+	}
+	return false
+}
+
+// isFirewallEnabled checks if one of the common firewall frontends is active
+func isFirewallEnabled(ctx context.Context) bool {
+	services, err := checkServices(ctx, []string{"firewalld.service", "nftables.service"})
+	if err == nil {
+		for _, service := range services {
+			if service.Running {
+				return true
+			}
+		}
+	}
+
+	return isUFWEnabled(ctx)
+}
+
+// isUFWEnabled checks the ufw configuration as the ufw unit stays active after the firewall has been disabled
+func isUFWEnabled(ctx context.Context) bool {
+	if _, err := exec.LookPath("ufw"); err != nil {
+		return false
+	}
+
+	config, err := os.ReadFile(ufwConfigPath)
+	if err != nil {
+		return false
+	}
+
+	for _, line := range strings.Split(string(config), "\n") {
+		if strings.TrimSpace(line) == "ENABLED=yes" {
+			services, err := checkServices(ctx, []string{"ufw.service"})
+			return err == nil && len(services) == 1 && services[0].Running
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+//go:build !android
+
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRootMountSource(t *testing.T) {
+	mountInfo := `22 28 0:21 / /sys rw,nosuid,nodev,noexec,relatime shared:7 - sysfs sysfs rw
+1 0 253:0 / / rw,relatime - ext4 /dev/sda1 rw
+28 1 253:1 / / rw,relatime shared:1 - ext4 /dev/mapper/vg-root rw,errors=remount-ro
+29 28 0:5 / /dev rw,nosuid,relatime shared:2 - devtmpfs udev rw
+`
+	source, err := rootMountSource(strings.NewReader(mountInfo))
+	require.NoError(t, err)
+	assert.Equal(t, "/dev/mapper/vg-root", source)
+
+	_, err = rootMountSource(strings.NewReader("29 28 0:5 / /dev rw - devtmpfs udev rw\n"))
+	assert.Error(t, err)
+}
+
+func TestIsBlockDeviceEncrypted(t *testing.T) {
+	sysBlock := t.TempDir()
+
+	// dm-1 is an LVM volume on top of the LUKS device dm-0 on sda2, dm-2 is an LVM volume on sda3
+	writeFile(t, filepath.Join(sysBlock, "dm-0", "dm", "uuid"), "CRYPT-LUKS2-0a1b2c3d-cryptroot\n")
+	makeDir(t, filepath.Join(sysBlock, "dm-0", "slaves", "sda2"))
+	writeFile(t, filepath.Join(sysBlock, "dm-1", "dm", "uuid"), "LVM-abcdef\n")
+	makeDir(t, filepath.Join(sysBlock, "dm-1", "slaves", "dm-0"))
+	writeFile(t, filepath.Join(sysBlock, "dm-2", "dm", "uuid"), "LVM-ghijkl\n")
+	makeDir(t, filepath.Join(sysBlock, "dm-2", "slaves", "sda3"))
+	makeDir(t, filepath.Join(sysBlock, "sda2"))
+	makeDir(t, filepath.Join(sysBlock, "sda3"))
+
+	assert.True(t, isBlockDeviceEncrypted(sysBlock, "dm-0", 0), "LUKS device")
+	assert.True(t, isBlockDeviceEncrypted(sysBlock, "dm-1", 0), "LVM on LUKS")
+	assert.False(t, isBlockDeviceEncrypted(sysBlock, "dm-2", 0), "LVM on plain partition")
+	assert.False(t, isBlockDeviceEncrypted(sysBlock, "sda3", 0), "plain partition")
+	assert.False(t, isBlockDeviceEncrypted(sysBlock, "missing", 0), "missing device")
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	makeDir(t, filepath.Dir(path))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func makeDir(t *testing.T, path string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(path, 0o755))
+}
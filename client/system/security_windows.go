@@ -0,0 +1,31 @@
+package system
+
+import (
+	"context"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+const firewallPolicyKey = `SYSTEM\CurrentControlSet\Services\SharedAccess\Parameters\FirewallPolicy\`
+
+// checkHostSecurity returns whether the disk is encrypted and the Windows firewall is enabled for all profiles.
+// The disk encryption isn't collected on Windows yet.
+func checkHostSecurity(_ context.Context) (bool, bool) {
+	for _, profile := range []string{"DomainProfile", "StandardProfile", "PublicProfile"} {
+		if !isFirewallProfileEnabled(profile) {
+			return false, false
+		}
+	}
+	return false, true
+}
+
+func isFirewallProfileEnabled(profile string) bool {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, firewallPolicyKey+profile, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	defer key.Close()
+
+	enabled, _, err := key.GetIntegerValue("EnableFirewall")
+	return err == nil && enabled == 1
+}
@@ -0,0 +1,29 @@
+//go:build !ios
+
+package system
+
+import (
+	"context"
+	"os/exec"
+	"regexp"
+)
+
+// launchdPIDRegex matches the PID of a running launchd service in the output of launchctl list <label>
+var launchdPIDRegex = regexp.MustCompile(`"PID"\s*=\s*\d+;`)
+
+// checkServices checks if the launchd services are running.
+func checkServices(ctx context.Context, labels []string) ([]Service, error) {
+	services := make([]Service, len(labels))
+	for i, label := range labels {
+		service := Service{Name: label}
+
+		// launchctl fails if the service is not loaded
+		out, err := exec.CommandContext(ctx, "launchctl", "list", label).Output()
+		if err == nil {
+			service.Running = launchdPIDRegex.Match(out)
+		}
+		services[i] = service
+	}
+
+	return services, nil
+}
@@ -0,0 +1,20 @@
+//go:build freebsd
+
+package system
+
+import (
+	"context"
+	"os/exec"
+)
+
+// checkServices checks if the rc.d services are running.
+func checkServices(ctx context.Context, names []string) ([]Service, error) {
+	services := make([]Service, len(names))
+	for i, name := range names {
+		// status exits with 0 only if the service is running
+		err := exec.CommandContext(ctx, "service", name, "status").Run()
+		services[i] = Service{Name: name, Running: err == nil}
+	}
+
+	return services, nil
+}
@@ -0,0 +1,33 @@
+//go:build !android
+
+package system
+
+import (
+	"context"
+	"os/exec"
+)
+
+// checkServices checks if the systemd units are active.
+func checkServices(ctx context.Context, names []string) ([]Service, error) {
+	services := make([]Service, len(names))
+	if len(names) == 0 {
+		return services, nil
+	}
+
+	systemctl, err := exec.LookPath("systemctl")
+	if err != nil {
+		// without systemd there is no service manager to ask, so none of the services is considered running
+		for i, name := range names {
+			services[i] = Service{Name: name}
+		}
+		return services, nil
+	}
+
+	for i, name := range names {
+		// is-active exits with 0 only if the unit is active
+		err := exec.CommandContext(ctx, systemctl, "is-active", "--quiet", name).Run()
+		services[i] = Service{Name: name, Running: err == nil}
+	}
+
+	return services, nil
+}
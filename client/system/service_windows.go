@@ -0,0 +1,47 @@
+package system
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// checkServices checks if the Windows services are running.
+func checkServices(_ context.Context, names []string) ([]Service, error) {
+	services := make([]Service, len(names))
+	if len(names) == 0 {
+		return services, nil
+	}
+
+	// connect with the least privileges, which are sufficient to query the service status
+	h, err := windows.OpenSCManager(nil, nil, windows.SC_MANAGER_CONNECT)
+	if err != nil {
+		return nil, fmt.Errorf("connect to service manager: %w", err)
+	}
+	m := &mgr.Mgr{Handle: h}
+	defer m.Disconnect() //nolint:errcheck
+
+	for i, name := range names {
+		services[i] = Service{Name: name, Running: isServiceRunning(m, name)}
+	}
+
+	return services, nil
+}
+
+func isServiceRunning(m *mgr.Mgr, name string) bool {
+	h, err := windows.OpenService(m.Handle, windows.StringToUTF16Ptr(name), windows.SERVICE_QUERY_STATUS)
+	if err != nil {
+		return false
+	}
+	s := &mgr.Service{Name: name, Handle: h}
+	defer s.Close()
+
+	serviceStatus, err := s.Query()
+	if err != nil {
+		return false
+	}
+	return serviceStatus.State == svc.Running
+}
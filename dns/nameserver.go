@@ -13,6 +13,10 @@ const (
 	InvalidNameServerType NameServerType = iota
 	// UDPNameServerType udp nameserver type
 	UDPNameServerType
+	// TLSNameServerType DNS-over-TLS nameserver type
+	TLSNameServerType
+	// HTTPSNameServerType DNS-over-HTTPS nameserver type
+	HTTPSNameServerType
 )
 
 const (
@@ -22,6 +26,12 @@ const (
 	InvalidNameServerTypeString = "invalid"
 	// UDPNameServerTypeString udp nameserver type as string
 	UDPNameServerTypeString = "udp"
+	// TLSNameServerTypeString DNS-over-TLS nameserver type as string
+	TLSNameServerTypeString = "tls"
+	// HTTPSNameServerTypeString DNS-over-HTTPS nameserver type as string
+	HTTPSNameServerTypeString = "https"
+	// DefaultHTTPSPath is the URL path of the DNS-over-HTTPS endpoint used if a nameserver doesn't set one
+	DefaultHTTPSPath = "/dns-query"
 )
 
 // NameServerType nameserver type
@@ -32,6 +42,10 @@ func (n NameServerType) String() string {
 	switch n {
 	case UDPNameServerType:
 		return UDPNameServerTypeString
+	case TLSNameServerType:
+		return TLSNameServerTypeString
+	case HTTPSNameServerType:
+		return HTTPSNameServerTypeString
 	default:
 		return InvalidNameServerTypeString
 	}
@@ -42,6 +56,10 @@ func ToNameServerType(typeString string) NameServerType {
 	switch typeString {
 	case UDPNameServerTypeString:
 		return UDPNameServerType
+	case TLSNameServerTypeString:
+		return TLSNameServerType
+	case HTTPSNameServerTypeString:
+		return HTTPSNameServerType
 	default:
 		return InvalidNameServerType
 	}
@@ -79,6 +97,10 @@ type NameServer struct {
 	NSType NameServerType
 	// Port nameserver listening port
 	Port int
+	// ServerName is the name the certificate of an encrypted nameserver is verified against, the IP if empty
+	ServerName string
+	// Path is the URL path of a DNS-over-HTTPS nameserver, DefaultHTTPSPath if empty
+	Path string
 }
 
 // EventMeta returns activity event meta related to the nameserver group
@@ -89,9 +111,11 @@ func (g *NameServerGroup) EventMeta() map[string]any {
 // Copy copies a nameserver object
 func (n *NameServer) Copy() *NameServer {
 	return &NameServer{
-		IP:     n.IP,
-		NSType: n.NSType,
-		Port:   n.Port,
+		IP:         n.IP,
+		NSType:     n.NSType,
+		Port:       n.Port,
+		ServerName: n.ServerName,
+		Path:       n.Path,
 	}
 }
 
@@ -99,10 +123,18 @@ func (n *NameServer) Copy() *NameServer {
 func (n *NameServer) IsEqual(other *NameServer) bool {
 	return other.IP == n.IP &&
 		other.NSType == n.NSType &&
-		other.Port == n.Port
+		other.Port == n.Port &&
+		other.ServerName == n.ServerName &&
+		other.Path == n.Path
 }
 
-// ParseNameServerURL parses a nameserver url in the format <type>://<ip>:<port>, e.g., udp://1.1.1.1:53
+// IsEncrypted returns true if the queries to the nameserver are encrypted
+func (n *NameServer) IsEncrypted() bool {
+	return n.NSType == TLSNameServerType || n.NSType == HTTPSNameServerType
+}
+
+// ParseNameServerURL parses a nameserver url in the format <type>://<ip>:<port>[/path], e.g., udp://1.1.1.1:53 or
+// https://1.1.1.1:443/dns-query. The path is only allowed for the https type.
 func ParseNameServerURL(nsURL string) (NameServer, error) {
 	parsedURL, err := url.Parse(nsURL)
 	if err != nil {
@@ -129,6 +161,13 @@ func ParseNameServerURL(nsURL string) (NameServer, error) {
 
 	ns.IP = parsedAddr
 
+	if parsedURL.Path != "" && parsedURL.Path != "/" {
+		if nsType != HTTPSNameServerType {
+			return NameServer{}, fmt.Errorf("nameserver url path is only supported for the %s type", HTTPSNameServerTypeString)
+		}
+		ns.Path = parsedURL.Path
+	}
+
 	return ns, nil
 }
 
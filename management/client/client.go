@@ -21,4 +21,6 @@ type Client interface {
 	GetNetworkMap(sysInfo *system.Info) (*proto.NetworkMap, error)
 	IsHealthy() bool
 	SyncMeta(sysInfo *system.Info) error
+	LogFlows(events []*proto.FlowEvent) error
+	LogSSHSessions(events []*proto.SSHSessionEvent) error
 }
@@ -478,6 +478,62 @@ func (c *GrpcClient) SyncMeta(sysInfo *system.Info) error {
 	return err
 }
 
+// LogFlows uploads the connection flow events recorded by the packet filter to the Management Service.
+func (c *GrpcClient) LogFlows(events []*proto.FlowEvent) error {
+	if !c.ready() {
+		return errors.New(errMsgNoMgmtConnection)
+	}
+
+	serverPubKey, err := c.GetServerPublicKey()
+	if err != nil {
+		log.Debugf(errMsgMgmtPublicKey, err)
+		return err
+	}
+
+	flowEvents, err := encryption.EncryptMessage(*serverPubKey, c.key, &proto.FlowEvents{Events: events})
+	if err != nil {
+		log.Errorf("failed to encrypt message: %s", err)
+		return err
+	}
+
+	mgmCtx, cancel := context.WithTimeout(c.ctx, ConnectTimeout)
+	defer cancel()
+
+	_, err = c.realClient.LogFlows(mgmCtx, &proto.EncryptedMessage{
+		WgPubKey: c.key.PublicKey().String(),
+		Body:     flowEvents,
+	})
+	return err
+}
+
+// LogSSHSessions reports the start and the end of the sessions on the SSH server of the peer to the Management Service.
+func (c *GrpcClient) LogSSHSessions(events []*proto.SSHSessionEvent) error {
+	if !c.ready() {
+		return errors.New(errMsgNoMgmtConnection)
+	}
+
+	serverPubKey, err := c.GetServerPublicKey()
+	if err != nil {
+		log.Debugf(errMsgMgmtPublicKey, err)
+		return err
+	}
+
+	sessionEvents, err := encryption.EncryptMessage(*serverPubKey, c.key, &proto.SSHSessionEvents{Events: events})
+	if err != nil {
+		log.Errorf("failed to encrypt message: %s", err)
+		return err
+	}
+
+	mgmCtx, cancel := context.WithTimeout(c.ctx, ConnectTimeout)
+	defer cancel()
+
+	_, err = c.realClient.LogSSHSessions(mgmCtx, &proto.EncryptedMessage{
+		WgPubKey: c.key.PublicKey().String(),
+		Body:     sessionEvents,
+	})
+	return err
+}
+
 func (c *GrpcClient) notifyDisconnected(err error) {
 	c.connStateCallbackLock.RLock()
 	defer c.connStateCallbackLock.RUnlock()
@@ -520,6 +576,23 @@ func infoToMetaData(info *system.Info) *proto.PeerSystemMeta {
 		})
 	}
 
+	services := make([]*proto.Service, 0, len(info.Services))
+	for _, service := range info.Services {
+		services = append(services, &proto.Service{
+			Name:    service.Name,
+			Running: service.Running,
+		})
+	}
+
+	ports := make([]*proto.Port, 0, len(info.Ports))
+	for _, port := range info.Ports {
+		ports = append(ports, &proto.Port{
+			Port:      uint32(port.Port),
+			Protocol:  port.Protocol,
+			Listening: port.Listening,
+		})
+	}
+
 	return &proto.PeerSystemMeta{
 		Hostname:           info.Hostname,
 		GoOS:               info.GoOS,
@@ -539,6 +612,10 @@ func infoToMetaData(info *system.Info) *proto.PeerSystemMeta {
 			Cloud:    info.Environment.Cloud,
 			Platform: info.Environment.Platform,
 		},
-		Files: files,
+		Files:           files,
+		Services:        services,
+		Ports:           ports,
+		DiskEncrypted:   info.DiskEncrypted,
+		FirewallEnabled: info.FirewallEnabled,
 	}
 }
@@ -18,6 +18,8 @@ type MockClient struct {
 	GetDeviceAuthorizationFlowFunc func(serverKey wgtypes.Key) (*proto.DeviceAuthorizationFlow, error)
 	GetPKCEAuthorizationFlowFunc   func(serverKey wgtypes.Key) (*proto.PKCEAuthorizationFlow, error)
 	SyncMetaFunc                   func(sysInfo *system.Info) error
+	LogFlowsFunc                   func(events []*proto.FlowEvent) error
+	LogSSHSessionsFunc             func(events []*proto.SSHSessionEvent) error
 }
 
 func (m *MockClient) IsHealthy() bool {
@@ -84,3 +86,17 @@ func (m *MockClient) SyncMeta(sysInfo *system.Info) error {
 	}
 	return m.SyncMetaFunc(sysInfo)
 }
+
+func (m *MockClient) LogFlows(events []*proto.FlowEvent) error {
+	if m.LogFlowsFunc == nil {
+		return nil
+	}
+	return m.LogFlowsFunc(events)
+}
+
+func (m *MockClient) LogSSHSessions(events []*proto.SSHSessionEvent) error {
+	if m.LogSSHSessionsFunc == nil {
+		return nil
+	}
+	return m.LogSSHSessionsFunc(events)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/management/server"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/telemetry"
+)
+
+// newPeersUpdateManager returns the peers update manager using the update bus of the cluster configuration.
+// With the postgres update bus the store locks are shared between the management instances too.
+// The returned function releases the resources of the bus and the locks.
+func newPeersUpdateManager(ctx context.Context, cfg *server.ClusterConfig, s store.Store, metrics telemetry.AppMetrics) (*server.PeersUpdateManager, func(), error) {
+	if cfg == nil || cfg.UpdateBus == "" || cfg.UpdateBus == server.MemoryUpdateBusKind {
+		return server.NewPeersUpdateManager(metrics), func() {}, nil
+	}
+
+	if cfg.UpdateBus != server.PostgresUpdateBusKind {
+		return nil, nil, fmt.Errorf("unsupported update bus: %s", cfg.UpdateBus)
+	}
+
+	sqlStore, ok := s.(*store.SqlStore)
+	if !ok || s.GetStoreEngine() != store.PostgresStoreEngine {
+		return nil, nil, fmt.Errorf("%s update bus requires the %s store engine", cfg.UpdateBus, store.PostgresStoreEngine)
+	}
+
+	dsn, err := store.GetPostgresDSN()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	locker, err := store.NewPostgresLocker(ctx, dsn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("create store locker: %w", err)
+	}
+	sqlStore.SetLocker(locker)
+
+	bus, err := server.NewPostgresUpdateBus(ctx, dsn)
+	if err != nil {
+		_ = locker.Close()
+		return nil, nil, fmt.Errorf("create update bus: %w", err)
+	}
+
+	log.WithContext(ctx).Infof("using %s update bus and shared store locks", cfg.UpdateBus)
+
+	closeFn := func() {
+		if err := bus.Close(); err != nil {
+			log.WithContext(ctx).Warnf("failed to close update bus: %v", err)
+		}
+		if err := locker.Close(); err != nil {
+			log.WithContext(ctx).Warnf("failed to close store locker: %v", err)
+		}
+	}
+	return server.NewPeersUpdateManagerWithBus(metrics, bus), closeFn, nil
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/netbirdio/netbird/formatter"
+	"github.com/netbirdio/netbird/management/server"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/util"
+)
+
+var (
+	exportFile      string
+	exportAccounts  []string
+	importOverwrite bool
+	storeDataDir    string
+	storeEngine     string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export --file backup.json [--account id]",
+	Short: "Export accounts from the management store to a portable backup file",
+	Long: "Export accounts from the management store to a portable backup file.\n\n" +
+		"The export includes peers, users, groups, policies, posture checks, routes, nameserver groups, " +
+		"networks, setup keys and settings and can be restored with the import command into any supported store engine. " +
+		"The management service should be stopped while running this command.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flag.Parse()
+		ctx, err := initStoreCommand(cmd)
+		if err != nil {
+			return err
+		}
+
+		s, err := openStore(ctx)
+		if err != nil {
+			return err
+		}
+		defer s.Close(ctx) //nolint
+
+		export, err := store.ExportAccounts(ctx, s, exportAccounts...)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.OpenFile(exportFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to create export file %s: %v", exportFile, err)
+		}
+		defer f.Close()
+
+		if err := store.WriteExport(f, export); err != nil {
+			return fmt.Errorf("failed to write export file %s: %v", exportFile, err)
+		}
+
+		log.WithContext(ctx).Infof("exported %d accounts from the %s store to %s", len(export.Accounts), s.GetStoreEngine(), exportFile)
+
+		return nil
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import --file backup.json [--overwrite]",
+	Short: "Import accounts from a backup file created with the export command",
+	Long: "Import accounts from a backup file created with the export command.\n\n" +
+		"The target store engine is taken from the management config file and can be overridden with --store-engine. " +
+		"The management service should be stopped while running this command.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flag.Parse()
+		ctx, err := initStoreCommand(cmd)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(exportFile)
+		if err != nil {
+			return fmt.Errorf("failed to open export file %s: %v", exportFile, err)
+		}
+		defer f.Close()
+
+		export, err := store.ReadExport(f)
+		if err != nil {
+			return err
+		}
+
+		s, err := openStore(ctx)
+		if err != nil {
+			return err
+		}
+		defer s.Close(ctx) //nolint
+
+		if err := store.ImportAccounts(ctx, s, export, importOverwrite); err != nil {
+			return err
+		}
+
+		log.WithContext(ctx).Infof("imported %d accounts from %s to the %s store", len(export.Accounts), exportFile, s.GetStoreEngine())
+
+		return nil
+	},
+}
+
+func initStoreCommand(cmd *cobra.Command) (context.Context, error) {
+	if err := util.InitLog(logLevel, logFile); err != nil {
+		return nil, fmt.Errorf("failed initializing log %v", err)
+	}
+
+	//nolint
+	ctx := context.WithValue(cmd.Context(), formatter.ExecutionContextKey, formatter.SystemSource)
+
+	return ctx, nil
+}
+
+// openStore opens the store configured in the management config file.
+// The OIDC discovery performed by loadMgmtConfig is not needed here, so the config is read directly.
+func openStore(ctx context.Context) (store.Store, error) {
+	config := &server.Config{}
+	if _, err := util.ReadJsonWithEnvSub(mgmtConfig, config); err != nil {
+		return nil, fmt.Errorf("failed reading config %s: %v", mgmtConfig, err)
+	}
+
+	if storeDataDir != "" {
+		config.Datadir = storeDataDir
+	}
+
+	if _, err := os.Stat(config.Datadir); os.IsNotExist(err) {
+		if err := os.MkdirAll(config.Datadir, 0755); err != nil {
+			return nil, fmt.Errorf("failed creating datadir: %s: %v", config.Datadir, err)
+		}
+	}
+
+	engine := config.StoreConfig.Engine
+	if storeEngine != "" {
+		engine = store.Engine(storeEngine)
+	}
+
+	s, err := store.NewStore(ctx, engine, config.Datadir, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating Store: %s: %v", config.Datadir, err)
+	}
+
+	return s, nil
+}
+
+func init() {
+	for _, c := range []*cobra.Command{exportCmd, importCmd} {
+		c.Flags().StringVar(&mgmtConfig, "config", defaultMgmtConfig, "Netbird config file location")
+		c.Flags().StringVar(&storeDataDir, "datadir", "", "server data directory location. Overrides the Datadir from the config file")
+		c.Flags().StringVar(&storeEngine, "store-engine", "", "store engine to use (sqlite, postgres or mysql). Overrides the StoreConfig from the config file")
+		c.Flags().StringVar(&exportFile, "file", "", "location of the export file")
+		c.MarkFlagRequired("file") //nolint
+	}
+
+	exportCmd.Flags().StringSliceVar(&exportAccounts, "account", nil, "ID of an account to export. Can be repeated. All accounts are exported when not set")
+	importCmd.Flags().BoolVar(&importOverwrite, "overwrite", false, "replace accounts that already exist in the target store")
+
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+}
@@ -39,18 +39,23 @@ import (
 	"github.com/netbirdio/netbird/formatter"
 	mgmtProto "github.com/netbirdio/netbird/management/proto"
 	"github.com/netbirdio/netbird/management/server"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/activity/retention"
+	"github.com/netbirdio/netbird/management/server/activity/sinks"
 	nbContext "github.com/netbirdio/netbird/management/server/context"
 	"github.com/netbirdio/netbird/management/server/geolocation"
 	"github.com/netbirdio/netbird/management/server/groups"
 	nbhttp "github.com/netbirdio/netbird/management/server/http"
 	"github.com/netbirdio/netbird/management/server/http/configs"
 	"github.com/netbirdio/netbird/management/server/idp"
+	"github.com/netbirdio/netbird/management/server/integrated_validator"
 	"github.com/netbirdio/netbird/management/server/jwtclaims"
 	"github.com/netbirdio/netbird/management/server/metrics"
 	"github.com/netbirdio/netbird/management/server/networks"
 	"github.com/netbirdio/netbird/management/server/networks/resources"
 	"github.com/netbirdio/netbird/management/server/networks/routers"
 	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/roles"
 	"github.com/netbirdio/netbird/management/server/settings"
 	"github.com/netbirdio/netbird/management/server/store"
 	"github.com/netbirdio/netbird/management/server/telemetry"
@@ -162,7 +167,10 @@ var (
 			if err != nil {
 				return fmt.Errorf("failed creating Store: %s: %v", config.Datadir, err)
 			}
-			peersUpdateManager := server.NewPeersUpdateManager(appMetrics)
+			peersUpdateManager, closeCluster, err := newPeersUpdateManager(ctx, config.Cluster, store, appMetrics)
+			if err != nil {
+				return fmt.Errorf("failed to set up peers update manager: %v", err)
+			}
 
 			var idpManager idp.Manager
 			if config.IdpManagerConfig != nil {
@@ -189,6 +197,24 @@ var (
 				}
 			}
 
+			if config.EventRetention != nil {
+				retentionStore, ok := eventStore.(retention.Store)
+				if ok {
+					retention.NewManager(retentionStore, config.EventRetention).Start(ctx)
+				} else {
+					log.WithContext(ctx).Warnf("activity event store doesn't support retention, events won't be pruned")
+				}
+			}
+
+			eventSinks, err := sinks.NewSinks(config.EventSinks)
+			if err != nil {
+				return fmt.Errorf("failed to initialize activity event sinks: %v", err)
+			}
+			if len(eventSinks) > 0 {
+				log.WithContext(ctx).Infof("streaming activity events to %d sinks", len(eventSinks))
+				eventStore = activity.NewSinkStore(ctx, eventStore, eventSinks...)
+			}
+
 			geo, err := geolocation.NewGeolocation(ctx, config.Datadir, !disableGeoliteUpdate)
 			if err != nil {
 				log.WithContext(ctx).Warnf("could not initialize geolocation service. proceeding without geolocation support: %v", err)
@@ -196,9 +222,15 @@ var (
 				log.WithContext(ctx).Infof("geolocation service has been initialized from %s", config.Datadir)
 			}
 
-			integratedPeerValidator, err := integrations.NewIntegratedValidator(ctx, eventStore)
-			if err != nil {
-				return fmt.Errorf("failed to initialize integrated peer validator: %v", err)
+			var integratedPeerValidator integrated_validator.IntegratedValidator
+			if config.PeerApproval {
+				log.WithContext(ctx).Infof("using the built-in peer approval validator")
+				integratedPeerValidator = integrated_validator.NewPeerApprovalValidator()
+			} else {
+				integratedPeerValidator, err = integrations.NewIntegratedValidator(ctx, eventStore)
+				if err != nil {
+					return fmt.Errorf("failed to initialize integrated peer validator: %v", err)
+				}
 			}
 			accountManager, err := server.BuildManager(ctx, store, peersUpdateManager, idpManager, mgmtSingleAccModeDomain,
 				dnsDomain, eventStore, geo, userDeleteFromIDPEnabled, integratedPeerValidator, appMetrics)
@@ -280,8 +312,9 @@ var (
 			resourcesManager := resources.NewManager(store, permissionsManager, groupsManager, accountManager)
 			routersManager := routers.NewManager(store, permissionsManager, accountManager)
 			networksManager := networks.NewManager(store, permissionsManager, resourcesManager, routersManager, accountManager)
+			rolesManager := roles.NewManager(store, permissionsManager, accountManager)
 
-			httpAPIHandler, err := nbhttp.NewAPIHandler(ctx, accountManager, networksManager, resourcesManager, routersManager, groupsManager, geo, jwtValidator, appMetrics, httpAPIAuthCfg, integratedPeerValidator)
+			httpAPIHandler, err := nbhttp.NewAPIHandler(ctx, accountManager, networksManager, resourcesManager, routersManager, groupsManager, rolesManager, permissionsManager, geo, jwtValidator, appMetrics, httpAPIAuthCfg, integratedPeerValidator)
 			if err != nil {
 				return fmt.Errorf("failed creating HTTP API handler: %v", err)
 			}
@@ -369,6 +402,7 @@ var (
 				_ = certManager.Listener().Close()
 			}
 			gRPCAPIHandler.Stop()
+			closeCluster()
 			_ = store.Close(ctx)
 			_ = eventStore.Close(ctx)
 			log.WithContext(ctx).Infof("stopped Management Service")
@@ -88,6 +88,8 @@ type AccountManager interface {
 	MarkPeerConnected(ctx context.Context, peerKey string, connected bool, realIP net.IP, account *types.Account) error
 	DeletePeer(ctx context.Context, accountID, peerID, userID string) error
 	UpdatePeer(ctx context.Context, accountID, userID string, peer *nbpeer.Peer) (*nbpeer.Peer, error)
+	ApprovePeer(ctx context.Context, accountID, userID, peerID string) (*nbpeer.Peer, error)
+	RejectPeer(ctx context.Context, accountID, userID, peerID string) error
 	GetNetworkMap(ctx context.Context, peerID string) (*types.NetworkMap, error)
 	GetPeerNetwork(ctx context.Context, peerID string) (*types.Network, error)
 	AddPeer(ctx context.Context, setupKey, userID string, peer *nbpeer.Peer) (*nbpeer.Peer, *types.NetworkMap, []*posture.Checks, error)
@@ -121,7 +123,7 @@ type AccountManager interface {
 	ListNameServerGroups(ctx context.Context, accountID string, userID string) ([]*nbdns.NameServerGroup, error)
 	GetDNSDomain() string
 	StoreEvent(ctx context.Context, initiatorID, targetID, accountID string, activityID activity.ActivityDescriber, meta map[string]any)
-	GetEvents(ctx context.Context, accountID, userID string) ([]*activity.Event, error)
+	GetEvents(ctx context.Context, accountID, userID string, filter activity.Filter) ([]*activity.Event, uint64, error)
 	GetDNSSettings(ctx context.Context, accountID string, userID string) (*types.DNSSettings, error)
 	SaveDNSSettings(ctx context.Context, accountID string, userID string, dnsSettingsToSave *types.DNSSettings) error
 	GetPeer(ctx context.Context, accountID, peerID, userID string) (*nbpeer.Peer, error)
@@ -142,6 +144,7 @@ type AccountManager interface {
 	SyncAndMarkPeer(ctx context.Context, accountID string, peerPubKey string, meta nbpeer.PeerSystemMeta, realIP net.IP) (*nbpeer.Peer, *types.NetworkMap, []*posture.Checks, error)
 	OnPeerDisconnected(ctx context.Context, accountID string, peerPubKey string) error
 	SyncPeerMeta(ctx context.Context, peerPubKey string, meta nbpeer.PeerSystemMeta) error
+	LogPeerSSHSessions(ctx context.Context, peerPubKey string, events []SSHSessionEvent) error
 	FindExistingPostureCheck(accountID string, checks *posture.ChecksDefinition) (*posture.Checks, error)
 	GetAccountIDForPeerKey(ctx context.Context, peerKey string) (string, error)
 	GetAccountSettings(ctx context.Context, accountID string, userID string) (*types.Settings, error)
@@ -177,6 +180,9 @@ type DefaultAccountManager struct {
 
 	peerInactivityExpiry Scheduler
 
+	// policySchedule pushes network map updates when scheduled policy rules become active or inactive
+	policySchedule Scheduler
+
 	// userDeleteFromIDPEnabled allows to delete user from IDP when user is deleted from account
 	userDeleteFromIDPEnabled bool
 
@@ -261,11 +267,13 @@ func BuildManager(
 		eventStore:               eventStore,
 		peerLoginExpiry:          NewDefaultScheduler(),
 		peerInactivityExpiry:     NewDefaultScheduler(),
+		policySchedule:           NewDefaultScheduler(),
 		userDeleteFromIDPEnabled: userDeleteFromIDPEnabled,
 		integratedPeerValidator:  integratedPeerValidator,
 		metrics:                  metrics,
 		requestBuffer:            NewAccountRequestBuffer(ctx, store),
 	}
+	peersUpdateManager.Subscribe(am.handleUpdateEvent)
 	allAccounts := store.GetAllAccounts(ctx)
 	// enable single account mode only if configured by user and number of existing accounts is not grater than 1
 	am.singleAccountMode = singleAccountModeDomain != "" && len(allAccounts) <= 1
@@ -299,6 +307,8 @@ func BuildManager(
 				return nil, err
 			}
 		}
+
+		am.checkAndSchedulePolicyScheduleUpdate(ctx, account)
 	}
 
 	goCacheClient := gocache.New(CacheExpirationMax, 30*time.Minute)
@@ -372,6 +382,10 @@ func (am *DefaultAccountManager) UpdateAccountSettings(ctx context.Context, acco
 		return nil, err
 	}
 
+	if err = validatePeerApprovalSettings(account, newSettings); err != nil {
+		return nil, err
+	}
+
 	oldSettings := account.Settings
 	if oldSettings.PeerLoginExpirationEnabled != newSettings.PeerLoginExpirationEnabled {
 		event := activity.AccountPeerLoginExpirationEnabled
@@ -400,6 +414,11 @@ func (am *DefaultAccountManager) UpdateAccountSettings(ctx context.Context, acco
 		account.Network.Serial++
 	}
 
+	if am.handlePeerApprovalSettings(ctx, account, oldSettings, newSettings, userID, accountID) {
+		updateAccountPeers = true
+		account.Network.Serial++
+	}
+
 	err = am.handleInactivityExpirationSettings(ctx, account, oldSettings, newSettings, userID, accountID)
 	if err != nil {
 		return nil, err
@@ -424,6 +443,58 @@ func (am *DefaultAccountManager) UpdateAccountSettings(ctx context.Context, acco
 	return updatedAccount, nil
 }
 
+// validatePeerApprovalSettings checks that the peer approval groups and setup keys exist in the account
+func validatePeerApprovalSettings(account *types.Account, newSettings *types.Settings) error {
+	if newSettings.Extra == nil {
+		return nil
+	}
+
+	for _, groupID := range newSettings.Extra.IntegratedValidatorGroups {
+		if _, ok := account.Groups[groupID]; !ok {
+			return status.Errorf(status.InvalidArgument, "peer approval group %s not found", groupID)
+		}
+	}
+
+	setupKeyIDs := make(map[string]struct{}, len(account.SetupKeys))
+	for _, setupKey := range account.SetupKeys {
+		setupKeyIDs[setupKey.Id] = struct{}{}
+	}
+	for _, setupKeyID := range newSettings.Extra.PeerApprovalSetupKeys {
+		if _, ok := setupKeyIDs[setupKeyID]; !ok {
+			return status.Errorf(status.InvalidArgument, "peer approval setup key %s not found", setupKeyID)
+		}
+	}
+
+	return nil
+}
+
+// handlePeerApprovalSettings stores the peer approval events, disabling the approval approves all pending peers.
+// It returns true if the network maps of the peers have to be updated.
+func (am *DefaultAccountManager) handlePeerApprovalSettings(ctx context.Context, account *types.Account, oldSettings, newSettings *types.Settings, userID, accountID string) bool {
+	oldEnabled := oldSettings.Extra != nil && oldSettings.Extra.PeerApprovalEnabled
+	newEnabled := newSettings.Extra != nil && newSettings.Extra.PeerApprovalEnabled
+	if oldEnabled == newEnabled {
+		return false
+	}
+
+	if newEnabled {
+		am.StoreEvent(ctx, userID, accountID, accountID, activity.AccountPeerApprovalEnabled, nil)
+		return false
+	}
+
+	updated := false
+	for _, peer := range account.Peers {
+		if peer.Status == nil || !peer.Status.RequiresApproval {
+			continue
+		}
+		peer.Status.RequiresApproval = false
+		updated = true
+	}
+	am.StoreEvent(ctx, userID, accountID, accountID, activity.AccountPeerApprovalDisabled, nil)
+
+	return updated
+}
+
 func (am *DefaultAccountManager) handleGroupsPropagationSettings(ctx context.Context, oldSettings, newSettings *types.Settings, userID, accountID string) error {
 	if oldSettings.GroupsPropagationEnabled != newSettings.GroupsPropagationEnabled {
 		if newSettings.GroupsPropagationEnabled {
@@ -533,6 +604,38 @@ func (am *DefaultAccountManager) checkAndSchedulePeerInactivityExpiration(ctx co
 	}
 }
 
+// policyScheduleJob sends out network map updates to the account peers when a scheduled policy rule changes its state
+// and returns the duration until the next scheduled rule of the account changes its state if found
+func (am *DefaultAccountManager) policyScheduleJob(ctx context.Context, accountID string) func() (time.Duration, bool) {
+	return func() (time.Duration, bool) {
+		unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+		err := am.Store.IncrementNetworkSerial(ctx, store.LockingStrengthUpdate, accountID)
+		unlock()
+		if err != nil {
+			log.WithContext(ctx).Errorf("failed incrementing network serial for account %s on policy schedule change: %v", accountID, err)
+		}
+
+		account, err := am.Store.GetAccount(ctx, accountID)
+		if err != nil {
+			log.WithContext(ctx).Errorf("failed getting account %s on policy schedule change: %v", accountID, err)
+			return 0, false
+		}
+
+		log.WithContext(ctx).Debugf("scheduled policy rules changed state for account %s, updating peers", accountID)
+		am.UpdateAccountPeers(ctx, accountID)
+
+		return account.GetNextPolicyScheduleTransition(time.Now())
+	}
+}
+
+// checkAndSchedulePolicyScheduleUpdate schedules a network map update of the account peers for the next time a scheduled policy rule changes its state
+func (am *DefaultAccountManager) checkAndSchedulePolicyScheduleUpdate(ctx context.Context, account *types.Account) {
+	am.policySchedule.Cancel(ctx, []string{account.Id})
+	if nextRun, ok := account.GetNextPolicyScheduleTransition(time.Now()); ok {
+		go am.policySchedule.Schedule(ctx, nextRun, account.Id, am.policyScheduleJob(ctx, account.Id))
+	}
+}
+
 // newAccount creates a new Account with a generated ID and generated default setup keys.
 // If ID is already in use (due to collision) we try one more time before returning error
 func (am *DefaultAccountManager) newAccount(ctx context.Context, userID, domain string) (*types.Account, error) {
@@ -6,14 +6,18 @@ type ExtraSettings struct {
 
 	// IntegratedValidatorGroups list of group IDs to be used with integrated approval configurations
 	IntegratedValidatorGroups []string `gorm:"serializer:json"`
+
+	// PeerApprovalSetupKeys list of setup key IDs the peers added with require approval
+	PeerApprovalSetupKeys []string `gorm:"serializer:json"`
 }
 
 // Copy copies the ExtraSettings struct
 func (e *ExtraSettings) Copy() *ExtraSettings {
-	var cpGroup []string
+	var cpGroup, cpSetupKeys []string
 
 	return &ExtraSettings{
 		PeerApprovalEnabled:       e.PeerApprovalEnabled,
 		IntegratedValidatorGroups: append(cpGroup, e.IntegratedValidatorGroups...),
+		PeerApprovalSetupKeys:     append(cpSetupKeys, e.PeerApprovalSetupKeys...),
 	}
 }
@@ -1512,7 +1512,7 @@ func getEvent(t *testing.T, accountID string, manager AccountManager, eventType
 		case <-time.After(time.Second):
 			t.Fatal("no PeerAddedWithSetupKey event was generated")
 		default:
-			events, err := manager.GetEvents(context.Background(), accountID, userID)
+			events, _, err := manager.GetEvents(context.Background(), accountID, userID, activity.Filter{Descending: true})
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -1724,10 +1724,11 @@ func TestAccount_Copy(t *testing.T) {
 		},
 		Policies: []*types.Policy{
 			{
-				ID:                  "policy1",
-				Enabled:             true,
-				Rules:               make([]*types.PolicyRule, 0),
-				SourcePostureChecks: make([]string, 0),
+				ID:                       "policy1",
+				Enabled:                  true,
+				Rules:                    make([]*types.PolicyRule, 0),
+				SourcePostureChecks:      make([]string, 0),
+				DestinationPostureChecks: make([]string, 0),
 			},
 		},
 		Routes: map[route.ID]*route.Route{
@@ -1776,6 +1777,13 @@ func TestAccount_Copy(t *testing.T) {
 				Address:   "172.12.6.1/24",
 			},
 		},
+		CustomRoles: []*types.CustomRole{
+			{
+				ID:          "role1",
+				Name:        "operators",
+				Permissions: []types.RolePermission{{Module: "routes", Read: true, Groups: []string{"group1"}}},
+			},
+		},
 	}
 	err := hasNilField(account)
 	if err != nil {
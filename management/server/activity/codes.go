@@ -169,6 +169,25 @@ const (
 
 	ResourceAddedToGroup     Activity = 82
 	ResourceRemovedFromGroup Activity = 83
+
+	CustomRoleCreated Activity = 84
+	CustomRoleUpdated Activity = 85
+	CustomRoleDeleted Activity = 86
+	// UserCustomRoleUpdated indicates that a user changed the custom role of a user
+	UserCustomRoleUpdated Activity = 87
+
+	// PeerSSHFeaturesUpdated indicates that a user changed the port forwarding, SFTP or command execution setting of a peer SSH server
+	PeerSSHFeaturesUpdated Activity = 88
+
+	// PeerSSHSessionStarted indicates that a peer opened a session on the SSH server of another peer
+	PeerSSHSessionStarted Activity = 89
+	// PeerSSHSessionEnded indicates that a session on the SSH server of a peer ended
+	PeerSSHSessionEnded Activity = 90
+
+	// PeerApprovalRejected indicates that a user rejected a peer pending approval, the peer is removed
+	PeerApprovalRejected Activity = 91
+	// PeerPendingApproval indicates that a peer was added pending approval
+	PeerPendingApproval Activity = 92
 )
 
 var activityMap = map[Activity]Code{
@@ -264,6 +283,19 @@ var activityMap = map[Activity]Code{
 
 	ResourceAddedToGroup:     {"Resource added to group", "resource.group.add"},
 	ResourceRemovedFromGroup: {"Resource removed from group", "resource.group.delete"},
+
+	CustomRoleCreated:     {"Custom role created", "role.create"},
+	CustomRoleUpdated:     {"Custom role updated", "role.update"},
+	CustomRoleDeleted:     {"Custom role deleted", "role.delete"},
+	UserCustomRoleUpdated: {"User custom role updated", "user.custom_role.update"},
+
+	PeerSSHFeaturesUpdated: {"Peer SSH server features updated", "peer.ssh.features.update"},
+
+	PeerSSHSessionStarted: {"Peer SSH session started", "peer.ssh.session.start"},
+	PeerSSHSessionEnded:   {"Peer SSH session ended", "peer.ssh.session.end"},
+
+	PeerApprovalRejected: {"Peer approval rejected", "peer.approval.reject"},
+	PeerPendingApproval:  {"Peer pending approval", "peer.approval.pending"},
 }
 
 // StringCode returns a string code of the activity
@@ -282,6 +314,16 @@ func (a Activity) Message() string {
 	return "UNKNOWN_ACTIVITY"
 }
 
+// ActivityFromStringCode returns the activity with the given string code
+func ActivityFromStringCode(stringCode string) (Activity, bool) {
+	for activity, code := range activityMap {
+		if code.Code == stringCode {
+			return activity, true
+		}
+	}
+	return 0, false
+}
+
 // RegisterActivityMap adds new codes to the activity map
 func RegisterActivityMap(codes map[Activity]Code) {
 	maps.Copy(activityMap, codes)
@@ -0,0 +1,67 @@
+package retention
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/activity/sinks"
+)
+
+// archive writes the events of a single pruning run of an account to a gzip compressed JSON lines file
+type archive struct {
+	path string
+	file *os.File
+	gz   *gzip.Writer
+	enc  *json.Encoder
+}
+
+// newArchive creates the archive file of the account in the directory. Files are named after the account
+// and the time of the pruning run, e.g. <dir>/<account ID>/events-20240101T000000Z.jsonl.gz
+func newArchive(dir, accountID string, now time.Time) (*archive, error) {
+	accountDir := filepath.Join(dir, filepath.Base(accountID))
+	if err := os.MkdirAll(accountDir, 0o750); err != nil {
+		return nil, fmt.Errorf("create archive directory: %w", err)
+	}
+
+	path := filepath.Join(accountDir, fmt.Sprintf("events-%s.jsonl.gz", now.UTC().Format("20060102T150405Z")))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("create archive file: %w", err)
+	}
+
+	gz := gzip.NewWriter(file)
+	return &archive{
+		path: path,
+		file: file,
+		gz:   gz,
+		enc:  json.NewEncoder(gz),
+	}, nil
+}
+
+// write appends the events to the archive
+func (a *archive) write(events []*activity.Event) error {
+	for _, event := range events {
+		if err := a.enc.Encode(sinks.NewEvent(event)); err != nil {
+			return fmt.Errorf("write event %d to archive %s: %w", event.ID, a.path, err)
+		}
+	}
+	return nil
+}
+
+// close flushes the compressed data and syncs the file so the events can be safely deleted afterwards
+func (a *archive) close() error {
+	if err := a.gz.Close(); err != nil {
+		_ = a.file.Close()
+		return err
+	}
+	if err := a.file.Sync(); err != nil {
+		_ = a.file.Close()
+		return err
+	}
+	return a.file.Close()
+}
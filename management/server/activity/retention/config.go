@@ -0,0 +1,39 @@
+package retention
+
+import (
+	"github.com/netbirdio/netbird/util"
+)
+
+// Config of the activity events retention
+type Config struct {
+	// Interval between the pruning runs, defaults to one hour
+	Interval util.Duration
+	// Default policy applied to the accounts without an override
+	Default Policy
+	// Accounts overrides the default policy per account ID
+	Accounts map[string]Policy
+	// ArchiveDir is the directory pruned events are archived to as gzip compressed JSON lines before deletion.
+	// Events are deleted without archival when empty
+	ArchiveDir string
+}
+
+// Policy defines which events of an account are kept. Zero values don't limit the retention
+type Policy struct {
+	// MaxAge of the kept events
+	MaxAge util.Duration
+	// MaxCount is the number of the newest events kept
+	MaxCount int
+}
+
+// IsEmpty returns true if the policy doesn't limit the retention
+func (p Policy) IsEmpty() bool {
+	return p.MaxAge.Duration <= 0 && p.MaxCount <= 0
+}
+
+// PolicyFor returns the policy of the account
+func (c *Config) PolicyFor(accountID string) Policy {
+	if policy, ok := c.Accounts[accountID]; ok {
+		return policy
+	}
+	return c.Default
+}
@@ -0,0 +1,175 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+)
+
+const (
+	defaultInterval = time.Hour
+	// batchSize is the number of events read for the archival or deleted at once, so the store isn't locked for long
+	batchSize = 1000
+)
+
+// Store is an activity event store supporting the deletion of expired events
+type Store interface {
+	// List returns the events of an account matching the filter ordered by ID
+	List(ctx context.Context, accountID string, filter activity.Filter) ([]*activity.Event, error)
+	// AccountIDs returns the IDs of the accounts having events
+	AccountIDs(ctx context.Context) ([]string, error)
+	// LastExpiredEventID returns the ID of the newest event of the account which happened before the given time
+	// or isn't among the newest "keep" events, 0 when no event is expired
+	LastExpiredEventID(ctx context.Context, accountID string, before time.Time, keep int) (uint64, error)
+	// DeleteEvents deletes at most "limit" oldest events of the account up to and including the given ID
+	DeleteEvents(ctx context.Context, accountID string, lastID uint64, limit int) (int64, error)
+}
+
+// Manager periodically prunes the events exceeding the retention policies of the accounts
+type Manager struct {
+	store  Store
+	config *Config
+}
+
+// NewManager creates a retention manager of the store
+func NewManager(store Store, config *Config) *Manager {
+	return &Manager{
+		store:  store,
+		config: config,
+	}
+}
+
+// Start prunes the events right away and then in the configured interval until the context is canceled
+func (m *Manager) Start(ctx context.Context) {
+	interval := m.config.Interval.Duration
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := m.Prune(ctx); err != nil {
+				log.WithContext(ctx).Errorf("failed to prune activity events: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Prune applies the retention policies to the events of all accounts once
+func (m *Manager) Prune(ctx context.Context) error {
+	accountIDs, err := m.store.AccountIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("get accounts: %w", err)
+	}
+
+	now := time.Now().UTC()
+	var errs []error
+	for _, accountID := range accountIDs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		policy := m.config.PolicyFor(accountID)
+		if policy.IsEmpty() {
+			continue
+		}
+
+		deleted, err := m.pruneAccount(ctx, accountID, policy, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("account %s: %w", accountID, err))
+			continue
+		}
+		if deleted > 0 {
+			log.WithContext(ctx).Infof("pruned %d activity events of account %s", deleted, accountID)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m *Manager) pruneAccount(ctx context.Context, accountID string, policy Policy, now time.Time) (int64, error) {
+	var before time.Time
+	if policy.MaxAge.Duration > 0 {
+		before = now.Add(-policy.MaxAge.Duration)
+	}
+
+	lastID, err := m.store.LastExpiredEventID(ctx, accountID, before, policy.MaxCount)
+	if err != nil {
+		return 0, fmt.Errorf("find expired events: %w", err)
+	}
+	if lastID == 0 {
+		return 0, nil
+	}
+
+	if m.config.ArchiveDir != "" {
+		if err := m.archiveEvents(ctx, accountID, lastID, now); err != nil {
+			return 0, err
+		}
+	}
+
+	var total int64
+	for {
+		deleted, err := m.store.DeleteEvents(ctx, accountID, lastID, batchSize)
+		if err != nil {
+			return total, fmt.Errorf("delete expired events: %w", err)
+		}
+		total += deleted
+		if deleted < batchSize {
+			return total, nil
+		}
+	}
+}
+
+// archiveEvents writes the events of the account up to and including the given ID to a new archive file
+func (m *Manager) archiveEvents(ctx context.Context, accountID string, lastID uint64, now time.Time) error {
+	a, err := newArchive(m.config.ArchiveDir, accountID, now)
+	if err != nil {
+		return err
+	}
+
+	filter := activity.Filter{Limit: batchSize}
+	for {
+		events, err := m.store.List(ctx, accountID, filter)
+		if err != nil {
+			_ = a.close()
+			return fmt.Errorf("read expired events: %w", err)
+		}
+
+		expired := events
+		for i, event := range events {
+			if event.ID > lastID {
+				expired = events[:i]
+				break
+			}
+		}
+
+		if err = a.write(expired); err != nil {
+			_ = a.close()
+			return err
+		}
+
+		if len(expired) < batchSize {
+			break
+		}
+		filter.Cursor = expired[len(expired)-1].ID
+	}
+
+	if err = a.close(); err != nil {
+		return fmt.Errorf("close archive %s: %w", a.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package retention
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/activity/sinks"
+	"github.com/netbirdio/netbird/management/server/activity/sqlite"
+	"github.com/netbirdio/netbird/util"
+)
+
+func newTestStore(t *testing.T) *sqlite.Store {
+	t.Helper()
+
+	key, err := sqlite.GenerateKey()
+	require.NoError(t, err)
+	store, err := sqlite.NewSQLiteStore(context.Background(), t.TempDir(), key)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = store.Close(context.Background())
+	})
+	return store
+}
+
+// saveEvents stores count events of the account, one per day with the newest happening now
+func saveEvents(t *testing.T, store *sqlite.Store, accountID string, count int) {
+	t.Helper()
+
+	now := time.Now().UTC()
+	for i := count - 1; i >= 0; i-- {
+		_, err := store.Save(context.Background(), &activity.Event{
+			Timestamp:   now.Add(-time.Duration(i) * 24 * time.Hour),
+			Activity:    activity.PeerAddedByUser,
+			InitiatorID: "user",
+			TargetID:    fmt.Sprintf("peer-%d", i),
+			AccountID:   accountID,
+		})
+		require.NoError(t, err)
+	}
+}
+
+func countEvents(t *testing.T, store *sqlite.Store, accountID string) int {
+	t.Helper()
+
+	events, err := store.List(context.Background(), accountID, activity.Filter{})
+	require.NoError(t, err)
+	return len(events)
+}
+
+func TestManager_Prune(t *testing.T) {
+	store := newTestStore(t)
+	saveEvents(t, store, "by-age", 10)
+	saveEvents(t, store, "by-count", 10)
+	saveEvents(t, store, "unlimited", 10)
+
+	manager := NewManager(store, &Config{
+		Default: Policy{MaxAge: util.Duration{Duration: 72 * time.Hour}},
+		Accounts: map[string]Policy{
+			"by-count":  {MaxCount: 4},
+			"unlimited": {},
+		},
+	})
+	require.NoError(t, manager.Prune(context.Background()))
+
+	assert.Equal(t, 3, countEvents(t, store, "by-age"), "events older than 3 days should be pruned")
+	assert.Equal(t, 4, countEvents(t, store, "by-count"), "only the newest events should be kept")
+	assert.Equal(t, 10, countEvents(t, store, "unlimited"), "empty policy shouldn't prune events")
+}
+
+func TestManager_PruneInBatches(t *testing.T) {
+	store := newTestStore(t)
+	saveEvents(t, store, "account", batchSize*2+5)
+
+	manager := NewManager(store, &Config{
+		Default:    Policy{MaxCount: 2},
+		ArchiveDir: t.TempDir(),
+	})
+	require.NoError(t, manager.Prune(context.Background()))
+
+	assert.Equal(t, 2, countEvents(t, store, "account"))
+
+	files, err := filepath.Glob(filepath.Join(manager.config.ArchiveDir, "account", "*.jsonl.gz"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Len(t, readArchive(t, files[0]), batchSize*2+3)
+}
+
+func TestManager_PruneArchivesEvents(t *testing.T) {
+	store := newTestStore(t)
+	saveEvents(t, store, "account", 5)
+
+	archiveDir := t.TempDir()
+	manager := NewManager(store, &Config{
+		Default:    Policy{MaxAge: util.Duration{Duration: 36 * time.Hour}},
+		ArchiveDir: archiveDir,
+	})
+	require.NoError(t, manager.Prune(context.Background()))
+	assert.Equal(t, 2, countEvents(t, store, "account"))
+
+	files, err := filepath.Glob(filepath.Join(archiveDir, "account", "events-*.jsonl.gz"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	archived := readArchive(t, files[0])
+	require.Len(t, archived, 3)
+	for i, event := range archived {
+		assert.Equal(t, fmt.Sprint(i+1), event.ID, "events should be archived from the oldest")
+		assert.Equal(t, "account", event.AccountID)
+		assert.Equal(t, activity.PeerAddedByUser.StringCode(), event.ActivityCode)
+	}
+
+	require.NoError(t, manager.Prune(context.Background()))
+	files, err = filepath.Glob(filepath.Join(archiveDir, "account", "*"))
+	require.NoError(t, err)
+	assert.Len(t, files, 1, "archive shouldn't be created when nothing is pruned")
+}
+
+func readArchive(t *testing.T, path string) []*sinks.Event {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	require.NoError(t, err)
+
+	events := make([]*sinks.Event, 0)
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		event := &sinks.Event{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), event))
+		events = append(events, event)
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
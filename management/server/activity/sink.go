@@ -0,0 +1,117 @@
+package activity
+
+import (
+	"context"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// sinkQueueSize is the number of events buffered for each sink before new events are dropped
+const sinkQueueSize = 1000
+
+// Sink streams activity events to an external system, e.g. a SIEM
+type Sink interface {
+	// Send delivers the event to the external system
+	Send(ctx context.Context, event *Event) error
+	// Close the sink releasing its resources
+	Close(ctx context.Context) error
+}
+
+// SinkStore is a Store that fans out every saved event to the configured sinks.
+// Events are delivered asynchronously so a slow or unavailable sink doesn't block storing events.
+type SinkStore struct {
+	Store
+	sinks  []*sinkWorker
+	wg     sync.WaitGroup
+	mu     sync.RWMutex
+	closed bool
+}
+
+type sinkWorker struct {
+	sink   Sink
+	events chan *Event
+}
+
+// NewSinkStore returns a Store saving events to the given store and streaming them to the sinks
+func NewSinkStore(ctx context.Context, store Store, sinks ...Sink) *SinkStore {
+	s := &SinkStore{Store: store}
+	for _, sink := range sinks {
+		worker := &sinkWorker{
+			sink:   sink,
+			events: make(chan *Event, sinkQueueSize),
+		}
+		s.sinks = append(s.sinks, worker)
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			worker.run(ctx)
+		}()
+	}
+	return s
+}
+
+// Save stores the event and queues it for delivery to the sinks
+func (s *SinkStore) Save(ctx context.Context, event *Event) (*Event, error) {
+	saved, err := s.Store.Save(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return saved, nil
+	}
+
+	for _, worker := range s.sinks {
+		select {
+		case worker.events <- saved.Copy():
+		default:
+			log.WithContext(ctx).Warnf("activity event sink queue is full, dropping event %d", saved.ID)
+		}
+	}
+
+	return saved, nil
+}
+
+// Close flushes the queued events to the sinks, closes them and the underlying store
+func (s *SinkStore) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		for _, worker := range s.sinks {
+			close(worker.events)
+		}
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.WithContext(ctx).Warnf("timed out flushing activity events to sinks")
+	}
+
+	for _, worker := range s.sinks {
+		if err := worker.sink.Close(ctx); err != nil {
+			log.WithContext(ctx).Errorf("failed to close activity event sink: %v", err)
+		}
+	}
+
+	return s.Store.Close(ctx)
+}
+
+func (w *sinkWorker) run(ctx context.Context) {
+	for event := range w.events {
+		if err := w.sink.Send(ctx, event); err != nil {
+			log.WithContext(ctx).Errorf("failed to send activity event %d to sink: %v", event.ID, err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSink struct {
+	mu     sync.Mutex
+	events []*Event
+	closed bool
+	err    error
+}
+
+func (s *testSink) Send(_ context.Context, event *Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return s.err
+}
+
+func (s *testSink) Close(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func TestSinkStore(t *testing.T) {
+	ctx := context.Background()
+	failing := &testSink{err: errors.New("unavailable")}
+	working := &testSink{}
+	store := NewSinkStore(ctx, &InMemoryEventStore{}, failing, working)
+
+	for i := 0; i < 10; i++ {
+		_, err := store.Save(ctx, &Event{
+			Timestamp:   time.Now().UTC(),
+			Activity:    PeerAddedByUser,
+			InitiatorID: "user",
+			TargetID:    "peer",
+			AccountID:   "account",
+		})
+		require.NoError(t, err)
+	}
+
+	events, err := store.Get(ctx, "account", 0, 10, false)
+	require.NoError(t, err)
+	assert.Len(t, events, 10, "events should be saved in the underlying store")
+
+	closeCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	require.NoError(t, store.Close(closeCtx))
+
+	for _, sink := range []*testSink{failing, working} {
+		assert.True(t, sink.closed)
+		require.Len(t, sink.events, 10, "queued events should be flushed on close")
+		for i, event := range sink.events {
+			assert.Equal(t, uint64(i+1), event.ID)
+		}
+	}
+
+	_, err = store.Save(ctx, &Event{Activity: PeerAddedByUser, AccountID: "account"})
+	require.NoError(t, err, "saving events after close should not panic")
+}
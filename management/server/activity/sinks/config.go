@@ -0,0 +1,109 @@
+package sinks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/util"
+)
+
+const (
+	// TypeWebhook sends events as HTTP POST requests signed with HMAC-SHA256
+	TypeWebhook = "webhook"
+	// TypeSyslog sends events as RFC 5424 syslog messages
+	TypeSyslog = "syslog"
+	// TypeFile writes events as JSON lines to a rotating file
+	TypeFile = "file"
+)
+
+// Config of an activity event sink. Only the configuration matching the Type is used.
+type Config struct {
+	Type    string
+	Webhook *WebhookConfig
+	Syslog  *SyslogConfig
+	File    *FileConfig
+}
+
+// WebhookConfig of a sink delivering events to an HTTP endpoint
+type WebhookConfig struct {
+	// URL the events are posted to
+	URL string
+	// Secret used to sign the requests. Requests are not signed when empty
+	Secret string
+	// Headers added to every request, e.g. for authorization
+	Headers map[string]string
+	// Timeout of a single delivery attempt
+	Timeout util.Duration
+	// MaxRetries is the number of retries of failed deliveries before the event is dropped
+	MaxRetries int
+}
+
+// SyslogConfig of a sink delivering events to a syslog server
+type SyslogConfig struct {
+	// Network is one of udp, tcp or tls
+	Network string
+	// Address of the syslog server in the host:port form
+	Address string
+	// Facility name, e.g. local0 or auth
+	Facility string
+	// AppName identifies the management service in the syslog messages
+	AppName string
+}
+
+// FileConfig of a sink writing events to a rotating JSON lines file
+type FileConfig struct {
+	// Path of the file
+	Path string
+	// MaxSizeMB is the size of the file in megabytes before it gets rotated
+	MaxSizeMB int
+	// MaxBackups is the number of rotated files to keep
+	MaxBackups int
+	// MaxAgeDays is the number of days to keep rotated files
+	MaxAgeDays int
+	// Compress rotated files with gzip
+	Compress bool
+}
+
+// NewSinks creates the activity event sinks from the configs
+func NewSinks(configs []*Config) ([]activity.Sink, error) {
+	sinks := make([]activity.Sink, 0, len(configs))
+	for _, config := range configs {
+		sink, err := newSink(config)
+		if err != nil {
+			for _, s := range sinks {
+				_ = s.Close(context.Background())
+			}
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+func newSink(config *Config) (activity.Sink, error) {
+	if config == nil {
+		return nil, errors.New("empty event sink config")
+	}
+
+	switch config.Type {
+	case TypeWebhook:
+		if config.Webhook == nil {
+			return nil, fmt.Errorf("missing %s event sink config", config.Type)
+		}
+		return NewWebhookSink(*config.Webhook)
+	case TypeSyslog:
+		if config.Syslog == nil {
+			return nil, fmt.Errorf("missing %s event sink config", config.Type)
+		}
+		return NewSyslogSink(*config.Syslog)
+	case TypeFile:
+		if config.File == nil {
+			return nil, fmt.Errorf("missing %s event sink config", config.Type)
+		}
+		return NewFileSink(*config.File)
+	default:
+		return nil, fmt.Errorf("unsupported event sink type: %s", config.Type)
+	}
+}
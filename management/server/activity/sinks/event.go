@@ -0,0 +1,46 @@
+package sinks
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+)
+
+// Event is the representation of an activity event delivered to the sinks.
+// It follows the format of the events returned by the management API.
+type Event struct {
+	ID           string         `json:"id"`
+	Timestamp    time.Time      `json:"timestamp"`
+	Activity     string         `json:"activity"`
+	ActivityCode string         `json:"activity_code"`
+	InitiatorID  string         `json:"initiator_id"`
+	TargetID     string         `json:"target_id"`
+	AccountID    string         `json:"account_id"`
+	Meta         map[string]any `json:"meta"`
+}
+
+// NewEvent converts the activity event to the sink representation
+func NewEvent(event *activity.Event) *Event {
+	meta := event.Meta
+	if meta == nil {
+		meta = make(map[string]any)
+	}
+
+	return &Event{
+		ID:           strconv.FormatUint(event.ID, 10),
+		Timestamp:    event.Timestamp.UTC(),
+		Activity:     event.Activity.Message(),
+		ActivityCode: event.Activity.StringCode(),
+		InitiatorID:  event.InitiatorID,
+		TargetID:     event.TargetID,
+		AccountID:    event.AccountID,
+		Meta:         meta,
+	}
+}
+
+// marshalEvent returns the JSON encoding of the activity event
+func marshalEvent(event *activity.Event) ([]byte, error) {
+	return json.Marshal(NewEvent(event))
+}
@@ -0,0 +1,72 @@
+package sinks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+)
+
+const (
+	defaultFileMaxSizeMB  = 100
+	defaultFileMaxBackups = 10
+)
+
+// FileSink writes activity events as JSON lines to a file which is rotated when it reaches the maximum size
+type FileSink struct {
+	mu     sync.Mutex
+	logger *lumberjack.Logger
+}
+
+// NewFileSink creates a file sink from the config
+func NewFileSink(config FileConfig) (*FileSink, error) {
+	if config.Path == "" {
+		return nil, errors.New("event sink file path is empty")
+	}
+
+	maxSize := config.MaxSizeMB
+	if maxSize <= 0 {
+		maxSize = defaultFileMaxSizeMB
+	}
+
+	maxBackups := config.MaxBackups
+	if maxBackups <= 0 {
+		maxBackups = defaultFileMaxBackups
+	}
+
+	return &FileSink{
+		logger: &lumberjack.Logger{
+			Filename:   filepath.ToSlash(config.Path),
+			MaxSize:    maxSize,
+			MaxBackups: maxBackups,
+			MaxAge:     config.MaxAgeDays,
+			Compress:   config.Compress,
+		},
+	}, nil
+}
+
+// Send appends the event as a JSON line to the file
+func (f *FileSink) Send(_ context.Context, event *activity.Event) error {
+	line, err := marshalEvent(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, err = f.logger.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the file
+func (f *FileSink) Close(_ context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.logger.Close()
+}
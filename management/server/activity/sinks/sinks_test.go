@@ -0,0 +1,187 @@
+package sinks
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/util"
+)
+
+func testEvent() *activity.Event {
+	return &activity.Event{
+		ID:          42,
+		Timestamp:   time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Activity:    activity.PeerAddedByUser,
+		InitiatorID: "user",
+		TargetID:    "peer",
+		AccountID:   "account",
+		Meta:        map[string]any{"name": "peer-a"},
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	secret := "secret"
+	var attempts atomic.Int32
+	received := make(chan *Event, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		err = VerifySignature([]byte(secret), r.Header.Get(TimestampHeader), body, r.Header.Get(SignatureHeader))
+		assert.NoError(t, err)
+		assert.Equal(t, "token", r.Header.Get("Authorization"))
+
+		var event Event
+		require.NoError(t, json.Unmarshal(body, &event))
+		received <- &event
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(WebhookConfig{
+		URL:        server.URL,
+		Secret:     secret,
+		Headers:    map[string]string{"Authorization": "token"},
+		Timeout:    util.Duration{Duration: time.Second},
+		MaxRetries: 3,
+	})
+	require.NoError(t, err)
+	defer sink.Close(context.Background())
+
+	require.NoError(t, sink.Send(context.Background(), testEvent()))
+	assert.Equal(t, int32(2), attempts.Load(), "failed delivery should be retried")
+
+	event := <-received
+	assert.Equal(t, "42", event.ID)
+	assert.Equal(t, activity.PeerAddedByUser.StringCode(), event.ActivityCode)
+	assert.Equal(t, "peer-a", event.Meta["name"])
+}
+
+func TestWebhookSink_RejectedEvent(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(WebhookConfig{URL: server.URL, MaxRetries: 3})
+	require.NoError(t, err)
+
+	require.Error(t, sink.Send(context.Background(), testEvent()))
+	assert.Equal(t, int32(1), attempts.Load(), "rejected events should not be retried")
+}
+
+func TestSyslogSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	sink, err := NewSyslogSink(SyslogConfig{Address: conn.LocalAddr().String(), Facility: "auth", AppName: "netbird"})
+	require.NoError(t, err)
+	defer sink.Close(context.Background())
+
+	require.NoError(t, sink.Send(context.Background(), testEvent()))
+
+	buf := make([]byte, 4096)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	message := string(buf[:n])
+	// facility auth (4) * 8 + severity informational (6)
+	assert.True(t, strings.HasPrefix(message, "<38>1 2024-05-01T10:00:00Z "), message)
+	assert.Contains(t, message, " netbird ")
+	assert.Contains(t, message, " "+activity.PeerAddedByUser.StringCode()+" ")
+	assert.Contains(t, message, `[netbird@32473 account_id="account" initiator_id="user" target_id="peer"]`)
+	assert.Contains(t, message, `"activity_code":"`+activity.PeerAddedByUser.StringCode()+`"`)
+}
+
+func TestSyslogSink_TCPFraming(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString(' ')
+		received <- line
+	}()
+
+	sink, err := NewSyslogSink(SyslogConfig{Network: "tcp", Address: listener.Addr().String()})
+	require.NoError(t, err)
+	defer sink.Close(context.Background())
+
+	message, err := sink.formatMessage(testEvent())
+	require.NoError(t, err)
+	require.NoError(t, sink.Send(context.Background(), testEvent()))
+
+	select {
+	case length := <-received:
+		assert.Equal(t, strconv.Itoa(len(message))+" ", length, "messages should be framed with octet counting")
+	case <-time.After(5 * time.Second):
+		t.Fatal("syslog message was not received")
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+	sink, err := NewFileSink(FileConfig{Path: path})
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Send(context.Background(), testEvent()))
+	require.NoError(t, sink.Send(context.Background(), testEvent()))
+	require.NoError(t, sink.Close(context.Background()))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		var event Event
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		assert.Equal(t, "account", event.AccountID)
+	}
+}
+
+func TestNewSinks(t *testing.T) {
+	_, err := NewSinks([]*Config{{Type: "unknown"}})
+	assert.Error(t, err)
+
+	_, err = NewSinks([]*Config{{Type: TypeWebhook}})
+	assert.Error(t, err, "missing webhook config should fail")
+
+	sinks, err := NewSinks([]*Config{
+		{Type: TypeFile, File: &FileConfig{Path: filepath.Join(t.TempDir(), "events.log")}},
+		{Type: TypeSyslog, Syslog: &SyslogConfig{Address: "127.0.0.1:514"}},
+	})
+	require.NoError(t, err)
+	assert.Len(t, sinks, 2)
+}
@@ -0,0 +1,214 @@
+package sinks
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+)
+
+const (
+	defaultSyslogAppName  = "netbird-management"
+	defaultSyslogFacility = "local0"
+	syslogDialTimeout     = 10 * time.Second
+	syslogWriteTimeout    = 10 * time.Second
+
+	// syslogSeverityInfo is the informational severity all activity events are sent with
+	syslogSeverityInfo = 6
+	// syslogSDID is the structured data element ID of the event details. 32473 is the private enterprise number reserved for documentation
+	syslogSDID = "netbird@32473"
+	// syslogNilValue is used for the header fields without a value
+	syslogNilValue = "-"
+)
+
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"audit":    13,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// SyslogSink sends activity events as RFC 5424 messages to a syslog server.
+// Messages sent over TCP and TLS are framed with octet counting as defined in RFC 6587.
+type SyslogSink struct {
+	network  string
+	address  string
+	facility int
+	appName  string
+	hostname string
+	procID   string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslogSink creates a syslog sink from the config
+func NewSyslogSink(config SyslogConfig) (*SyslogSink, error) {
+	network := config.Network
+	if network == "" {
+		network = "udp"
+	}
+	if network != "udp" && network != "tcp" && network != "tls" {
+		return nil, fmt.Errorf("unsupported syslog network: %s", network)
+	}
+
+	if _, _, err := net.SplitHostPort(config.Address); err != nil {
+		return nil, fmt.Errorf("invalid syslog address %s: %w", config.Address, err)
+	}
+
+	facilityName := config.Facility
+	if facilityName == "" {
+		facilityName = defaultSyslogFacility
+	}
+	facility, ok := syslogFacilities[facilityName]
+	if !ok {
+		return nil, fmt.Errorf("unsupported syslog facility: %s", facilityName)
+	}
+
+	appName := config.AppName
+	if appName == "" {
+		appName = defaultSyslogAppName
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = syslogNilValue
+	}
+
+	return &SyslogSink{
+		network:  network,
+		address:  config.Address,
+		facility: facility,
+		appName:  appName,
+		hostname: hostname,
+		procID:   fmt.Sprint(os.Getpid()),
+	}, nil
+}
+
+// Send writes the event to the syslog server reconnecting once if the connection was lost
+func (s *SyslogSink) Send(ctx context.Context, event *activity.Event) error {
+	message, err := s.formatMessage(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err = s.write(ctx, message); err == nil {
+		return nil
+	}
+
+	s.closeConn()
+	return s.write(ctx, message)
+}
+
+// Close closes the connection to the syslog server
+func (s *SyslogSink) Close(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeConn()
+	return nil
+}
+
+func (s *SyslogSink) write(ctx context.Context, message []byte) error {
+	if s.conn == nil {
+		conn, err := s.dial(ctx)
+		if err != nil {
+			return fmt.Errorf("connect to syslog server: %w", err)
+		}
+		s.conn = conn
+	}
+
+	if s.network != "udp" {
+		message = append([]byte(fmt.Sprintf("%d ", len(message))), message...)
+	}
+
+	if err := s.conn.SetWriteDeadline(time.Now().Add(syslogWriteTimeout)); err != nil {
+		return err
+	}
+
+	_, err := s.conn.Write(message)
+	return err
+}
+
+func (s *SyslogSink) dial(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: syslogDialTimeout}
+	if s.network == "tls" {
+		tlsDialer := &tls.Dialer{NetDialer: dialer}
+		return tlsDialer.DialContext(ctx, "tcp", s.address)
+	}
+	return dialer.DialContext(ctx, s.network, s.address)
+}
+
+func (s *SyslogSink) closeConn() {
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// formatMessage formats the event as an RFC 5424 message with the activity code as MSGID,
+// the event references as structured data and the JSON encoded event as message
+func (s *SyslogSink) formatMessage(event *activity.Event) ([]byte, error) {
+	body, err := marshalEvent(event)
+	if err != nil {
+		return nil, fmt.Errorf("marshal event: %w", err)
+	}
+
+	priority := s.facility*8 + syslogSeverityInfo
+	timestamp := event.Timestamp.UTC().Format(time.RFC3339Nano)
+	structuredData := fmt.Sprintf("[%s account_id=\"%s\" initiator_id=\"%s\" target_id=\"%s\"]", syslogSDID,
+		escapeSDParam(event.AccountID), escapeSDParam(event.InitiatorID), escapeSDParam(event.TargetID))
+
+	header := fmt.Sprintf("<%d>1 %s %s %s %s %s %s ", priority, timestamp, s.hostname, s.appName, s.procID,
+		headerValue(event.Activity.StringCode(), 32), structuredData)
+
+	return append([]byte(header), body...), nil
+}
+
+// escapeSDParam escapes the characters which must be escaped in structured data parameter values
+func escapeSDParam(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+}
+
+// headerValue returns a header field value limited to printable ASCII characters and the maximum length
+func headerValue(value string, maxLength int) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, value)
+
+	if value == "" {
+		return syslogNilValue
+	}
+	if len(value) > maxLength {
+		return value[:maxLength]
+	}
+	return value
+}
@@ -0,0 +1,142 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+)
+
+const (
+	// SignatureHeader contains the hex encoded HMAC-SHA256 of the timestamp and the body of the request
+	SignatureHeader = "X-Netbird-Signature"
+	// TimestampHeader contains the unix time the request was signed at
+	TimestampHeader = "X-Netbird-Timestamp"
+
+	defaultWebhookTimeout    = 10 * time.Second
+	defaultWebhookMaxRetries = 5
+)
+
+// WebhookSink posts activity events as JSON to an HTTP endpoint
+type WebhookSink struct {
+	url        string
+	secret     []byte
+	headers    map[string]string
+	maxRetries int
+	client     *http.Client
+}
+
+// NewWebhookSink creates a webhook sink from the config
+func NewWebhookSink(config WebhookConfig) (*WebhookSink, error) {
+	endpoint, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parse webhook URL: %w", err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported webhook URL scheme: %s", endpoint.Scheme)
+	}
+
+	timeout := config.Timeout.Duration
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+
+	maxRetries := config.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultWebhookMaxRetries
+	}
+
+	return &WebhookSink{
+		url:        endpoint.String(),
+		secret:     []byte(config.Secret),
+		headers:    config.Headers,
+		maxRetries: maxRetries,
+		client:     &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Send posts the event retrying with an exponential backoff on network errors and server side failures
+func (w *WebhookSink) Send(ctx context.Context, event *activity.Event) error {
+	body, err := marshalEvent(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	operation := func() error {
+		return w.post(ctx, body)
+	}
+
+	retry := backoff.WithContext(backoff.WithMaxRetries(backoff.NewExponentialBackOff(), uint64(w.maxRetries)), ctx)
+	return backoff.Retry(operation, retry)
+}
+
+func (w *WebhookSink) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return backoff.Permanent(fmt.Errorf("create request: %w", err))
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range w.headers {
+		req.Header.Set(key, value)
+	}
+
+	if len(w.secret) > 0 {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, "sha256="+Sign(w.secret, timestamp, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post event: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	default:
+		return backoff.Permanent(fmt.Errorf("webhook rejected event with status %d", resp.StatusCode))
+	}
+}
+
+// Close releases the idle connections of the webhook client
+func (w *WebhookSink) Close(_ context.Context) error {
+	w.client.CloseIdleConnections()
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the timestamp and the body joined by a dot.
+// Receivers should recompute the signature and reject requests with old timestamps to prevent replays.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks the signature header value of a webhook request
+func VerifySignature(secret []byte, timestamp string, body []byte, signature string) error {
+	expected := "sha256=" + Sign(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
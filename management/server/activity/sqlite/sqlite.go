@@ -4,8 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -58,6 +60,23 @@ const (
 		WHERE account_id = ? 
 		ORDER BY timestamp ASC LIMIT ? OFFSET ?;`
 
+	listQuery = `SELECT events.id, activity, timestamp, initiator_id, i.name as "initiator_name", i.email as "initiator_email", target_id, t.name as "target_name", t.email as "target_email", account_id, meta
+		FROM events 
+		LEFT JOIN (
+		    SELECT id, MAX(name) as name, MAX(email) as email 
+		    FROM deleted_users
+		    GROUP BY id
+		) i ON events.initiator_id = i.id 
+		LEFT JOIN (
+		    SELECT id, MAX(name) as name, MAX(email) as email 
+		    FROM deleted_users
+		    GROUP BY id
+		) t ON events.target_id = t.id
+		WHERE account_id = ?`
+
+	// createAccountIndexQuery creates the index used for listing the events of an account page by page
+	createAccountIndexQuery = `CREATE INDEX IF NOT EXISTS idx_events_account_id_id ON events (account_id, id);`
+
 	insertQuery = "INSERT INTO events(activity, timestamp, initiator_id, target_id, account_id, meta) " +
 		"VALUES(?, ?, ?, ?, ?, ?)"
 
@@ -214,6 +233,154 @@ func (store *Store) Get(ctx context.Context, accountID string, offset, limit int
 	return store.processResult(ctx, result)
 }
 
+// List returns the events of an account matching the filter ordered by ID.
+// The cursor is applied on the primary key, so the pages are read from the account index instead of skipping rows.
+func (store *Store) List(ctx context.Context, accountID string, filter activity.Filter) ([]*activity.Event, error) {
+	query, args := buildListQuery(accountID, filter)
+
+	result, err := store.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer result.Close() //nolint
+	return store.processResult(ctx, result)
+}
+
+func buildListQuery(accountID string, filter activity.Filter) (string, []any) {
+	var query strings.Builder
+	query.WriteString(listQuery)
+	args := []any{accountID}
+
+	if len(filter.Activities) > 0 {
+		placeholders := make([]string, len(filter.Activities))
+		for i, a := range filter.Activities {
+			placeholders[i] = "?"
+			args = append(args, a)
+		}
+		query.WriteString(" AND activity IN (" + strings.Join(placeholders, ", ") + ")")
+	}
+
+	if filter.InitiatorID != "" {
+		query.WriteString(" AND initiator_id = ?")
+		args = append(args, filter.InitiatorID)
+	}
+
+	if filter.TargetID != "" {
+		query.WriteString(" AND target_id = ?")
+		args = append(args, filter.TargetID)
+	}
+
+	if !filter.From.IsZero() {
+		query.WriteString(" AND timestamp >= ?")
+		args = append(args, filter.From.UTC())
+	}
+
+	if !filter.To.IsZero() {
+		query.WriteString(" AND timestamp < ?")
+		args = append(args, filter.To.UTC())
+	}
+
+	if filter.Search != "" {
+		query.WriteString(` AND meta LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+likeEscaper.Replace(filter.Search)+"%")
+	}
+
+	order := "ASC"
+	if filter.Descending {
+		order = "DESC"
+	}
+
+	if filter.Cursor != 0 {
+		if filter.Descending {
+			query.WriteString(" AND events.id < ?")
+		} else {
+			query.WriteString(" AND events.id > ?")
+		}
+		args = append(args, filter.Cursor)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+
+	query.WriteString(" ORDER BY events.id " + order + " LIMIT ?;")
+	args = append(args, limit)
+
+	return query.String(), args
+}
+
+// AccountIDs returns the IDs of the accounts having events
+func (store *Store) AccountIDs(ctx context.Context) ([]string, error) {
+	rows, err := store.db.QueryContext(ctx, `SELECT DISTINCT account_id FROM events;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close() //nolint
+
+	accountIDs := make([]string, 0)
+	for rows.Next() {
+		var accountID sql.NullString
+		if err = rows.Scan(&accountID); err != nil {
+			return nil, err
+		}
+		if accountID.Valid {
+			accountIDs = append(accountIDs, accountID.String)
+		}
+	}
+
+	return accountIDs, rows.Err()
+}
+
+// LastExpiredEventID returns the ID of the newest event of the account which happened before the given time
+// or isn't among the newest "keep" events. Zero time or keep disable the respective condition.
+// It returns 0 when no event is expired.
+func (store *Store) LastExpiredEventID(ctx context.Context, accountID string, before time.Time, keep int) (uint64, error) {
+	var lastID uint64
+
+	if keep > 0 {
+		var id sql.NullInt64
+		err := store.db.QueryRowContext(ctx, `SELECT id FROM events WHERE account_id = ? ORDER BY id DESC LIMIT 1 OFFSET ?;`,
+			accountID, keep).Scan(&id)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return 0, err
+		}
+		if id.Valid {
+			lastID = uint64(id.Int64)
+		}
+	}
+
+	if !before.IsZero() {
+		var id sql.NullInt64
+		err := store.db.QueryRowContext(ctx, `SELECT MAX(id) FROM events WHERE account_id = ? AND timestamp < ?;`,
+			accountID, before.UTC()).Scan(&id)
+		if err != nil {
+			return 0, err
+		}
+		if id.Valid && uint64(id.Int64) > lastID {
+			lastID = uint64(id.Int64)
+		}
+	}
+
+	return lastID, nil
+}
+
+// DeleteEvents deletes at most "limit" oldest events of the account up to and including the given ID
+// and returns the number of deleted events
+func (store *Store) DeleteEvents(ctx context.Context, accountID string, lastID uint64, limit int) (int64, error) {
+	result, err := store.db.ExecContext(ctx,
+		`DELETE FROM events WHERE id IN (SELECT id FROM events WHERE account_id = ? AND id <= ? ORDER BY id LIMIT ?);`,
+		accountID, lastID, limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// likeEscaper escapes the LIKE wildcards so the search text is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 // Save an event in the SQLite events table end encrypt the "email" element in meta map
 func (store *Store) Save(_ context.Context, event *activity.Event) (*activity.Event, error) {
 	var jsonMeta string
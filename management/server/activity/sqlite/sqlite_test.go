@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/netbirdio/netbird/management/server/activity"
 )
@@ -55,3 +56,101 @@ func TestNewSQLiteStore(t *testing.T) {
 	assert.Len(t, result, 5)
 	assert.True(t, result[0].Timestamp.After(result[len(result)-1].Timestamp))
 }
+
+func TestStore_List(t *testing.T) {
+	key, _ := GenerateKey()
+	store, err := NewSQLiteStore(context.Background(), t.TempDir(), key)
+	require.NoError(t, err)
+	defer store.Close(context.Background()) //nolint
+
+	accountID := "account_1"
+	start := time.Now().UTC().Add(-time.Hour)
+	for i := 0; i < 10; i++ {
+		typ := activity.PeerAddedByUser
+		if i%2 == 1 {
+			typ = activity.GroupCreated
+		}
+		_, err = store.Save(context.Background(), &activity.Event{
+			Timestamp:   start.Add(time.Duration(i) * time.Minute),
+			Activity:    typ,
+			InitiatorID: "user_" + fmt.Sprint(i%3),
+			TargetID:    "target_" + fmt.Sprint(i),
+			AccountID:   accountID,
+			Meta:        map[string]any{"name": fmt.Sprintf("Peer_%d%%", i)},
+		})
+		require.NoError(t, err)
+	}
+
+	_, err = store.Save(context.Background(), &activity.Event{
+		Timestamp:   start,
+		Activity:    activity.PeerAddedByUser,
+		InitiatorID: "user_0",
+		AccountID:   "account_2",
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		filter      activity.Filter
+		expectedIDs []uint64
+	}{
+		{
+			name:        "all events ascending",
+			filter:      activity.Filter{},
+			expectedIDs: []uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		},
+		{
+			name:        "activity and limit descending",
+			filter:      activity.Filter{Activities: []activity.Activity{activity.GroupCreated}, Limit: 3, Descending: true},
+			expectedIDs: []uint64{10, 8, 6},
+		},
+		{
+			name:        "cursor descending",
+			filter:      activity.Filter{Activities: []activity.Activity{activity.GroupCreated}, Cursor: 6, Limit: 3, Descending: true},
+			expectedIDs: []uint64{4, 2},
+		},
+		{
+			name:        "cursor ascending",
+			filter:      activity.Filter{Cursor: 8},
+			expectedIDs: []uint64{9, 10},
+		},
+		{
+			name:        "initiator",
+			filter:      activity.Filter{InitiatorID: "user_1"},
+			expectedIDs: []uint64{2, 5, 8},
+		},
+		{
+			name:        "target",
+			filter:      activity.Filter{TargetID: "target_4"},
+			expectedIDs: []uint64{5},
+		},
+		{
+			name:        "time range",
+			filter:      activity.Filter{From: start.Add(2 * time.Minute), To: start.Add(5 * time.Minute)},
+			expectedIDs: []uint64{3, 4, 5},
+		},
+		{
+			name:        "case-insensitive search",
+			filter:      activity.Filter{Search: "peer_7"},
+			expectedIDs: []uint64{8},
+		},
+		{
+			name:        "search escapes wildcards",
+			filter:      activity.Filter{Search: "_3%"},
+			expectedIDs: []uint64{4},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			events, err := store.List(context.Background(), accountID, tc.filter)
+			require.NoError(t, err)
+
+			ids := make([]uint64, 0, len(events))
+			for _, event := range events {
+				ids = append(ids, event.ID)
+			}
+			assert.Equal(t, tc.expectedIDs, ids)
+		})
+	}
+}
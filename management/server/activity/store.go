@@ -2,15 +2,84 @@ package activity
 
 import (
 	"context"
+	"encoding/json"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 )
 
+// Filter narrows down and paginates the events returned by Store.List. Zero value fields don't filter.
+type Filter struct {
+	// Activities limits the events to the given activities
+	Activities []Activity
+	// InitiatorID limits the events to the ones initiated by the given ID
+	InitiatorID string
+	// TargetID limits the events to the ones affecting the given ID
+	TargetID string
+	// From limits the events to the ones that happened at or after the given time
+	From time.Time
+	// To limits the events to the ones that happened before the given time
+	To time.Time
+	// Search limits the events to the ones containing the given text in the meta, case-insensitive
+	Search string
+	// Cursor is the ID of the last event of the previous page. Events following it in the requested order are returned
+	Cursor uint64
+	// Limit is the maximum number of events returned
+	Limit int
+	// Descending returns the newest events first
+	Descending bool
+}
+
+// Matches checks if the event passes the filter without taking the cursor into consideration
+func (f *Filter) Matches(event *Event) bool {
+	if len(f.Activities) > 0 {
+		found := false
+		for _, activity := range f.Activities {
+			if event.Activity != nil && event.Activity.StringCode() == activity.StringCode() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if f.InitiatorID != "" && event.InitiatorID != f.InitiatorID {
+		return false
+	}
+
+	if f.TargetID != "" && event.TargetID != f.TargetID {
+		return false
+	}
+
+	if !f.From.IsZero() && event.Timestamp.Before(f.From) {
+		return false
+	}
+
+	if !f.To.IsZero() && !event.Timestamp.Before(f.To) {
+		return false
+	}
+
+	if f.Search != "" {
+		meta, err := json.Marshal(event.Meta)
+		if err != nil || !strings.Contains(strings.ToLower(string(meta)), strings.ToLower(f.Search)) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Store provides an interface to store or stream events.
 type Store interface {
 	// Save an event in the store
 	Save(ctx context.Context, event *Event) (*Event, error)
 	// Get returns "limit" number of events from the "offset" index ordered descending or ascending by a timestamp
 	Get(ctx context.Context, accountID string, offset, limit int, descending bool) ([]*Event, error)
+	// List returns the events of an account matching the filter ordered by ID, which follows the order they were saved in
+	List(ctx context.Context, accountID string, filter Filter) ([]*Event, error)
 	// Close the sink flushing events if necessary
 	Close(ctx context.Context) error
 }
@@ -22,29 +91,65 @@ type InMemoryEventStore struct {
 	events []*Event
 }
 
-// Save sets the Event.ID to 1
+// Save sets the Event.ID to the next sequential ID starting from 1
 func (store *InMemoryEventStore) Save(_ context.Context, event *Event) (*Event, error) {
 	store.mu.Lock()
 	defer store.mu.Unlock()
 	if store.events == nil {
 		store.events = make([]*Event, 0)
 	}
-	event.ID = store.nextID
 	store.nextID++
+	event.ID = store.nextID
 	store.events = append(store.events, event)
 	return event, nil
 }
 
-// Get returns a list of ALL events that belong to the given accountID without taking offset, limit and order into consideration
-func (store *InMemoryEventStore) Get(_ context.Context, accountID string, offset, limit int, descending bool) ([]*Event, error) {
+// Get returns the events of the given accountID in the requested order, skipping offset events and returning at most
+// limit events
+func (store *InMemoryEventStore) Get(ctx context.Context, accountID string, offset, limit int, descending bool) ([]*Event, error) {
+	events, err := store.List(ctx, accountID, Filter{Descending: descending})
+	if err != nil {
+		return nil, err
+	}
+
+	if offset >= len(events) {
+		return make([]*Event, 0), nil
+	}
+	events = events[max(offset, 0):]
+
+	if limit > 0 && len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+// List returns the events of the given accountID matching the filter
+func (store *InMemoryEventStore) List(_ context.Context, accountID string, filter Filter) ([]*Event, error) {
 	store.mu.Lock()
 	defer store.mu.Unlock()
+
 	events := make([]*Event, 0)
 	for _, event := range store.events {
-		if event.AccountID == accountID {
-			events = append(events, event)
+		if event.AccountID != accountID || !filter.Matches(event) {
+			continue
 		}
+		if filter.Cursor != 0 && (filter.Descending && event.ID >= filter.Cursor || !filter.Descending && event.ID <= filter.Cursor) {
+			continue
+		}
+		events = append(events, event)
 	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if filter.Descending {
+			return events[i].ID > events[j].ID
+		}
+		return events[i].ID < events[j].ID
+	})
+
+	if filter.Limit > 0 && len(events) > filter.Limit {
+		events = events[:filter.Limit]
+	}
+
 	return events, nil
 }
 
@@ -0,0 +1,39 @@
+package activity
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryEventStore_Get(t *testing.T) {
+	store := &InMemoryEventStore{}
+	for i := 0; i < 5; i++ {
+		_, err := store.Save(context.Background(), &Event{AccountID: "account", Activity: PeerAddedByUser})
+		require.NoError(t, err)
+	}
+	_, err := store.Save(context.Background(), &Event{AccountID: "other", Activity: PeerAddedByUser})
+	require.NoError(t, err)
+
+	eventIDs := func(events []*Event) []uint64 {
+		ids := make([]uint64, 0, len(events))
+		for _, event := range events {
+			ids = append(ids, event.ID)
+		}
+		return ids
+	}
+
+	events, err := store.Get(context.Background(), "account", 0, 10, false)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5}, eventIDs(events), "only the events of the account should be returned")
+
+	events, err = store.Get(context.Background(), "account", 1, 2, true)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{4, 3}, eventIDs(events), "offset and limit should be applied to the descending order")
+
+	events, err = store.Get(context.Background(), "account", 5, 2, false)
+	require.NoError(t, err)
+	assert.Empty(t, events, "offset past the events should return no events")
+}
@@ -4,6 +4,9 @@ import (
 	"net/netip"
 	"net/url"
 
+	"github.com/netbirdio/netbird/management/server/activity/retention"
+	"github.com/netbirdio/netbird/management/server/activity/sinks"
+	"github.com/netbirdio/netbird/management/server/flows"
 	"github.com/netbirdio/netbird/management/server/idp"
 	"github.com/netbirdio/netbird/management/server/store"
 	"github.com/netbirdio/netbird/util"
@@ -52,6 +55,21 @@ type Config struct {
 	StoreConfig StoreConfig
 
 	ReverseProxy ReverseProxy
+
+	// EventSinks stream the activity events to external systems in addition to the events store
+	EventSinks []*sinks.Config
+
+	// EventRetention prunes and optionally archives the activity events exceeding the retention policies
+	EventRetention *retention.Config
+
+	// Flows enables the upload of the connection flow events recorded by the peers
+	Flows *flows.Config
+
+	// Cluster enables running multiple management instances sharing the same store
+	Cluster *ClusterConfig
+
+	// PeerApproval replaces the integrated peer validator with the built-in peer approval validator
+	PeerApproval bool
 }
 
 // GetAuthAudiences returns the audience from the http config and device authorization flow config
@@ -160,6 +178,23 @@ type StoreConfig struct {
 	Engine store.Engine
 }
 
+// UpdateBusKind is the kind of bus delivering the peer updates between the management instances
+type UpdateBusKind string
+
+const (
+	// MemoryUpdateBusKind delivers the updates within a single management instance
+	MemoryUpdateBusKind UpdateBusKind = "memory"
+	// PostgresUpdateBusKind delivers the updates between the instances with Postgres LISTEN/NOTIFY
+	PostgresUpdateBusKind UpdateBusKind = "postgres"
+)
+
+// ClusterConfig contains the configuration of the management instances sharing the same store
+type ClusterConfig struct {
+	// UpdateBus delivers the peer updates between the instances, memory by default. The postgres bus requires
+	// the postgres store engine and makes the account locks shared between the instances too.
+	UpdateBus UpdateBusKind
+}
+
 // ReverseProxy contains reverse proxy configuration in front of management.
 type ReverseProxy struct {
 	// TrustedHTTPProxies represents a list of trusted HTTP proxies by their IP prefixes.
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+// checkCustomRolePermission returns the permission of the user custom role granting the operation on the module.
+// It is used for users without admin power, who are denied unless their custom role grants the operation.
+// The returned permission can be scoped to groups, which must be checked against the affected objects.
+func (am *DefaultAccountManager) checkCustomRolePermission(ctx context.Context, user *types.User, module permissions.Module, operation permissions.Operation) (*types.RolePermission, error) {
+	if user.CustomRoleID == "" || user.Role != types.UserRoleUser || user.IsBlocked() {
+		return nil, status.NewAdminPermissionError()
+	}
+
+	role, err := am.Store.GetCustomRoleByID(ctx, store.LockingStrengthShare, user.AccountID, user.CustomRoleID)
+	if err != nil {
+		log.WithContext(ctx).Warnf("failed to get custom role %s of user %s: %v", user.CustomRoleID, user.Id, err)
+		return nil, status.NewAdminPermissionError()
+	}
+
+	permission := permissions.GetCustomRolePermission(role, module, operation)
+	if permission == nil {
+		return nil, status.NewAdminPermissionError()
+	}
+
+	return permission, nil
+}
+
+// checkUserCustomRolePermission loads the user and checks its custom role permission when the user has no admin power.
+// It returns a nil permission for admins, which is never scoped.
+func (am *DefaultAccountManager) checkUserCustomRolePermission(ctx context.Context, accountID, userID string, module permissions.Module, operation permissions.Operation) (*types.RolePermission, error) {
+	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.AccountID != accountID {
+		return nil, status.NewUserNotPartOfAccountError()
+	}
+
+	if user.HasAdminPower() {
+		return nil, nil
+	}
+
+	return am.checkCustomRolePermission(ctx, user, module, operation)
+}
+
+// filterByRoleScope returns the objects whose groups are covered by the permission scope
+func filterByRoleScope[T any](permission *types.RolePermission, objects []T, getGroups func(T) []string) []T {
+	if !permission.IsScoped() {
+		return objects
+	}
+
+	filtered := make([]T, 0, len(objects))
+	for _, object := range objects {
+		if permission.CoversAllGroups(getGroups(object)) {
+			filtered = append(filtered, object)
+		}
+	}
+	return filtered
+}
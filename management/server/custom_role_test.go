@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/server/idp"
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+func setupCustomRoleAccount(t *testing.T, permissions []types.RolePermission) (*DefaultAccountManager, string, string) {
+	t.Helper()
+
+	manager, err := createManager(t)
+	require.NoError(t, err)
+
+	adminID := "adminUser"
+	account, err := manager.GetOrCreateAccountByUser(context.Background(), adminID, "")
+	require.NoError(t, err)
+
+	err = manager.SaveGroups(context.Background(), account.Id, adminID, []*types.Group{
+		{ID: "office", Name: "office", Peers: []string{}},
+		{ID: "datacenter", Name: "datacenter", Peers: []string{}},
+	})
+	require.NoError(t, err)
+
+	role := types.NewCustomRole(account.Id, "operators", "", permissions)
+	err = manager.Store.SaveCustomRole(context.Background(), store.LockingStrengthUpdate, role)
+	require.NoError(t, err)
+
+	operatorID := "operatorUser"
+	operator := types.NewRegularUser(operatorID)
+	operator.AccountID = account.Id
+	operator.CustomRoleID = role.ID
+	err = manager.Store.SaveUser(context.Background(), store.LockingStrengthUpdate, operator)
+	require.NoError(t, err)
+
+	return manager, account.Id, operatorID
+}
+
+func TestCustomRole_SetupKeys(t *testing.T) {
+	manager, accountID, operatorID := setupCustomRoleAccount(t, []types.RolePermission{
+		{Module: "setup_keys", Read: true, Write: true, Groups: []string{"office"}},
+	})
+
+	officeKey, err := manager.CreateSetupKey(context.Background(), accountID, "office", types.SetupKeyReusable, time.Hour,
+		[]string{"office"}, types.SetupKeyUnlimitedUsage, operatorID, false)
+	require.NoError(t, err, "role scope should allow creating keys of the office group")
+
+	_, err = manager.CreateSetupKey(context.Background(), accountID, "datacenter", types.SetupKeyReusable, time.Hour,
+		[]string{"datacenter"}, types.SetupKeyUnlimitedUsage, operatorID, false)
+	requireStatusType(t, err, status.PermissionDenied)
+
+	datacenterKey, err := manager.CreateSetupKey(context.Background(), accountID, "datacenter", types.SetupKeyReusable, time.Hour,
+		[]string{"datacenter"}, types.SetupKeyUnlimitedUsage, "adminUser", false)
+	require.NoError(t, err)
+
+	keys, err := manager.ListSetupKeys(context.Background(), accountID, operatorID)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, officeKey.Id, keys[0].Id)
+
+	_, err = manager.GetSetupKey(context.Background(), accountID, operatorID, datacenterKey.Id)
+	requireStatusType(t, err, status.NotFound)
+
+	err = manager.DeleteSetupKey(context.Background(), accountID, operatorID, officeKey.Id)
+	requireStatusType(t, err, status.PermissionDenied)
+}
+
+func TestCustomRole_Groups(t *testing.T) {
+	manager, accountID, operatorID := setupCustomRoleAccount(t, []types.RolePermission{
+		{Module: "groups", Read: true, Delete: true, Groups: []string{"office"}},
+	})
+
+	groups, err := manager.GetAllGroups(context.Background(), accountID, operatorID)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, "office", groups[0].ID)
+
+	_, err = manager.GetGroup(context.Background(), accountID, "datacenter", operatorID)
+	requireStatusType(t, err, status.NotFound)
+
+	err = manager.SaveGroup(context.Background(), accountID, operatorID, &types.Group{ID: "office", Name: "office-2"})
+	requireStatusType(t, err, status.PermissionDenied)
+
+	err = manager.DeleteGroup(context.Background(), accountID, operatorID, "datacenter")
+	requireStatusType(t, err, status.PermissionDenied)
+
+	err = manager.DeleteGroup(context.Background(), accountID, "adminUser", "office")
+	require.Error(t, err, "groups referenced by custom roles should not be deleted")
+}
+
+func TestCustomRole_WithoutRole(t *testing.T) {
+	manager, accountID, _ := setupCustomRoleAccount(t, nil)
+
+	regularUser := types.NewRegularUser("regularUser")
+	regularUser.AccountID = accountID
+	err := manager.Store.SaveUser(context.Background(), store.LockingStrengthUpdate, regularUser)
+	require.NoError(t, err)
+
+	_, err = manager.ListSetupKeys(context.Background(), accountID, regularUser.Id)
+	requireStatusType(t, err, status.PermissionDenied)
+
+	_, err = manager.ListPostureChecks(context.Background(), accountID, regularUser.Id)
+	requireStatusType(t, err, status.PermissionDenied)
+}
+
+func TestCustomRole_AssignToUser(t *testing.T) {
+	manager, accountID, operatorID := setupCustomRoleAccount(t, []types.RolePermission{
+		{Module: "users", Read: true, Write: true},
+	})
+
+	operator, err := manager.Store.GetUserByUserID(context.Background(), store.LockingStrengthShare, operatorID)
+	require.NoError(t, err)
+
+	_, err = manager.SaveUser(context.Background(), accountID, "adminUser", &types.User{
+		Id:           operatorID,
+		Role:         types.UserRoleAdmin,
+		CustomRoleID: operator.CustomRoleID,
+	})
+	requireStatusType(t, err, status.InvalidArgument)
+
+	_, err = manager.SaveUser(context.Background(), accountID, "adminUser", &types.User{
+		Id:           operatorID,
+		Role:         types.UserRoleUser,
+		CustomRoleID: "unknown",
+	})
+	requireStatusType(t, err, status.NotFound)
+
+	_, err = manager.SaveUser(context.Background(), accountID, operatorID, &types.User{
+		Id:   operatorID,
+		Role: types.UserRoleUser,
+	})
+	requireStatusType(t, err, status.PermissionDenied)
+
+	userInfo, err := manager.SaveUser(context.Background(), accountID, "adminUser", &types.User{
+		Id:   operatorID,
+		Role: types.UserRoleUser,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, userInfo.CustomRoleID)
+}
+
+func TestCustomRole_InviteUser(t *testing.T) {
+	manager, accountID, operatorID := setupCustomRoleAccount(t, []types.RolePermission{
+		{Module: "users", Read: true, Write: true, Groups: []string{"office"}},
+	})
+	manager.idpManager = &idp.MockIDP{}
+
+	_, err := manager.CreateUser(context.Background(), accountID, operatorID, &types.UserInfo{
+		Name:  "admin",
+		Email: "admin@example.com",
+		Role:  string(types.UserRoleAdmin),
+	})
+	requireStatusType(t, err, status.PermissionDenied)
+
+	_, err = manager.CreateUser(context.Background(), accountID, operatorID, &types.UserInfo{
+		Name:       "datacenter",
+		Email:      "datacenter@example.com",
+		Role:       string(types.UserRoleUser),
+		AutoGroups: []string{"datacenter"},
+	})
+	requireStatusType(t, err, status.PermissionDenied)
+}
+
+func requireStatusType(t *testing.T, err error, expectedType status.Type) {
+	t.Helper()
+
+	require.Error(t, err)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok, "expected a status error, got %v", err)
+	assert.Equal(t, expectedType, sErr.Type(), sErr.Message)
+}
@@ -10,6 +10,7 @@ import (
 	nbdns "github.com/netbirdio/netbird/dns"
 	"github.com/netbirdio/netbird/management/proto"
 	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/permissions"
 	"github.com/netbirdio/netbird/management/server/status"
 	"github.com/netbirdio/netbird/management/server/store"
 	"github.com/netbirdio/netbird/management/server/types"
@@ -72,7 +73,9 @@ func (am *DefaultAccountManager) GetDNSSettings(ctx context.Context, accountID s
 	}
 
 	if user.IsRegularUser() {
-		return nil, status.NewAdminPermissionError()
+		if _, err = am.checkCustomRolePermission(ctx, user, permissions.DNS, permissions.Read); err != nil {
+			return nil, err
+		}
 	}
 
 	return am.Store.GetAccountDNSSettings(ctx, store.LockingStrengthShare, accountID)
@@ -93,8 +96,16 @@ func (am *DefaultAccountManager) SaveDNSSettings(ctx context.Context, accountID
 		return status.NewUserNotPartOfAccountError()
 	}
 
+	var permission *types.RolePermission
 	if !user.HasAdminPower() {
-		return status.NewAdminPermissionError()
+		permission, err = am.checkCustomRolePermission(ctx, user, permissions.DNS, permissions.Write)
+		if err != nil {
+			return err
+		}
+	}
+
+	if permission.IsScoped() {
+		return status.Errorf(status.PermissionDenied, "DNS settings can't be updated with a role limited to groups")
 	}
 
 	var updateAccountPeers bool
@@ -272,9 +283,11 @@ func convertToProtoNameServerGroup(nsGroup *nbdns.NameServerGroup) *proto.NameSe
 	}
 	for _, ns := range nsGroup.NameServers {
 		protoGroup.NameServers = append(protoGroup.NameServers, &proto.NameServer{
-			IP:     ns.IP.String(),
-			Port:   int64(ns.Port),
-			NSType: int64(ns.NSType),
+			IP:         ns.IP.String(),
+			Port:       int64(ns.Port),
+			NSType:     int64(ns.NSType),
+			ServerName: ns.ServerName,
+			Path:       ns.Path,
 		})
 	}
 	return protoGroup
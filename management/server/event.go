@@ -9,6 +9,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/permissions"
 	"github.com/netbirdio/netbird/management/server/status"
 )
 
@@ -17,28 +18,45 @@ func isEnabled() bool {
 	return response == "" || response == "true"
 }
 
-// GetEvents returns a list of activity events of an account
-func (am *DefaultAccountManager) GetEvents(ctx context.Context, accountID, userID string) ([]*activity.Event, error) {
+// maxEventsLimit is the maximum number of events returned by a single GetEvents call
+const maxEventsLimit = 10000
+
+// GetEvents returns a list of activity events of an account matching the filter and the cursor of the next page.
+// When the filter has no limit or exceeds maxEventsLimit, at most maxEventsLimit events are returned.
+// The next cursor is zero when there are no more events to return.
+func (am *DefaultAccountManager) GetEvents(ctx context.Context, accountID, userID string, filter activity.Filter) ([]*activity.Event, uint64, error) {
 	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
 	defer unlock()
 
 	account, err := am.Store.GetAccount(ctx, accountID)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	user, err := account.FindUser(userID)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
 	if !(user.HasAdminPower() || user.IsServiceUser) {
-		return nil, status.Errorf(status.PermissionDenied, "only users with admin power can view events")
+		if _, err = am.checkCustomRolePermission(ctx, user, permissions.Events, permissions.Read); err != nil {
+			return nil, 0, status.Errorf(status.PermissionDenied, "only users with admin power can view events")
+		}
+	}
+
+	if filter.Limit <= 0 || filter.Limit > maxEventsLimit {
+		filter.Limit = maxEventsLimit
 	}
 
-	events, err := am.eventStore.Get(ctx, accountID, 0, 10000, true)
+	events, err := am.eventStore.List(ctx, accountID, filter)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	// the next page follows the last event returned by the store, the duplicates removed below don't shorten the page
+	var nextCursor uint64
+	if len(events) == filter.Limit {
+		nextCursor = events[len(events)-1].ID
 	}
 
 	// this is a workaround for duplicate activity.UserJoined events that might occur when a user redeems invite.
@@ -58,14 +76,19 @@ func (am *DefaultAccountManager) GetEvents(ctx context.Context, accountID, userI
 		filtered = append(filtered, event)
 	}
 
-	return filtered, nil
+	return filtered, nextCursor, nil
 }
 
 func (am *DefaultAccountManager) StoreEvent(ctx context.Context, initiatorID, targetID, accountID string, activityID activity.ActivityDescriber, meta map[string]any) {
+	am.storeEventAt(ctx, time.Now(), initiatorID, targetID, accountID, activityID, meta)
+}
+
+// storeEventAt stores an activity event that happened at the given time, e.g. reported later by a peer
+func (am *DefaultAccountManager) storeEventAt(ctx context.Context, timestamp time.Time, initiatorID, targetID, accountID string, activityID activity.ActivityDescriber, meta map[string]any) {
 	if isEnabled() {
 		go func() {
 			_, err := am.eventStore.Save(ctx, &activity.Event{
-				Timestamp:   time.Now().UTC(),
+				Timestamp:   timestamp.UTC(),
 				Activity:    activityID,
 				InitiatorID: initiatorID,
 				TargetID:    targetID,
@@ -36,7 +36,7 @@ func TestDefaultAccountManager_GetEvents(t *testing.T) {
 	accountID := "accountID"
 
 	t.Run("get empty events list", func(t *testing.T) {
-		events, err := manager.GetEvents(context.Background(), accountID, userID)
+		events, _, err := manager.GetEvents(context.Background(), accountID, userID, activity.Filter{Descending: true})
 		if err != nil {
 			return
 		}
@@ -46,7 +46,7 @@ func TestDefaultAccountManager_GetEvents(t *testing.T) {
 
 	t.Run("get events", func(t *testing.T) {
 		generateAndStoreEvents(t, manager, activity.PeerAddedByUser, userID, "peer", accountID, 10)
-		events, err := manager.GetEvents(context.Background(), accountID, userID)
+		events, _, err := manager.GetEvents(context.Background(), accountID, userID, activity.Filter{Descending: true})
 		if err != nil {
 			return
 		}
@@ -57,11 +57,36 @@ func TestDefaultAccountManager_GetEvents(t *testing.T) {
 
 	t.Run("get events without duplicates", func(t *testing.T) {
 		generateAndStoreEvents(t, manager, activity.UserJoined, userID, "", accountID, 10)
-		events, err := manager.GetEvents(context.Background(), accountID, userID)
+		events, _, err := manager.GetEvents(context.Background(), accountID, userID, activity.Filter{Descending: true})
 		if err != nil {
 			return
 		}
 		assert.Len(t, events, 1)
 		_ = manager.eventStore.Close(context.Background()) //nolint
 	})
+
+	t.Run("get filtered events page", func(t *testing.T) {
+		generateAndStoreEvents(t, manager, activity.PeerAddedByUser, userID, "peer", accountID, 5)
+		generateAndStoreEvents(t, manager, activity.GroupCreated, userID, "group", accountID, 5)
+
+		filter := activity.Filter{Activities: []activity.Activity{activity.GroupCreated}, Limit: 3, Descending: true}
+		events, nextCursor, err := manager.GetEvents(context.Background(), accountID, userID, filter)
+		if err != nil {
+			return
+		}
+		assert.Len(t, events, 3)
+		assert.Equal(t, events[len(events)-1].ID, nextCursor)
+
+		filter.Cursor = nextCursor
+		events, nextCursor, err = manager.GetEvents(context.Background(), accountID, userID, filter)
+		if err != nil {
+			return
+		}
+		assert.Len(t, events, 2)
+		assert.Zero(t, nextCursor, "the last page should not have a next cursor")
+		for _, event := range events {
+			assert.Equal(t, activity.GroupCreated, event.Activity)
+		}
+		_ = manager.eventStore.Close(context.Background()) //nolint
+	})
 }
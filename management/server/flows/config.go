@@ -0,0 +1,21 @@
+package flows
+
+import (
+	"github.com/netbirdio/netbird/util"
+)
+
+// Config of the connection flow events uploaded by the peers. Peers don't upload flow events when not set
+type Config struct {
+	// Interval between the uploads of a peer, defaults to one minute
+	Interval util.Duration
+	// Path of the JSON lines file the flow events are written to
+	Path string
+	// MaxSizeMB is the size of the file in megabytes before it gets rotated
+	MaxSizeMB int
+	// MaxBackups is the number of rotated files to keep
+	MaxBackups int
+	// MaxAgeDays is the number of days to keep rotated files
+	MaxAgeDays int
+	// Compress rotated files with gzip
+	Compress bool
+}
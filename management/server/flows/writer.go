@@ -0,0 +1,173 @@
+package flows
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/netbirdio/netbird/management/proto"
+)
+
+const (
+	// DefaultInterval between the flow event uploads of a peer
+	DefaultInterval = time.Minute
+
+	defaultFileMaxSizeMB  = 100
+	defaultFileMaxBackups = 10
+)
+
+// Event is a flow event of a peer as written to the file
+type Event struct {
+	EventID    string    `json:"event_id"`
+	Timestamp  time.Time `json:"timestamp"`
+	ReceivedAt time.Time `json:"received_at"`
+	AccountID  string    `json:"account_id"`
+	PeerKey    string    `json:"peer_key"`
+	FlowID     string    `json:"flow_id"`
+	Type       string    `json:"type"`
+	RuleID     string    `json:"rule_id,omitempty"`
+	Direction  string    `json:"direction"`
+	Protocol   uint32    `json:"protocol"`
+	SourceIP   string    `json:"source_ip"`
+	DestIP     string    `json:"dest_ip"`
+	SourcePort uint32    `json:"source_port,omitempty"`
+	DestPort   uint32    `json:"dest_port,omitempty"`
+	ICMPType   uint32    `json:"icmp_type,omitempty"`
+	ICMPCode   uint32    `json:"icmp_code,omitempty"`
+	RxPackets  uint64    `json:"rx_packets,omitempty"`
+	TxPackets  uint64    `json:"tx_packets,omitempty"`
+	RxBytes    uint64    `json:"rx_bytes,omitempty"`
+	TxBytes    uint64    `json:"tx_bytes,omitempty"`
+}
+
+// Writer writes the flow events of the peers as JSON lines to a file which is rotated when it reaches the maximum size
+type Writer struct {
+	mu     sync.Mutex
+	logger *lumberjack.Logger
+}
+
+// NewWriter creates a flow event writer from the config
+func NewWriter(config *Config) (*Writer, error) {
+	if config == nil || config.Path == "" {
+		return nil, errors.New("flow events file path is empty")
+	}
+
+	maxSize := config.MaxSizeMB
+	if maxSize <= 0 {
+		maxSize = defaultFileMaxSizeMB
+	}
+
+	maxBackups := config.MaxBackups
+	if maxBackups <= 0 {
+		maxBackups = defaultFileMaxBackups
+	}
+
+	return &Writer{
+		logger: &lumberjack.Logger{
+			Filename:   filepath.ToSlash(config.Path),
+			MaxSize:    maxSize,
+			MaxBackups: maxBackups,
+			MaxAge:     config.MaxAgeDays,
+			Compress:   config.Compress,
+		},
+	}, nil
+}
+
+// Write appends the flow events of a peer to the file
+func (w *Writer) Write(accountID, peerKey string, events []*proto.FlowEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	receivedAt := time.Now().UTC()
+
+	var buf []byte
+	for _, event := range events {
+		line, err := json.Marshal(toEvent(accountID, peerKey, receivedAt, event))
+		if err != nil {
+			return fmt.Errorf("marshal flow event: %w", err)
+		}
+		buf = append(buf, line...)
+		buf = append(buf, '\n')
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, err := w.logger.Write(buf)
+	return err
+}
+
+// Close closes the file
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.logger.Close()
+}
+
+func toEvent(accountID, peerKey string, receivedAt time.Time, event *proto.FlowEvent) *Event {
+	e := &Event{
+		EventID:    bytesToUUID(event.GetEventId()),
+		ReceivedAt: receivedAt,
+		AccountID:  accountID,
+		PeerKey:    peerKey,
+		FlowID:     bytesToUUID(event.GetFlowId()),
+		Type:       flowTypeName(event.GetType()),
+		RuleID:     event.GetRuleId(),
+		Direction:  directionName(event.GetDirection()),
+		Protocol:   event.GetProtocol(),
+		SourceIP:   bytesToAddr(event.GetSourceIp()),
+		DestIP:     bytesToAddr(event.GetDestIp()),
+		SourcePort: event.GetSourcePort(),
+		DestPort:   event.GetDestPort(),
+		ICMPType:   event.GetIcmpType(),
+		ICMPCode:   event.GetIcmpCode(),
+		RxPackets:  event.GetRxPackets(),
+		TxPackets:  event.GetTxPackets(),
+		RxBytes:    event.GetRxBytes(),
+		TxBytes:    event.GetTxBytes(),
+	}
+	if ts := event.GetTimestamp(); ts != nil {
+		e.Timestamp = ts.AsTime()
+	}
+	return e
+}
+
+func flowTypeName(t proto.FlowEventType) string {
+	return strings.ToLower(strings.TrimPrefix(t.String(), "FLOW_"))
+}
+
+func directionName(d proto.RuleDirection) string {
+	switch d {
+	case proto.RuleDirection_IN:
+		return "ingress"
+	case proto.RuleDirection_OUT:
+		return "egress"
+	default:
+		return "unknown"
+	}
+}
+
+func bytesToUUID(b []byte) string {
+	id, err := uuid.FromBytes(b)
+	if err != nil {
+		return ""
+	}
+	return id.String()
+}
+
+func bytesToAddr(b []byte) string {
+	addr, ok := netip.AddrFromSlice(b)
+	if !ok {
+		return ""
+	}
+	return addr.Unmap().String()
+}
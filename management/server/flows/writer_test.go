@@ -0,0 +1,90 @@
+package flows
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/netbirdio/netbird/management/proto"
+)
+
+func TestNewWriter(t *testing.T) {
+	_, err := NewWriter(nil)
+	require.Error(t, err)
+
+	_, err = NewWriter(&Config{})
+	require.Error(t, err, "empty path should be rejected")
+}
+
+func TestWriter_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flows.log")
+	writer, err := NewWriter(&Config{Path: path})
+	require.NoError(t, err)
+
+	eventID := uuid.New()
+	flowID := uuid.New()
+	timestamp := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	events := []*proto.FlowEvent{
+		{
+			EventId:    eventID[:],
+			Timestamp:  timestamppb.New(timestamp),
+			FlowId:     flowID[:],
+			Type:       proto.FlowEventType_FLOW_END,
+			RuleId:     "rule-1",
+			Direction:  proto.RuleDirection_OUT,
+			Protocol:   6,
+			SourceIp:   []byte{100, 64, 0, 1},
+			DestIp:     []byte{100, 64, 0, 2},
+			SourcePort: 40000,
+			DestPort:   443,
+			TxBytes:    1024,
+		},
+		{
+			Type:      proto.FlowEventType_FLOW_DROP,
+			Direction: proto.RuleDirection_IN,
+			Protocol:  1,
+			SourceIp:  []byte{100, 64, 0, 3},
+			DestIp:    []byte{100, 64, 0, 1},
+		},
+	}
+
+	require.NoError(t, writer.Write("account", "peer-key", events))
+	require.NoError(t, writer.Write("account", "peer-key", nil))
+	require.NoError(t, writer.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+
+	var first Event
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, eventID.String(), first.EventID)
+	assert.Equal(t, flowID.String(), first.FlowID)
+	assert.Equal(t, timestamp, first.Timestamp)
+	assert.Equal(t, "account", first.AccountID)
+	assert.Equal(t, "peer-key", first.PeerKey)
+	assert.Equal(t, "end", first.Type)
+	assert.Equal(t, "rule-1", first.RuleID)
+	assert.Equal(t, "egress", first.Direction)
+	assert.Equal(t, "100.64.0.1", first.SourceIP)
+	assert.Equal(t, "100.64.0.2", first.DestIP)
+	assert.Equal(t, uint32(443), first.DestPort)
+	assert.Equal(t, uint64(1024), first.TxBytes)
+
+	var second Event
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+	assert.Equal(t, "drop", second.Type)
+	assert.Equal(t, "ingress", second.Direction)
+	assert.Empty(t, second.EventID)
+}
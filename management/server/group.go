@@ -16,6 +16,7 @@ import (
 	"github.com/netbirdio/netbird/route"
 
 	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/permissions"
 	"github.com/netbirdio/netbird/management/server/status"
 )
 
@@ -30,36 +31,56 @@ func (e *GroupLinkError) Error() string {
 
 // CheckGroupPermissions validates if a user has the necessary permissions to view groups
 func (am *DefaultAccountManager) CheckGroupPermissions(ctx context.Context, accountID, userID string) error {
+	_, err := am.checkGroupPermissions(ctx, accountID, userID, permissions.Read)
+	return err
+}
+
+// checkGroupPermissions validates if a user has the necessary permissions for the groups operation.
+// It returns the custom role permission of regular users, which might limit the operation to specific groups.
+func (am *DefaultAccountManager) checkGroupPermissions(ctx context.Context, accountID, userID string, operation permissions.Operation) (*types.RolePermission, error) {
 	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if user.AccountID != accountID {
-		return status.NewUserNotPartOfAccountError()
+		return nil, status.NewUserNotPartOfAccountError()
 	}
 
 	if user.IsRegularUser() {
-		return status.NewAdminPermissionError()
+		return am.checkCustomRolePermission(ctx, user, permissions.Groups, operation)
 	}
 
-	return nil
+	return nil, nil
 }
 
 // GetGroup returns a specific group by groupID in an account
 func (am *DefaultAccountManager) GetGroup(ctx context.Context, accountID, groupID, userID string) (*types.Group, error) {
-	if err := am.CheckGroupPermissions(ctx, accountID, userID); err != nil {
+	permission, err := am.checkGroupPermissions(ctx, accountID, userID, permissions.Read)
+	if err != nil {
 		return nil, err
 	}
+
+	if !permission.CoversAllGroups([]string{groupID}) {
+		return nil, status.NewGroupNotFoundError(groupID)
+	}
+
 	return am.Store.GetGroupByID(ctx, store.LockingStrengthShare, accountID, groupID)
 }
 
 // GetAllGroups returns all groups in an account
 func (am *DefaultAccountManager) GetAllGroups(ctx context.Context, accountID, userID string) ([]*types.Group, error) {
-	if err := am.CheckGroupPermissions(ctx, accountID, userID); err != nil {
+	permission, err := am.checkGroupPermissions(ctx, accountID, userID, permissions.Read)
+	if err != nil {
 		return nil, err
 	}
-	return am.Store.GetAccountGroups(ctx, store.LockingStrengthShare, accountID)
+
+	groups, err := am.Store.GetAccountGroups(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterByRoleScope(permission, groups, func(group *types.Group) []string { return []string{group.ID} }), nil
 }
 
 // GetGroupByName filters all groups in an account by name and returns the one with the most peers
@@ -78,17 +99,16 @@ func (am *DefaultAccountManager) SaveGroup(ctx context.Context, accountID, userI
 // Note: This function does not acquire the global lock.
 // It is the caller's responsibility to ensure proper locking is in place before invoking this method.
 func (am *DefaultAccountManager) SaveGroups(ctx context.Context, accountID, userID string, groups []*types.Group) error {
-	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
+	permission, err := am.checkGroupPermissions(ctx, accountID, userID, permissions.Write)
 	if err != nil {
 		return err
 	}
 
-	if user.AccountID != accountID {
-		return status.NewUserNotPartOfAccountError()
-	}
-
-	if user.IsRegularUser() {
-		return status.NewAdminPermissionError()
+	// new groups get their ID assigned during validation and can't be in the scope of a custom role
+	for _, newGroup := range groups {
+		if !permission.CoversAllGroups([]string{newGroup.ID}) {
+			return status.NewPermissionDeniedError()
+		}
 	}
 
 	var eventsToStore []func()
@@ -210,17 +230,13 @@ func (am *DefaultAccountManager) DeleteGroup(ctx context.Context, accountID, use
 // If an error occurs while deleting a group, the function skips it and continues deleting other groups.
 // Errors are collected and returned at the end.
 func (am *DefaultAccountManager) DeleteGroups(ctx context.Context, accountID, userID string, groupIDs []string) error {
-	user, err := am.Store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
+	permission, err := am.checkGroupPermissions(ctx, accountID, userID, permissions.Delete)
 	if err != nil {
 		return err
 	}
 
-	if user.AccountID != accountID {
-		return status.NewUserNotPartOfAccountError()
-	}
-
-	if user.IsRegularUser() {
-		return status.NewAdminPermissionError()
+	if !permission.CoversAllGroups(groupIDs) {
+		return status.NewPermissionDeniedError()
 	}
 
 	var allErrors error
@@ -498,6 +514,10 @@ func validateDeleteGroup(ctx context.Context, transaction store.Store, group *ty
 		return &GroupLinkError{"user", linkedUser.Id}
 	}
 
+	if isLinked, linkedRole := isGroupLinkedToCustomRole(ctx, transaction, group.AccountID, group.ID); isLinked {
+		return &GroupLinkError{"custom role", linkedRole.Name}
+	}
+
 	return checkGroupLinkedToSettings(ctx, transaction, group)
 }
 
@@ -613,6 +633,22 @@ func isGroupLinkedToUser(ctx context.Context, transaction store.Store, accountID
 	return false, nil
 }
 
+// isGroupLinkedToCustomRole checks if a group is linked to any custom role permission scope in the account.
+func isGroupLinkedToCustomRole(ctx context.Context, transaction store.Store, accountID string, groupID string) (bool, *types.CustomRole) {
+	roles, err := transaction.GetAccountCustomRoles(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		log.WithContext(ctx).Errorf("error retrieving custom roles while checking group linkage: %v", err)
+		return false, nil
+	}
+
+	for _, role := range roles {
+		if role.HasGroups(groupID) {
+			return true, role
+		}
+	}
+	return false, nil
+}
+
 // areGroupChangesAffectPeers checks if any changes to the specified groups will affect peers.
 func areGroupChangesAffectPeers(ctx context.Context, transaction store.Store, accountID string, groupIDs []string) (bool, error) {
 	if len(groupIDs) == 0 {
@@ -16,10 +16,12 @@ import (
 	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	"github.com/netbirdio/netbird/encryption"
 	"github.com/netbirdio/netbird/management/proto"
 	nbContext "github.com/netbirdio/netbird/management/server/context"
+	"github.com/netbirdio/netbird/management/server/flows"
 	"github.com/netbirdio/netbird/management/server/jwtclaims"
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
 	"github.com/netbirdio/netbird/management/server/posture"
@@ -43,6 +45,7 @@ type GRPCServer struct {
 	appMetrics         telemetry.AppMetrics
 	ephemeralManager   *EphemeralManager
 	peerLocks          sync.Map
+	flowWriter         *flows.Writer
 }
 
 // NewServer creates a new Management server
@@ -98,6 +101,14 @@ func NewServer(
 		jwtclaims.WithUserIDClaim(userIDClaim),
 	)
 
+	var flowWriter *flows.Writer
+	if config.Flows != nil {
+		flowWriter, err = flows.NewWriter(config.Flows)
+		if err != nil {
+			return nil, fmt.Errorf("create flow events writer: %w", err)
+		}
+	}
+
 	return &GRPCServer{
 		wgKey: key,
 		// peerKey -> event channel
@@ -110,6 +121,7 @@ func NewServer(
 		jwtClaimsExtractor: jwtClaimsExtractor,
 		appMetrics:         appMetrics,
 		ephemeralManager:   ephemeralManager,
+		flowWriter:         flowWriter,
 	}, nil
 }
 
@@ -372,6 +384,27 @@ func extractPeerMeta(ctx context.Context, meta *proto.PeerSystemMeta) nbpeer.Pee
 		})
 	}
 
+	services := make([]nbpeer.Service, 0, len(meta.GetServices()))
+	for _, service := range meta.GetServices() {
+		services = append(services, nbpeer.Service{
+			Name:    service.GetName(),
+			Running: service.GetRunning(),
+		})
+	}
+
+	ports := make([]nbpeer.Port, 0, len(meta.GetPorts()))
+	for _, port := range meta.GetPorts() {
+		if port.GetPort() == 0 || port.GetPort() > 65535 {
+			log.WithContext(ctx).Warnf("ignoring invalid port in peer meta: %d", port.GetPort())
+			continue
+		}
+		ports = append(ports, nbpeer.Port{
+			Port:      uint16(port.GetPort()),
+			Protocol:  port.GetProtocol(),
+			Listening: port.GetListening(),
+		})
+	}
+
 	return nbpeer.PeerSystemMeta{
 		Hostname:           meta.GetHostname(),
 		GoOS:               meta.GetGoOS(),
@@ -390,7 +423,11 @@ func extractPeerMeta(ctx context.Context, meta *proto.PeerSystemMeta) nbpeer.Pee
 			Cloud:    meta.GetEnvironment().GetCloud(),
 			Platform: meta.GetEnvironment().GetPlatform(),
 		},
-		Files: files,
+		Files:           files,
+		Services:        services,
+		Ports:           ports,
+		DiskEncrypted:   meta.GetDiskEncrypted(),
+		FirewallEnabled: meta.GetFirewallEnabled(),
 	}
 }
 
@@ -601,6 +638,23 @@ func toWiretrusteeConfig(config *Config, turnCredentials *Token, relayToken *Tok
 			Protocol: ToResponseProto(config.Signal.Proto),
 		},
 		Relay: relayCfg,
+		Flow:  toFlowConfig(config.Flows),
+	}
+}
+
+func toFlowConfig(config *flows.Config) *proto.FlowConfig {
+	if config == nil {
+		return nil
+	}
+
+	interval := config.Interval.Duration
+	if interval <= 0 {
+		interval = flows.DefaultInterval
+	}
+
+	return &proto.FlowConfig{
+		Enabled:  true,
+		Interval: durationpb.New(interval),
 	}
 }
 
@@ -609,12 +663,22 @@ func toPeerConfig(peer *nbpeer.Peer, network *types.Network, dnsName string, dns
 	fqdn := peer.FQDN(dnsName)
 	return &proto.PeerConfig{
 		Address:                         fmt.Sprintf("%s/%d", peer.IP.String(), netmask), // take it from the network
-		SshConfig:                       &proto.SSHConfig{SshEnabled: peer.SSHEnabled},
+		SshConfig:                       toSSHConfig(peer),
 		Fqdn:                            fqdn,
 		RoutingPeerDnsResolutionEnabled: dnsResolutionOnRoutingPeerEnabled,
 	}
 }
 
+// toSSHConfig returns the SSH server settings of the peer
+func toSSHConfig(peer *nbpeer.Peer) *proto.SSHConfig {
+	return &proto.SSHConfig{
+		SshEnabled:            peer.SSHEnabled,
+		PortForwardingEnabled: peer.SSHPortForwardingEnabled,
+		SftpEnabled:           peer.SSHSFTPEnabled,
+		ExecEnabled:           peer.SSHExecEnabled,
+	}
+}
+
 func toSyncResponse(ctx context.Context, config *Config, peer *nbpeer.Peer, turnCredentials *Token, relayCredentials *Token, networkMap *types.NetworkMap, dnsName string, checks []*posture.Checks, dnsCache *DNSConfigCache, dnsResolutionOnRoutingPeerEnbled bool) *proto.SyncResponse {
 	response := &proto.SyncResponse{
 		WiretrusteeConfig: toWiretrusteeConfig(config, turnCredentials, relayCredentials),
@@ -630,13 +694,13 @@ func toSyncResponse(ctx context.Context, config *Config, peer *nbpeer.Peer, turn
 	response.NetworkMap.PeerConfig = response.PeerConfig
 
 	allPeers := make([]*proto.RemotePeerConfig, 0, len(networkMap.Peers)+len(networkMap.OfflinePeers))
-	allPeers = appendRemotePeerConfig(allPeers, networkMap.Peers, dnsName)
+	allPeers = appendRemotePeerConfig(allPeers, networkMap.Peers, dnsName, networkMap.SSHLocalUsers)
 	response.RemotePeers = allPeers
 	response.NetworkMap.RemotePeers = allPeers
 	response.RemotePeersIsEmpty = len(allPeers) == 0
 	response.NetworkMap.RemotePeersIsEmpty = response.RemotePeersIsEmpty
 
-	response.NetworkMap.OfflinePeers = appendRemotePeerConfig(nil, networkMap.OfflinePeers, dnsName)
+	response.NetworkMap.OfflinePeers = appendRemotePeerConfig(nil, networkMap.OfflinePeers, dnsName, networkMap.SSHLocalUsers)
 
 	firewallRules := toProtocolFirewallRules(networkMap.FirewallRules)
 	response.NetworkMap.FirewallRules = firewallRules
@@ -649,12 +713,12 @@ func toSyncResponse(ctx context.Context, config *Config, peer *nbpeer.Peer, turn
 	return response
 }
 
-func appendRemotePeerConfig(dst []*proto.RemotePeerConfig, peers []*nbpeer.Peer, dnsName string) []*proto.RemotePeerConfig {
+func appendRemotePeerConfig(dst []*proto.RemotePeerConfig, peers []*nbpeer.Peer, dnsName string, sshLocalUsers map[string][]string) []*proto.RemotePeerConfig {
 	for _, rPeer := range peers {
 		dst = append(dst, &proto.RemotePeerConfig{
 			WgPubKey:   rPeer.Key,
 			AllowedIps: []string{rPeer.IP.String() + "/32"},
-			SshConfig:  &proto.SSHConfig{SshPubKey: []byte(rPeer.SSHKey)},
+			SshConfig:  &proto.SSHConfig{SshPubKey: []byte(rPeer.SSHKey), LocalUsers: sshLocalUsers[rPeer.ID]},
 			Fqdn:       rPeer.FQDN(dnsName),
 		})
 	}
@@ -836,6 +900,60 @@ func (s *GRPCServer) SyncMeta(ctx context.Context, req *proto.EncryptedMessage)
 	return &proto.Empty{}, nil
 }
 
+// LogFlows endpoint receives the connection flow events recorded by the peer's packet filter
+// and writes them to the configured flow events file.
+func (s *GRPCServer) LogFlows(ctx context.Context, req *proto.EncryptedMessage) (*proto.Empty, error) {
+	if s.flowWriter == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "flow logging is not enabled")
+	}
+
+	flowEvents := &proto.FlowEvents{}
+	peerKey, err := s.parseRequest(ctx, req, flowEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	accountID, err := s.accountManager.GetAccountIDForPeerKey(ctx, peerKey.String())
+	if err != nil {
+		return nil, mapError(ctx, err)
+	}
+
+	if err := s.flowWriter.Write(accountID, peerKey.String(), flowEvents.GetEvents()); err != nil {
+		log.WithContext(ctx).Errorf("failed to write flow events of peer %s: %v", peerKey.String(), err)
+		return nil, status.Errorf(codes.Internal, "failed to store flow events")
+	}
+
+	return &proto.Empty{}, nil
+}
+
+// LogSSHSessions endpoint receives the start and the end of the sessions on the SSH server of the peer
+// and stores them as activity events.
+func (s *GRPCServer) LogSSHSessions(ctx context.Context, req *proto.EncryptedMessage) (*proto.Empty, error) {
+	sessionEvents := &proto.SSHSessionEvents{}
+	peerKey, err := s.parseRequest(ctx, req, sessionEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]SSHSessionEvent, 0, len(sessionEvents.GetEvents()))
+	for _, event := range sessionEvents.GetEvents() {
+		events = append(events, SSHSessionEvent{
+			SessionID:     event.GetSessionId(),
+			Ended:         event.GetType() == proto.SSHSessionEventType_SSH_SESSION_END,
+			Timestamp:     event.GetTimestamp().AsTime(),
+			RemotePeerKey: event.GetPeerKey(),
+			LocalUser:     event.GetLocalUser(),
+			Duration:      event.GetDuration().AsDuration(),
+		})
+	}
+
+	if err := s.accountManager.LogPeerSSHSessions(ctx, peerKey.String(), events); err != nil {
+		return nil, mapError(ctx, err)
+	}
+
+	return &proto.Empty{}, nil
+}
+
 // toProtocolChecks converts posture checks to protocol checks.
 func toProtocolChecks(ctx context.Context, postureChecks []*posture.Checks) []*proto.Checks {
 	protoChecks := make([]*proto.Checks, 0, len(postureChecks))
@@ -864,5 +982,29 @@ func toProtocolCheck(postureCheck *posture.Checks) *proto.Checks {
 		}
 	}
 
+	if check := postureCheck.Checks.ServiceCheck; check != nil {
+		for _, service := range check.Services {
+			if service.LinuxName != "" {
+				protoCheck.Services = append(protoCheck.Services, service.LinuxName)
+			}
+			if service.MacName != "" {
+				protoCheck.Services = append(protoCheck.Services, service.MacName)
+			}
+			if service.WindowsName != "" {
+				protoCheck.Services = append(protoCheck.Services, service.WindowsName)
+			}
+		}
+		for _, port := range check.Ports {
+			protoCheck.Ports = append(protoCheck.Ports, &proto.Port{
+				Port:     uint32(port.Port),
+				Protocol: port.Protocol,
+			})
+		}
+	}
+
+	if postureCheck.Checks.HostSecurityCheck != nil {
+		protoCheck.HostSecurity = true
+	}
+
 	return protoCheck
 }
@@ -83,9 +83,17 @@ const (
 	GroupMinimumIssuedJwt         GroupMinimumIssued = "jwt"
 )
 
+// Defines values for ListeningPortProtocol.
+const (
+	ListeningPortProtocolTcp ListeningPortProtocol = "tcp"
+	ListeningPortProtocolUdp ListeningPortProtocol = "udp"
+)
+
 // Defines values for NameserverNsType.
 const (
-	NameserverNsTypeUdp NameserverNsType = "udp"
+	NameserverNsTypeHttps NameserverNsType = "https"
+	NameserverNsTypeTls   NameserverNsType = "tls"
+	NameserverNsTypeUdp   NameserverNsType = "udp"
 )
 
 // Defines values for NetworkResourceType.
@@ -129,6 +137,17 @@ const (
 	PolicyRuleMinimumProtocolUdp  PolicyRuleMinimumProtocol = "udp"
 )
 
+// Defines values for PolicyRuleScheduleDays.
+const (
+	PolicyRuleScheduleDaysFriday    PolicyRuleScheduleDays = "friday"
+	PolicyRuleScheduleDaysMonday    PolicyRuleScheduleDays = "monday"
+	PolicyRuleScheduleDaysSaturday  PolicyRuleScheduleDays = "saturday"
+	PolicyRuleScheduleDaysSunday    PolicyRuleScheduleDays = "sunday"
+	PolicyRuleScheduleDaysThursday  PolicyRuleScheduleDays = "thursday"
+	PolicyRuleScheduleDaysTuesday   PolicyRuleScheduleDays = "tuesday"
+	PolicyRuleScheduleDaysWednesday PolicyRuleScheduleDays = "wednesday"
+)
+
 // Defines values for PolicyRuleUpdateAction.
 const (
 	PolicyRuleUpdateActionAccept PolicyRuleUpdateAction = "accept"
@@ -143,6 +162,20 @@ const (
 	PolicyRuleUpdateProtocolUdp  PolicyRuleUpdateProtocol = "udp"
 )
 
+// Defines values for PolicySimulationMatchAction.
+const (
+	PolicySimulationMatchActionAccept PolicySimulationMatchAction = "accept"
+	PolicySimulationMatchActionDrop   PolicySimulationMatchAction = "drop"
+)
+
+// Defines values for PolicySimulationRequestProtocol.
+const (
+	PolicySimulationRequestProtocolAll  PolicySimulationRequestProtocol = "all"
+	PolicySimulationRequestProtocolIcmp PolicySimulationRequestProtocol = "icmp"
+	PolicySimulationRequestProtocolTcp  PolicySimulationRequestProtocol = "tcp"
+	PolicySimulationRequestProtocolUdp  PolicySimulationRequestProtocol = "udp"
+)
+
 // Defines values for ResourceType.
 const (
 	ResourceTypeDomain ResourceType = "domain"
@@ -150,6 +183,44 @@ const (
 	ResourceTypeSubnet ResourceType = "subnet"
 )
 
+// Defines values for RolePermissionModule.
+const (
+	RolePermissionModuleDns           RolePermissionModule = "dns"
+	RolePermissionModuleEvents        RolePermissionModule = "events"
+	RolePermissionModuleGroups        RolePermissionModule = "groups"
+	RolePermissionModuleNetworks      RolePermissionModule = "networks"
+	RolePermissionModulePeers         RolePermissionModule = "peers"
+	RolePermissionModulePolicies      RolePermissionModule = "policies"
+	RolePermissionModulePostureChecks RolePermissionModule = "posture_checks"
+	RolePermissionModuleRoutes        RolePermissionModule = "routes"
+	RolePermissionModuleSetupKeys     RolePermissionModule = "setup_keys"
+	RolePermissionModuleUsers         RolePermissionModule = "users"
+)
+
+// Defines values for ServiceCheckAction.
+const (
+	ServiceCheckActionAllow ServiceCheckAction = "allow"
+	ServiceCheckActionDeny  ServiceCheckAction = "deny"
+)
+
+// Defines values for SimulatedFirewallRuleAction.
+const (
+	SimulatedFirewallRuleActionAccept SimulatedFirewallRuleAction = "accept"
+	SimulatedFirewallRuleActionDrop   SimulatedFirewallRuleAction = "drop"
+)
+
+// Defines values for SimulatedFirewallRuleDirection.
+const (
+	SimulatedFirewallRuleDirectionIn  SimulatedFirewallRuleDirection = "in"
+	SimulatedFirewallRuleDirectionOut SimulatedFirewallRuleDirection = "out"
+)
+
+// Defines values for SimulatedRouteFirewallRuleAction.
+const (
+	SimulatedRouteFirewallRuleActionAccept SimulatedRouteFirewallRuleAction = "accept"
+	SimulatedRouteFirewallRuleActionDrop   SimulatedRouteFirewallRuleAction = "drop"
+)
+
 // Defines values for UserStatus.
 const (
 	UserStatusActive  UserStatus = "active"
@@ -209,8 +280,14 @@ type Account struct {
 
 // AccountExtraSettings defines model for AccountExtraSettings.
 type AccountExtraSettings struct {
-	// PeerApprovalEnabled (Cloud only) Enables or disables peer approval globally. If enabled, all peers added will be in pending state until approved by an admin.
+	// PeerApprovalEnabled Enables or disables peer approval globally. If enabled, all peers added will be in pending state until approved by an admin. Disabling it approves all pending peers.
 	PeerApprovalEnabled *bool `json:"peer_approval_enabled,omitempty"`
+
+	// PeerApprovalGroups Limits the peer approval to the peers added to these groups. If neither groups nor setup keys are set, all new peers require approval.
+	PeerApprovalGroups *[]string `json:"peer_approval_groups,omitempty"`
+
+	// PeerApprovalSetupKeys Limits the peer approval to the peers added with these setup keys. If neither groups nor setup keys are set, all new peers require approval.
+	PeerApprovalSetupKeys *[]string `json:"peer_approval_setup_keys,omitempty"`
 }
 
 // AccountRequest defines model for AccountRequest.
@@ -258,6 +335,9 @@ type Checks struct {
 	// GeoLocationCheck Posture check for geo location
 	GeoLocationCheck *GeoLocationCheck `json:"geo_location_check,omitempty"`
 
+	// HostSecurityCheck Posture check for the disk encryption and the host firewall of the peer. The disk encryption is reported by Linux peers only
+	HostSecurityCheck *HostSecurityCheck `json:"host_security_check,omitempty"`
+
 	// NbVersionCheck Posture check for the version of operating system
 	NbVersionCheck *NBVersionCheck `json:"nb_version_check,omitempty"`
 
@@ -269,6 +349,9 @@ type Checks struct {
 
 	// ProcessCheck Posture Check for binaries exist and are running in the peer’s system
 	ProcessCheck *ProcessCheck `json:"process_check,omitempty"`
+
+	// ServiceCheck Posture check for allow or deny access based on the running system services and listening ports of the peer
+	ServiceCheck *ServiceCheck `json:"service_check,omitempty"`
 }
 
 // City Describe city geographical location information
@@ -316,6 +399,36 @@ type CreateSetupKeyRequest struct {
 	UsageLimit int `json:"usage_limit"`
 }
 
+// CustomRole defines model for CustomRole.
+type CustomRole struct {
+	// Description Custom role description
+	Description *string `json:"description,omitempty"`
+
+	// Id Custom role ID
+	Id string `json:"id"`
+
+	// Name Custom role name
+	Name string `json:"name"`
+
+	// Permissions Permissions granted by the custom role
+	Permissions []RolePermission `json:"permissions"`
+
+	// UsersCount Count of users the custom role is assigned to
+	UsersCount int `json:"users_count"`
+}
+
+// CustomRoleRequest defines model for CustomRoleRequest.
+type CustomRoleRequest struct {
+	// Description Custom role description
+	Description *string `json:"description,omitempty"`
+
+	// Name Custom role name
+	Name string `json:"name"`
+
+	// Permissions Permissions granted by the custom role
+	Permissions []RolePermission `json:"permissions"`
+}
+
 // DNSSettings defines model for DNSSettings.
 type DNSSettings struct {
 	// DisabledManagementGroups Groups whose DNS management is disabled
@@ -423,6 +536,27 @@ type GroupRequest struct {
 	Resources *[]Resource `json:"resources,omitempty"`
 }
 
+// HostSecurityCheck Posture check for the disk encryption and the host firewall of the peer. The disk encryption is reported by Linux peers only
+type HostSecurityCheck struct {
+	// DiskEncryption Requires the disk of the root filesystem to be encrypted
+	DiskEncryption bool `json:"disk_encryption"`
+
+	// Firewall Requires a host firewall to be active
+	Firewall bool `json:"firewall"`
+}
+
+// ListeningPort Describes a port a peer's system listens on
+type ListeningPort struct {
+	// Port Port number
+	Port int `json:"port"`
+
+	// Protocol Transport protocol of the port
+	Protocol ListeningPortProtocol `json:"protocol"`
+}
+
+// ListeningPortProtocol Transport protocol of the port
+type ListeningPortProtocol string
+
 // Location Describe geographical location information
 type Location struct {
 	// CityName Commonly used English name of the city
@@ -452,14 +586,20 @@ type Nameserver struct {
 	// Ip Nameserver IP
 	Ip string `json:"ip"`
 
-	// NsType Nameserver Type
+	// NsType Nameserver Type, tls for DNS-over-TLS and https for DNS-over-HTTPS
 	NsType NameserverNsType `json:"ns_type"`
 
+	// Path URL path of a https nameserver, defaults to /dns-query
+	Path *string `json:"path,omitempty"`
+
 	// Port Nameserver Port
 	Port int `json:"port"`
+
+	// ServerName Name the certificate of a tls or https nameserver is verified against, the IP is verified if not set
+	ServerName *string `json:"server_name,omitempty"`
 }
 
-// NameserverNsType Nameserver Type
+// NameserverNsType Nameserver Type, tls for DNS-over-TLS and https for DNS-over-HTTPS
 type NameserverNsType string
 
 // NameserverGroup defines model for NameserverGroup.
@@ -734,6 +874,15 @@ type Peer struct {
 	// SshEnabled Indicates whether SSH server is enabled on this peer
 	SshEnabled bool `json:"ssh_enabled"`
 
+	// SshExecEnabled Indicates whether the SSH server of this peer runs non-interactive commands
+	SshExecEnabled bool `json:"ssh_exec_enabled"`
+
+	// SshPortForwardingEnabled Indicates whether the SSH server of this peer allows local and remote TCP port forwarding
+	SshPortForwardingEnabled bool `json:"ssh_port_forwarding_enabled"`
+
+	// SshSftpEnabled Indicates whether the SSH server of this peer serves the SFTP subsystem
+	SshSftpEnabled bool `json:"ssh_sftp_enabled"`
+
 	// UiVersion Peer's desktop UI version
 	UiVersion string `json:"ui_version"`
 
@@ -812,6 +961,15 @@ type PeerBatch struct {
 	// SshEnabled Indicates whether SSH server is enabled on this peer
 	SshEnabled bool `json:"ssh_enabled"`
 
+	// SshExecEnabled Indicates whether the SSH server of this peer runs non-interactive commands
+	SshExecEnabled bool `json:"ssh_exec_enabled"`
+
+	// SshPortForwardingEnabled Indicates whether the SSH server of this peer allows local and remote TCP port forwarding
+	SshPortForwardingEnabled bool `json:"ssh_port_forwarding_enabled"`
+
+	// SshSftpEnabled Indicates whether the SSH server of this peer serves the SFTP subsystem
+	SshSftpEnabled bool `json:"ssh_sftp_enabled"`
+
 	// UiVersion Peer's desktop UI version
 	UiVersion string `json:"ui_version"`
 
@@ -851,6 +1009,15 @@ type PeerRequest struct {
 	LoginExpirationEnabled      bool   `json:"login_expiration_enabled"`
 	Name                        string `json:"name"`
 	SshEnabled                  bool   `json:"ssh_enabled"`
+
+	// SshExecEnabled Allows non-interactive command execution on the SSH server of the peer. Unchanged if omitted
+	SshExecEnabled *bool `json:"ssh_exec_enabled,omitempty"`
+
+	// SshPortForwardingEnabled Allows local and remote TCP port forwarding on the SSH server of the peer. Unchanged if omitted
+	SshPortForwardingEnabled *bool `json:"ssh_port_forwarding_enabled,omitempty"`
+
+	// SshSftpEnabled Enables the SFTP subsystem on the SSH server of the peer. Unchanged if omitted
+	SshSftpEnabled *bool `json:"ssh_sftp_enabled,omitempty"`
 }
 
 // PersonalAccessToken defines model for PersonalAccessToken.
@@ -896,6 +1063,9 @@ type Policy struct {
 	// Description Policy friendly description
 	Description *string `json:"description,omitempty"`
 
+	// DestinationPostureChecks Posture checks ID's applied to policy destination groups. Destination peers failing them are removed from the network map of the source peers
+	DestinationPostureChecks []string `json:"destination_posture_checks"`
+
 	// Enabled Policy status
 	Enabled bool `json:"enabled"`
 
@@ -917,6 +1087,9 @@ type PolicyCreate struct {
 	// Description Policy friendly description
 	Description *string `json:"description,omitempty"`
 
+	// DestinationPostureChecks Posture checks ID's applied to policy destination groups. Destination peers failing them are removed from the network map of the source peers
+	DestinationPostureChecks *[]string `json:"destination_posture_checks,omitempty"`
+
 	// Enabled Policy status
 	Enabled bool `json:"enabled"`
 
@@ -973,11 +1146,17 @@ type PolicyRule struct {
 	Ports *[]string `json:"ports,omitempty"`
 
 	// Protocol Policy rule type of the traffic
-	Protocol       PolicyRuleProtocol `json:"protocol"`
-	SourceResource *Resource          `json:"sourceResource,omitempty"`
+	Protocol PolicyRuleProtocol `json:"protocol"`
+
+	// Schedule Restricts the time when an enabled policy rule is applied. The rule is applied when all the set conditions match.
+	Schedule       *PolicyRuleSchedule `json:"schedule,omitempty"`
+	SourceResource *Resource           `json:"sourceResource,omitempty"`
 
 	// Sources Policy rule source group IDs
 	Sources *[]GroupMinimum `json:"sources,omitempty"`
+
+	// SshLocalUsers Local accounts the peers of the rule may log in as on the SSH server of the peers they connect to. Any account when empty and no other rule maps local users for the peer.
+	SshLocalUsers *[]string `json:"ssh_local_users,omitempty"`
 }
 
 // PolicyRuleAction Policy rule accept or drops packets
@@ -1011,6 +1190,12 @@ type PolicyRuleMinimum struct {
 
 	// Protocol Policy rule type of the traffic
 	Protocol PolicyRuleMinimumProtocol `json:"protocol"`
+
+	// Schedule Restricts the time when an enabled policy rule is applied. The rule is applied when all the set conditions match.
+	Schedule *PolicyRuleSchedule `json:"schedule,omitempty"`
+
+	// SshLocalUsers Local accounts the peers of the rule may log in as on the SSH server of the peers they connect to. Any account when empty and no other rule maps local users for the peer.
+	SshLocalUsers *[]string `json:"ssh_local_users,omitempty"`
 }
 
 // PolicyRuleMinimumAction Policy rule accept or drops packets
@@ -1019,6 +1204,36 @@ type PolicyRuleMinimumAction string
 // PolicyRuleMinimumProtocol Policy rule type of the traffic
 type PolicyRuleMinimumProtocol string
 
+// PolicyRuleSchedule Restricts the time when an enabled policy rule is applied. The rule is applied when all the set conditions match.
+type PolicyRuleSchedule struct {
+	// Days Days of the week when the rule is applied. All days when empty.
+	Days *[]PolicyRuleScheduleDays `json:"days,omitempty"`
+
+	// EndsAt Time after which the rule is no longer applied
+	EndsAt *time.Time `json:"ends_at,omitempty"`
+
+	// StartsAt Time from which the rule is applied
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+
+	// TimeWindows Time of day windows when the rule is applied. The whole day when empty.
+	TimeWindows *[]PolicyRuleTimeWindow `json:"time_windows,omitempty"`
+
+	// Timezone IANA time zone used to evaluate days and time windows. Defaults to UTC.
+	Timezone *string `json:"timezone,omitempty"`
+}
+
+// PolicyRuleScheduleDays defines model for PolicyRuleSchedule.Days.
+type PolicyRuleScheduleDays string
+
+// PolicyRuleTimeWindow Time of day window in HH:MM format. A window with an end before its start spans midnight.
+type PolicyRuleTimeWindow struct {
+	// End End of the window
+	End string `json:"end"`
+
+	// Start Start of the window
+	Start string `json:"start"`
+}
+
 // PolicyRuleUpdate defines model for PolicyRuleUpdate.
 type PolicyRuleUpdate struct {
 	// Action Policy rule accept or drops packets
@@ -1050,11 +1265,17 @@ type PolicyRuleUpdate struct {
 	Ports *[]string `json:"ports,omitempty"`
 
 	// Protocol Policy rule type of the traffic
-	Protocol       PolicyRuleUpdateProtocol `json:"protocol"`
-	SourceResource *Resource                `json:"sourceResource,omitempty"`
+	Protocol PolicyRuleUpdateProtocol `json:"protocol"`
+
+	// Schedule Restricts the time when an enabled policy rule is applied. The rule is applied when all the set conditions match.
+	Schedule       *PolicyRuleSchedule `json:"schedule,omitempty"`
+	SourceResource *Resource           `json:"sourceResource,omitempty"`
 
 	// Sources Policy rule source group IDs
 	Sources *[]string `json:"sources,omitempty"`
+
+	// SshLocalUsers Local accounts the peers of the rule may log in as on the SSH server of the peers they connect to. Any account when empty and no other rule maps local users for the peer.
+	SshLocalUsers *[]string `json:"ssh_local_users,omitempty"`
 }
 
 // PolicyRuleUpdateAction Policy rule accept or drops packets
@@ -1063,11 +1284,98 @@ type PolicyRuleUpdateAction string
 // PolicyRuleUpdateProtocol Policy rule type of the traffic
 type PolicyRuleUpdateProtocol string
 
+// PolicySimulationMatch defines model for PolicySimulationMatch.
+type PolicySimulationMatch struct {
+	// Action Action of the matching rule
+	Action PolicySimulationMatchAction `json:"action"`
+
+	// Applied Indicates whether the rule is applied. A rule is not applied when the source or the destination peer fails the policy posture checks
+	Applied bool `json:"applied"`
+
+	// DestinationFirewallRules Firewall rules generated by the matching rule on the destination peer
+	DestinationFirewallRules []SimulatedFirewallRule `json:"destination_firewall_rules"`
+
+	// DestinationPostureChecks Destination posture checks of the policy
+	DestinationPostureChecks []string `json:"destination_posture_checks"`
+
+	// FailedDestinationPostureChecks Destination posture checks the destination peer didn't pass
+	FailedDestinationPostureChecks []string `json:"failed_destination_posture_checks"`
+
+	// FailedPostureChecks Source posture checks the source peer didn't pass
+	FailedPostureChecks []string `json:"failed_posture_checks"`
+
+	// PolicyId ID of the matching policy. Empty for routes without access control groups
+	PolicyId string `json:"policy_id"`
+
+	// PolicyName Name of the matching policy
+	PolicyName string `json:"policy_name"`
+
+	// PostureChecks Source posture checks of the policy
+	PostureChecks []string `json:"posture_checks"`
+
+	// RouteFirewallRules Firewall rules generated by the matching rule on the routing peer
+	RouteFirewallRules []SimulatedRouteFirewallRule `json:"route_firewall_rules"`
+
+	// RouteId ID of the route or network resource the traffic is routed through
+	RouteId *string `json:"route_id,omitempty"`
+
+	// RoutingPeerId ID of the peer routing the traffic
+	RoutingPeerId *string `json:"routing_peer_id,omitempty"`
+
+	// RuleId ID of the matching policy rule
+	RuleId string `json:"rule_id"`
+
+	// RuleName Name of the matching policy rule
+	RuleName string `json:"rule_name"`
+
+	// SourceFirewallRules Firewall rules generated by the matching rule on the source peer
+	SourceFirewallRules []SimulatedFirewallRule `json:"source_firewall_rules"`
+}
+
+// PolicySimulationMatchAction Action of the matching rule
+type PolicySimulationMatchAction string
+
+// PolicySimulationRequest Traffic to evaluate against the account policies. Exactly one destination field must be set.
+type PolicySimulationRequest struct {
+	// DestinationDomain Destination domain. Can be a peer FQDN or a domain of a network route or resource
+	DestinationDomain *string `json:"destination_domain,omitempty"`
+
+	// DestinationIp Destination IP address. Can be a peer IP or an address routed by a network route or resource
+	DestinationIp *string `json:"destination_ip,omitempty"`
+
+	// DestinationPeerId ID of the destination peer
+	DestinationPeerId *string `json:"destination_peer_id,omitempty"`
+
+	// DestinationResourceId ID of the destination network resource
+	DestinationResourceId *string `json:"destination_resource_id,omitempty"`
+
+	// Port Destination port of the traffic. Ignored for the all and icmp protocols
+	Port *int `json:"port,omitempty"`
+
+	// Protocol Protocol of the traffic
+	Protocol PolicySimulationRequestProtocol `json:"protocol"`
+}
+
+// PolicySimulationRequestProtocol Protocol of the traffic
+type PolicySimulationRequestProtocol string
+
+// PolicySimulationResult defines model for PolicySimulationResult.
+type PolicySimulationResult struct {
+	// Allowed Indicates whether the traffic is allowed by the account policies
+	Allowed bool `json:"allowed"`
+
+	// Matches Rules that matched the traffic, including the ones not applied because of failed posture checks
+	Matches []PolicySimulationMatch `json:"matches"`
+}
+
 // PolicyUpdate defines model for PolicyUpdate.
 type PolicyUpdate struct {
 	// Description Policy friendly description
 	Description *string `json:"description,omitempty"`
 
+	// DestinationPostureChecks Posture checks ID's applied to policy destination groups. Destination peers failing them are removed from the network map of the source peers
+	DestinationPostureChecks *[]string `json:"destination_posture_checks,omitempty"`
+
 	// Enabled Policy status
 	Enabled bool `json:"enabled"`
 
@@ -1135,6 +1443,27 @@ type Resource struct {
 // ResourceType defines model for ResourceType.
 type ResourceType string
 
+// RolePermission defines model for RolePermission.
+type RolePermission struct {
+	// Delete Allows deleting the module objects
+	Delete bool `json:"delete"`
+
+	// Groups Group IDs limiting the permission to the objects related to these groups. The permission applies to all objects of the module when empty
+	Groups *[]string `json:"groups,omitempty"`
+
+	// Module Management API module the permission applies to
+	Module RolePermissionModule `json:"module"`
+
+	// Read Allows listing and viewing the module objects
+	Read bool `json:"read"`
+
+	// Write Allows creating and updating the module objects
+	Write bool `json:"write"`
+}
+
+// RolePermissionModule Management API module the permission applies to
+type RolePermissionModule string
+
 // Route defines model for Route.
 type Route struct {
 	// AccessControlGroups Access control group identifier associated with route.
@@ -1228,6 +1557,33 @@ type RulePortRange struct {
 	Start int `json:"start"`
 }
 
+// Service Describes a system service per operating system
+type Service struct {
+	// LinuxName Name of the systemd unit in a Linux operating system
+	LinuxName *string `json:"linux_name,omitempty"`
+
+	// MacName Label of the launchd service in a Mac operating system
+	MacName *string `json:"mac_name,omitempty"`
+
+	// WindowsName Name of the service in a Windows operating system
+	WindowsName *string `json:"windows_name,omitempty"`
+}
+
+// ServiceCheck Posture check for allow or deny access based on the running system services and listening ports of the peer
+type ServiceCheck struct {
+	// Action Action to take upon policy match
+	Action ServiceCheckAction `json:"action"`
+
+	// Ports List of ports. With the allow action all of them must be listening, with the deny action none of them may be listening
+	Ports *[]ListeningPort `json:"ports,omitempty"`
+
+	// Services List of system services. With the allow action all of them must be running, with the deny action none of them may be running
+	Services *[]Service `json:"services,omitempty"`
+}
+
+// ServiceCheckAction Action to take upon policy match
+type ServiceCheckAction string
+
 // SetupKey defines model for SetupKey.
 type SetupKey struct {
 	// AutoGroups List of group IDs to auto-assign to peers registered with this key
@@ -1369,11 +1725,68 @@ type SetupKeyRequest struct {
 	Revoked bool `json:"revoked"`
 }
 
+// SimulatedFirewallRule defines model for SimulatedFirewallRule.
+type SimulatedFirewallRule struct {
+	// Action Action applied to the traffic
+	Action SimulatedFirewallRuleAction `json:"action"`
+
+	// Direction Direction of the traffic
+	Direction SimulatedFirewallRuleDirection `json:"direction"`
+
+	// PeerIp IP of the remote peer. 0.0.0.0 means all peers
+	PeerIp string `json:"peer_ip"`
+
+	// Port Port of the traffic. Empty means all ports unless a port range is set
+	Port string `json:"port"`
+
+	// PortRange Policy rule affected ports range
+	PortRange *RulePortRange `json:"port_range,omitempty"`
+
+	// Protocol Protocol of the traffic
+	Protocol string `json:"protocol"`
+}
+
+// SimulatedFirewallRuleAction Action applied to the traffic
+type SimulatedFirewallRuleAction string
+
+// SimulatedFirewallRuleDirection Direction of the traffic
+type SimulatedFirewallRuleDirection string
+
+// SimulatedRouteFirewallRule defines model for SimulatedRouteFirewallRule.
+type SimulatedRouteFirewallRule struct {
+	// Action Action applied to the traffic
+	Action SimulatedRouteFirewallRuleAction `json:"action"`
+
+	// Destination Destination network of the traffic
+	Destination string `json:"destination"`
+
+	// Domains Destination domains of the traffic
+	Domains *[]string `json:"domains,omitempty"`
+
+	// Port Port of the traffic. 0 means all ports unless a port range is set
+	Port int `json:"port"`
+
+	// PortRange Policy rule affected ports range
+	PortRange *RulePortRange `json:"port_range,omitempty"`
+
+	// Protocol Protocol of the traffic
+	Protocol string `json:"protocol"`
+
+	// SourceRanges Source ranges of the traffic
+	SourceRanges []string `json:"source_ranges"`
+}
+
+// SimulatedRouteFirewallRuleAction Action applied to the traffic
+type SimulatedRouteFirewallRuleAction string
+
 // User defines model for User.
 type User struct {
 	// AutoGroups Group IDs to auto-assign to peers registered by this user
 	AutoGroups []string `json:"auto_groups"`
 
+	// CustomRoleId ID of the custom role granting the user additional permissions
+	CustomRoleId *string `json:"custom_role_id,omitempty"`
+
 	// Email User's email address
 	Email string `json:"email"`
 
@@ -1414,6 +1827,9 @@ type UserCreateRequest struct {
 	// AutoGroups Group IDs to auto-assign to peers registered by this user
 	AutoGroups []string `json:"auto_groups"`
 
+	// CustomRoleId ID of the custom role granting the user additional permissions. Can only be set for users with the user role
+	CustomRoleId *string `json:"custom_role_id,omitempty"`
+
 	// Email User's Email to send invite to
 	Email *string `json:"email,omitempty"`
 
@@ -1441,6 +1857,9 @@ type UserRequest struct {
 	// AutoGroups Group IDs to auto-assign to peers registered by this user
 	AutoGroups []string `json:"auto_groups"`
 
+	// CustomRoleId ID of the custom role granting the user additional permissions. Can only be set for users with the user role
+	CustomRoleId *string `json:"custom_role_id,omitempty"`
+
 	// IsBlocked If set to true then user is blocked and can't use the system
 	IsBlocked bool `json:"is_blocked"`
 
@@ -1448,6 +1867,33 @@ type UserRequest struct {
 	Role string `json:"role"`
 }
 
+// GetApiEventsParams defines parameters for GetApiEvents.
+type GetApiEventsParams struct {
+	// ActivityCode Filters events by activity code
+	ActivityCode *[]string `form:"activity_code,omitempty" json:"activity_code,omitempty"`
+
+	// InitiatorId Filters events by the ID of the initiator
+	InitiatorId *string `form:"initiator_id,omitempty" json:"initiator_id,omitempty"`
+
+	// TargetId Filters events by the ID of the affected object
+	TargetId *string `form:"target_id,omitempty" json:"target_id,omitempty"`
+
+	// StartDate Filters events that happened at or after the given time
+	StartDate *time.Time `form:"start_date,omitempty" json:"start_date,omitempty"`
+
+	// EndDate Filters events that happened before the given time
+	EndDate *time.Time `form:"end_date,omitempty" json:"end_date,omitempty"`
+
+	// Search Filters events containing the given text in the metadata
+	Search *string `form:"search,omitempty" json:"search,omitempty"`
+
+	// Cursor Cursor returned in the X-Next-Cursor header of the previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Limit Maximum number of events returned
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
 // GetApiUsersParams defines parameters for GetApiUsers.
 type GetApiUsersParams struct {
 	// ServiceUser Filters users and returns either regular users or service users
@@ -1493,6 +1939,9 @@ type PutApiNetworksNetworkIdRoutersRouterIdJSONRequestBody = NetworkRouterReques
 // PutApiPeersPeerIdJSONRequestBody defines body for PutApiPeersPeerId for application/json ContentType.
 type PutApiPeersPeerIdJSONRequestBody = PeerRequest
 
+// PostApiPeersPeerIdPolicySimulationJSONRequestBody defines body for PostApiPeersPeerIdPolicySimulation for application/json ContentType.
+type PostApiPeersPeerIdPolicySimulationJSONRequestBody = PolicySimulationRequest
+
 // PostApiPoliciesJSONRequestBody defines body for PostApiPolicies for application/json ContentType.
 type PostApiPoliciesJSONRequestBody = PolicyUpdate
 
@@ -1505,6 +1954,12 @@ type PostApiPostureChecksJSONRequestBody = PostureCheckUpdate
 // PutApiPostureChecksPostureCheckIdJSONRequestBody defines body for PutApiPostureChecksPostureCheckId for application/json ContentType.
 type PutApiPostureChecksPostureCheckIdJSONRequestBody = PostureCheckUpdate
 
+// PostApiRolesJSONRequestBody defines body for PostApiRoles for application/json ContentType.
+type PostApiRolesJSONRequestBody = CustomRoleRequest
+
+// PutApiRolesRoleIdJSONRequestBody defines body for PutApiRolesRoleId for application/json ContentType.
+type PutApiRolesRoleIdJSONRequestBody = CustomRoleRequest
+
 // PostApiRoutesJSONRequestBody defines body for PostApiRoutes for application/json ContentType.
 type PostApiRoutesJSONRequestBody = RouteRequest
 
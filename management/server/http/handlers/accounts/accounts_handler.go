@@ -93,7 +93,16 @@ func (h *handler) updateAccount(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if req.Settings.Extra != nil {
-		settings.Extra = &account.ExtraSettings{PeerApprovalEnabled: *req.Settings.Extra.PeerApprovalEnabled}
+		settings.Extra = &account.ExtraSettings{}
+		if req.Settings.Extra.PeerApprovalEnabled != nil {
+			settings.Extra.PeerApprovalEnabled = *req.Settings.Extra.PeerApprovalEnabled
+		}
+		if req.Settings.Extra.PeerApprovalGroups != nil {
+			settings.Extra.IntegratedValidatorGroups = *req.Settings.Extra.PeerApprovalGroups
+		}
+		if req.Settings.Extra.PeerApprovalSetupKeys != nil {
+			settings.Extra.PeerApprovalSetupKeys = *req.Settings.Extra.PeerApprovalSetupKeys
+		}
 	}
 
 	if req.Settings.JwtGroupsEnabled != nil {
@@ -162,7 +171,20 @@ func toAccountResponse(accountID string, settings *types.Settings) *api.Account
 	}
 
 	if settings.Extra != nil {
-		apiSettings.Extra = &api.AccountExtraSettings{PeerApprovalEnabled: &settings.Extra.PeerApprovalEnabled}
+		approvalGroups := settings.Extra.IntegratedValidatorGroups
+		if approvalGroups == nil {
+			approvalGroups = []string{}
+		}
+		approvalSetupKeys := settings.Extra.PeerApprovalSetupKeys
+		if approvalSetupKeys == nil {
+			approvalSetupKeys = []string{}
+		}
+
+		apiSettings.Extra = &api.AccountExtraSettings{
+			PeerApprovalEnabled:   &settings.Extra.PeerApprovalEnabled,
+			PeerApprovalGroups:    &approvalGroups,
+			PeerApprovalSetupKeys: &approvalSetupKeys,
+		}
 	}
 
 	return &api.Account{
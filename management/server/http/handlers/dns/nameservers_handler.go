@@ -209,6 +209,12 @@ func toServerNSList(apiNSList []api.Nameserver) ([]nbdns.NameServer, error) {
 		if err != nil {
 			return nil, err
 		}
+		if apiNS.ServerName != nil {
+			parsed.ServerName = *apiNS.ServerName
+		}
+		if apiNS.Path != nil {
+			parsed.Path = *apiNS.Path
+		}
 		nsList = append(nsList, parsed)
 	}
 
@@ -223,6 +229,12 @@ func toNameserverGroupResponse(serverNSGroup *nbdns.NameServerGroup) *api.Namese
 			NsType: api.NameserverNsType(ns.NSType.String()),
 			Port:   ns.Port,
 		}
+		if ns.ServerName != "" {
+			apiNS.ServerName = &ns.ServerName
+		}
+		if ns.Path != "" {
+			apiNS.Path = &ns.Path
+		}
 		nsList = append(nsList, apiNS)
 	}
 
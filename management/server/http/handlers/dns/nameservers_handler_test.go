@@ -15,6 +15,7 @@ import (
 	nbdns "github.com/netbirdio/netbird/dns"
 	"github.com/netbirdio/netbird/management/server/http/api"
 	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/util"
 
 	"github.com/gorilla/mux"
 
@@ -145,6 +146,32 @@ func TestNameserversHandlers(t *testing.T) {
 				Primary: true,
 			},
 		},
+		{
+			name:        "POST DNS-over-HTTPS Nameserver OK",
+			requestType: http.MethodPost,
+			requestPath: "/api/dns/nameservers",
+			requestBody: bytes.NewBuffer(
+				[]byte("{\"name\":\"name\",\"Description\":\"Post\",\"nameservers\":[{\"ip\":\"1.1.1.1\",\"ns_type\":\"https\",\"port\":443,\"server_name\":\"cloudflare-dns.com\",\"path\":\"/dns-query\"}],\"groups\":[\"group\"],\"enabled\":true,\"primary\":true}")),
+			expectedStatus: http.StatusOK,
+			expectedBody:   true,
+			expectedNSGroup: &api.NameserverGroup{
+				Id:          existingNSGroupID,
+				Name:        "name",
+				Description: "Post",
+				Nameservers: []api.Nameserver{
+					{
+						Ip:         "1.1.1.1",
+						NsType:     "https",
+						Port:       443,
+						ServerName: util.ToPtr("cloudflare-dns.com"),
+						Path:       util.ToPtr("/dns-query"),
+					},
+				},
+				Groups:  []string{"group"},
+				Enabled: true,
+				Primary: true,
+			},
+		},
 		{
 			name:        "POST Invalid Nameserver",
 			requestType: http.MethodPost,
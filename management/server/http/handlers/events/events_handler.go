@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	log "github.com/sirupsen/logrus"
@@ -14,8 +16,12 @@ import (
 	"github.com/netbirdio/netbird/management/server/http/configs"
 	"github.com/netbirdio/netbird/management/server/http/util"
 	"github.com/netbirdio/netbird/management/server/jwtclaims"
+	"github.com/netbirdio/netbird/management/server/status"
 )
 
+// nextCursorHeader is the response header containing the cursor of the next page of events
+const nextCursorHeader = "X-Next-Cursor"
+
 // handler HTTP handler
 type handler struct {
 	accountManager  server.AccountManager
@@ -48,7 +54,13 @@ func (h *handler) getAllEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	accountEvents, err := h.accountManager.GetEvents(r.Context(), accountID, userID)
+	filter, err := parseFilter(r)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	accountEvents, nextCursor, err := h.accountManager.GetEvents(r.Context(), accountID, userID, filter)
 	if err != nil {
 		util.WriteError(r.Context(), err, w)
 		return
@@ -64,9 +76,59 @@ func (h *handler) getAllEvents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if nextCursor != 0 {
+		w.Header().Set(nextCursorHeader, strconv.FormatUint(nextCursor, 10))
+	}
+
 	util.WriteJSONObject(r.Context(), w, events)
 }
 
+// parseFilter builds the events filter from the request query parameters
+func parseFilter(r *http.Request) (activity.Filter, error) {
+	query := r.URL.Query()
+	filter := activity.Filter{
+		InitiatorID: query.Get("initiator_id"),
+		TargetID:    query.Get("target_id"),
+		Search:      query.Get("search"),
+		Descending:  true,
+	}
+
+	for _, code := range query["activity_code"] {
+		a, ok := activity.ActivityFromStringCode(code)
+		if !ok {
+			return filter, status.Errorf(status.InvalidArgument, "unknown activity code: %s", code)
+		}
+		filter.Activities = append(filter.Activities, a)
+	}
+
+	var err error
+	if value := query.Get("start_date"); value != "" {
+		if filter.From, err = time.Parse(time.RFC3339, value); err != nil {
+			return filter, status.Errorf(status.InvalidArgument, "invalid start_date, expected RFC 3339 format: %s", value)
+		}
+	}
+
+	if value := query.Get("end_date"); value != "" {
+		if filter.To, err = time.Parse(time.RFC3339, value); err != nil {
+			return filter, status.Errorf(status.InvalidArgument, "invalid end_date, expected RFC 3339 format: %s", value)
+		}
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		if filter.Cursor, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return filter, status.Errorf(status.InvalidArgument, "invalid cursor: %s", value)
+		}
+	}
+
+	if value := query.Get("limit"); value != "" {
+		if filter.Limit, err = strconv.Atoi(value); err != nil || filter.Limit < 1 {
+			return filter, status.Errorf(status.InvalidArgument, "invalid limit: %s", value)
+		}
+	}
+
+	return filter, nil
+}
+
 func (h *handler) fillEventsWithUserInfo(ctx context.Context, events []*api.Event, accountId, userId string) error {
 	// build email, name maps based on users
 	userInfos, err := h.accountManager.GetUsersFromAccount(ctx, accountId, userId)
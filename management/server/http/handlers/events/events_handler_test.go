@@ -23,11 +23,11 @@ import (
 func initEventsTestData(account string, events ...*activity.Event) *handler {
 	return &handler{
 		accountManager: &mock_server.MockAccountManager{
-			GetEventsFunc: func(_ context.Context, accountID, userID string) ([]*activity.Event, error) {
+			GetEventsFunc: func(_ context.Context, accountID, userID string, filter activity.Filter) ([]*activity.Event, uint64, error) {
 				if accountID == account {
-					return events, nil
+					return events, 0, nil
 				}
-				return []*activity.Event{}, nil
+				return []*activity.Event{}, 0, nil
 			},
 			GetAccountIDFromTokenFunc: func(_ context.Context, claims jwtclaims.AuthorizationClaims) (string, string, error) {
 				return claims.AccountId, claims.UserId, nil
@@ -228,6 +228,7 @@ func TestEvents_GetEvents(t *testing.T) {
 			}
 
 			assert.Len(t, got, len(events))
+			assert.Empty(t, recorder.Header().Get(nextCursorHeader), "the last page should not have a next cursor")
 			actual := map[string]*api.Event{}
 			for _, event := range got {
 				actual[event.Id] = event
@@ -246,3 +247,86 @@ func TestEvents_GetEvents(t *testing.T) {
 		})
 	}
 }
+
+func TestEvents_GetEventsFilter(t *testing.T) {
+	tt := []struct {
+		name           string
+		requestPath    string
+		expectedStatus int
+		expectedFilter activity.Filter
+	}{
+		{
+			name:           "no filters",
+			requestPath:    "/api/events/",
+			expectedStatus: http.StatusOK,
+			expectedFilter: activity.Filter{Descending: true},
+		},
+		{
+			name: "all filters",
+			requestPath: "/api/events/?activity_code=user.peer.add&activity_code=group.add&initiator_id=user&target_id=peer" +
+				"&start_date=2024-01-01T00:00:00Z&end_date=2024-02-01T00:00:00Z&search=office&cursor=42&limit=50",
+			expectedStatus: http.StatusOK,
+			expectedFilter: activity.Filter{
+				Activities:  []activity.Activity{activity.PeerAddedByUser, activity.GroupCreated},
+				InitiatorID: "user",
+				TargetID:    "peer",
+				From:        time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				To:          time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+				Search:      "office",
+				Cursor:      42,
+				Limit:       50,
+				Descending:  true,
+			},
+		},
+		{
+			name:           "unknown activity code",
+			requestPath:    "/api/events/?activity_code=unknown",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "invalid start date",
+			requestPath:    "/api/events/?start_date=yesterday",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "invalid cursor",
+			requestPath:    "/api/events/?cursor=abc",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "invalid limit",
+			requestPath:    "/api/events/?limit=0",
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	accountID := "test_account"
+	events := generateEvents(accountID, "test_user")
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotFilter activity.Filter
+			handler := initEventsTestData(accountID, events...)
+			mockManager := handler.accountManager.(*mock_server.MockAccountManager)
+			mockManager.GetEventsFunc = func(_ context.Context, _, _ string, filter activity.Filter) ([]*activity.Event, uint64, error) {
+				gotFilter = filter
+				return events, events[len(events)-1].ID, nil
+			}
+
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tc.requestPath, nil)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/api/events/", handler.getAllEvents).Methods("GET")
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, tc.expectedFilter, gotFilter)
+			assert.Equal(t, strconv.FormatUint(events[len(events)-1].ID, 10), recorder.Header().Get(nextCursorHeader))
+		})
+	}
+}
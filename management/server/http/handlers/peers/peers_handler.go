@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/netip"
 
 	"github.com/gorilla/mux"
 	log "github.com/sirupsen/logrus"
@@ -32,6 +33,9 @@ func AddEndpoints(accountManager server.AccountManager, authCfg configs.AuthCfg,
 	router.HandleFunc("/peers/{peerId}", peersHandler.HandlePeer).
 		Methods("GET", "PUT", "DELETE", "OPTIONS")
 	router.HandleFunc("/peers/{peerId}/accessible-peers", peersHandler.GetAccessiblePeers).Methods("GET", "OPTIONS")
+	router.HandleFunc("/peers/{peerId}/policy-simulation", peersHandler.SimulatePolicies).Methods("POST", "OPTIONS")
+	router.HandleFunc("/peers/{peerId}/approve", peersHandler.ApprovePeer).Methods("POST", "OPTIONS")
+	router.HandleFunc("/peers/{peerId}/reject", peersHandler.RejectPeer).Methods("POST", "OPTIONS")
 }
 
 // NewHandler creates a new peers Handler
@@ -102,6 +106,22 @@ func (h *Handler) updatePeer(ctx context.Context, account *types.Account, userID
 		InactivityExpirationEnabled: req.InactivityExpirationEnabled,
 	}
 
+	// the SSH server features are optional in the request, omitted ones keep their current value
+	if current := account.GetPeer(peerID); current != nil {
+		update.SSHPortForwardingEnabled = current.SSHPortForwardingEnabled
+		update.SSHSFTPEnabled = current.SSHSFTPEnabled
+		update.SSHExecEnabled = current.SSHExecEnabled
+	}
+	if req.SshPortForwardingEnabled != nil {
+		update.SSHPortForwardingEnabled = *req.SshPortForwardingEnabled
+	}
+	if req.SshSftpEnabled != nil {
+		update.SSHSFTPEnabled = *req.SshSftpEnabled
+	}
+	if req.SshExecEnabled != nil {
+		update.SSHExecEnabled = *req.SshExecEnabled
+	}
+
 	if req.ApprovalRequired != nil {
 		// todo: looks like that we reset all status property, is it right?
 		update.Status = &nbpeer.PeerStatus{
@@ -177,6 +197,68 @@ func (h *Handler) HandlePeer(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// ApprovePeer approves a peer pending approval and returns the approved peer
+func (h *Handler) ApprovePeer(w http.ResponseWriter, r *http.Request) {
+	claims := h.claimsExtractor.FromRequestContext(r)
+	accountID, userID, err := h.accountManager.GetAccountIDFromToken(r.Context(), claims)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	peerID := mux.Vars(r)["peerId"]
+	if len(peerID) == 0 {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid peer ID"), w)
+		return
+	}
+
+	peer, err := h.accountManager.ApprovePeer(r.Context(), accountID, userID, peerID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	account, err := h.accountManager.GetAccountByID(r.Context(), accountID, userID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	validPeers, err := h.accountManager.GetValidatedPeers(account)
+	if err != nil {
+		log.WithContext(r.Context()).Errorf("failed to list approved peers: %v", err)
+		util.WriteError(r.Context(), fmt.Errorf("internal error"), w)
+		return
+	}
+
+	_, valid := validPeers[peer.ID]
+	groupsInfo := groups.ToGroupsInfo(account.Groups, peer.ID)
+	util.WriteJSONObject(r.Context(), w, toSinglePeerResponse(peer, groupsInfo, h.accountManager.GetDNSDomain(), valid))
+}
+
+// RejectPeer rejects a peer pending approval, the peer is deleted from the account
+func (h *Handler) RejectPeer(w http.ResponseWriter, r *http.Request) {
+	claims := h.claimsExtractor.FromRequestContext(r)
+	accountID, userID, err := h.accountManager.GetAccountIDFromToken(r.Context(), claims)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	peerID := mux.Vars(r)["peerId"]
+	if len(peerID) == 0 {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid peer ID"), w)
+		return
+	}
+
+	if err = h.accountManager.RejectPeer(r.Context(), accountID, userID, peerID); err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, util.EmptyObject{})
+}
+
 // GetAllPeers returns a list of all peers associated with a provided account
 func (h *Handler) GetAllPeers(w http.ResponseWriter, r *http.Request) {
 	claims := h.claimsExtractor.FromRequestContext(r)
@@ -296,6 +378,199 @@ func (h *Handler) GetAccessiblePeers(w http.ResponseWriter, r *http.Request) {
 	util.WriteJSONObject(r.Context(), w, toAccessiblePeers(netMap, dnsDomain))
 }
 
+// SimulatePolicies evaluates whether the account policies allow traffic from the peer to the requested destination
+func (h *Handler) SimulatePolicies(w http.ResponseWriter, r *http.Request) {
+	claims := h.claimsExtractor.FromRequestContext(r)
+	accountID, userID, err := h.accountManager.GetAccountIDFromToken(r.Context(), claims)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	vars := mux.Vars(r)
+	peerID := vars["peerId"]
+	if len(peerID) == 0 {
+		util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid peer ID"), w)
+		return
+	}
+
+	req := &api.PostApiPeersPeerIdPolicySimulationJSONRequestBody{}
+	if err = json.NewDecoder(r.Body).Decode(&req); err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	simulationReq, err := toPolicySimulationRequest(peerID, req)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	account, err := h.accountManager.GetAccountByID(r.Context(), accountID, userID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	user, err := account.FindUser(userID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	if !user.HasAdminPower() && !user.IsServiceUser {
+		util.WriteError(r.Context(), status.NewAdminPermissionError(), w)
+		return
+	}
+
+	validPeers, err := h.accountManager.GetValidatedPeers(account)
+	if err != nil {
+		log.WithContext(r.Context()).Errorf("failed to list approved peers: %v", err)
+		util.WriteError(r.Context(), fmt.Errorf("internal error"), w)
+		return
+	}
+
+	result, err := account.SimulatePolicies(r.Context(), h.accountManager.GetDNSDomain(), validPeers, simulationReq)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, toPolicySimulationResponse(result))
+}
+
+func toPolicySimulationRequest(peerID string, req *api.PolicySimulationRequest) (*types.PolicySimulationRequest, error) {
+	simulationReq := &types.PolicySimulationRequest{
+		SourcePeerID: peerID,
+		Protocol:     types.PolicyRuleProtocolType(req.Protocol),
+	}
+
+	destinations := 0
+	if req.DestinationPeerId != nil && *req.DestinationPeerId != "" {
+		simulationReq.DestinationPeerID = *req.DestinationPeerId
+		destinations++
+	}
+	if req.DestinationResourceId != nil && *req.DestinationResourceId != "" {
+		simulationReq.DestinationResourceID = *req.DestinationResourceId
+		destinations++
+	}
+	if req.DestinationIp != nil && *req.DestinationIp != "" {
+		ip, err := netip.ParseAddr(*req.DestinationIp)
+		if err != nil {
+			return nil, status.Errorf(status.InvalidArgument, "invalid destination IP %s", *req.DestinationIp)
+		}
+		simulationReq.DestinationIP = ip
+		destinations++
+	}
+	if req.DestinationDomain != nil && *req.DestinationDomain != "" {
+		simulationReq.DestinationDomain = *req.DestinationDomain
+		destinations++
+	}
+	if destinations != 1 {
+		return nil, status.Errorf(status.InvalidArgument, "exactly one of destination_peer_id, destination_resource_id, destination_ip or destination_domain must be set")
+	}
+
+	if req.Port != nil {
+		if *req.Port < 0 || *req.Port > 65535 {
+			return nil, status.Errorf(status.InvalidArgument, "invalid port %d", *req.Port)
+		}
+		simulationReq.Port = uint16(*req.Port)
+	}
+
+	return simulationReq, nil
+}
+
+func toPolicySimulationResponse(result *types.PolicySimulationResult) *api.PolicySimulationResult {
+	matches := make([]api.PolicySimulationMatch, 0, len(result.Matches))
+	for _, match := range result.Matches {
+		apiMatch := api.PolicySimulationMatch{
+			PolicyId:                       match.PolicyID,
+			PolicyName:                     match.PolicyName,
+			RuleId:                         match.RuleID,
+			RuleName:                       match.RuleName,
+			Action:                         api.PolicySimulationMatchAction(match.Action),
+			Applied:                        match.Applied,
+			PostureChecks:                  emptyIfNil(match.PostureChecks),
+			FailedPostureChecks:            emptyIfNil(match.FailedPostureChecks),
+			DestinationPostureChecks:       emptyIfNil(match.DestinationPostureChecks),
+			FailedDestinationPostureChecks: emptyIfNil(match.FailedDestinationPostureChecks),
+			SourceFirewallRules:            toSimulatedFirewallRules(match.SourceFirewallRules),
+			DestinationFirewallRules:       toSimulatedFirewallRules(match.DestinationFirewallRules),
+			RouteFirewallRules:             toSimulatedRouteFirewallRules(match.RouteFirewallRules),
+		}
+		if match.RouteID != "" {
+			routeID := string(match.RouteID)
+			apiMatch.RouteId = &routeID
+		}
+		if match.RoutingPeerID != "" {
+			apiMatch.RoutingPeerId = &match.RoutingPeerID
+		}
+		matches = append(matches, apiMatch)
+	}
+
+	return &api.PolicySimulationResult{
+		Allowed: result.Allowed,
+		Matches: matches,
+	}
+}
+
+func toSimulatedFirewallRules(rules []*types.FirewallRule) []api.SimulatedFirewallRule {
+	apiRules := make([]api.SimulatedFirewallRule, 0, len(rules))
+	for _, rule := range rules {
+		direction := api.SimulatedFirewallRuleDirectionIn
+		if rule.Direction == types.FirewallRuleDirectionOUT {
+			direction = api.SimulatedFirewallRuleDirectionOut
+		}
+		apiRule := api.SimulatedFirewallRule{
+			PeerIp:    rule.PeerIP,
+			Direction: direction,
+			Action:    api.SimulatedFirewallRuleAction(rule.Action),
+			Protocol:  rule.Protocol,
+			Port:      rule.Port,
+		}
+		if rule.PortRange.Start != 0 {
+			apiRule.PortRange = &api.RulePortRange{
+				Start: int(rule.PortRange.Start),
+				End:   int(rule.PortRange.End),
+			}
+		}
+		apiRules = append(apiRules, apiRule)
+	}
+	return apiRules
+}
+
+func toSimulatedRouteFirewallRules(rules []*types.RouteFirewallRule) []api.SimulatedRouteFirewallRule {
+	apiRules := make([]api.SimulatedRouteFirewallRule, 0, len(rules))
+	for _, rule := range rules {
+		apiRule := api.SimulatedRouteFirewallRule{
+			SourceRanges: rule.SourceRanges,
+			Destination:  rule.Destination,
+			Action:       api.SimulatedRouteFirewallRuleAction(rule.Action),
+			Protocol:     rule.Protocol,
+			Port:         int(rule.Port),
+		}
+		if len(rule.Domains) > 0 {
+			domains := rule.Domains.ToPunycodeList()
+			apiRule.Domains = &domains
+		}
+		if rule.PortRange.Start != 0 {
+			apiRule.PortRange = &api.RulePortRange{
+				Start: int(rule.PortRange.Start),
+				End:   int(rule.PortRange.End),
+			}
+		}
+		apiRules = append(apiRules, apiRule)
+	}
+	return apiRules
+}
+
+func emptyIfNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
 func toAccessiblePeers(netMap *types.NetworkMap, dnsDomain string) []api.AccessiblePeer {
 	accessiblePeers := make([]api.AccessiblePeer, 0, len(netMap.Peers)+len(netMap.OfflinePeers))
 	for _, p := range netMap.Peers {
@@ -344,6 +619,9 @@ func toSinglePeerResponse(peer *nbpeer.Peer, groupsInfo []api.GroupMinimum, dnsD
 		Version:                     peer.Meta.WtVersion,
 		Groups:                      groupsInfo,
 		SshEnabled:                  peer.SSHEnabled,
+		SshPortForwardingEnabled:    peer.SSHPortForwardingEnabled,
+		SshSftpEnabled:              peer.SSHSFTPEnabled,
+		SshExecEnabled:              peer.SSHExecEnabled,
 		Hostname:                    peer.Meta.Hostname,
 		UserId:                      peer.UserID,
 		UiVersion:                   peer.Meta.UIVersion,
@@ -391,6 +669,9 @@ func toPeerListItemResponse(peer *nbpeer.Peer, groupsInfo []api.GroupMinimum, dn
 		SerialNumber:           peer.Meta.SystemSerialNumber,
 
 		InactivityExpirationEnabled: peer.InactivityExpirationEnabled,
+		SshPortForwardingEnabled:    peer.SSHPortForwardingEnabled,
+		SshSftpEnabled:              peer.SSHSFTPEnabled,
+		SshExecEnabled:              peer.SSHExecEnabled,
 	}
 }
 
@@ -18,6 +18,7 @@ import (
 	"github.com/netbirdio/netbird/management/server/http/api"
 	"github.com/netbirdio/netbird/management/server/jwtclaims"
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/status"
 	"github.com/netbirdio/netbird/management/server/types"
 
 	"github.com/stretchr/testify/assert"
@@ -49,6 +50,9 @@ func initTestMetaData(peers ...*nbpeer.Peer) *Handler {
 					}
 				}
 				p.SSHEnabled = update.SSHEnabled
+				p.SSHPortForwardingEnabled = update.SSHPortForwardingEnabled
+				p.SSHSFTPEnabled = update.SSHSFTPEnabled
+				p.SSHExecEnabled = update.SSHExecEnabled
 				p.LoginExpirationEnabled = update.LoginExpirationEnabled
 				p.Name = update.Name
 				return p, nil
@@ -66,6 +70,24 @@ func initTestMetaData(peers ...*nbpeer.Peer) *Handler {
 			GetPeersFunc: func(_ context.Context, accountID, userID string) ([]*nbpeer.Peer, error) {
 				return peers, nil
 			},
+			ApprovePeerFunc: func(_ context.Context, accountID, userID, peerID string) (*nbpeer.Peer, error) {
+				for _, peer := range peers {
+					if peer.ID == peerID && peer.Status.RequiresApproval {
+						p := peer.Copy()
+						p.Status.RequiresApproval = false
+						return p, nil
+					}
+				}
+				return nil, status.Errorf(status.PreconditionFailed, "peer %s is not pending approval", peerID)
+			},
+			RejectPeerFunc: func(_ context.Context, accountID, userID, peerID string) error {
+				for _, peer := range peers {
+					if peer.ID == peerID && peer.Status.RequiresApproval {
+						return nil
+					}
+				}
+				return status.Errorf(status.PreconditionFailed, "peer %s is not pending approval", peerID)
+			},
 			GetDNSDomainFunc: func() string {
 				return "netbird.selfhosted"
 			},
@@ -190,6 +212,7 @@ func TestGetPeers(t *testing.T) {
 	expectedUpdatedPeer := peer.Copy()
 	expectedUpdatedPeer.LoginExpirationEnabled = true
 	expectedUpdatedPeer.SSHEnabled = true
+	expectedUpdatedPeer.SSHSFTPEnabled = true
 	expectedUpdatedPeer.Name = "New Name"
 
 	expectedPeer1 := peer1.Copy()
@@ -234,7 +257,7 @@ func TestGetPeers(t *testing.T) {
 			requestPath:    "/api/peers/" + testPeerID,
 			expectedStatus: http.StatusOK,
 			expectedArray:  false,
-			requestBody:    bytes.NewBufferString("{\"login_expiration_enabled\":true,\"name\":\"New Name\",\"ssh_enabled\":true}"),
+			requestBody:    bytes.NewBufferString("{\"login_expiration_enabled\":true,\"name\":\"New Name\",\"ssh_enabled\":true,\"ssh_sftp_enabled\":true}"),
 			expectedPeer:   expectedUpdatedPeer,
 		},
 	}
@@ -305,6 +328,8 @@ func TestGetPeers(t *testing.T) {
 			assert.Equal(t, got.Os, "OS core")
 			assert.Equal(t, got.LoginExpirationEnabled, tc.expectedPeer.LoginExpirationEnabled)
 			assert.Equal(t, got.SshEnabled, tc.expectedPeer.SSHEnabled)
+			assert.Equal(t, got.SshSftpEnabled, tc.expectedPeer.SSHSFTPEnabled)
+			assert.Equal(t, got.SshPortForwardingEnabled, tc.expectedPeer.SSHPortForwardingEnabled)
 			assert.Equal(t, got.Connected, tc.expectedPeer.Status.Connected)
 			assert.Equal(t, got.SerialNumber, tc.expectedPeer.Meta.SystemSerialNumber)
 		})
@@ -426,3 +451,185 @@ func TestGetAccessiblePeers(t *testing.T) {
 		})
 	}
 }
+
+func TestSimulatePolicies(t *testing.T) {
+	peer1 := &nbpeer.Peer{
+		ID:     "peer1",
+		Key:    "key1",
+		IP:     net.ParseIP("100.64.0.1"),
+		Status: &nbpeer.PeerStatus{Connected: true},
+		Name:   "peer1",
+		UserID: regularUser,
+	}
+
+	peer2 := &nbpeer.Peer{
+		ID:     "peer2",
+		Key:    "key2",
+		IP:     net.ParseIP("100.64.0.2"),
+		Status: &nbpeer.PeerStatus{Connected: true},
+		Name:   "peer2",
+		UserID: adminUser,
+	}
+
+	p := initTestMetaData(peer1, peer2)
+
+	tt := []struct {
+		name            string
+		callerUserID    string
+		requestBody     string
+		expectedStatus  int
+		expectedAllowed bool
+		expectedMatches int
+	}{
+		{
+			name:            "allowed by destination peer",
+			callerUserID:    adminUser,
+			requestBody:     `{"destination_peer_id":"peer2","protocol":"tcp","port":22}`,
+			expectedStatus:  http.StatusOK,
+			expectedAllowed: true,
+			expectedMatches: 1,
+		},
+		{
+			name:            "allowed by destination IP for service user",
+			callerUserID:    serviceUser,
+			requestBody:     `{"destination_ip":"100.64.0.2","protocol":"icmp"}`,
+			expectedStatus:  http.StatusOK,
+			expectedAllowed: true,
+			expectedMatches: 1,
+		},
+		{
+			name:           "unknown destination IP",
+			callerUserID:   adminUser,
+			requestBody:    `{"destination_ip":"10.0.0.1","protocol":"tcp","port":443}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "non admin user is denied",
+			callerUserID:   regularUser,
+			requestBody:    `{"destination_peer_id":"peer2","protocol":"tcp","port":22}`,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "missing destination",
+			callerUserID:   adminUser,
+			requestBody:    `{"protocol":"tcp","port":22}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "multiple destinations",
+			callerUserID:   adminUser,
+			requestBody:    `{"destination_peer_id":"peer2","destination_ip":"100.64.0.2","protocol":"tcp"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:           "invalid protocol",
+			callerUserID:   adminUser,
+			requestBody:    `{"destination_peer_id":"peer2","protocol":"sctp"}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/peers/peer1/policy-simulation", bytes.NewBufferString(tc.requestBody))
+			ctx := context.WithValue(context.Background(), userIDKey, tc.callerUserID)
+			req = req.WithContext(ctx)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/api/peers/{peerId}/policy-simulation", p.SimulatePolicies).Methods("POST")
+			router.ServeHTTP(recorder, req)
+
+			res := recorder.Result()
+			defer res.Body.Close()
+			if res.StatusCode != tc.expectedStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", res.StatusCode, tc.expectedStatus)
+			}
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var result api.PolicySimulationResult
+			err := json.NewDecoder(res.Body).Decode(&result)
+			if err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+
+			assert.Equal(t, tc.expectedAllowed, result.Allowed)
+			assert.Len(t, result.Matches, tc.expectedMatches)
+		})
+	}
+}
+
+func TestPeerApproval(t *testing.T) {
+	pendingPeer := &nbpeer.Peer{
+		ID:     "pending",
+		Key:    "key1",
+		IP:     net.ParseIP("100.64.0.1"),
+		Status: &nbpeer.PeerStatus{RequiresApproval: true},
+		Name:   "pending",
+	}
+
+	approvedPeer := &nbpeer.Peer{
+		ID:     "approved",
+		Key:    "key2",
+		IP:     net.ParseIP("100.64.0.2"),
+		Status: &nbpeer.PeerStatus{},
+		Name:   "approved",
+	}
+
+	p := initTestMetaData(pendingPeer, approvedPeer)
+
+	tt := []struct {
+		name           string
+		requestPath    string
+		expectedStatus int
+	}{
+		{
+			name:           "approve pending peer",
+			requestPath:    "/api/peers/pending/approve",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "approve approved peer",
+			requestPath:    "/api/peers/approved/approve",
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:           "reject pending peer",
+			requestPath:    "/api/peers/pending/reject",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "reject approved peer",
+			requestPath:    "/api/peers/approved/reject",
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, tc.requestPath, nil)
+			ctx := context.WithValue(context.Background(), userIDKey, adminUser)
+			req = req.WithContext(ctx)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/api/peers/{peerId}/approve", p.ApprovePeer).Methods("POST")
+			router.HandleFunc("/api/peers/{peerId}/reject", p.RejectPeer).Methods("POST")
+			router.ServeHTTP(recorder, req)
+
+			res := recorder.Result()
+			defer res.Body.Close()
+			assert.Equal(t, tc.expectedStatus, res.StatusCode)
+
+			if tc.expectedStatus == http.StatusOK && tc.requestPath == "/api/peers/pending/approve" {
+				var peer api.Peer
+				err := json.NewDecoder(res.Body).Decode(&peer)
+				assert.NoError(t, err)
+				assert.Equal(t, "pending", peer.Id)
+				assert.False(t, peer.ApprovalRequired)
+			}
+		})
+	}
+}
@@ -3,7 +3,10 @@ package policies
 import (
 	"encoding/json"
 	"net/http"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -17,6 +20,9 @@ import (
 	"github.com/netbirdio/netbird/management/server/types"
 )
 
+// sshLocalUserRegexp matches portable local account names
+var sshLocalUserRegexp = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,31}$`)
+
 // handler is a handler that returns policy of the account
 type handler struct {
 	accountManager  server.AccountManager
@@ -258,6 +264,25 @@ func (h *handler) savePolicy(w http.ResponseWriter, r *http.Request, accountID s
 			}
 		}
 
+		if rule.Schedule != nil {
+			schedule, err := toRuleSchedule(rule.Schedule)
+			if err != nil {
+				util.WriteError(r.Context(), err, w)
+				return
+			}
+			pr.Schedule = schedule
+		}
+
+		if rule.SshLocalUsers != nil {
+			for _, localUser := range *rule.SshLocalUsers {
+				if !sshLocalUserRegexp.MatchString(localUser) {
+					util.WriteError(r.Context(), status.Errorf(status.InvalidArgument, "invalid SSH local user name: %s", localUser), w)
+					return
+				}
+				pr.SSHLocalUsers = append(pr.SSHLocalUsers, localUser)
+			}
+		}
+
 		// validate policy object
 		switch pr.Protocol {
 		case types.PolicyRuleProtocolALL, types.PolicyRuleProtocolICMP:
@@ -283,6 +308,10 @@ func (h *handler) savePolicy(w http.ResponseWriter, r *http.Request, accountID s
 		policy.SourcePostureChecks = *req.SourcePostureChecks
 	}
 
+	if req.DestinationPostureChecks != nil {
+		policy.DestinationPostureChecks = *req.DestinationPostureChecks
+	}
+
 	policy, err := h.accountManager.SavePolicy(r.Context(), accountID, userID, policy)
 	if err != nil {
 		util.WriteError(r.Context(), err, w)
@@ -373,11 +402,12 @@ func toPolicyResponse(groups []*types.Group, policy *types.Policy) *api.Policy {
 
 	cache := make(map[string]api.GroupMinimum)
 	ap := &api.Policy{
-		Id:                  &policy.ID,
-		Name:                policy.Name,
-		Description:         &policy.Description,
-		Enabled:             policy.Enabled,
-		SourcePostureChecks: policy.SourcePostureChecks,
+		Id:                       &policy.ID,
+		Name:                     policy.Name,
+		Description:              &policy.Description,
+		Enabled:                  policy.Enabled,
+		SourcePostureChecks:      policy.SourcePostureChecks,
+		DestinationPostureChecks: policy.DestinationPostureChecks,
 	}
 	for _, r := range policy.Rules {
 		rID := r.ID
@@ -410,6 +440,13 @@ func toPolicyResponse(groups []*types.Group, policy *types.Policy) *api.Policy {
 			rule.PortRanges = &portRanges
 		}
 
+		rule.Schedule = toPolicyRuleScheduleResponse(r.Schedule)
+
+		if len(r.SSHLocalUsers) != 0 {
+			localUsersCopy := r.SSHLocalUsers
+			rule.SshLocalUsers = &localUsersCopy
+		}
+
 		var sources []api.GroupMinimum
 		for _, gid := range r.Sources {
 			_, ok := cache[gid]
@@ -452,3 +489,86 @@ func toPolicyResponse(groups []*types.Group, policy *types.Policy) *api.Policy {
 	}
 	return ap
 }
+
+var scheduleDays = map[api.PolicyRuleScheduleDays]time.Weekday{
+	api.PolicyRuleScheduleDaysMonday:    time.Monday,
+	api.PolicyRuleScheduleDaysTuesday:   time.Tuesday,
+	api.PolicyRuleScheduleDaysWednesday: time.Wednesday,
+	api.PolicyRuleScheduleDaysThursday:  time.Thursday,
+	api.PolicyRuleScheduleDaysFriday:    time.Friday,
+	api.PolicyRuleScheduleDaysSaturday:  time.Saturday,
+	api.PolicyRuleScheduleDaysSunday:    time.Sunday,
+}
+
+func toRuleSchedule(req *api.PolicyRuleSchedule) (*types.RuleSchedule, error) {
+	schedule := &types.RuleSchedule{
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+	}
+
+	if req.Timezone != nil {
+		schedule.TimeZone = *req.Timezone
+	}
+
+	if req.Days != nil {
+		for _, day := range *req.Days {
+			weekday, ok := scheduleDays[day]
+			if !ok {
+				return nil, status.Errorf(status.InvalidArgument, "unknown schedule day: %s", day)
+			}
+			schedule.Days = append(schedule.Days, weekday)
+		}
+	}
+
+	if req.TimeWindows != nil {
+		for _, window := range *req.TimeWindows {
+			schedule.TimeWindows = append(schedule.TimeWindows, types.RuleTimeWindow{
+				Start: window.Start,
+				End:   window.End,
+			})
+		}
+	}
+
+	if err := schedule.Validate(); err != nil {
+		return nil, status.Errorf(status.InvalidArgument, "invalid rule schedule: %v", err)
+	}
+
+	return schedule, nil
+}
+
+func toPolicyRuleScheduleResponse(schedule *types.RuleSchedule) *api.PolicyRuleSchedule {
+	if schedule == nil {
+		return nil
+	}
+
+	resp := &api.PolicyRuleSchedule{
+		StartsAt: schedule.StartsAt,
+		EndsAt:   schedule.EndsAt,
+	}
+
+	if schedule.TimeZone != "" {
+		timeZone := schedule.TimeZone
+		resp.Timezone = &timeZone
+	}
+
+	if len(schedule.Days) != 0 {
+		days := make([]api.PolicyRuleScheduleDays, 0, len(schedule.Days))
+		for _, weekday := range schedule.Days {
+			days = append(days, api.PolicyRuleScheduleDays(strings.ToLower(weekday.String())))
+		}
+		resp.Days = &days
+	}
+
+	if len(schedule.TimeWindows) != 0 {
+		windows := make([]api.PolicyRuleTimeWindow, 0, len(schedule.TimeWindows))
+		for _, window := range schedule.TimeWindows {
+			windows = append(windows, api.PolicyRuleTimeWindow{
+				Start: window.Start,
+				End:   window.End,
+			})
+		}
+		resp.TimeWindows = &windows
+	}
+
+	return resp
+}
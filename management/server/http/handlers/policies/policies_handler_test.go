@@ -251,6 +251,66 @@ func TestPoliciesWritePolicy(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "WritePolicy POST SSH Local Users",
+			requestType: http.MethodPost,
+			requestPath: "/api/policies",
+			requestBody: bytes.NewBuffer(
+				[]byte(`{
+                    "Name":"SSH Policy",
+                    "Rules":[
+                        {
+                            "Name":"SSH Rule",
+                            "Description": "Description",
+                            "Protocol": "all",
+                            "Action": "accept",
+                            "Bidirectional":true,
+							"Sources": ["F"],
+							"Destinations": ["G"],
+							"ssh_local_users": ["deploy", "www-data"]
+                        }
+                ]}`)),
+			expectedStatus: http.StatusOK,
+			expectedBody:   true,
+			expectedPolicy: &api.Policy{
+				Id:          str("id-was-set"),
+				Name:        "SSH Policy",
+				Description: &emptyString,
+				Rules: []api.PolicyRule{
+					{
+						Id:            str("id-was-set"),
+						Name:          "SSH Rule",
+						Description:   str("Description"),
+						Protocol:      "all",
+						Action:        "accept",
+						Bidirectional: true,
+						Sources:       &[]api.GroupMinimum{{Id: "F"}},
+						Destinations:  &[]api.GroupMinimum{{Id: "G"}},
+						SshLocalUsers: &[]string{"deploy", "www-data"},
+					},
+				},
+			},
+		},
+		{
+			name:        "WritePolicy POST Invalid SSH Local User",
+			requestType: http.MethodPost,
+			requestPath: "/api/policies",
+			requestBody: bytes.NewBuffer(
+				[]byte(`{
+                    "Name":"SSH Policy",
+                    "Rules":[
+                        {
+                            "Name":"SSH Rule",
+                            "Protocol": "all",
+                            "Action": "accept",
+                            "Bidirectional":true,
+							"Sources": ["F"],
+							"Destinations": ["G"],
+							"ssh_local_users": ["root; rm -rf /"]
+                        }
+                ]}`)),
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
 		{
 			name:        "WritePolicy PUT Invalid Name",
 			requestType: http.MethodPut,
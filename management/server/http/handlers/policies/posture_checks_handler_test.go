@@ -470,6 +470,122 @@ func TestPostureCheckUpdate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "Create Posture Checks Service Check",
+			requestType: http.MethodPost,
+			requestPath: "/api/posture-checks",
+			requestBody: bytes.NewBuffer(
+				[]byte(`{
+					"name": "default",
+					"description": "default",
+					"checks": {
+						"service_check": {
+							"action": "deny",
+							"services": [
+								{
+									"linux_name": "xrdp.service",
+									"windows_name": "TermService"
+								}
+							],
+							"ports": [
+								{
+									"port": 3389,
+									"protocol": "tcp"
+								}
+							]
+						}
+					}
+					}`)),
+			expectedStatus: http.StatusOK,
+			expectedBody:   true,
+			expectedPostureCheck: &api.PostureCheck{
+				Id:          "postureCheck",
+				Name:        "default",
+				Description: str("default"),
+				Checks: api.Checks{
+					ServiceCheck: &api.ServiceCheck{
+						Action: api.ServiceCheckActionDeny,
+						Services: &[]api.Service{
+							{
+								LinuxName:   str("xrdp.service"),
+								MacName:     str(""),
+								WindowsName: str("TermService"),
+							},
+						},
+						Ports: &[]api.ListeningPort{
+							{Port: 3389, Protocol: api.ListeningPortProtocolTcp},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:        "Create Posture Checks Host Security",
+			requestType: http.MethodPost,
+			requestPath: "/api/posture-checks",
+			requestBody: bytes.NewBuffer(
+				[]byte(`{
+					"name": "default",
+					"checks": {
+						"host_security_check": {
+							"disk_encryption": true,
+							"firewall": true
+						}
+					}
+					}`)),
+			expectedStatus: http.StatusOK,
+			expectedBody:   true,
+			expectedPostureCheck: &api.PostureCheck{
+				Id:          "postureCheck",
+				Name:        "default",
+				Description: str(""),
+				Checks: api.Checks{
+					HostSecurityCheck: &api.HostSecurityCheck{
+						DiskEncryption: true,
+						Firewall:       true,
+					},
+				},
+			},
+		},
+		{
+			name:        "Create Posture Checks Host Security Without Requirements",
+			requestType: http.MethodPost,
+			requestPath: "/api/posture-checks",
+			requestBody: bytes.NewBuffer(
+				[]byte(`{
+					"name": "default",
+					"checks": {
+						"host_security_check": {
+							"disk_encryption": false,
+							"firewall": false
+						}
+					}
+					}`)),
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   false,
+		},
+		{
+			name:        "Create Posture Checks Service Check Invalid Port",
+			requestType: http.MethodPost,
+			requestPath: "/api/posture-checks",
+			requestBody: bytes.NewBuffer(
+				[]byte(`{
+					"name": "default",
+					"checks": {
+						"service_check": {
+							"action": "deny",
+							"ports": [
+								{
+									"port": 70000,
+									"protocol": "tcp"
+								}
+							]
+						}
+					}
+					}`)),
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   false,
+		},
 		{
 			name:        "Create Posture Checks Invalid Check",
 			requestType: http.MethodPost,
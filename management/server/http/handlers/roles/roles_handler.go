@@ -0,0 +1,244 @@
+package roles
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	s "github.com/netbirdio/netbird/management/server"
+	"github.com/netbirdio/netbird/management/server/http/api"
+	"github.com/netbirdio/netbird/management/server/http/configs"
+	"github.com/netbirdio/netbird/management/server/http/util"
+	"github.com/netbirdio/netbird/management/server/jwtclaims"
+	"github.com/netbirdio/netbird/management/server/roles"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+// handler is a handler that returns custom roles of the account
+type handler struct {
+	rolesManager     roles.Manager
+	accountManager   s.AccountManager
+	extractFromToken func(ctx context.Context, claims jwtclaims.AuthorizationClaims) (string, string, error)
+	claimsExtractor  *jwtclaims.ClaimsExtractor
+}
+
+func AddEndpoints(rolesManager roles.Manager, accountManager s.AccountManager, extractFromToken func(ctx context.Context, claims jwtclaims.AuthorizationClaims) (string, string, error), authCfg configs.AuthCfg, router *mux.Router) {
+	rolesHandler := newHandler(rolesManager, accountManager, extractFromToken, authCfg)
+	router.HandleFunc("/roles", rolesHandler.getAllRoles).Methods("GET", "OPTIONS")
+	router.HandleFunc("/roles", rolesHandler.createRole).Methods("POST", "OPTIONS")
+	router.HandleFunc("/roles/{roleId}", rolesHandler.getRole).Methods("GET", "OPTIONS")
+	router.HandleFunc("/roles/{roleId}", rolesHandler.updateRole).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/roles/{roleId}", rolesHandler.deleteRole).Methods("DELETE", "OPTIONS")
+}
+
+func newHandler(rolesManager roles.Manager, accountManager s.AccountManager, extractFromToken func(ctx context.Context, claims jwtclaims.AuthorizationClaims) (string, string, error), authCfg configs.AuthCfg) *handler {
+	return &handler{
+		rolesManager:     rolesManager,
+		accountManager:   accountManager,
+		extractFromToken: extractFromToken,
+		claimsExtractor: jwtclaims.NewClaimsExtractor(
+			jwtclaims.WithAudience(authCfg.Audience),
+			jwtclaims.WithUserIDClaim(authCfg.UserIDClaim),
+		),
+	}
+}
+
+func (h *handler) getAllRoles(w http.ResponseWriter, r *http.Request) {
+	claims := h.claimsExtractor.FromRequestContext(r)
+	accountID, userID, err := h.extractFromToken(r.Context(), claims)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	customRoles, err := h.rolesManager.GetAllRoles(r.Context(), accountID, userID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	usersCount, err := h.getRolesUsersCount(r.Context(), accountID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	rolesResponse := make([]*api.CustomRole, 0, len(customRoles))
+	for _, role := range customRoles {
+		rolesResponse = append(rolesResponse, toCustomRoleResponse(role, usersCount[role.ID]))
+	}
+
+	util.WriteJSONObject(r.Context(), w, rolesResponse)
+}
+
+func (h *handler) createRole(w http.ResponseWriter, r *http.Request) {
+	claims := h.claimsExtractor.FromRequestContext(r)
+	accountID, userID, err := h.extractFromToken(r.Context(), claims)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	var req api.CustomRoleRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	role := fromCustomRoleRequest(&req)
+	role.AccountID = accountID
+
+	role, err = h.rolesManager.CreateRole(r.Context(), userID, role)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, toCustomRoleResponse(role, 0))
+}
+
+func (h *handler) getRole(w http.ResponseWriter, r *http.Request) {
+	claims := h.claimsExtractor.FromRequestContext(r)
+	accountID, userID, err := h.extractFromToken(r.Context(), claims)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	role, err := h.rolesManager.GetRole(r.Context(), accountID, userID, mux.Vars(r)["roleId"])
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	usersCount, err := h.getRolesUsersCount(r.Context(), accountID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, toCustomRoleResponse(role, usersCount[role.ID]))
+}
+
+func (h *handler) updateRole(w http.ResponseWriter, r *http.Request) {
+	claims := h.claimsExtractor.FromRequestContext(r)
+	accountID, userID, err := h.extractFromToken(r.Context(), claims)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	var req api.CustomRoleRequest
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		util.WriteErrorResponse("couldn't parse JSON request", http.StatusBadRequest, w)
+		return
+	}
+
+	role := fromCustomRoleRequest(&req)
+	role.ID = mux.Vars(r)["roleId"]
+	role.AccountID = accountID
+
+	role, err = h.rolesManager.UpdateRole(r.Context(), userID, role)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	usersCount, err := h.getRolesUsersCount(r.Context(), accountID)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, toCustomRoleResponse(role, usersCount[role.ID]))
+}
+
+func (h *handler) deleteRole(w http.ResponseWriter, r *http.Request) {
+	claims := h.claimsExtractor.FromRequestContext(r)
+	accountID, userID, err := h.extractFromToken(r.Context(), claims)
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	err = h.rolesManager.DeleteRole(r.Context(), accountID, userID, mux.Vars(r)["roleId"])
+	if err != nil {
+		util.WriteError(r.Context(), err, w)
+		return
+	}
+
+	util.WriteJSONObject(r.Context(), w, util.EmptyObject{})
+}
+
+// getRolesUsersCount returns the number of users each custom role is assigned to
+func (h *handler) getRolesUsersCount(ctx context.Context, accountID string) (map[string]int, error) {
+	users, err := h.accountManager.ListUsers(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	usersCount := make(map[string]int)
+	for _, user := range users {
+		if user.CustomRoleID != "" {
+			usersCount[user.CustomRoleID]++
+		}
+	}
+	return usersCount, nil
+}
+
+func fromCustomRoleRequest(req *api.CustomRoleRequest) *types.CustomRole {
+	permissions := make([]types.RolePermission, 0, len(req.Permissions))
+	for _, permission := range req.Permissions {
+		var groups []string
+		if permission.Groups != nil {
+			groups = *permission.Groups
+		}
+		permissions = append(permissions, types.RolePermission{
+			Module: string(permission.Module),
+			Read:   permission.Read,
+			Write:  permission.Write,
+			Delete: permission.Delete,
+			Groups: groups,
+		})
+	}
+
+	var description string
+	if req.Description != nil {
+		description = *req.Description
+	}
+
+	return &types.CustomRole{
+		Name:        req.Name,
+		Description: description,
+		Permissions: permissions,
+	}
+}
+
+func toCustomRoleResponse(role *types.CustomRole, usersCount int) *api.CustomRole {
+	permissions := make([]api.RolePermission, 0, len(role.Permissions))
+	for _, permission := range role.Permissions {
+		groups := permission.Groups
+		if groups == nil {
+			groups = []string{}
+		}
+		permissions = append(permissions, api.RolePermission{
+			Module: api.RolePermissionModule(permission.Module),
+			Read:   permission.Read,
+			Write:  permission.Write,
+			Delete: permission.Delete,
+			Groups: &groups,
+		})
+	}
+
+	return &api.CustomRole{
+		Id:          role.ID,
+		Name:        role.Name,
+		Description: &role.Description,
+		Permissions: permissions,
+		UsersCount:  usersCount,
+	}
+}
@@ -90,9 +90,15 @@ func (h *handler) updateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	customRoleID := ""
+	if req.CustomRoleId != nil {
+		customRoleID = *req.CustomRoleId
+	}
+
 	newUser, err := h.accountManager.SaveUser(r.Context(), accountID, userID, &types.User{
 		Id:                   targetUserID,
 		Role:                 userRole,
+		CustomRoleID:         customRoleID,
 		AutoGroups:           req.AutoGroups,
 		Blocked:              req.IsBlocked,
 		Issued:               existingUser.Issued,
@@ -172,10 +178,16 @@ func (h *handler) createUser(w http.ResponseWriter, r *http.Request) {
 		name = *req.Name
 	}
 
+	customRoleID := ""
+	if req.CustomRoleId != nil {
+		customRoleID = *req.CustomRoleId
+	}
+
 	newUser, err := h.accountManager.CreateUser(r.Context(), accountID, userID, &types.UserInfo{
 		Email:         email,
 		Name:          name,
 		Role:          req.Role,
+		CustomRoleID:  customRoleID,
 		AutoGroups:    req.AutoGroups,
 		IsServiceUser: req.IsServiceUser,
 		Issued:        types.UserIssuedAPI,
@@ -285,6 +297,11 @@ func toUserResponse(user *types.UserInfo, currenUserID string) *api.User {
 		userStatus = api.UserStatusBlocked
 	}
 
+	var customRoleID *string
+	if user.CustomRoleID != "" {
+		customRoleID = &user.CustomRoleID
+	}
+
 	isCurrent := user.ID == currenUserID
 	return &api.User{
 		Id:            user.ID,
@@ -298,6 +315,7 @@ func toUserResponse(user *types.UserInfo, currenUserID string) *api.User {
 		IsBlocked:     user.IsBlocked,
 		LastLogin:     &user.LastLogin,
 		Issued:        &user.Issued,
+		CustomRoleId:  customRoleID,
 		Permissions: &api.UserPermissions{
 			DashboardView: (*api.UserPermissionsDashboardView)(&user.Permissions.DashboardView),
 		},
@@ -4,11 +4,13 @@ import (
 	"context"
 	"net/http"
 	"regexp"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netbirdio/netbird/management/server/http/middleware/bypass"
 	"github.com/netbirdio/netbird/management/server/http/util"
+	"github.com/netbirdio/netbird/management/server/permissions"
 	"github.com/netbirdio/netbird/management/server/status"
 	"github.com/netbirdio/netbird/management/server/types"
 
@@ -18,25 +20,62 @@ import (
 // GetUser function defines a function to fetch user from Account by jwtclaims.AuthorizationClaims
 type GetUser func(ctx context.Context, claims jwtclaims.AuthorizationClaims) (*types.User, error)
 
+// ValidatePermissions function defines a function to check if a user is allowed to perform an operation on a module
+type ValidatePermissions func(ctx context.Context, accountID, userID string, module permissions.Module, operation permissions.Operation) (bool, error)
+
 // AccessControl middleware to restrict to make POST/PUT/DELETE requests by admin only
 type AccessControl struct {
-	claimsExtract jwtclaims.ClaimsExtractor
-	getUser       GetUser
+	claimsExtract       jwtclaims.ClaimsExtractor
+	getUser             GetUser
+	validatePermissions ValidatePermissions
 }
 
 // NewAccessControl instance constructor
-func NewAccessControl(audience, userIDClaim string, getUser GetUser) *AccessControl {
+func NewAccessControl(audience, userIDClaim string, getUser GetUser, validatePermissions ValidatePermissions) *AccessControl {
 	return &AccessControl{
 		claimsExtract: *jwtclaims.NewClaimsExtractor(
 			jwtclaims.WithAudience(audience),
 			jwtclaims.WithUserIDClaim(userIDClaim),
 		),
-		getUser: getUser,
+		getUser:             getUser,
+		validatePermissions: validatePermissions,
 	}
 }
 
 var tokenPathRegexp = regexp.MustCompile(`^.*/api/users/.*/tokens.*$`)
 
+// modulePaths maps the API path prefixes to the modules which can be granted by custom roles
+var modulePaths = map[string]permissions.Module{
+	"/api/networks":       permissions.Networks,
+	"/api/peers":          permissions.Peers,
+	"/api/groups":         permissions.Groups,
+	"/api/policies":       permissions.Policies,
+	"/api/routes":         permissions.Routes,
+	"/api/dns":            permissions.DNS,
+	"/api/setup-keys":     permissions.SetupKeys,
+	"/api/users":          permissions.Users,
+	"/api/events":         permissions.Events,
+	"/api/posture-checks": permissions.PostureChecks,
+}
+
+// getPathModule returns the module of the API path or an empty string if the path doesn't belong to any module
+func getPathModule(path string) permissions.Module {
+	for prefix, module := range modulePaths {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return module
+		}
+	}
+	return ""
+}
+
+// getMethodOperation returns the operation performed by the modify request method
+func getMethodOperation(method string) permissions.Operation {
+	if method == http.MethodDelete {
+		return permissions.Delete
+	}
+	return permissions.Write
+}
+
 // Handler method of the middleware which forbids all modify requests for non admin users
 func (a *AccessControl) Handler(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -69,6 +108,12 @@ func (a *AccessControl) Handler(h http.Handler) http.Handler {
 					return
 				}
 
+				// the custom role permission scope is validated by the account manager
+				if user.CustomRoleID != "" && a.isAllowedByCustomRole(r, user) {
+					h.ServeHTTP(w, r)
+					return
+				}
+
 				util.WriteError(r.Context(), status.Errorf(status.PermissionDenied, "only users with admin power can perform this operation"), w)
 				return
 			}
@@ -77,3 +122,19 @@ func (a *AccessControl) Handler(h http.Handler) http.Handler {
 		h.ServeHTTP(w, r)
 	})
 }
+
+// isAllowedByCustomRole checks if the user custom role grants the modify request on the requested module
+func (a *AccessControl) isAllowedByCustomRole(r *http.Request, user *types.User) bool {
+	module := getPathModule(r.URL.Path)
+	if module == "" || a.validatePermissions == nil {
+		return false
+	}
+
+	allowed, err := a.validatePermissions(r.Context(), user.AccountID, user.Id, module, getMethodOperation(r.Method))
+	if err != nil {
+		log.WithContext(r.Context()).Errorf("failed to validate custom role permissions of user %s: %v", user.Id, err)
+		return false
+	}
+
+	return allowed
+}
@@ -0,0 +1,122 @@
+package integrated_validator
+
+import (
+	"context"
+	"slices"
+
+	"github.com/netbirdio/netbird/management/server/account"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+// PeerApprovalValidator holds the new peers in the pending state until an administrator approves them.
+// The approval is enabled with ExtraSettings.PeerApprovalEnabled. Without approval groups and setup keys all new
+// peers require approval, otherwise only the peers added to the approval groups or with the approval setup keys.
+// The pending peers receive an empty network map and are excluded from the network maps of the other peers.
+type PeerApprovalValidator struct{}
+
+// NewPeerApprovalValidator returns a new instance of PeerApprovalValidator
+func NewPeerApprovalValidator() *PeerApprovalValidator {
+	return &PeerApprovalValidator{}
+}
+
+// ApprovalRequired returns true if a new peer of the groups added with the setup key requires approval
+func ApprovalRequired(extraSettings *account.ExtraSettings, peerGroups []string, setupKeyID string) bool {
+	if extraSettings == nil || !extraSettings.PeerApprovalEnabled {
+		return false
+	}
+
+	if len(extraSettings.IntegratedValidatorGroups) == 0 && len(extraSettings.PeerApprovalSetupKeys) == 0 {
+		return true
+	}
+
+	if setupKeyID != "" && slices.Contains(extraSettings.PeerApprovalSetupKeys, setupKeyID) {
+		return true
+	}
+
+	for _, group := range peerGroups {
+		if slices.Contains(extraSettings.IntegratedValidatorGroups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateExtraSettings accepts any settings, the approval groups and setup keys are validated by the account manager
+func (v *PeerApprovalValidator) ValidateExtraSettings(_ context.Context, _ *account.ExtraSettings, _ *account.ExtraSettings, _ map[string]*nbpeer.Peer, _ string, _ string) error {
+	return nil
+}
+
+// ValidatePeer applies the approval status of the update to the peer, it returns true if the status changed
+// as the network maps of the other peers have to be updated then
+func (v *PeerApprovalValidator) ValidatePeer(_ context.Context, update *nbpeer.Peer, peer *nbpeer.Peer, _ string, _ string, _ string, _ []string, _ *account.ExtraSettings) (*nbpeer.Peer, bool, error) {
+	if update.Status == nil || peer.Status == nil || update.Status.RequiresApproval == peer.Status.RequiresApproval {
+		return update, false, nil
+	}
+
+	peer.Status.RequiresApproval = update.Status.RequiresApproval
+	return update, true, nil
+}
+
+// setupKeyIDCtxKey is the context key of the ID of the setup key a new peer is added with
+type setupKeyIDCtxKey struct{}
+
+// WithSetupKeyID returns a context passing the ID of the setup key the new peer is added with to PreparePeer
+func WithSetupKeyID(ctx context.Context, setupKeyID string) context.Context {
+	return context.WithValue(ctx, setupKeyIDCtxKey{}, setupKeyID)
+}
+
+// setupKeyIDFromContext returns the ID of the setup key the new peer is added with, it is empty for the peers added
+// by users
+func setupKeyIDFromContext(ctx context.Context) string {
+	setupKeyID, _ := ctx.Value(setupKeyIDCtxKey{}).(string)
+	return setupKeyID
+}
+
+// PreparePeer marks the new peer as pending approval if it is required for its groups or the setup key passed with
+// WithSetupKeyID
+func (v *PeerApprovalValidator) PreparePeer(ctx context.Context, _ string, peer *nbpeer.Peer, peersGroup []string, extraSettings *account.ExtraSettings) *nbpeer.Peer {
+	preparedPeer := peer.Copy()
+	if ApprovalRequired(extraSettings, peersGroup, setupKeyIDFromContext(ctx)) {
+		preparedPeer.Status.RequiresApproval = true
+	}
+	return preparedPeer
+}
+
+// IsNotValidPeer returns true if the peer is pending approval. The approval status never changes on its own.
+func (v *PeerApprovalValidator) IsNotValidPeer(_ context.Context, _ string, peer *nbpeer.Peer, _ []string, extraSettings *account.ExtraSettings) (bool, bool, error) {
+	return isPending(peer, extraSettings), false, nil
+}
+
+// GetValidatedPeers returns the IDs of the peers not pending approval
+func (v *PeerApprovalValidator) GetValidatedPeers(_ string, _ map[string]*types.Group, peers map[string]*nbpeer.Peer, extraSettings *account.ExtraSettings) (map[string]struct{}, error) {
+	validatedPeers := make(map[string]struct{}, len(peers))
+	for _, peer := range peers {
+		if isPending(peer, extraSettings) {
+			continue
+		}
+		validatedPeers[peer.ID] = struct{}{}
+	}
+	return validatedPeers, nil
+}
+
+// PeerDeleted has nothing to clean up, the approval status is stored with the peer
+func (v *PeerApprovalValidator) PeerDeleted(_ context.Context, _, _ string) error {
+	return nil
+}
+
+// SetPeerInvalidationListener does nothing, the peers are invalidated only by the account changes
+func (v *PeerApprovalValidator) SetPeerInvalidationListener(func(accountID string)) {
+}
+
+// Stop does nothing, the validator has no background tasks
+func (v *PeerApprovalValidator) Stop(_ context.Context) {
+}
+
+// isPending returns true if the peer waits for approval. Disabling the approval approves all pending peers.
+func isPending(peer *nbpeer.Peer, extraSettings *account.ExtraSettings) bool {
+	if extraSettings == nil || !extraSettings.PeerApprovalEnabled {
+		return false
+	}
+	return peer.Status != nil && peer.Status.RequiresApproval
+}
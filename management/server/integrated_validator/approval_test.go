@@ -0,0 +1,80 @@
+package integrated_validator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/management/server/account"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+)
+
+func TestApprovalRequired(t *testing.T) {
+	tt := []struct {
+		name       string
+		extra      *account.ExtraSettings
+		groups     []string
+		setupKeyID string
+		expected   bool
+	}{
+		{
+			name:     "no settings",
+			expected: false,
+		},
+		{
+			name:     "approval disabled",
+			extra:    &account.ExtraSettings{IntegratedValidatorGroups: []string{"group"}},
+			groups:   []string{"group"},
+			expected: false,
+		},
+		{
+			name:     "approval of all peers",
+			extra:    &account.ExtraSettings{PeerApprovalEnabled: true},
+			expected: true,
+		},
+		{
+			name:     "peer in approval group",
+			extra:    &account.ExtraSettings{PeerApprovalEnabled: true, IntegratedValidatorGroups: []string{"group"}},
+			groups:   []string{"other", "group"},
+			expected: true,
+		},
+		{
+			name:     "peer not in approval group",
+			extra:    &account.ExtraSettings{PeerApprovalEnabled: true, IntegratedValidatorGroups: []string{"group"}},
+			groups:   []string{"other"},
+			expected: false,
+		},
+		{
+			name:       "peer added with approval setup key",
+			extra:      &account.ExtraSettings{PeerApprovalEnabled: true, PeerApprovalSetupKeys: []string{"key"}},
+			setupKeyID: "key",
+			expected:   true,
+		},
+		{
+			name:       "peer added with other setup key",
+			extra:      &account.ExtraSettings{PeerApprovalEnabled: true, PeerApprovalSetupKeys: []string{"key"}},
+			setupKeyID: "other",
+			expected:   false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ApprovalRequired(tc.extra, tc.groups, tc.setupKeyID))
+		})
+	}
+}
+
+func TestPeerApprovalValidator_PreparePeer(t *testing.T) {
+	validator := NewPeerApprovalValidator()
+	extra := &account.ExtraSettings{PeerApprovalEnabled: true, PeerApprovalSetupKeys: []string{"key"}}
+	peer := &nbpeer.Peer{ID: "peer", Status: &nbpeer.PeerStatus{}}
+
+	prepared := validator.PreparePeer(WithSetupKeyID(context.Background(), "key"), "account", peer, nil, extra)
+	assert.True(t, prepared.Status.RequiresApproval, "peer added with the approval setup key should require approval")
+	assert.False(t, peer.Status.RequiresApproval, "the original peer should not be changed")
+
+	prepared = validator.PreparePeer(context.Background(), "account", peer, nil, extra)
+	assert.False(t, prepared.Status.RequiresApproval, "peer added without the approval setup key should not require approval")
+}
@@ -191,6 +191,7 @@ func (w *Worker) generateProperties(ctx context.Context) properties {
 		rulesProtocol             map[string]int
 		rulesDirection            map[string]int
 		rulesWithSrcPostureChecks int
+		rulesWithDstPostureChecks int
 		postureChecks             int
 		groups                    int
 		routes                    int
@@ -254,6 +255,9 @@ func (w *Worker) generateProperties(ctx context.Context) properties {
 			if len(policy.SourcePostureChecks) > 0 {
 				rulesWithSrcPostureChecks++
 			}
+			if len(policy.DestinationPostureChecks) > 0 {
+				rulesWithDstPostureChecks++
+			}
 		}
 
 		postureChecks += len(account.PostureChecks)
@@ -324,6 +328,7 @@ func (w *Worker) generateProperties(ctx context.Context) properties {
 	metricsProperties["user_peers"] = userPeers
 	metricsProperties["rules"] = rules
 	metricsProperties["rules_with_src_posture_checks"] = rulesWithSrcPostureChecks
+	metricsProperties["rules_with_dst_posture_checks"] = rulesWithDstPostureChecks
 	metricsProperties["posture_checks"] = postureChecks
 	metricsProperties["groups"] = groups
 	metricsProperties["networks"] = networks
@@ -57,6 +57,8 @@ type MockAccountManager struct {
 	MarkPATUsedFunc                     func(ctx context.Context, pat string) error
 	UpdatePeerMetaFunc                  func(ctx context.Context, peerID string, meta nbpeer.PeerSystemMeta) error
 	UpdatePeerFunc                      func(ctx context.Context, accountID, userID string, peer *nbpeer.Peer) (*nbpeer.Peer, error)
+	ApprovePeerFunc                     func(ctx context.Context, accountID, userID, peerID string) (*nbpeer.Peer, error)
+	RejectPeerFunc                      func(ctx context.Context, accountID, userID, peerID string) error
 	CreateRouteFunc                     func(ctx context.Context, accountID string, prefix netip.Prefix, networkType route.NetworkType, domains domain.List, peer string, peerGroups []string, description string, netID route.NetID, masquerade bool, metric int, groups, accessControlGroupIDs []string, enabled bool, userID string, keepRoute bool) (*route.Route, error)
 	GetRouteFunc                        func(ctx context.Context, accountID string, routeID route.ID, userID string) (*route.Route, error)
 	SaveRouteFunc                       func(ctx context.Context, accountID string, userID string, route *route.Route) error
@@ -84,7 +86,7 @@ type MockAccountManager struct {
 	DeleteAccountFunc                   func(ctx context.Context, accountID, userID string) error
 	GetDNSDomainFunc                    func() string
 	StoreEventFunc                      func(ctx context.Context, initiatorID, targetID, accountID string, activityID activity.ActivityDescriber, meta map[string]any)
-	GetEventsFunc                       func(ctx context.Context, accountID, userID string) ([]*activity.Event, error)
+	GetEventsFunc                       func(ctx context.Context, accountID, userID string, filter activity.Filter) ([]*activity.Event, uint64, error)
 	GetDNSSettingsFunc                  func(ctx context.Context, accountID, userID string) (*types.DNSSettings, error)
 	SaveDNSSettingsFunc                 func(ctx context.Context, accountID, userID string, dnsSettingsToSave *types.DNSSettings) error
 	GetPeerFunc                         func(ctx context.Context, accountID, peerID, userID string) (*nbpeer.Peer, error)
@@ -103,6 +105,7 @@ type MockAccountManager struct {
 	UpdateIntegratedValidatorGroupsFunc func(ctx context.Context, accountID string, userID string, groups []string) error
 	GroupValidationFunc                 func(ctx context.Context, accountId string, groups []string) (bool, error)
 	SyncPeerMetaFunc                    func(ctx context.Context, peerPubKey string, meta nbpeer.PeerSystemMeta) error
+	LogPeerSSHSessionsFunc              func(ctx context.Context, peerPubKey string, events []server.SSHSessionEvent) error
 	FindExistingPostureCheckFunc        func(accountID string, checks *posture.ChecksDefinition) (*posture.Checks, error)
 	GetAccountIDForPeerKeyFunc          func(ctx context.Context, peerKey string) (string, error)
 	GetAccountByIDFunc                  func(ctx context.Context, accountID string, userID string) (*types.Account, error)
@@ -444,6 +447,22 @@ func (am *MockAccountManager) UpdatePeer(ctx context.Context, accountID, userID
 	return nil, status.Errorf(codes.Unimplemented, "method UpdatePeer is not implemented")
 }
 
+// ApprovePeer mocks ApprovePeerFunc function of the account manager
+func (am *MockAccountManager) ApprovePeer(ctx context.Context, accountID, userID, peerID string) (*nbpeer.Peer, error) {
+	if am.ApprovePeerFunc != nil {
+		return am.ApprovePeerFunc(ctx, accountID, userID, peerID)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method ApprovePeer is not implemented")
+}
+
+// RejectPeer mocks RejectPeerFunc function of the account manager
+func (am *MockAccountManager) RejectPeer(ctx context.Context, accountID, userID, peerID string) error {
+	if am.RejectPeerFunc != nil {
+		return am.RejectPeerFunc(ctx, accountID, userID, peerID)
+	}
+	return status.Errorf(codes.Unimplemented, "method RejectPeer is not implemented")
+}
+
 // CreateRoute mock implementation of CreateRoute from server.AccountManager interface
 func (am *MockAccountManager) CreateRoute(ctx context.Context, accountID string, prefix netip.Prefix, networkType route.NetworkType, domains domain.List, peerID string, peerGroupIDs []string, description string, netID route.NetID, masquerade bool, metric int, groups, accessControlGroupID []string, enabled bool, userID string, keepRoute bool) (*route.Route, error) {
 	if am.CreateRouteFunc != nil {
@@ -638,11 +657,11 @@ func (am *MockAccountManager) GetDNSDomain() string {
 }
 
 // GetEvents mocks GetEvents of the AccountManager interface
-func (am *MockAccountManager) GetEvents(ctx context.Context, accountID, userID string) ([]*activity.Event, error) {
+func (am *MockAccountManager) GetEvents(ctx context.Context, accountID, userID string, filter activity.Filter) ([]*activity.Event, uint64, error) {
 	if am.GetEventsFunc != nil {
-		return am.GetEventsFunc(ctx, accountID, userID)
+		return am.GetEventsFunc(ctx, accountID, userID, filter)
 	}
-	return nil, status.Errorf(codes.Unimplemented, "method GetEvents is not implemented")
+	return nil, 0, status.Errorf(codes.Unimplemented, "method GetEvents is not implemented")
 }
 
 // GetDNSSettings mocks GetDNSSettings of the AccountManager interface
@@ -782,6 +801,14 @@ func (am *MockAccountManager) GroupValidation(ctx context.Context, accountId str
 	return false, status.Errorf(codes.Unimplemented, "method GroupValidation is not implemented")
 }
 
+// LogPeerSSHSessions mocks LogPeerSSHSessions of the AccountManager interface
+func (am *MockAccountManager) LogPeerSSHSessions(ctx context.Context, peerPubKey string, events []server.SSHSessionEvent) error {
+	if am.LogPeerSSHSessionsFunc != nil {
+		return am.LogPeerSSHSessionsFunc(ctx, peerPubKey, events)
+	}
+	return status.Errorf(codes.Unimplemented, "method LogPeerSSHSessions is not implemented")
+}
+
 // SyncPeerMeta mocks SyncPeerMeta of the AccountManager interface
 func (am *MockAccountManager) SyncPeerMeta(ctx context.Context, peerPubKey string, meta nbpeer.PeerSystemMeta) error {
 	if am.SyncPeerMetaFunc != nil {
@@ -19,6 +19,8 @@ type ManagementServiceServerMock struct {
 	GetDeviceAuthorizationFlowFunc func(ctx context.Context, req *proto.EncryptedMessage) (*proto.EncryptedMessage, error)
 	GetPKCEAuthorizationFlowFunc   func(ctx context.Context, req *proto.EncryptedMessage) (*proto.EncryptedMessage, error)
 	SyncMetaFunc                   func(ctx context.Context, req *proto.EncryptedMessage) (*proto.Empty, error)
+	LogFlowsFunc                   func(ctx context.Context, req *proto.EncryptedMessage) (*proto.Empty, error)
+	LogSSHSessionsFunc             func(ctx context.Context, req *proto.EncryptedMessage) (*proto.Empty, error)
 }
 
 func (m ManagementServiceServerMock) Login(ctx context.Context, req *proto.EncryptedMessage) (*proto.EncryptedMessage, error) {
@@ -69,3 +71,17 @@ func (m ManagementServiceServerMock) SyncMeta(ctx context.Context, req *proto.En
 	}
 	return nil, status.Errorf(codes.Unimplemented, "method SyncMeta not implemented")
 }
+
+func (m ManagementServiceServerMock) LogFlows(ctx context.Context, req *proto.EncryptedMessage) (*proto.Empty, error) {
+	if m.LogFlowsFunc != nil {
+		return m.LogFlowsFunc(ctx, req)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method LogFlows not implemented")
+}
+
+func (m ManagementServiceServerMock) LogSSHSessions(ctx context.Context, req *proto.EncryptedMessage) (*proto.Empty, error) {
+	if m.LogSSHSessionsFunc != nil {
+		return m.LogSSHSessionsFunc(ctx, req)
+	}
+	return nil, status.Errorf(codes.Unimplemented, "method LogSSHSessions not implemented")
+}
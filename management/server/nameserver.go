@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"regexp"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/miekg/dns"
@@ -11,6 +12,7 @@ import (
 
 	nbdns "github.com/netbirdio/netbird/dns"
 	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/permissions"
 	"github.com/netbirdio/netbird/management/server/status"
 	"github.com/netbirdio/netbird/management/server/store"
 	"github.com/netbirdio/netbird/management/server/types"
@@ -29,11 +31,24 @@ func (am *DefaultAccountManager) GetNameServerGroup(ctx context.Context, account
 		return nil, status.NewUserNotPartOfAccountError()
 	}
 
+	var permission *types.RolePermission
 	if user.IsRegularUser() {
-		return nil, status.NewAdminPermissionError()
+		permission, err = am.checkCustomRolePermission(ctx, user, permissions.DNS, permissions.Read)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return am.Store.GetNameServerGroupByID(ctx, store.LockingStrengthShare, accountID, nsGroupID)
+	nsGroup, err := am.Store.GetNameServerGroupByID(ctx, store.LockingStrengthShare, accountID, nsGroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !permission.CoversAllGroups(nsGroup.Groups) {
+		return nil, status.NewNameServerGroupNotFoundError(nsGroupID)
+	}
+
+	return nsGroup, nil
 }
 
 // CreateNameServerGroup creates and saves a new nameserver group
@@ -50,6 +65,16 @@ func (am *DefaultAccountManager) CreateNameServerGroup(ctx context.Context, acco
 		return nil, status.NewUserNotPartOfAccountError()
 	}
 
+	if !user.HasAdminPower() {
+		permission, err := am.checkCustomRolePermission(ctx, user, permissions.DNS, permissions.Write)
+		if err != nil {
+			return nil, err
+		}
+		if !permission.CoversAllGroups(groups) {
+			return nil, status.Errorf(status.PermissionDenied, "nameserver group groups are outside of the user role scope")
+		}
+	}
+
 	newNSGroup := &nbdns.NameServerGroup{
 		ID:                   xid.New().String(),
 		AccountID:            accountID,
@@ -112,6 +137,14 @@ func (am *DefaultAccountManager) SaveNameServerGroup(ctx context.Context, accoun
 		return status.NewUserNotPartOfAccountError()
 	}
 
+	var permission *types.RolePermission
+	if !user.HasAdminPower() {
+		permission, err = am.checkCustomRolePermission(ctx, user, permissions.DNS, permissions.Write)
+		if err != nil {
+			return err
+		}
+	}
+
 	var updateAccountPeers bool
 
 	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
@@ -121,6 +154,10 @@ func (am *DefaultAccountManager) SaveNameServerGroup(ctx context.Context, accoun
 		}
 		nsGroupToSave.AccountID = accountID
 
+		if !permission.CoversAllGroups(oldNSGroup.Groups) || !permission.CoversAllGroups(nsGroupToSave.Groups) {
+			return status.Errorf(status.PermissionDenied, "nameserver group groups are outside of the user role scope")
+		}
+
 		if err = validateNameServerGroup(ctx, transaction, accountID, nsGroupToSave); err != nil {
 			return err
 		}
@@ -163,6 +200,14 @@ func (am *DefaultAccountManager) DeleteNameServerGroup(ctx context.Context, acco
 		return status.NewUserNotPartOfAccountError()
 	}
 
+	var permission *types.RolePermission
+	if !user.HasAdminPower() {
+		permission, err = am.checkCustomRolePermission(ctx, user, permissions.DNS, permissions.Delete)
+		if err != nil {
+			return err
+		}
+	}
+
 	var nsGroup *nbdns.NameServerGroup
 	var updateAccountPeers bool
 
@@ -172,6 +217,10 @@ func (am *DefaultAccountManager) DeleteNameServerGroup(ctx context.Context, acco
 			return err
 		}
 
+		if !permission.CoversAllGroups(nsGroup.Groups) {
+			return status.NewPermissionDeniedError()
+		}
+
 		updateAccountPeers, err = anyGroupHasPeersOrResources(ctx, transaction, accountID, nsGroup.Groups)
 		if err != nil {
 			return err
@@ -207,11 +256,20 @@ func (am *DefaultAccountManager) ListNameServerGroups(ctx context.Context, accou
 		return nil, status.NewUserNotPartOfAccountError()
 	}
 
+	var permission *types.RolePermission
 	if user.IsRegularUser() {
-		return nil, status.NewAdminPermissionError()
+		permission, err = am.checkCustomRolePermission(ctx, user, permissions.DNS, permissions.Read)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return am.Store.GetAccountNameServerGroups(ctx, store.LockingStrengthShare, accountID)
+	nsGroups, err := am.Store.GetAccountNameServerGroups(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterByRoleScope(permission, nsGroups, func(nsGroup *nbdns.NameServerGroup) []string { return nsGroup.Groups }), nil
 }
 
 func validateNameServerGroup(ctx context.Context, transaction store.Store, accountID string, nameserverGroup *nbdns.NameServerGroup) error {
@@ -303,6 +361,40 @@ func validateNSList(list []nbdns.NameServer) error {
 	if nsListLength == 0 || nsListLength > 3 {
 		return status.Errorf(status.InvalidArgument, "the list of nameservers should be 1 or 3, got %d", len(list))
 	}
+
+	for _, ns := range list {
+		if err := validateNameServer(ns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateNameServer(ns nbdns.NameServer) error {
+	if ns.NSType == nbdns.InvalidNameServerType || ns.NSType > nbdns.HTTPSNameServerType {
+		return status.Errorf(status.InvalidArgument, "invalid type of nameserver %s", ns.IP)
+	}
+
+	if ns.ServerName != "" {
+		if !ns.IsEncrypted() {
+			return status.Errorf(status.InvalidArgument, "server name of nameserver %s is only supported for the %s and %s types",
+				ns.IP, nbdns.TLSNameServerTypeString, nbdns.HTTPSNameServerTypeString)
+		}
+		if err := validateDomain(ns.ServerName); err != nil {
+			return status.Errorf(status.InvalidArgument, "invalid server name %s of nameserver %s: %v", ns.ServerName, ns.IP, err)
+		}
+	}
+
+	if ns.Path != "" {
+		if ns.NSType != nbdns.HTTPSNameServerType {
+			return status.Errorf(status.InvalidArgument, "path of nameserver %s is only supported for the %s type",
+				ns.IP, nbdns.HTTPSNameServerTypeString)
+		}
+		if !strings.HasPrefix(ns.Path, "/") || strings.ContainsAny(ns.Path, "?# ") {
+			return status.Errorf(status.InvalidArgument, "invalid path %s of nameserver %s", ns.Path, ns.IP)
+		}
+	}
+
 	return nil
 }
 
@@ -939,6 +939,62 @@ func TestValidateDomain(t *testing.T) {
 
 }
 
+func TestValidateNameServer(t *testing.T) {
+	ip := netip.MustParseAddr("1.1.1.1")
+	testCases := []struct {
+		name    string
+		ns      nbdns.NameServer
+		errFunc require.ErrorAssertionFunc
+	}{
+		{
+			name:    "Valid UDP nameserver",
+			ns:      nbdns.NameServer{IP: ip, NSType: nbdns.UDPNameServerType, Port: 53},
+			errFunc: require.NoError,
+		},
+		{
+			name:    "Valid DNS-over-TLS nameserver with server name",
+			ns:      nbdns.NameServer{IP: ip, NSType: nbdns.TLSNameServerType, Port: 853, ServerName: "one.one.one.one"},
+			errFunc: require.NoError,
+		},
+		{
+			name:    "Valid DNS-over-HTTPS nameserver with path",
+			ns:      nbdns.NameServer{IP: ip, NSType: nbdns.HTTPSNameServerType, Port: 443, ServerName: "cloudflare-dns.com", Path: "/dns-query"},
+			errFunc: require.NoError,
+		},
+		{
+			name:    "Invalid nameserver type",
+			ns:      nbdns.NameServer{IP: ip, NSType: nbdns.InvalidNameServerType, Port: 53},
+			errFunc: require.Error,
+		},
+		{
+			name:    "Invalid server name for UDP nameserver",
+			ns:      nbdns.NameServer{IP: ip, NSType: nbdns.UDPNameServerType, Port: 53, ServerName: "one.one.one.one"},
+			errFunc: require.Error,
+		},
+		{
+			name:    "Invalid server name",
+			ns:      nbdns.NameServer{IP: ip, NSType: nbdns.TLSNameServerType, Port: 853, ServerName: "one one"},
+			errFunc: require.Error,
+		},
+		{
+			name:    "Invalid path for DNS-over-TLS nameserver",
+			ns:      nbdns.NameServer{IP: ip, NSType: nbdns.TLSNameServerType, Port: 853, Path: "/dns-query"},
+			errFunc: require.Error,
+		},
+		{
+			name:    "Invalid relative path",
+			ns:      nbdns.NameServer{IP: ip, NSType: nbdns.HTTPSNameServerType, Port: 443, Path: "dns-query"},
+			errFunc: require.Error,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.errFunc(t, validateNameServer(testCase.ns))
+		})
+	}
+}
+
 func TestNameServerAccountPeersUpdate(t *testing.T) {
 	manager, account, peer1, peer2, peer3 := setupNetworkMapTest(t)
 
@@ -16,6 +16,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netbirdio/netbird/management/server/idp"
+	"github.com/netbirdio/netbird/management/server/integrated_validator"
 	"github.com/netbirdio/netbird/management/server/posture"
 	"github.com/netbirdio/netbird/management/server/store"
 	"github.com/netbirdio/netbird/management/server/types"
@@ -23,6 +24,7 @@ import (
 	"github.com/netbirdio/netbird/management/proto"
 	"github.com/netbirdio/netbird/management/server/activity"
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
 	"github.com/netbirdio/netbird/management/server/status"
 )
 
@@ -75,6 +77,15 @@ func (am *DefaultAccountManager) GetPeers(ctx context.Context, accountID, userID
 
 	regularUser := !user.HasAdminPower() && !user.IsServiceUser
 
+	// users with a custom role granting access to peers see the peers of the role scope as admins do
+	var permission *types.RolePermission
+	if regularUser && user.CustomRoleID != "" {
+		permission, err = am.checkCustomRolePermission(ctx, user, permissions.Peers, permissions.Read)
+		if err == nil {
+			regularUser = false
+		}
+	}
+
 	if regularUser && account.Settings.RegularUsersViewBlocked {
 		return peers, nil
 	}
@@ -84,6 +95,9 @@ func (am *DefaultAccountManager) GetPeers(ctx context.Context, accountID, userID
 			// only display peers that belong to the current user if the current user is not an admin
 			continue
 		}
+		if !permission.CoversAnyGroup(account.GetPeerGroupsList(peer.ID)) && user.Id != peer.UserID {
+			continue
+		}
 		p := peer.Copy()
 		peers = append(peers, p)
 		peersMap[peer.ID] = p
@@ -181,7 +195,8 @@ func (am *DefaultAccountManager) updatePeerStatusAndLocation(ctx context.Context
 	return oldStatus.LoginExpired, nil
 }
 
-// UpdatePeer updates peer. Only Peer.Name, Peer.SSHEnabled, Peer.LoginExpirationEnabled and Peer.InactivityExpirationEnabled can be updated.
+// UpdatePeer updates peer. Only Peer.Name, Peer.SSHEnabled, the SSH server features, Peer.LoginExpirationEnabled and
+// Peer.InactivityExpirationEnabled can be updated.
 func (am *DefaultAccountManager) UpdatePeer(ctx context.Context, accountID, userID string, update *nbpeer.Peer) (*nbpeer.Peer, error) {
 	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
 	defer unlock()
@@ -196,12 +211,26 @@ func (am *DefaultAccountManager) UpdatePeer(ctx context.Context, accountID, user
 		return nil, status.Errorf(status.NotFound, "peer %s not found", update.ID)
 	}
 
+	if err = am.checkPeerCustomRolePermission(ctx, account, userID, peer.ID, permissions.Write); err != nil {
+		return nil, err
+	}
+
+	wasPendingApproval := peer.Status.RequiresApproval
+
 	var requiresPeerUpdates bool
 	update, requiresPeerUpdates, err = am.integratedPeerValidator.ValidatePeer(ctx, update, peer, userID, accountID, am.GetDNSDomain(), account.GetPeerGroupsList(peer.ID), account.Settings.Extra)
 	if err != nil {
 		return nil, err
 	}
 
+	if wasPendingApproval != peer.Status.RequiresApproval {
+		event := activity.PeerApproved
+		if peer.Status.RequiresApproval {
+			event = activity.PeerApprovalRevoked
+		}
+		am.StoreEvent(ctx, userID, peer.ID, accountID, event, peer.EventMeta(am.GetDNSDomain()))
+	}
+
 	sshEnabledUpdated := peer.SSHEnabled != update.SSHEnabled
 	if sshEnabledUpdated {
 		peer.SSHEnabled = update.SSHEnabled
@@ -212,6 +241,21 @@ func (am *DefaultAccountManager) UpdatePeer(ctx context.Context, accountID, user
 		am.StoreEvent(ctx, userID, peer.IP.String(), accountID, event, peer.EventMeta(am.GetDNSDomain()))
 	}
 
+	sshFeaturesUpdated := peer.SSHPortForwardingEnabled != update.SSHPortForwardingEnabled ||
+		peer.SSHSFTPEnabled != update.SSHSFTPEnabled ||
+		peer.SSHExecEnabled != update.SSHExecEnabled
+	if sshFeaturesUpdated {
+		peer.SSHPortForwardingEnabled = update.SSHPortForwardingEnabled
+		peer.SSHSFTPEnabled = update.SSHSFTPEnabled
+		peer.SSHExecEnabled = update.SSHExecEnabled
+
+		meta := peer.EventMeta(am.GetDNSDomain())
+		meta["port_forwarding"] = peer.SSHPortForwardingEnabled
+		meta["sftp"] = peer.SSHSFTPEnabled
+		meta["exec"] = peer.SSHExecEnabled
+		am.StoreEvent(ctx, userID, peer.IP.String(), accountID, activity.PeerSSHFeaturesUpdated, meta)
+	}
+
 	peerLabelUpdated := peer.Name != update.Name
 
 	if peerLabelUpdated {
@@ -276,7 +320,7 @@ func (am *DefaultAccountManager) UpdatePeer(ctx context.Context, accountID, user
 
 	if peerLabelUpdated || requiresPeerUpdates {
 		am.UpdateAccountPeers(ctx, accountID)
-	} else if sshEnabledUpdated {
+	} else if sshEnabledUpdated || sshFeaturesUpdated {
 		am.UpdateAccountPeer(ctx, account, peer)
 	}
 
@@ -307,30 +351,41 @@ func (am *DefaultAccountManager) deletePeers(ctx context.Context, account *types
 		}
 
 		account.DeletePeer(peer.ID)
-		am.peersUpdateManager.SendUpdate(ctx, peer.ID,
-			&UpdateMessage{
-				Update: &proto.SyncResponse{
-					// fill those field for backward compatibility
-					RemotePeers:        []*proto.RemotePeerConfig{},
-					RemotePeersIsEmpty: true,
-					// new field
-					NetworkMap: &proto.NetworkMap{
-						Serial:               account.Network.CurrentSerial(),
-						RemotePeers:          []*proto.RemotePeerConfig{},
-						RemotePeersIsEmpty:   true,
-						FirewallRules:        []*proto.FirewallRule{},
-						FirewallRulesIsEmpty: true,
-					},
-				},
-				NetworkMap: &types.NetworkMap{},
-			})
+		am.peersUpdateManager.SendUpdate(ctx, peer.ID, deletedPeerUpdate(account.Network.CurrentSerial()))
 		am.peersUpdateManager.CloseChannel(ctx, peer.ID)
 		am.StoreEvent(ctx, userID, peer.ID, account.Id, activity.PeerRemovedByUser, peer.EventMeta(am.GetDNSDomain()))
 	}
 
+	am.peersUpdateManager.Publish(ctx, &UpdateEvent{
+		Type:      UpdateEventPeersDeleted,
+		AccountID: account.Id,
+		PeerIDs:   peerIDs,
+		Serial:    account.Network.CurrentSerial(),
+	})
+
 	return nil
 }
 
+// deletedPeerUpdate returns the update with an empty network map sent to a peer removed from the account
+func deletedPeerUpdate(serial uint64) *UpdateMessage {
+	return &UpdateMessage{
+		Update: &proto.SyncResponse{
+			// fill those field for backward compatibility
+			RemotePeers:        []*proto.RemotePeerConfig{},
+			RemotePeersIsEmpty: true,
+			// new field
+			NetworkMap: &proto.NetworkMap{
+				Serial:               serial,
+				RemotePeers:          []*proto.RemotePeerConfig{},
+				RemotePeersIsEmpty:   true,
+				FirewallRules:        []*proto.FirewallRule{},
+				FirewallRulesIsEmpty: true,
+			},
+		},
+		NetworkMap: &types.NetworkMap{},
+	}
+}
+
 // DeletePeer removes peer from the account by its IP
 func (am *DefaultAccountManager) DeletePeer(ctx context.Context, accountID, peerID, userID string) error {
 	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
@@ -341,6 +396,10 @@ func (am *DefaultAccountManager) DeletePeer(ctx context.Context, accountID, peer
 		return err
 	}
 
+	if err = am.checkPeerCustomRolePermission(ctx, account, userID, peerID, permissions.Delete); err != nil {
+		return err
+	}
+
 	updateAccountPeers, err := am.isPeerInActiveGroup(ctx, account, peerID)
 	if err != nil {
 		return err
@@ -543,7 +602,8 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, setupKey, userID s
 		if err != nil {
 			return fmt.Errorf("failed to get account settings: %w", err)
 		}
-		newPeer = am.integratedPeerValidator.PreparePeer(ctx, accountID, newPeer, groupsToAdd, settings.Extra)
+		validatorCtx := integrated_validator.WithSetupKeyID(ctx, setupKeyID)
+		newPeer = am.integratedPeerValidator.PreparePeer(validatorCtx, accountID, newPeer, groupsToAdd, settings.Extra)
 
 		err = transaction.AddPeerToAllGroup(ctx, accountID, newPeer.ID)
 		if err != nil {
@@ -594,6 +654,9 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, setupKey, userID s
 	}
 
 	am.StoreEvent(ctx, opEvent.InitiatorID, opEvent.TargetID, opEvent.AccountID, opEvent.Activity, opEvent.Meta)
+	if newPeer.Status.RequiresApproval {
+		am.StoreEvent(ctx, opEvent.InitiatorID, opEvent.TargetID, opEvent.AccountID, activity.PeerPendingApproval, opEvent.Meta)
+	}
 
 	unlock()
 	unlock = nil
@@ -618,19 +681,12 @@ func (am *DefaultAccountManager) AddPeer(ctx context.Context, setupKey, userID s
 		am.UpdateAccountPeers(ctx, accountID)
 	}
 
-	approvedPeersMap, err := am.GetValidatedPeers(account)
+	isRequiresApproval, _, err := am.integratedPeerValidator.IsNotValidPeer(ctx, accountID, newPeer, groupsToAdd, account.Settings.Extra)
 	if err != nil {
 		return nil, nil, nil, err
 	}
 
-	postureChecks, err := am.getPeerPostureChecks(account, newPeer.ID)
-	if err != nil {
-		return nil, nil, nil, err
-	}
-
-	customZone := account.GetPeersCustomZone(ctx, am.dnsDomain)
-	networkMap := account.GetPeerNetworkMap(ctx, newPeer.ID, customZone, approvedPeersMap, account.GetResourcePoliciesMap(), account.GetResourceRoutersMap(), am.metrics.AccountManagerMetrics())
-	return newPeer, networkMap, postureChecks, nil
+	return am.getValidatedPeerWithMap(ctx, isRequiresApproval, account, newPeer)
 }
 
 func (am *DefaultAccountManager) getFreeIP(ctx context.Context, s store.Store, accountID string) (net.IP, error) {
@@ -984,6 +1040,14 @@ func (am *DefaultAccountManager) GetPeer(ctx context.Context, accountID, peerID,
 		return peer, nil
 	}
 
+	// users with a custom role granting access to peers can view the peers of the role scope
+	if user.CustomRoleID != "" {
+		permission, err := am.checkCustomRolePermission(ctx, user, permissions.Peers, permissions.Read)
+		if err == nil && permission.CoversAnyGroup(account.GetPeerGroupsList(peerID)) {
+			return peer, nil
+		}
+	}
+
 	// it is also possible that user doesn't own the peer but some of his peers have access to it,
 	// this is a valid case, show the peer as well.
 	userPeers, err := account.FindUserPeers(userID)
@@ -1008,9 +1072,43 @@ func (am *DefaultAccountManager) GetPeer(ctx context.Context, accountID, peerID,
 	return nil, status.Errorf(status.Internal, "user %s has no access to peer %s under account %s", userID, peerID, accountID)
 }
 
+// checkPeerCustomRolePermission checks if a user without admin power is allowed to modify the peer by its custom role.
+// A scoped role allows modifying peers which belong to at least one group of the scope.
+func (am *DefaultAccountManager) checkPeerCustomRolePermission(ctx context.Context, account *types.Account, userID, peerID string, operation permissions.Operation) error {
+	if userID == activity.SystemInitiator {
+		return nil
+	}
+
+	user, err := account.FindUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if user.HasAdminPower() || user.IsServiceUser {
+		return nil
+	}
+
+	permission, err := am.checkCustomRolePermission(ctx, user, permissions.Peers, operation)
+	if err != nil {
+		return err
+	}
+
+	if !permission.CoversAnyGroup(account.GetPeerGroupsList(peerID)) {
+		return status.NewPermissionDeniedError()
+	}
+
+	return nil
+}
+
 // UpdateAccountPeers updates all peers that belong to an account.
 // Should be called when changes have to be synced to peers.
 func (am *DefaultAccountManager) UpdateAccountPeers(ctx context.Context, accountID string) {
+	am.peersUpdateManager.Publish(ctx, &UpdateEvent{Type: UpdateEventAccountPeers, AccountID: accountID})
+	am.updateConnectedAccountPeers(ctx, accountID)
+}
+
+// updateConnectedAccountPeers updates the account peers connected to this management instance
+func (am *DefaultAccountManager) updateConnectedAccountPeers(ctx context.Context, accountID string) {
 	account, err := am.requestBuffer.GetAccountWithBackpressure(ctx, accountID)
 	if err != nil {
 		log.WithContext(ctx).Errorf("failed to send out updates to peers: %v", err)
@@ -1071,10 +1169,16 @@ func (am *DefaultAccountManager) UpdateAccountPeers(ctx context.Context, account
 // Should be called when changes need to be synced to a specific peer only.
 func (am *DefaultAccountManager) UpdateAccountPeer(ctx context.Context, account *types.Account, peer *nbpeer.Peer) {
 	if !am.peersUpdateManager.HasChannel(peer.ID) {
-		log.WithContext(ctx).Tracef("peer %s doesn't have a channel, skipping network map update", peer.ID)
+		log.WithContext(ctx).Tracef("peer %s doesn't have a channel, passing network map update to other instances", peer.ID)
+		am.peersUpdateManager.Publish(ctx, &UpdateEvent{Type: UpdateEventAccountPeers, AccountID: account.Id, PeerIDs: []string{peer.ID}})
 		return
 	}
 
+	am.updateConnectedAccountPeer(ctx, account, peer)
+}
+
+// updateConnectedAccountPeer updates a single peer connected to this management instance
+func (am *DefaultAccountManager) updateConnectedAccountPeer(ctx context.Context, account *types.Account, peer *nbpeer.Peer) {
 	approvedPeersMap, err := am.GetValidatedPeers(account)
 	if err != nil {
 		log.WithContext(ctx).Errorf("failed to send update to peer %s, failed to validate peers: %v", peer.ID, err)
@@ -1097,6 +1201,42 @@ func (am *DefaultAccountManager) UpdateAccountPeer(ctx context.Context, account
 	am.peersUpdateManager.SendUpdate(ctx, peer.ID, &UpdateMessage{Update: update, NetworkMap: remotePeerNetworkMap})
 }
 
+// handleUpdateEvent delivers the changes made by the other management instances to the peers connected to this one
+func (am *DefaultAccountManager) handleUpdateEvent(ctx context.Context, event *UpdateEvent) {
+	switch event.Type {
+	case UpdateEventAccountPeers:
+		if len(event.PeerIDs) == 0 {
+			am.updateConnectedAccountPeers(ctx, event.AccountID)
+			return
+		}
+
+		account, err := am.requestBuffer.GetAccountWithBackpressure(ctx, event.AccountID)
+		if err != nil {
+			log.WithContext(ctx).Errorf("failed to send out updates to peers: %v", err)
+			return
+		}
+		for _, peerID := range event.PeerIDs {
+			peer := account.GetPeer(peerID)
+			if peer == nil || !am.peersUpdateManager.HasChannel(peerID) {
+				continue
+			}
+			am.updateConnectedAccountPeer(ctx, account, peer)
+		}
+	case UpdateEventPeersDeleted:
+		for _, peerID := range event.PeerIDs {
+			if !am.peersUpdateManager.HasChannel(peerID) {
+				continue
+			}
+			am.peersUpdateManager.SendUpdate(ctx, peerID, deletedPeerUpdate(event.Serial))
+			am.peersUpdateManager.CloseChannel(ctx, peerID)
+		}
+	case UpdateEventPeersDisconnected:
+		am.peersUpdateManager.CloseChannels(ctx, event.PeerIDs)
+	default:
+		log.WithContext(ctx).Warnf("unknown update event type %s", event.Type)
+	}
+}
+
 func ConvertSliceToMap(existingLabels []string) map[string]struct{} {
 	labelMap := make(map[string]struct{}, len(existingLabels))
 	for _, label := range existingLabels {
@@ -5,6 +5,7 @@ import (
 	"net/netip"
 	"slices"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/netbirdio/netbird/management/server/util"
@@ -36,6 +37,12 @@ type Peer struct {
 	SSHKey string
 	// SSHEnabled indicates whether SSH server is enabled on the peer
 	SSHEnabled bool
+	// SSHPortForwardingEnabled indicates whether the SSH server of the peer allows local and remote TCP port forwarding
+	SSHPortForwardingEnabled bool
+	// SSHSFTPEnabled indicates whether the SSH server of the peer serves the SFTP subsystem
+	SSHSFTPEnabled bool
+	// SSHExecEnabled indicates whether the SSH server of the peer runs non-interactive commands
+	SSHExecEnabled bool
 	// LoginExpirationEnabled indicates whether peer's login expiration is enabled and once expired the peer has to re-login.
 	// Works with LastLogin
 	LoginExpirationEnabled bool
@@ -89,6 +96,19 @@ type File struct {
 	ProcessIsRunning bool
 }
 
+// Service is a system service on the system.
+type Service struct {
+	Name    string
+	Running bool
+}
+
+// Port is a transport port on the system.
+type Port struct {
+	Port      uint16
+	Protocol  string
+	Listening bool
+}
+
 // PeerSystemMeta is a metadata of a Peer machine system
 type PeerSystemMeta struct { //nolint:revive
 	Hostname           string
@@ -107,6 +127,10 @@ type PeerSystemMeta struct { //nolint:revive
 	SystemManufacturer string
 	Environment        Environment `gorm:"serializer:json"`
 	Files              []File      `gorm:"serializer:json"`
+	Services           []Service   `gorm:"serializer:json"`
+	Ports              []Port      `gorm:"serializer:json"`
+	DiskEncrypted      bool
+	FirewallEnabled    bool
 }
 
 func (p PeerSystemMeta) isEqual(other PeerSystemMeta) bool {
@@ -136,6 +160,26 @@ func (p PeerSystemMeta) isEqual(other PeerSystemMeta) bool {
 		return false
 	}
 
+	sort.Slice(p.Services, func(i, j int) bool {
+		return p.Services[i].Name < p.Services[j].Name
+	})
+	sort.Slice(other.Services, func(i, j int) bool {
+		return other.Services[i].Name < other.Services[j].Name
+	})
+	if !slices.Equal(p.Services, other.Services) {
+		return false
+	}
+
+	sort.Slice(p.Ports, func(i, j int) bool {
+		return comparePorts(p.Ports[i], p.Ports[j]) < 0
+	})
+	sort.Slice(other.Ports, func(i, j int) bool {
+		return comparePorts(other.Ports[i], other.Ports[j]) < 0
+	})
+	if !slices.Equal(p.Ports, other.Ports) {
+		return false
+	}
+
 	return p.Hostname == other.Hostname &&
 		p.GoOS == other.GoOS &&
 		p.Kernel == other.Kernel &&
@@ -150,7 +194,9 @@ func (p PeerSystemMeta) isEqual(other PeerSystemMeta) bool {
 		p.SystemProductName == other.SystemProductName &&
 		p.SystemManufacturer == other.SystemManufacturer &&
 		p.Environment.Cloud == other.Environment.Cloud &&
-		p.Environment.Platform == other.Environment.Platform
+		p.Environment.Platform == other.Environment.Platform &&
+		p.DiskEncrypted == other.DiskEncrypted &&
+		p.FirewallEnabled == other.FirewallEnabled
 }
 
 func (p PeerSystemMeta) isEmpty() bool {
@@ -170,7 +216,19 @@ func (p PeerSystemMeta) isEmpty() bool {
 		p.SystemManufacturer == "" &&
 		p.Environment.Cloud == "" &&
 		p.Environment.Platform == "" &&
-		len(p.Files) == 0
+		len(p.Files) == 0 &&
+		len(p.Services) == 0 &&
+		len(p.Ports) == 0 &&
+		!p.DiskEncrypted &&
+		!p.FirewallEnabled
+}
+
+// comparePorts orders the ports by protocol and number
+func comparePorts(a, b Port) int {
+	if a.Protocol != b.Protocol {
+		return strings.Compare(a.Protocol, b.Protocol)
+	}
+	return int(a.Port) - int(b.Port)
 }
 
 // AddedWithSSOLogin indicates whether this peer has been added with an SSO login by a user.
@@ -196,6 +254,9 @@ func (p *Peer) Copy() *Peer {
 		UserID:                      p.UserID,
 		SSHKey:                      p.SSHKey,
 		SSHEnabled:                  p.SSHEnabled,
+		SSHPortForwardingEnabled:    p.SSHPortForwardingEnabled,
+		SSHSFTPEnabled:              p.SSHSFTPEnabled,
+		SSHExecEnabled:              p.SSHExecEnabled,
 		LoginExpirationEnabled:      p.LoginExpirationEnabled,
 		LastLogin:                   p.LastLogin,
 		CreatedAt:                   p.CreatedAt,
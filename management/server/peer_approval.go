@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+// ApprovePeer approves a peer pending approval, the peer joins the network maps of the other peers
+func (am *DefaultAccountManager) ApprovePeer(ctx context.Context, accountID, userID, peerID string) (*nbpeer.Peer, error) {
+	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	defer unlock()
+
+	account, err := am.Store.GetAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	peer, err := am.getPendingPeer(ctx, account, userID, peerID, permissions.Write)
+	if err != nil {
+		return nil, err
+	}
+
+	peer.Status.RequiresApproval = false
+	account.UpdatePeer(peer)
+	account.Network.IncSerial()
+
+	if err = am.Store.SaveAccount(ctx, account); err != nil {
+		return nil, err
+	}
+
+	am.StoreEvent(ctx, userID, peer.ID, accountID, activity.PeerApproved, peer.EventMeta(am.GetDNSDomain()))
+	am.UpdateAccountPeers(ctx, accountID)
+
+	return peer, nil
+}
+
+// RejectPeer rejects a peer pending approval, the peer is removed from the account
+func (am *DefaultAccountManager) RejectPeer(ctx context.Context, accountID, userID, peerID string) error {
+	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
+	defer unlock()
+
+	account, err := am.Store.GetAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	peer, err := am.getPendingPeer(ctx, account, userID, peerID, permissions.Delete)
+	if err != nil {
+		return err
+	}
+	meta := peer.EventMeta(am.GetDNSDomain())
+
+	if err = am.deletePeers(ctx, account, []string{peerID}, userID); err != nil {
+		return err
+	}
+
+	if err = am.Store.SaveAccount(ctx, account); err != nil {
+		return err
+	}
+
+	am.StoreEvent(ctx, userID, peerID, accountID, activity.PeerApprovalRejected, meta)
+
+	return nil
+}
+
+func (am *DefaultAccountManager) getPendingPeer(ctx context.Context, account *types.Account, userID, peerID string, operation permissions.Operation) (*nbpeer.Peer, error) {
+	peer := account.GetPeer(peerID)
+	if peer == nil {
+		return nil, status.Errorf(status.NotFound, "peer %s not found", peerID)
+	}
+
+	if err := am.checkPeerCustomRolePermission(ctx, account, userID, peerID, operation); err != nil {
+		return nil, err
+	}
+
+	if !peer.Status.RequiresApproval {
+		return nil, status.Errorf(status.PreconditionFailed, "peer %s is not pending approval", peerID)
+	}
+
+	return peer, nil
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+
+	"github.com/netbirdio/netbird/management/server/account"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/integrated_validator"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/telemetry"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+func setupPeerApprovalTest(t *testing.T) (*DefaultAccountManager, *types.Account, *types.SetupKey, *nbpeer.Peer) {
+	t.Helper()
+
+	metrics, err := telemetry.NewDefaultAppMetrics(context.Background())
+	require.NoError(t, err)
+
+	s, err := createStore(t)
+	require.NoError(t, err)
+
+	manager, err := BuildManager(context.Background(), s, NewPeersUpdateManager(nil), nil, "",
+		"netbird.cloud", &activity.InMemoryEventStore{}, nil, false, integrated_validator.NewPeerApprovalValidator(), metrics)
+	require.NoError(t, err)
+
+	acc, err := createAccount(manager, "test_account", userID, "")
+	require.NoError(t, err)
+
+	setupKey, err := manager.CreateSetupKey(context.Background(), acc.Id, "test-key", types.SetupKeyReusable, time.Hour, nil, 999, userID, false)
+	require.NoError(t, err)
+
+	// the peer added before the approval is enabled stays approved
+	approvedPeer := addPeerWithSetupKey(t, manager, setupKey)
+
+	return manager, acc, setupKey, approvedPeer
+}
+
+func addPeerWithSetupKey(t *testing.T, manager *DefaultAccountManager, setupKey *types.SetupKey) *nbpeer.Peer {
+	t.Helper()
+
+	key, err := wgtypes.GeneratePrivateKey()
+	require.NoError(t, err)
+
+	peer, _, _, err := manager.AddPeer(context.Background(), setupKey.Key, "", &nbpeer.Peer{
+		Key:    key.PublicKey().String(),
+		Meta:   nbpeer.PeerSystemMeta{Hostname: key.PublicKey().String()},
+		Status: &nbpeer.PeerStatus{Connected: true, LastSeen: time.Now().UTC()},
+	})
+	require.NoError(t, err)
+
+	return peer
+}
+
+func enablePeerApproval(t *testing.T, manager *DefaultAccountManager, accountID string, extra *account.ExtraSettings) {
+	t.Helper()
+
+	acc, err := manager.Store.GetAccount(context.Background(), accountID)
+	require.NoError(t, err)
+
+	settings := acc.Settings.Copy()
+	settings.Extra = extra
+	_, err = manager.UpdateAccountSettings(context.Background(), accountID, userID, settings)
+	require.NoError(t, err)
+}
+
+func assertPeerInNetworkMap(t *testing.T, manager *DefaultAccountManager, peerID, remotePeerID string, expected bool) {
+	t.Helper()
+
+	networkMap, err := manager.GetNetworkMap(context.Background(), peerID)
+	require.NoError(t, err)
+
+	found := false
+	for _, peer := range networkMap.Peers {
+		if peer.ID == remotePeerID {
+			found = true
+		}
+	}
+	assert.Equal(t, expected, found, "peer %s presence in the network map of %s", remotePeerID, peerID)
+}
+
+func TestDefaultAccountManager_ApprovePeer(t *testing.T) {
+	manager, acc, setupKey, approvedPeer := setupPeerApprovalTest(t)
+	enablePeerApproval(t, manager, acc.Id, &account.ExtraSettings{PeerApprovalEnabled: true})
+
+	pendingPeer := addPeerWithSetupKey(t, manager, setupKey)
+	require.True(t, pendingPeer.Status.RequiresApproval, "new peer should be pending approval")
+	assertPeerInNetworkMap(t, manager, approvedPeer.ID, pendingPeer.ID, false)
+
+	_, err := manager.ApprovePeer(context.Background(), acc.Id, userID, approvedPeer.ID)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.PreconditionFailed, sErr.Type(), "approved peer can't be approved again")
+
+	peer, err := manager.ApprovePeer(context.Background(), acc.Id, userID, pendingPeer.ID)
+	require.NoError(t, err)
+	assert.False(t, peer.Status.RequiresApproval)
+	assertPeerInNetworkMap(t, manager, approvedPeer.ID, pendingPeer.ID, true)
+	assertPeerInNetworkMap(t, manager, pendingPeer.ID, approvedPeer.ID, true)
+}
+
+func TestDefaultAccountManager_RejectPeer(t *testing.T) {
+	manager, acc, setupKey, _ := setupPeerApprovalTest(t)
+	enablePeerApproval(t, manager, acc.Id, &account.ExtraSettings{PeerApprovalEnabled: true})
+
+	pendingPeer := addPeerWithSetupKey(t, manager, setupKey)
+
+	err := manager.RejectPeer(context.Background(), acc.Id, userID, pendingPeer.ID)
+	require.NoError(t, err)
+
+	_, err = manager.Store.GetAccountByPeerID(context.Background(), pendingPeer.ID)
+	assert.Error(t, err, "rejected peer should be deleted")
+
+	err = manager.RejectPeer(context.Background(), acc.Id, userID, pendingPeer.ID)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.NotFound, sErr.Type())
+}
+
+func TestDefaultAccountManager_PeerApprovalScope(t *testing.T) {
+	manager, acc, setupKey, approvedPeer := setupPeerApprovalTest(t)
+
+	otherKey, err := manager.CreateSetupKey(context.Background(), acc.Id, "approval-key", types.SetupKeyReusable, time.Hour, nil, 999, userID, false)
+	require.NoError(t, err)
+
+	_, err = manager.UpdateAccountSettings(context.Background(), acc.Id, userID, &types.Settings{
+		PeerLoginExpiration: time.Hour,
+		Extra:               &account.ExtraSettings{PeerApprovalEnabled: true, PeerApprovalSetupKeys: []string{"unknown"}},
+	})
+	assert.Error(t, err, "unknown approval setup key should be rejected")
+
+	enablePeerApproval(t, manager, acc.Id, &account.ExtraSettings{PeerApprovalEnabled: true, PeerApprovalSetupKeys: []string{otherKey.Id}})
+
+	peer := addPeerWithSetupKey(t, manager, setupKey)
+	assert.False(t, peer.Status.RequiresApproval, "peer added with other setup key should be approved")
+
+	pendingPeer := addPeerWithSetupKey(t, manager, otherKey)
+	assert.True(t, pendingPeer.Status.RequiresApproval, "peer added with approval setup key should be pending")
+	assertPeerInNetworkMap(t, manager, approvedPeer.ID, pendingPeer.ID, false)
+
+	enablePeerApproval(t, manager, acc.Id, &account.ExtraSettings{PeerApprovalEnabled: false})
+
+	updated, err := manager.Store.GetAccount(context.Background(), acc.Id)
+	require.NoError(t, err)
+	assert.False(t, updated.GetPeer(pendingPeer.ID).Status.RequiresApproval, "disabling the approval should approve pending peers")
+	assertPeerInNetworkMap(t, manager, approvedPeer.ID, pendingPeer.ID, true)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/netbirdio/netbird/management/server/settings"
 	"github.com/netbirdio/netbird/management/server/types"
@@ -13,22 +14,55 @@ import (
 type Module string
 
 const (
-	Networks Module = "networks"
-	Peers    Module = "peers"
-	Groups   Module = "groups"
+	Networks      Module = "networks"
+	Peers         Module = "peers"
+	Groups        Module = "groups"
+	Policies      Module = "policies"
+	Routes        Module = "routes"
+	DNS           Module = "dns"
+	SetupKeys     Module = "setup_keys"
+	Users         Module = "users"
+	Events        Module = "events"
+	PostureChecks Module = "posture_checks"
+	Roles         Module = "roles"
 )
 
+// CustomRoleModules are the modules which can be granted by custom roles.
+// Roles are managed by admins only so custom roles can't grant themselves more permissions.
+var CustomRoleModules = []Module{Networks, Peers, Groups, Policies, Routes, DNS, SetupKeys, Users, Events, PostureChecks}
+
 type Operation string
 
 const (
-	Read  Operation = "read"
-	Write Operation = "write"
+	Read   Operation = "read"
+	Write  Operation = "write"
+	Delete Operation = "delete"
 )
 
 type Manager interface {
 	ValidateUserPermissions(ctx context.Context, accountID, userID string, module Module, operation Operation) (bool, error)
 }
 
+// IsCustomRoleModule checks if the module can be granted by custom roles
+func IsCustomRoleModule(module Module) bool {
+	return slices.Contains(CustomRoleModules, module)
+}
+
+// GetCustomRolePermission returns the permission of the role granting the operation on the module or nil if the role doesn't grant it.
+// The returned permission can be scoped to specific groups, which is checked by the callers knowing the object the operation is performed on.
+func GetCustomRolePermission(role *types.CustomRole, module Module, operation Operation) *types.RolePermission {
+	if role == nil || !IsCustomRoleModule(module) {
+		return nil
+	}
+
+	permission := role.GetPermission(string(module))
+	if permission == nil || !permission.Allows(string(operation)) {
+		return nil
+	}
+
+	return permission
+}
+
 type managerImpl struct {
 	userManager     users.Manager
 	settingsManager settings.Manager
@@ -62,6 +96,9 @@ func (m *managerImpl) ValidateUserPermissions(ctx context.Context, accountID, us
 	case types.UserRoleAdmin, types.UserRoleOwner:
 		return true, nil
 	case types.UserRoleUser:
+		if user.CustomRoleID != "" {
+			return m.validateCustomRolePermissions(ctx, user, module, operation)
+		}
 		return m.validateRegularUserPermissions(ctx, accountID, userID, module, operation)
 	case types.UserRoleBillingAdmin:
 		return false, nil
@@ -79,7 +116,7 @@ func (m *managerImpl) validateRegularUserPermissions(ctx context.Context, accoun
 		return false, nil
 	}
 
-	if operation == Write {
+	if operation != Read {
 		return false, nil
 	}
 
@@ -90,6 +127,21 @@ func (m *managerImpl) validateRegularUserPermissions(ctx context.Context, accoun
 	return false, nil
 }
 
+// validateCustomRolePermissions checks the module level permissions of the user custom role.
+// Regular user permissions are kept for users with a custom role.
+func (m *managerImpl) validateCustomRolePermissions(ctx context.Context, user *types.User, module Module, operation Operation) (bool, error) {
+	role, err := m.userManager.GetCustomRole(ctx, user.AccountID, user.CustomRoleID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get custom role: %w", err)
+	}
+
+	if GetCustomRolePermission(role, module, operation) != nil {
+		return true, nil
+	}
+
+	return m.validateRegularUserPermissions(ctx, user.AccountID, user.Id, module, operation)
+}
+
 func NewManagerMock() Manager {
 	return &managerMock{}
 }
@@ -0,0 +1,44 @@
+package permissions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/server/settings"
+	"github.com/netbirdio/netbird/management/server/users"
+)
+
+func TestManager_ValidateUserPermissions(t *testing.T) {
+	manager := NewManager(users.NewManagerMock(), settings.NewManagerMock())
+
+	tests := []struct {
+		name      string
+		userID    string
+		module    Module
+		operation Operation
+		expected  bool
+	}{
+		{name: "admin writes routes", userID: "adminUser", module: Routes, operation: Write, expected: true},
+		{name: "regular user reads peers", userID: "regularUser", module: Peers, operation: Read, expected: true},
+		{name: "regular user writes peers", userID: "regularUser", module: Peers, operation: Write},
+		{name: "regular user writes routes", userID: "regularUser", module: Routes, operation: Write},
+		{name: "custom role writes routes", userID: "networkUser", module: Routes, operation: Write, expected: true},
+		{name: "custom role deletes routes", userID: "networkUser", module: Routes, operation: Delete, expected: true},
+		{name: "custom role deletes dns", userID: "networkUser", module: DNS, operation: Delete},
+		{name: "custom role reads peers as regular user", userID: "networkUser", module: Peers, operation: Read, expected: true},
+		{name: "custom role writes policies", userID: "networkUser", module: Policies, operation: Write},
+		{name: "custom role reads roles", userID: "networkUser", module: Roles, operation: Read},
+		{name: "billing admin reads peers", userID: "billingUser", module: Peers, operation: Read},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			allowed, err := manager.ValidateUserPermissions(context.Background(), "", tc.userID, tc.module, tc.operation)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, allowed)
+		})
+	}
+}
@@ -3,10 +3,13 @@ package server
 import (
 	"context"
 	_ "embed"
+	"fmt"
 
 	"github.com/rs/xid"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/netbirdio/netbird/management/proto"
+	"github.com/netbirdio/netbird/management/server/permissions"
 	"github.com/netbirdio/netbird/management/server/store"
 	"github.com/netbirdio/netbird/management/server/types"
 
@@ -26,11 +29,24 @@ func (am *DefaultAccountManager) GetPolicy(ctx context.Context, accountID, polic
 		return nil, status.NewUserNotPartOfAccountError()
 	}
 
+	var permission *types.RolePermission
 	if user.IsRegularUser() {
-		return nil, status.NewAdminPermissionError()
+		permission, err = am.checkCustomRolePermission(ctx, user, permissions.Policies, permissions.Read)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return am.Store.GetPolicyByID(ctx, store.LockingStrengthShare, accountID, policyID)
+	policy, err := am.Store.GetPolicyByID(ctx, store.LockingStrengthShare, accountID, policyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !permission.CoversAllGroups(policy.RuleGroups()) {
+		return nil, status.NewPolicyNotFoundError(policyID)
+	}
+
+	return policy, nil
 }
 
 // SavePolicy in the store
@@ -47,8 +63,12 @@ func (am *DefaultAccountManager) SavePolicy(ctx context.Context, accountID, user
 		return nil, status.NewUserNotPartOfAccountError()
 	}
 
+	var permission *types.RolePermission
 	if user.IsRegularUser() {
-		return nil, status.NewAdminPermissionError()
+		permission, err = am.checkCustomRolePermission(ctx, user, permissions.Policies, permissions.Write)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	var isUpdate = policy.ID != ""
@@ -56,6 +76,10 @@ func (am *DefaultAccountManager) SavePolicy(ctx context.Context, accountID, user
 	var action = activity.PolicyAdded
 
 	err = am.Store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		if err = validatePolicyRoleScope(ctx, transaction, permission, accountID, policy); err != nil {
+			return err
+		}
+
 		if err = validatePolicy(ctx, transaction, accountID, policy); err != nil {
 			return err
 		}
@@ -87,6 +111,8 @@ func (am *DefaultAccountManager) SavePolicy(ctx context.Context, accountID, user
 		am.UpdateAccountPeers(ctx, accountID)
 	}
 
+	am.reschedulePolicyScheduleUpdate(ctx, accountID)
+
 	return policy, nil
 }
 
@@ -104,8 +130,12 @@ func (am *DefaultAccountManager) DeletePolicy(ctx context.Context, accountID, po
 		return status.NewUserNotPartOfAccountError()
 	}
 
+	var permission *types.RolePermission
 	if user.IsRegularUser() {
-		return status.NewAdminPermissionError()
+		permission, err = am.checkCustomRolePermission(ctx, user, permissions.Policies, permissions.Delete)
+		if err != nil {
+			return err
+		}
 	}
 
 	var policy *types.Policy
@@ -117,6 +147,10 @@ func (am *DefaultAccountManager) DeletePolicy(ctx context.Context, accountID, po
 			return err
 		}
 
+		if !permission.CoversAllGroups(policy.RuleGroups()) {
+			return status.NewPermissionDeniedError()
+		}
+
 		updateAccountPeers, err = arePolicyChangesAffectPeers(ctx, transaction, accountID, policy, false)
 		if err != nil {
 			return err
@@ -138,6 +172,8 @@ func (am *DefaultAccountManager) DeletePolicy(ctx context.Context, accountID, po
 		am.UpdateAccountPeers(ctx, accountID)
 	}
 
+	am.reschedulePolicyScheduleUpdate(ctx, accountID)
+
 	return nil
 }
 
@@ -152,11 +188,56 @@ func (am *DefaultAccountManager) ListPolicies(ctx context.Context, accountID, us
 		return nil, status.NewUserNotPartOfAccountError()
 	}
 
+	var permission *types.RolePermission
 	if user.IsRegularUser() {
-		return nil, status.NewAdminPermissionError()
+		permission, err = am.checkCustomRolePermission(ctx, user, permissions.Policies, permissions.Read)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	policies, err := am.Store.GetAccountPolicies(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterByRoleScope(permission, policies, (*types.Policy).RuleGroups), nil
+}
+
+// validatePolicyRoleScope checks that the groups of the policy and of the policy being updated are within the custom role scope
+func validatePolicyRoleScope(ctx context.Context, transaction store.Store, permission *types.RolePermission, accountID string, policy *types.Policy) error {
+	if !permission.IsScoped() {
+		return nil
+	}
+
+	if !permission.CoversAllGroups(policy.RuleGroups()) {
+		return status.Errorf(status.PermissionDenied, "policy groups are outside of the user role scope")
+	}
+
+	if policy.ID == "" {
+		return nil
+	}
+
+	existingPolicy, err := transaction.GetPolicyByID(ctx, store.LockingStrengthShare, accountID, policy.ID)
+	if err != nil {
+		return err
 	}
 
-	return am.Store.GetAccountPolicies(ctx, store.LockingStrengthShare, accountID)
+	if !permission.CoversAllGroups(existingPolicy.RuleGroups()) {
+		return status.NewPermissionDeniedError()
+	}
+
+	return nil
+}
+
+// reschedulePolicyScheduleUpdate updates the scheduled network map update of the account after its policies changed.
+func (am *DefaultAccountManager) reschedulePolicyScheduleUpdate(ctx context.Context, accountID string) {
+	account, err := am.Store.GetAccount(ctx, accountID)
+	if err != nil {
+		log.WithContext(ctx).Errorf("failed to get account %s to schedule policy updates: %v", accountID, err)
+		return
+	}
+	am.checkAndSchedulePolicyScheduleUpdate(ctx, account)
 }
 
 // arePolicyChangesAffectPeers checks if changes to a policy will affect any associated peers.
@@ -201,12 +282,18 @@ func validatePolicy(ctx context.Context, transaction store.Store, accountID stri
 		return err
 	}
 
-	postureChecks, err := transaction.GetPostureChecksByIDs(ctx, store.LockingStrengthShare, accountID, policy.SourcePostureChecks)
+	postureChecks, err := transaction.GetPostureChecksByIDs(ctx, store.LockingStrengthShare, accountID, policy.PostureChecks())
 	if err != nil {
 		return err
 	}
 
 	for i, rule := range policy.Rules {
+		if rule.Schedule != nil {
+			if err = rule.Schedule.Validate(); err != nil {
+				return status.Errorf(status.InvalidArgument, "invalid schedule for rule %s: %v", rule.Name, err)
+			}
+		}
+
 		ruleCopy := rule.Copy()
 		if ruleCopy.ID == "" {
 			ruleCopy.ID = policy.ID // TODO: when policy can contain multiple rules, need refactor
@@ -222,6 +309,10 @@ func validatePolicy(ctx context.Context, transaction store.Store, accountID stri
 		policy.SourcePostureChecks = getValidPostureCheckIDs(postureChecks, policy.SourcePostureChecks)
 	}
 
+	if policy.DestinationPostureChecks != nil {
+		policy.DestinationPostureChecks = getValidPostureCheckIDs(postureChecks, policy.DestinationPostureChecks)
+	}
+
 	return nil
 }
 
@@ -256,12 +347,27 @@ func toProtocolFirewallRules(rules []*types.FirewallRule) []*proto.FirewallRule
 		rule := rules[i]
 
 		result[i] = &proto.FirewallRule{
+			PolicyID:  rule.PolicyID,
 			PeerIP:    rule.PeerIP,
 			Direction: getProtoDirection(rule.Direction),
 			Action:    getProtoAction(rule.Action),
 			Protocol:  getProtoProtocol(rule.Protocol),
 			Port:      rule.Port,
 		}
+
+		if rule.PortRange.Start != 0 && rule.PortRange.End != 0 {
+			// the range form of Port makes clients without port range support skip the rule
+			// instead of treating it as a rule without port restrictions
+			result[i].Port = fmt.Sprintf("%d-%d", rule.PortRange.Start, rule.PortRange.End)
+			result[i].PortInfo = &proto.PortInfo{
+				PortSelection: &proto.PortInfo_Range_{
+					Range: &proto.PortInfo_Range{
+						Start: uint32(rule.PortRange.Start),
+						End:   uint32(rule.PortRange.End),
+					},
+				},
+			}
+		}
 	}
 	return result
 }
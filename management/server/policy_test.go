@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/exp/slices"
 
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
@@ -815,6 +816,275 @@ func TestAccount_getPeersByPolicyPostureChecks(t *testing.T) {
 	})
 }
 
+func TestAccount_getPeersByPolicyDestinationPostureChecks(t *testing.T) {
+	account := &types.Account{
+		Peers: map[string]*nbpeer.Peer{
+			"client": {
+				ID:     "client",
+				IP:     net.ParseIP("100.65.14.88"),
+				Status: &nbpeer.PeerStatus{},
+				Meta:   nbpeer.PeerSystemMeta{GoOS: "linux", WtVersion: "0.23.0"},
+			},
+			"patchedServer": {
+				ID:     "patchedServer",
+				IP:     net.ParseIP("100.65.80.39"),
+				Status: &nbpeer.PeerStatus{},
+				Meta:   nbpeer.PeerSystemMeta{GoOS: "linux", WtVersion: "0.25.9"},
+			},
+			"unpatchedServer": {
+				ID:     "unpatchedServer",
+				IP:     net.ParseIP("100.65.254.139"),
+				Status: &nbpeer.PeerStatus{},
+				Meta:   nbpeer.PeerSystemMeta{GoOS: "linux", WtVersion: "0.24.0"},
+			},
+		},
+		Groups: map[string]*types.Group{
+			"GroupClients": {ID: "GroupClients", Name: "clients", Peers: []string{"client"}},
+			"GroupServers": {ID: "GroupServers", Name: "servers", Peers: []string{"patchedServer", "unpatchedServer"}},
+		},
+		PostureChecks: []*posture.Checks{
+			{
+				ID:   "PostureChecksPatched",
+				Name: "Patched",
+				Checks: posture.ChecksDefinition{
+					NBVersionCheck: &posture.NBVersionCheck{MinVersion: "0.25"},
+				},
+			},
+		},
+		Policies: []*types.Policy{
+			{
+				ID:      "PolicyServers",
+				Enabled: true,
+				Rules: []*types.PolicyRule{
+					{
+						ID:           "RuleServers",
+						Enabled:      true,
+						Action:       types.PolicyTrafficActionAccept,
+						Sources:      []string{"GroupClients"},
+						Destinations: []string{"GroupServers"},
+						Protocol:     types.PolicyRuleProtocolTCP,
+						Ports:        []string{"22"},
+					},
+				},
+				DestinationPostureChecks: []string{"PostureChecksPatched"},
+			},
+		},
+	}
+
+	approvedPeers := make(map[string]struct{})
+	for p := range account.Peers {
+		approvedPeers[p] = struct{}{}
+	}
+
+	// the client doesn't fulfill the posture check, but it is applied to the destination peers only
+	peers, firewallRules := account.GetPeerConnectionResources(context.Background(), "client", approvedPeers)
+	assert.Equal(t, []*nbpeer.Peer{account.Peers["patchedServer"]}, peers)
+	assert.Equal(t, []*types.FirewallRule{
+		{
+			PeerIP:    "100.65.80.39",
+			Direction: types.FirewallRuleDirectionOUT,
+			Action:    "accept",
+			Protocol:  "tcp",
+			Port:      "22",
+		},
+	}, firewallRules)
+
+	peers, firewallRules = account.GetPeerConnectionResources(context.Background(), "patchedServer", approvedPeers)
+	assert.Equal(t, []*nbpeer.Peer{account.Peers["client"]}, peers)
+	assert.Equal(t, []*types.FirewallRule{
+		{
+			PeerIP:    "100.65.14.88",
+			Direction: types.FirewallRuleDirectionIN,
+			Action:    "accept",
+			Protocol:  "tcp",
+			Port:      "22",
+		},
+	}, firewallRules)
+
+	// the unpatched server drops out of the mesh and doesn't accept connections
+	peers, firewallRules = account.GetPeerConnectionResources(context.Background(), "unpatchedServer", approvedPeers)
+	assert.Empty(t, peers)
+	assert.Empty(t, firewallRules)
+
+	// the destination peers collect the posture checks data, the source peers don't
+	am := &DefaultAccountManager{}
+	checks, err := am.getPeerPostureChecks(account, "unpatchedServer")
+	require.NoError(t, err)
+	assert.Equal(t, []*posture.Checks{account.PostureChecks[0]}, checks)
+
+	checks, err = am.getPeerPostureChecks(account, "client")
+	require.NoError(t, err)
+	assert.Empty(t, checks)
+}
+
+func TestAccount_getPeersByPolicyPortRanges(t *testing.T) {
+	account := &types.Account{
+		Peers: map[string]*nbpeer.Peer{
+			"client": {
+				ID:     "client",
+				IP:     net.ParseIP("100.65.14.88"),
+				Status: &nbpeer.PeerStatus{},
+			},
+			"server": {
+				ID:     "server",
+				IP:     net.ParseIP("100.65.80.39"),
+				Status: &nbpeer.PeerStatus{},
+			},
+		},
+		Groups: map[string]*types.Group{
+			"GroupClients": {ID: "GroupClients", Name: "clients", Peers: []string{"client"}},
+			"GroupServers": {ID: "GroupServers", Name: "servers", Peers: []string{"server"}},
+		},
+		Policies: []*types.Policy{
+			{
+				ID:      "PolicyEphemeral",
+				Enabled: true,
+				Rules: []*types.PolicyRule{
+					{
+						ID:           "RuleEphemeral",
+						PolicyID:     "PolicyEphemeral",
+						Enabled:      true,
+						Action:       types.PolicyTrafficActionAccept,
+						Sources:      []string{"GroupClients"},
+						Destinations: []string{"GroupServers"},
+						Protocol:     types.PolicyRuleProtocolUDP,
+						PortRanges:   []types.RulePortRange{{Start: 49152, End: 65535}},
+					},
+				},
+			},
+		},
+	}
+
+	approvedPeers := map[string]struct{}{"client": {}, "server": {}}
+
+	_, firewallRules := account.GetPeerConnectionResources(context.Background(), "server", approvedPeers)
+	expectedRule := &types.FirewallRule{
+		PolicyID:  "PolicyEphemeral",
+		PeerIP:    "100.65.14.88",
+		Direction: types.FirewallRuleDirectionIN,
+		Action:    "accept",
+		Protocol:  "udp",
+		PortRange: types.RulePortRange{Start: 49152, End: 65535},
+	}
+	assert.Equal(t, []*types.FirewallRule{expectedRule}, firewallRules, "the port range should not be expanded")
+
+	protoRules := toProtocolFirewallRules(firewallRules)
+	require.Len(t, protoRules, 1)
+	assert.Equal(t, "49152-65535", protoRules[0].Port)
+	assert.Equal(t, "PolicyEphemeral", protoRules[0].PolicyID)
+	assert.Equal(t, uint32(49152), protoRules[0].GetPortInfo().GetRange().GetStart())
+	assert.Equal(t, uint32(65535), protoRules[0].GetPortInfo().GetRange().GetEnd())
+}
+
+func TestAccount_GetPeerSSHLocalUsers(t *testing.T) {
+	account := &types.Account{
+		Peers: map[string]*nbpeer.Peer{
+			"admin": {
+				ID:     "admin",
+				IP:     net.ParseIP("100.65.14.88"),
+				Status: &nbpeer.PeerStatus{},
+			},
+			"dev": {
+				ID:     "dev",
+				IP:     net.ParseIP("100.65.14.89"),
+				Status: &nbpeer.PeerStatus{},
+			},
+			"server": {
+				ID:     "server",
+				IP:     net.ParseIP("100.65.80.39"),
+				Status: &nbpeer.PeerStatus{},
+			},
+		},
+		Groups: map[string]*types.Group{
+			"GroupAdmins":  {ID: "GroupAdmins", Name: "admins", Peers: []string{"admin"}},
+			"GroupDevs":    {ID: "GroupDevs", Name: "devs", Peers: []string{"dev", "admin"}},
+			"GroupServers": {ID: "GroupServers", Name: "servers", Peers: []string{"server"}},
+		},
+		Policies: []*types.Policy{
+			{
+				ID:      "PolicyDevs",
+				Enabled: true,
+				Rules: []*types.PolicyRule{
+					{
+						ID:            "RuleDevs",
+						PolicyID:      "PolicyDevs",
+						Enabled:       true,
+						Action:        types.PolicyTrafficActionAccept,
+						Sources:       []string{"GroupDevs"},
+						Destinations:  []string{"GroupServers"},
+						Protocol:      types.PolicyRuleProtocolTCP,
+						Ports:         []string{"44338"},
+						SSHLocalUsers: []string{"deploy", "app"},
+					},
+				},
+			},
+			{
+				ID:      "PolicyAdmins",
+				Enabled: true,
+				Rules: []*types.PolicyRule{
+					{
+						ID:            "RuleAdmins",
+						PolicyID:      "PolicyAdmins",
+						Enabled:       true,
+						Action:        types.PolicyTrafficActionAccept,
+						Sources:       []string{"GroupAdmins"},
+						Destinations:  []string{"GroupServers"},
+						Protocol:      types.PolicyRuleProtocolTCP,
+						Ports:         []string{"44338"},
+						SSHLocalUsers: []string{"root"},
+					},
+				},
+			},
+		},
+	}
+
+	approvedPeers := map[string]struct{}{"admin": {}, "dev": {}, "server": {}}
+
+	localUsers := account.GetPeerSSHLocalUsers(context.Background(), "server", approvedPeers)
+	assert.Equal(t, map[string][]string{
+		"admin": {"app", "deploy", "root"},
+		"dev":   {"app", "deploy"},
+	}, localUsers)
+
+	localUsers = account.GetPeerSSHLocalUsers(context.Background(), "admin", approvedPeers)
+	assert.Empty(t, localUsers, "the rules are not bidirectional, the server can not log in to the admin peer")
+
+	// a rule without local users doesn't lift the restriction of the other rules
+	account.Policies[1].Rules[0].SSHLocalUsers = nil
+	localUsers = account.GetPeerSSHLocalUsers(context.Background(), "server", approvedPeers)
+	assert.Equal(t, map[string][]string{
+		"admin": {"app", "deploy"},
+		"dev":   {"app", "deploy"},
+	}, localUsers)
+
+	// the default policy allowing all traffic keeps the mapped peers restricted
+	account.Groups["GroupAll"] = &types.Group{ID: "GroupAll", Name: "All", Peers: []string{"admin", "dev", "server"}}
+	account.Policies = append(account.Policies, &types.Policy{
+		ID:      "PolicyDefault",
+		Enabled: true,
+		Rules: []*types.PolicyRule{
+			{
+				ID:            "RuleDefault",
+				PolicyID:      "PolicyDefault",
+				Enabled:       true,
+				Action:        types.PolicyTrafficActionAccept,
+				Sources:       []string{"GroupAll"},
+				Destinations:  []string{"GroupAll"},
+				Bidirectional: true,
+				Protocol:      types.PolicyRuleProtocolALL,
+			},
+		},
+	})
+	localUsers = account.GetPeerSSHLocalUsers(context.Background(), "server", approvedPeers)
+	assert.Equal(t, map[string][]string{
+		"admin": {"app", "deploy"},
+		"dev":   {"app", "deploy"},
+	}, localUsers)
+
+	localUsers = account.GetPeerSSHLocalUsers(context.Background(), "admin", approvedPeers)
+	assert.Empty(t, localUsers, "peers without mapped local users may log in as any account")
+}
+
 func sortFunc() func(a *types.FirewallRule, b *types.FirewallRule) int {
 	return func(a, b *types.FirewallRule) int {
 		// Concatenate PeerIP and Direction as string for comparison
@@ -3,6 +3,7 @@ package posture
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/netip"
 	"regexp"
 
@@ -18,6 +19,8 @@ const (
 	GeoLocationCheckName      = "GeoLocationCheck"
 	PeerNetworkRangeCheckName = "PeerNetworkRangeCheck"
 	ProcessCheckName          = "ProcessCheck"
+	ServiceCheckName          = "ServiceCheck"
+	HostSecurityCheckName     = "HostSecurityCheck"
 
 	CheckActionAllow string = "allow"
 	CheckActionDeny  string = "deny"
@@ -58,6 +61,8 @@ type ChecksDefinition struct {
 	GeoLocationCheck      *GeoLocationCheck      `json:",omitempty"`
 	PeerNetworkRangeCheck *PeerNetworkRangeCheck `json:",omitempty"`
 	ProcessCheck          *ProcessCheck          `json:",omitempty"`
+	ServiceCheck          *ServiceCheck          `json:",omitempty"`
+	HostSecurityCheck     *HostSecurityCheck     `json:",omitempty"`
 }
 
 // Copy returns a copy of a checks definition.
@@ -110,6 +115,22 @@ func (cd ChecksDefinition) Copy() ChecksDefinition {
 		}
 		copy(cdCopy.ProcessCheck.Processes, processCheck.Processes)
 	}
+	if cd.ServiceCheck != nil {
+		serviceCheck := cd.ServiceCheck
+		cdCopy.ServiceCheck = &ServiceCheck{
+			Action:   serviceCheck.Action,
+			Services: make([]Service, len(serviceCheck.Services)),
+			Ports:    make([]Port, len(serviceCheck.Ports)),
+		}
+		copy(cdCopy.ServiceCheck.Services, serviceCheck.Services)
+		copy(cdCopy.ServiceCheck.Ports, serviceCheck.Ports)
+	}
+	if cd.HostSecurityCheck != nil {
+		cdCopy.HostSecurityCheck = &HostSecurityCheck{
+			DiskEncryption: cd.HostSecurityCheck.DiskEncryption,
+			Firewall:       cd.HostSecurityCheck.Firewall,
+		}
+	}
 	return cdCopy
 }
 
@@ -153,6 +174,12 @@ func (pc *Checks) GetChecks() []Check {
 	if pc.Checks.ProcessCheck != nil {
 		checks = append(checks, pc.Checks.ProcessCheck)
 	}
+	if pc.Checks.ServiceCheck != nil {
+		checks = append(checks, pc.Checks.ServiceCheck)
+	}
+	if pc.Checks.HostSecurityCheck != nil {
+		checks = append(checks, pc.Checks.HostSecurityCheck)
+	}
 	return checks
 }
 
@@ -208,6 +235,20 @@ func buildPostureCheck(postureChecksID string, name string, description string,
 		postureChecks.Checks.ProcessCheck = toProcessCheck(processCheck)
 	}
 
+	if serviceCheck := checks.ServiceCheck; serviceCheck != nil {
+		postureChecks.Checks.ServiceCheck, err = toServiceCheck(serviceCheck)
+		if err != nil {
+			return nil, status.Errorf(status.InvalidArgument, "invalid service check: %v", err)
+		}
+	}
+
+	if hostSecurityCheck := checks.HostSecurityCheck; hostSecurityCheck != nil {
+		postureChecks.Checks.HostSecurityCheck = &HostSecurityCheck{
+			DiskEncryption: hostSecurityCheck.DiskEncryption,
+			Firewall:       hostSecurityCheck.Firewall,
+		}
+	}
+
 	return &postureChecks, nil
 }
 
@@ -242,6 +283,17 @@ func (pc *Checks) ToAPIResponse() *api.PostureCheck {
 		checks.ProcessCheck = toProcessCheckResponse(pc.Checks.ProcessCheck)
 	}
 
+	if pc.Checks.ServiceCheck != nil {
+		checks.ServiceCheck = toServiceCheckResponse(pc.Checks.ServiceCheck)
+	}
+
+	if pc.Checks.HostSecurityCheck != nil {
+		checks.HostSecurityCheck = &api.HostSecurityCheck{
+			DiskEncryption: pc.Checks.HostSecurityCheck.DiskEncryption,
+			Firewall:       pc.Checks.HostSecurityCheck.Firewall,
+		}
+	}
+
 	return &api.PostureCheck{
 		Id:          pc.ID,
 		Name:        pc.Name,
@@ -386,3 +438,66 @@ func toProcessCheck(check *api.ProcessCheck) *ProcessCheck {
 		Processes: processes,
 	}
 }
+
+func toServiceCheckResponse(check *ServiceCheck) *api.ServiceCheck {
+	services := make([]api.Service, 0, len(check.Services))
+	for i := range check.Services {
+		services = append(services, api.Service{
+			LinuxName:   &check.Services[i].LinuxName,
+			MacName:     &check.Services[i].MacName,
+			WindowsName: &check.Services[i].WindowsName,
+		})
+	}
+
+	ports := make([]api.ListeningPort, 0, len(check.Ports))
+	for _, port := range check.Ports {
+		ports = append(ports, api.ListeningPort{
+			Port:     int(port.Port),
+			Protocol: api.ListeningPortProtocol(port.Protocol),
+		})
+	}
+
+	return &api.ServiceCheck{
+		Action:   api.ServiceCheckAction(check.Action),
+		Services: &services,
+		Ports:    &ports,
+	}
+}
+
+func toServiceCheck(check *api.ServiceCheck) (*ServiceCheck, error) {
+	serviceCheck := &ServiceCheck{
+		Action:   string(check.Action),
+		Services: make([]Service, 0),
+		Ports:    make([]Port, 0),
+	}
+
+	if check.Services != nil {
+		for _, service := range *check.Services {
+			var s Service
+			if service.LinuxName != nil {
+				s.LinuxName = *service.LinuxName
+			}
+			if service.MacName != nil {
+				s.MacName = *service.MacName
+			}
+			if service.WindowsName != nil {
+				s.WindowsName = *service.WindowsName
+			}
+			serviceCheck.Services = append(serviceCheck.Services, s)
+		}
+	}
+
+	if check.Ports != nil {
+		for _, port := range *check.Ports {
+			if port.Port < 1 || port.Port > 65535 {
+				return nil, fmt.Errorf("port %d is out of range", port.Port)
+			}
+			serviceCheck.Ports = append(serviceCheck.Ports, Port{
+				Port:     uint16(port.Port),
+				Protocol: string(port.Protocol),
+			})
+		}
+	}
+
+	return serviceCheck, nil
+}
@@ -283,6 +283,15 @@ func TestChecks_Copy(t *testing.T) {
 					},
 				},
 			},
+			ServiceCheck: &ServiceCheck{
+				Action:   CheckActionDeny,
+				Services: []Service{{LinuxName: "xrdp.service", WindowsName: "TermService"}},
+				Ports:    []Port{{Port: 3389, Protocol: "tcp"}},
+			},
+			HostSecurityCheck: &HostSecurityCheck{
+				DiskEncryption: true,
+				Firewall:       true,
+			},
 		},
 	}
 	checkCopy := check.Copy()
@@ -0,0 +1,39 @@
+package posture
+
+import (
+	"context"
+	"fmt"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+)
+
+// HostSecurityCheck requires the peer's disk to be encrypted and a host firewall to be active.
+// The disk encryption is reported for the root filesystem of Linux peers only,
+// peers of other operating systems don't pass a check requiring it.
+type HostSecurityCheck struct {
+	DiskEncryption bool
+	Firewall       bool
+}
+
+var _ Check = (*HostSecurityCheck)(nil)
+
+func (h *HostSecurityCheck) Check(_ context.Context, peer nbpeer.Peer) (bool, error) {
+	if h.DiskEncryption && !peer.Meta.DiskEncrypted {
+		return false, nil
+	}
+	if h.Firewall && !peer.Meta.FirewallEnabled {
+		return false, nil
+	}
+	return true, nil
+}
+
+func (h *HostSecurityCheck) Name() string {
+	return HostSecurityCheckName
+}
+
+func (h *HostSecurityCheck) Validate() error {
+	if !h.DiskEncryption && !h.Firewall {
+		return fmt.Errorf("%s should require disk encryption or firewall", h.Name())
+	}
+	return nil
+}
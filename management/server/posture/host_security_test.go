@@ -0,0 +1,64 @@
+package posture
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/management/server/peer"
+)
+
+func TestHostSecurityCheck_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   peer.Peer
+		check   HostSecurityCheck
+		isValid bool
+	}{
+		{
+			name:    "encrypted disk and active firewall",
+			input:   peer.Peer{Meta: peer.PeerSystemMeta{DiskEncrypted: true, FirewallEnabled: true}},
+			check:   HostSecurityCheck{DiskEncryption: true, Firewall: true},
+			isValid: true,
+		},
+		{
+			name:    "unencrypted disk",
+			input:   peer.Peer{Meta: peer.PeerSystemMeta{FirewallEnabled: true}},
+			check:   HostSecurityCheck{DiskEncryption: true, Firewall: true},
+			isValid: false,
+		},
+		{
+			name:    "inactive firewall",
+			input:   peer.Peer{Meta: peer.PeerSystemMeta{DiskEncrypted: true}},
+			check:   HostSecurityCheck{DiskEncryption: true, Firewall: true},
+			isValid: false,
+		},
+		{
+			name:    "unencrypted disk not required",
+			input:   peer.Peer{Meta: peer.PeerSystemMeta{FirewallEnabled: true}},
+			check:   HostSecurityCheck{Firewall: true},
+			isValid: true,
+		},
+		{
+			name:    "peer without reported state",
+			input:   peer.Peer{},
+			check:   HostSecurityCheck{DiskEncryption: true},
+			isValid: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isValid, err := tt.check.Check(context.Background(), tt.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.isValid, isValid)
+		})
+	}
+}
+
+func TestHostSecurityCheck_Validate(t *testing.T) {
+	assert.NoError(t, (&HostSecurityCheck{DiskEncryption: true}).Validate())
+	assert.NoError(t, (&HostSecurityCheck{Firewall: true}).Validate())
+	assert.Error(t, (&HostSecurityCheck{}).Validate())
+}
@@ -0,0 +1,145 @@
+package posture
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/status"
+)
+
+const (
+	protocolTCP = "tcp"
+	protocolUDP = "udp"
+)
+
+// Service is a system service identified by its name in each operating system
+type Service struct {
+	LinuxName   string
+	MacName     string
+	WindowsName string
+}
+
+// Port is a transport port a peer can listen on
+type Port struct {
+	Port     uint16
+	Protocol string
+}
+
+// ServiceCheck allows or denies access based on the running services and listening ports of a peer.
+// With the allow action all services must be running and all ports listening,
+// with the deny action none of the services may be running and none of the ports listening.
+type ServiceCheck struct {
+	Action   string
+	Services []Service
+	Ports    []Port
+}
+
+var _ Check = (*ServiceCheck)(nil)
+
+func (s *ServiceCheck) Check(_ context.Context, peer nbpeer.Peer) (bool, error) {
+	var nameSelector func(Service) string
+	switch peer.Meta.GoOS {
+	case "linux":
+		nameSelector = func(service Service) string { return service.LinuxName }
+	case "darwin":
+		nameSelector = func(service Service) string { return service.MacName }
+	case "windows":
+		nameSelector = func(service Service) string { return service.WindowsName }
+	default:
+		return false, fmt.Errorf("unsupported peer's operating system: %s", peer.Meta.GoOS)
+	}
+
+	// the services and ports missing from the peer meta are unknown, e.g. reported by an old client, and fail the check
+	services := extractPeerServices(peer.Meta.Services)
+	ports := extractPeerPorts(peer.Meta.Ports)
+
+	switch s.Action {
+	case CheckActionAllow:
+		for _, service := range s.Services {
+			name := nameSelector(service)
+			if name == "" || !services[name] {
+				return false, nil
+			}
+		}
+		for _, port := range s.Ports {
+			if !ports[port] {
+				return false, nil
+			}
+		}
+		return true, nil
+	case CheckActionDeny:
+		for _, service := range s.Services {
+			name := nameSelector(service)
+			if name == "" {
+				continue
+			}
+			if running, reported := services[name]; !reported || running {
+				return false, nil
+			}
+		}
+		for _, port := range s.Ports {
+			if listening, reported := ports[port]; !reported || listening {
+				return false, nil
+			}
+		}
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid service check action: %s", s.Action)
+	}
+}
+
+func (s *ServiceCheck) Name() string {
+	return ServiceCheckName
+}
+
+func (s *ServiceCheck) Validate() error {
+	if s.Action == "" {
+		return status.Errorf(status.InvalidArgument, "action for service check shouldn't be empty")
+	}
+
+	allowedActions := []string{CheckActionAllow, CheckActionDeny}
+	if !slices.Contains(allowedActions, s.Action) {
+		return fmt.Errorf("%s action is not valid", s.Name())
+	}
+
+	if len(s.Services) == 0 && len(s.Ports) == 0 {
+		return fmt.Errorf("%s services and ports shouldn't be both empty", s.Name())
+	}
+
+	for _, service := range s.Services {
+		if service.LinuxName == "" && service.MacName == "" && service.WindowsName == "" {
+			return fmt.Errorf("%s service name shouldn't be empty", s.Name())
+		}
+	}
+
+	for _, port := range s.Ports {
+		if port.Port == 0 {
+			return fmt.Errorf("%s port shouldn't be zero", s.Name())
+		}
+		if port.Protocol != protocolTCP && port.Protocol != protocolUDP {
+			return fmt.Errorf("%s port protocol %s is not valid", s.Name(), port.Protocol)
+		}
+	}
+
+	return nil
+}
+
+// extractPeerServices maps the names of the services reported in the peer meta to their running state.
+func extractPeerServices(services []nbpeer.Service) map[string]bool {
+	result := make(map[string]bool, len(services))
+	for _, service := range services {
+		result[service.Name] = service.Running
+	}
+	return result
+}
+
+// extractPeerPorts maps the ports reported in the peer meta to their listening state.
+func extractPeerPorts(ports []nbpeer.Port) map[Port]bool {
+	result := make(map[Port]bool, len(ports))
+	for _, port := range ports {
+		result[Port{Port: port.Port, Protocol: port.Protocol}] = port.Listening
+	}
+	return result
+}
@@ -0,0 +1,242 @@
+package posture
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netbirdio/netbird/management/server/peer"
+)
+
+func TestServiceCheck_Check(t *testing.T) {
+	linuxPeer := peer.Peer{
+		Meta: peer.PeerSystemMeta{
+			GoOS: "linux",
+			Services: []peer.Service{
+				{Name: "falcon-sensor.service", Running: true},
+				{Name: "xrdp.service", Running: false},
+			},
+			Ports: []peer.Port{
+				{Port: 22, Protocol: "tcp", Listening: true},
+				{Port: 3389, Protocol: "tcp", Listening: false},
+				{Port: 3389, Protocol: "udp", Listening: false},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		input   peer.Peer
+		check   ServiceCheck
+		wantErr bool
+		isValid bool
+	}{
+		{
+			name:  "allow with running service",
+			input: linuxPeer,
+			check: ServiceCheck{
+				Action:   CheckActionAllow,
+				Services: []Service{{LinuxName: "falcon-sensor.service", WindowsName: "CSFalconService"}},
+			},
+			isValid: true,
+		},
+		{
+			name:  "allow with stopped service",
+			input: linuxPeer,
+			check: ServiceCheck{
+				Action:   CheckActionAllow,
+				Services: []Service{{LinuxName: "falcon-sensor.service"}, {LinuxName: "xrdp.service"}},
+			},
+			isValid: false,
+		},
+		{
+			name:  "allow without service name for the peer's operating system",
+			input: linuxPeer,
+			check: ServiceCheck{
+				Action:   CheckActionAllow,
+				Services: []Service{{WindowsName: "CSFalconService"}},
+			},
+			isValid: false,
+		},
+		{
+			name:  "allow with listening port",
+			input: linuxPeer,
+			check: ServiceCheck{
+				Action: CheckActionAllow,
+				Ports:  []Port{{Port: 22, Protocol: "tcp"}},
+			},
+			isValid: true,
+		},
+		{
+			name:  "allow with port listening on another protocol",
+			input: linuxPeer,
+			check: ServiceCheck{
+				Action: CheckActionAllow,
+				Ports:  []Port{{Port: 22, Protocol: "udp"}},
+			},
+			isValid: false,
+		},
+		{
+			name:  "deny with listening port",
+			input: linuxPeer,
+			check: ServiceCheck{
+				Action: CheckActionDeny,
+				Ports:  []Port{{Port: 22, Protocol: "tcp"}},
+			},
+			isValid: false,
+		},
+		{
+			name:  "deny without listening ports and running services",
+			input: linuxPeer,
+			check: ServiceCheck{
+				Action:   CheckActionDeny,
+				Services: []Service{{LinuxName: "xrdp.service", WindowsName: "TermService"}},
+				Ports:    []Port{{Port: 3389, Protocol: "tcp"}, {Port: 3389, Protocol: "udp"}},
+			},
+			isValid: true,
+		},
+		{
+			name:  "deny with port not reported by the peer",
+			input: linuxPeer,
+			check: ServiceCheck{
+				Action: CheckActionDeny,
+				Ports:  []Port{{Port: 5900, Protocol: "tcp"}},
+			},
+			isValid: false,
+		},
+		{
+			name:  "deny with empty peer meta",
+			input: peer.Peer{Meta: peer.PeerSystemMeta{GoOS: "linux"}},
+			check: ServiceCheck{
+				Action:   CheckActionDeny,
+				Services: []Service{{LinuxName: "xrdp.service"}},
+				Ports:    []Port{{Port: 3389, Protocol: "tcp"}},
+			},
+			isValid: false,
+		},
+		{
+			name:  "deny with service not reported by the peer",
+			input: linuxPeer,
+			check: ServiceCheck{
+				Action:   CheckActionDeny,
+				Services: []Service{{LinuxName: "vncserver.service"}},
+			},
+			isValid: false,
+		},
+		{
+			name:  "deny with running service",
+			input: linuxPeer,
+			check: ServiceCheck{
+				Action:   CheckActionDeny,
+				Services: []Service{{LinuxName: "falcon-sensor.service"}},
+			},
+			isValid: false,
+		},
+		{
+			name: "windows with running service",
+			input: peer.Peer{
+				Meta: peer.PeerSystemMeta{
+					GoOS:     "windows",
+					Services: []peer.Service{{Name: "CSFalconService", Running: true}},
+				},
+			},
+			check: ServiceCheck{
+				Action:   CheckActionAllow,
+				Services: []Service{{LinuxName: "falcon-sensor.service", WindowsName: "CSFalconService"}},
+			},
+			isValid: true,
+		},
+		{
+			name: "unsupported operating system",
+			input: peer.Peer{
+				Meta: peer.PeerSystemMeta{GoOS: "android"},
+			},
+			check: ServiceCheck{
+				Action: CheckActionDeny,
+				Ports:  []Port{{Port: 3389, Protocol: "tcp"}},
+			},
+			wantErr: true,
+			isValid: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isValid, err := tt.check.Check(context.Background(), tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.isValid, isValid)
+		})
+	}
+}
+
+func TestServiceCheck_Validate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		check         ServiceCheck
+		expectedError bool
+	}{
+		{
+			name: "Valid service and port",
+			check: ServiceCheck{
+				Action:   CheckActionAllow,
+				Services: []Service{{LinuxName: "falcon-sensor.service"}},
+				Ports:    []Port{{Port: 443, Protocol: "tcp"}},
+			},
+			expectedError: false,
+		},
+		{
+			name: "Invalid empty action",
+			check: ServiceCheck{
+				Services: []Service{{LinuxName: "falcon-sensor.service"}},
+			},
+			expectedError: true,
+		},
+		{
+			name: "Invalid empty services and ports",
+			check: ServiceCheck{
+				Action: CheckActionDeny,
+			},
+			expectedError: true,
+		},
+		{
+			name: "Invalid empty service names",
+			check: ServiceCheck{
+				Action:   CheckActionAllow,
+				Services: []Service{{}},
+			},
+			expectedError: true,
+		},
+		{
+			name: "Invalid port protocol",
+			check: ServiceCheck{
+				Action: CheckActionDeny,
+				Ports:  []Port{{Port: 3389, Protocol: "sctp"}},
+			},
+			expectedError: true,
+		},
+		{
+			name: "Invalid zero port",
+			check: ServiceCheck{
+				Action: CheckActionDeny,
+				Ports:  []Port{{Protocol: "tcp"}},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.check.Validate()
+			if tc.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"golang.org/x/exp/maps"
 
 	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/permissions"
 	"github.com/netbirdio/netbird/management/server/posture"
 	"github.com/netbirdio/netbird/management/server/status"
 	"github.com/netbirdio/netbird/management/server/store"
@@ -27,7 +28,9 @@ func (am *DefaultAccountManager) GetPostureChecks(ctx context.Context, accountID
 	}
 
 	if !user.HasAdminPower() {
-		return nil, status.NewAdminPermissionError()
+		if _, err = am.checkCustomRolePermission(ctx, user, permissions.PostureChecks, permissions.Read); err != nil {
+			return nil, err
+		}
 	}
 
 	return am.Store.GetPostureChecksByID(ctx, store.LockingStrengthShare, accountID, postureChecksID)
@@ -48,7 +51,9 @@ func (am *DefaultAccountManager) SavePostureChecks(ctx context.Context, accountI
 	}
 
 	if !user.HasAdminPower() {
-		return nil, status.NewAdminPermissionError()
+		if _, err = am.checkCustomRolePermission(ctx, user, permissions.PostureChecks, permissions.Write); err != nil {
+			return nil, err
+		}
 	}
 
 	var updateAccountPeers bool
@@ -104,7 +109,9 @@ func (am *DefaultAccountManager) DeletePostureChecks(ctx context.Context, accoun
 	}
 
 	if !user.HasAdminPower() {
-		return status.NewAdminPermissionError()
+		if _, err = am.checkCustomRolePermission(ctx, user, permissions.PostureChecks, permissions.Delete); err != nil {
+			return err
+		}
 	}
 
 	var postureChecks *posture.Checks
@@ -146,7 +153,9 @@ func (am *DefaultAccountManager) ListPostureChecks(ctx context.Context, accountI
 	}
 
 	if !user.HasAdminPower() {
-		return nil, status.NewAdminPermissionError()
+		if _, err = am.checkCustomRolePermission(ctx, user, permissions.PostureChecks, permissions.Read); err != nil {
+			return nil, err
+		}
 	}
 
 	return am.Store.GetAccountPostureChecks(ctx, store.LockingStrengthShare, accountID)
@@ -161,7 +170,7 @@ func (am *DefaultAccountManager) getPeerPostureChecks(account *types.Account, pe
 	}
 
 	for _, policy := range account.Policies {
-		if !policy.Enabled || len(policy.SourcePostureChecks) == 0 {
+		if !policy.Enabled || len(policy.PostureChecks()) == 0 {
 			continue
 		}
 
@@ -181,7 +190,7 @@ func arePostureCheckChangesAffectPeers(ctx context.Context, transaction store.St
 	}
 
 	for _, policy := range policies {
-		if slices.Contains(policy.SourcePostureChecks, postureCheckID) {
+		if slices.Contains(policy.PostureChecks(), postureCheckID) {
 			hasPeers, err := anyGroupHasPeersOrResources(ctx, transaction, accountID, policy.RuleGroups())
 			if err != nil {
 				return false, err
@@ -227,39 +236,61 @@ func validatePostureChecks(ctx context.Context, transaction store.Store, account
 	return nil
 }
 
-// addPolicyPostureChecks adds posture checks from a policy to the peer posture checks map if the peer is in the policy's source groups.
+// addPolicyPostureChecks adds posture checks from a policy to the peer posture checks map
+// if the peer is in the policy's source groups for the source posture checks
+// or in the policy's destination groups for the destination posture checks.
 func addPolicyPostureChecks(account *types.Account, peerID string, policy *types.Policy, peerPostureChecks map[string]*posture.Checks) error {
-	isInGroup, err := isPeerInPolicySourceGroups(account, peerID, policy)
+	isInSources, err := isPeerInPolicyGroups(account, peerID, policy, func(rule *types.PolicyRule) []string { return rule.Sources })
 	if err != nil {
 		return err
 	}
 
-	if !isInGroup {
+	if isInSources {
+		if err = addPostureChecks(account, policy.SourcePostureChecks, peerPostureChecks); err != nil {
+			return err
+		}
+	}
+
+	if len(policy.DestinationPostureChecks) == 0 {
 		return nil
 	}
 
-	for _, sourcePostureCheckID := range policy.SourcePostureChecks {
-		postureCheck := account.GetPostureChecks(sourcePostureCheckID)
+	isInDestinations, err := isPeerInPolicyGroups(account, peerID, policy, func(rule *types.PolicyRule) []string { return rule.Destinations })
+	if err != nil {
+		return err
+	}
+
+	if isInDestinations {
+		return addPostureChecks(account, policy.DestinationPostureChecks, peerPostureChecks)
+	}
+
+	return nil
+}
+
+// addPostureChecks adds the posture checks with the given IDs to the peer posture checks map.
+func addPostureChecks(account *types.Account, postureChecksIDs []string, peerPostureChecks map[string]*posture.Checks) error {
+	for _, postureCheckID := range postureChecksIDs {
+		postureCheck := account.GetPostureChecks(postureCheckID)
 		if postureCheck == nil {
 			return errors.New("failed to add policy posture checks: posture checks not found")
 		}
-		peerPostureChecks[sourcePostureCheckID] = postureCheck
+		peerPostureChecks[postureCheckID] = postureCheck
 	}
 
 	return nil
 }
 
-// isPeerInPolicySourceGroups checks if a peer is present in any of the policy rule source groups.
-func isPeerInPolicySourceGroups(account *types.Account, peerID string, policy *types.Policy) (bool, error) {
+// isPeerInPolicyGroups checks if a peer is present in any of the policy rule groups returned by ruleGroups.
+func isPeerInPolicyGroups(account *types.Account, peerID string, policy *types.Policy, ruleGroups func(*types.PolicyRule) []string) (bool, error) {
 	for _, rule := range policy.Rules {
 		if !rule.Enabled {
 			continue
 		}
 
-		for _, sourceGroup := range rule.Sources {
-			group := account.GetGroup(sourceGroup)
+		for _, groupID := range ruleGroups(rule) {
+			group := account.GetGroup(groupID)
 			if group == nil {
-				return false, fmt.Errorf("failed to check peer in policy source group: group not found")
+				return false, fmt.Errorf("failed to check peer in policy group: group not found")
 			}
 
 			if slices.Contains(group.Peers, peerID) {
@@ -279,7 +310,7 @@ func isPostureCheckLinkedToPolicy(ctx context.Context, transaction store.Store,
 	}
 
 	for _, policy := range policies {
-		if slices.Contains(policy.SourcePostureChecks, postureChecksID) {
+		if slices.Contains(policy.PostureChecks(), postureChecksID) {
 			return status.Errorf(status.PreconditionFailed, "posture checks have been linked to policy: %s", policy.Name)
 		}
 	}
@@ -533,6 +533,19 @@ func TestArePostureCheckChangesAffectPeers(t *testing.T) {
 		assert.True(t, result)
 	})
 
+	t.Run("posture check is linked to policy as destination posture check", func(t *testing.T) {
+		policy.DestinationPostureChecks = []string{postureCheckB.ID}
+		_, err = manager.SavePolicy(context.Background(), account.Id, adminUserID, policy)
+		require.NoError(t, err, "failed to update policy")
+
+		result, err := arePostureCheckChangesAffectPeers(context.Background(), manager.Store, account.Id, postureCheckB.ID)
+		require.NoError(t, err)
+		assert.True(t, result)
+
+		err = isPostureCheckLinkedToPolicy(context.Background(), manager.Store, postureCheckB.ID, account.Id)
+		assert.Error(t, err)
+	})
+
 	t.Run("posture check is linked to policy but no peers in groups", func(t *testing.T) {
 		groupA.Peers = []string{}
 		err = manager.Store.SaveGroup(context.Background(), store.LockingStrengthUpdate, groupA)
@@ -0,0 +1,229 @@
+package roles
+
+import (
+	"context"
+	"fmt"
+
+	s "github.com/netbirdio/netbird/management/server"
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+type Manager interface {
+	GetAllRoles(ctx context.Context, accountID, userID string) ([]*types.CustomRole, error)
+	GetRole(ctx context.Context, accountID, userID, roleID string) (*types.CustomRole, error)
+	CreateRole(ctx context.Context, userID string, role *types.CustomRole) (*types.CustomRole, error)
+	UpdateRole(ctx context.Context, userID string, role *types.CustomRole) (*types.CustomRole, error)
+	DeleteRole(ctx context.Context, accountID, userID, roleID string) error
+}
+
+type managerImpl struct {
+	store              store.Store
+	permissionsManager permissions.Manager
+	accountManager     s.AccountManager
+}
+
+type mockManager struct {
+}
+
+func NewManager(store store.Store, permissionsManager permissions.Manager, accountManager s.AccountManager) Manager {
+	return &managerImpl{
+		store:              store,
+		permissionsManager: permissionsManager,
+		accountManager:     accountManager,
+	}
+}
+
+func (m *managerImpl) GetAllRoles(ctx context.Context, accountID, userID string) ([]*types.CustomRole, error) {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, permissions.Roles, permissions.Read)
+	if err != nil {
+		return nil, status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return nil, status.NewPermissionDeniedError()
+	}
+
+	return m.store.GetAccountCustomRoles(ctx, store.LockingStrengthShare, accountID)
+}
+
+func (m *managerImpl) GetRole(ctx context.Context, accountID, userID, roleID string) (*types.CustomRole, error) {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, permissions.Roles, permissions.Read)
+	if err != nil {
+		return nil, status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return nil, status.NewPermissionDeniedError()
+	}
+
+	return m.store.GetCustomRoleByID(ctx, store.LockingStrengthShare, accountID, roleID)
+}
+
+func (m *managerImpl) CreateRole(ctx context.Context, userID string, role *types.CustomRole) (*types.CustomRole, error) {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, role.AccountID, userID, permissions.Roles, permissions.Write)
+	if err != nil {
+		return nil, status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return nil, status.NewPermissionDeniedError()
+	}
+
+	unlock := m.store.AcquireWriteLockByUID(ctx, role.AccountID)
+	defer unlock()
+
+	role = types.NewCustomRole(role.AccountID, role.Name, role.Description, role.Permissions)
+
+	err = m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		if err = validateRole(ctx, transaction, role); err != nil {
+			return err
+		}
+
+		return transaction.SaveCustomRole(ctx, store.LockingStrengthUpdate, role)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create custom role: %w", err)
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, role.ID, role.AccountID, activity.CustomRoleCreated, role.EventMeta())
+
+	return role, nil
+}
+
+func (m *managerImpl) UpdateRole(ctx context.Context, userID string, role *types.CustomRole) (*types.CustomRole, error) {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, role.AccountID, userID, permissions.Roles, permissions.Write)
+	if err != nil {
+		return nil, status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return nil, status.NewPermissionDeniedError()
+	}
+
+	unlock := m.store.AcquireWriteLockByUID(ctx, role.AccountID)
+	defer unlock()
+
+	err = m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		if _, err = transaction.GetCustomRoleByID(ctx, store.LockingStrengthUpdate, role.AccountID, role.ID); err != nil {
+			return err
+		}
+
+		if err = validateRole(ctx, transaction, role); err != nil {
+			return err
+		}
+
+		return transaction.SaveCustomRole(ctx, store.LockingStrengthUpdate, role)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update custom role: %w", err)
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, role.ID, role.AccountID, activity.CustomRoleUpdated, role.EventMeta())
+
+	return role, nil
+}
+
+func (m *managerImpl) DeleteRole(ctx context.Context, accountID, userID, roleID string) error {
+	ok, err := m.permissionsManager.ValidateUserPermissions(ctx, accountID, userID, permissions.Roles, permissions.Delete)
+	if err != nil {
+		return status.NewPermissionValidationError(err)
+	}
+	if !ok {
+		return status.NewPermissionDeniedError()
+	}
+
+	unlock := m.store.AcquireWriteLockByUID(ctx, accountID)
+	defer unlock()
+
+	var role *types.CustomRole
+	err = m.store.ExecuteInTransaction(ctx, func(transaction store.Store) error {
+		role, err = transaction.GetCustomRoleByID(ctx, store.LockingStrengthUpdate, accountID, roleID)
+		if err != nil {
+			return err
+		}
+
+		users, err := transaction.GetAccountUsers(ctx, store.LockingStrengthShare, accountID)
+		if err != nil {
+			return err
+		}
+
+		for _, user := range users {
+			if user.CustomRoleID == roleID {
+				return status.Errorf(status.PreconditionFailed, "custom role %s is assigned to user %s", role.Name, user.Id)
+			}
+		}
+
+		return transaction.DeleteCustomRole(ctx, store.LockingStrengthUpdate, accountID, roleID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete custom role: %w", err)
+	}
+
+	m.accountManager.StoreEvent(ctx, userID, roleID, accountID, activity.CustomRoleDeleted, role.EventMeta())
+
+	return nil
+}
+
+// validateRole checks that the role permissions reference modules which can be granted by custom roles
+// and groups which exist in the account.
+func validateRole(ctx context.Context, transaction store.Store, role *types.CustomRole) error {
+	if role.Name == "" {
+		return status.Errorf(status.InvalidArgument, "custom role name shouldn't be empty")
+	}
+
+	modules := make(map[string]struct{}, len(role.Permissions))
+	var groupIDs []string
+	for _, permission := range role.Permissions {
+		if !permissions.IsCustomRoleModule(permissions.Module(permission.Module)) {
+			return status.Errorf(status.InvalidArgument, "module %s can't be granted by custom roles", permission.Module)
+		}
+
+		if _, ok := modules[permission.Module]; ok {
+			return status.Errorf(status.InvalidArgument, "duplicate permission for module %s", permission.Module)
+		}
+		modules[permission.Module] = struct{}{}
+
+		groupIDs = append(groupIDs, permission.Groups...)
+	}
+
+	if len(groupIDs) == 0 {
+		return nil
+	}
+
+	groups, err := transaction.GetGroupsByIDs(ctx, store.LockingStrengthShare, role.AccountID, groupIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, groupID := range groupIDs {
+		if _, ok := groups[groupID]; !ok {
+			return status.NewGroupNotFoundError(groupID)
+		}
+	}
+
+	return nil
+}
+
+func NewManagerMock() Manager {
+	return &mockManager{}
+}
+
+func (m *mockManager) GetAllRoles(ctx context.Context, accountID, userID string) ([]*types.CustomRole, error) {
+	return []*types.CustomRole{}, nil
+}
+
+func (m *mockManager) GetRole(ctx context.Context, accountID, userID, roleID string) (*types.CustomRole, error) {
+	return &types.CustomRole{}, nil
+}
+
+func (m *mockManager) CreateRole(ctx context.Context, userID string, role *types.CustomRole) (*types.CustomRole, error) {
+	return role, nil
+}
+
+func (m *mockManager) UpdateRole(ctx context.Context, userID string, role *types.CustomRole) (*types.CustomRole, error) {
+	return role, nil
+}
+
+func (m *mockManager) DeleteRole(ctx context.Context, accountID, userID, roleID string) error {
+	return nil
+}
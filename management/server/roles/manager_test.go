@@ -0,0 +1,129 @@
+package roles
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/server/mock_server"
+	"github.com/netbirdio/netbird/management/server/permissions"
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/store"
+	"github.com/netbirdio/netbird/management/server/types"
+)
+
+const (
+	testAccountID = "testAccountId"
+	testUserID    = "allowedUser"
+)
+
+func createTestManager(t *testing.T) (Manager, store.Store) {
+	t.Helper()
+
+	s, cleanUp, err := store.NewTestStoreFromSQL(context.Background(), "../testdata/networks.sql", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cleanUp)
+
+	err = s.SaveGroup(context.Background(), store.LockingStrengthUpdate, &types.Group{ID: "office", AccountID: testAccountID, Name: "office"})
+	require.NoError(t, err)
+
+	am := mock_server.MockAccountManager{}
+	return NewManager(s, permissions.NewManagerMock(), &am), s
+}
+
+func Test_CreateRole(t *testing.T) {
+	manager, _ := createTestManager(t)
+
+	tests := []struct {
+		name         string
+		permissions  []types.RolePermission
+		expectedType status.Type
+	}{
+		{
+			name:        "valid permissions",
+			permissions: []types.RolePermission{{Module: "routes", Read: true, Write: true, Groups: []string{"office"}}},
+		},
+		{
+			name:         "roles module",
+			permissions:  []types.RolePermission{{Module: "roles", Read: true, Write: true}},
+			expectedType: status.InvalidArgument,
+		},
+		{
+			name:         "duplicate module",
+			permissions:  []types.RolePermission{{Module: "dns", Read: true}, {Module: "dns", Write: true}},
+			expectedType: status.InvalidArgument,
+		},
+		{
+			name:         "unknown group",
+			permissions:  []types.RolePermission{{Module: "dns", Read: true, Groups: []string{"datacenter"}}},
+			expectedType: status.NotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			role, err := manager.CreateRole(context.Background(), testUserID, &types.CustomRole{
+				AccountID:   testAccountID,
+				Name:        "operators",
+				Permissions: tc.permissions,
+			})
+			if tc.expectedType == 0 {
+				require.NoError(t, err)
+				assert.NotEmpty(t, role.ID)
+				return
+			}
+
+			require.Error(t, err)
+			sErr, ok := status.FromError(err)
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedType, sErr.Type())
+		})
+	}
+}
+
+func Test_CreateRoleReturnsPermissionDenied(t *testing.T) {
+	manager, _ := createTestManager(t)
+
+	_, err := manager.CreateRole(context.Background(), "invalidUser", &types.CustomRole{
+		AccountID: testAccountID,
+		Name:      "operators",
+	})
+	require.Error(t, err)
+}
+
+func Test_DeleteRoleAssignedToUser(t *testing.T) {
+	manager, s := createTestManager(t)
+
+	role, err := manager.CreateRole(context.Background(), testUserID, &types.CustomRole{
+		AccountID:   testAccountID,
+		Name:        "operators",
+		Permissions: []types.RolePermission{{Module: "peers", Read: true}},
+	})
+	require.NoError(t, err)
+
+	user := types.NewRegularUser("operatorUser")
+	user.AccountID = testAccountID
+	user.CustomRoleID = role.ID
+	err = s.SaveUser(context.Background(), store.LockingStrengthUpdate, user)
+	require.NoError(t, err)
+
+	err = manager.DeleteRole(context.Background(), testAccountID, testUserID, role.ID)
+	require.Error(t, err)
+	sErr, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, status.PreconditionFailed, sErr.Type())
+
+	user.CustomRoleID = ""
+	err = s.SaveUser(context.Background(), store.LockingStrengthUpdate, user)
+	require.NoError(t, err)
+
+	err = manager.DeleteRole(context.Background(), testAccountID, testUserID, role.ID)
+	require.NoError(t, err)
+
+	_, err = manager.GetRole(context.Background(), testAccountID, testUserID, role.ID)
+	require.Error(t, err)
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"net/netip"
+	"slices"
 	"unicode/utf8"
 
 	"github.com/rs/xid"
 
+	"github.com/netbirdio/netbird/management/server/permissions"
 	"github.com/netbirdio/netbird/management/server/store"
 	"github.com/netbirdio/netbird/management/server/types"
 
@@ -25,11 +27,35 @@ func (am *DefaultAccountManager) GetRoute(ctx context.Context, accountID string,
 		return nil, err
 	}
 
-	if !user.IsAdminOrServiceUser() || user.AccountID != accountID {
+	if user.AccountID != accountID {
 		return nil, status.Errorf(status.PermissionDenied, "only users with admin power can view Network Routes")
 	}
 
-	return am.Store.GetRouteByID(ctx, store.LockingStrengthShare, string(routeID), accountID)
+	var permission *types.RolePermission
+	if !user.IsAdminOrServiceUser() {
+		permission, err = am.checkCustomRolePermission(ctx, user, permissions.Routes, permissions.Read)
+		if err != nil {
+			return nil, status.Errorf(status.PermissionDenied, "only users with admin power can view Network Routes")
+		}
+	}
+
+	r, err := am.Store.GetRouteByID(ctx, store.LockingStrengthShare, string(routeID), accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !permission.CoversAllGroups(getRouteGroups(r)) {
+		return nil, status.Errorf(status.NotFound, "route with ID %s doesn't exist", routeID)
+	}
+
+	return r, nil
+}
+
+// getRouteGroups returns the distribution, routing peer and access control groups of the route
+func getRouteGroups(r *route.Route) []string {
+	groups := slices.Concat(r.Groups, r.PeerGroups, r.AccessControlGroups)
+	slices.Sort(groups)
+	return slices.Compact(groups)
 }
 
 // checkRoutePrefixOrDomainsExistForPeers checks if a route with a given prefix exists for a single peer or multiple peer groups.
@@ -119,6 +145,15 @@ func (am *DefaultAccountManager) CreateRoute(ctx context.Context, accountID stri
 	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
 	defer unlock()
 
+	permission, err := am.checkUserCustomRolePermission(ctx, accountID, userID, permissions.Routes, permissions.Write)
+	if err != nil {
+		return nil, err
+	}
+
+	if !permission.CoversAllGroups(slices.Concat(groups, peerGroupIDs, accessControlGroupIDs)) {
+		return nil, status.Errorf(status.PermissionDenied, "route groups are outside of the user role scope")
+	}
+
 	account, err := am.Store.GetAccount(ctx, accountID)
 	if err != nil {
 		return nil, err
@@ -236,11 +271,26 @@ func (am *DefaultAccountManager) SaveRoute(ctx context.Context, accountID, userI
 		return status.Errorf(status.InvalidArgument, "identifier should be between 1 and %d", route.MaxNetIDChar)
 	}
 
+	permission, err := am.checkUserCustomRolePermission(ctx, accountID, userID, permissions.Routes, permissions.Write)
+	if err != nil {
+		return err
+	}
+
 	account, err := am.Store.GetAccount(ctx, accountID)
 	if err != nil {
 		return err
 	}
 
+	if permission.IsScoped() {
+		existingRoute, ok := account.Routes[routeToSave.ID]
+		if ok && !permission.CoversAllGroups(getRouteGroups(existingRoute)) {
+			return status.NewPermissionDeniedError()
+		}
+		if !permission.CoversAllGroups(getRouteGroups(routeToSave)) {
+			return status.Errorf(status.PermissionDenied, "route groups are outside of the user role scope")
+		}
+	}
+
 	// Do not allow non-Linux peers
 	if peer := account.GetPeer(routeToSave.Peer); peer != nil {
 		if peer.Meta.GoOS != "linux" {
@@ -310,6 +360,11 @@ func (am *DefaultAccountManager) DeleteRoute(ctx context.Context, accountID stri
 	unlock := am.Store.AcquireWriteLockByUID(ctx, accountID)
 	defer unlock()
 
+	permission, err := am.checkUserCustomRolePermission(ctx, accountID, userID, permissions.Routes, permissions.Delete)
+	if err != nil {
+		return err
+	}
+
 	account, err := am.Store.GetAccount(ctx, accountID)
 	if err != nil {
 		return err
@@ -319,6 +374,10 @@ func (am *DefaultAccountManager) DeleteRoute(ctx context.Context, accountID stri
 	if routy == nil {
 		return status.Errorf(status.NotFound, "route with ID %s doesn't exist", routeID)
 	}
+
+	if !permission.CoversAllGroups(getRouteGroups(routy)) {
+		return status.NewPermissionDeniedError()
+	}
 	delete(account.Routes, routeID)
 
 	account.Network.IncSerial()
@@ -342,11 +401,24 @@ func (am *DefaultAccountManager) ListRoutes(ctx context.Context, accountID, user
 		return nil, err
 	}
 
-	if !user.IsAdminOrServiceUser() || user.AccountID != accountID {
+	if user.AccountID != accountID {
 		return nil, status.Errorf(status.PermissionDenied, "only users with admin power can view Network Routes")
 	}
 
-	return am.Store.GetAccountRoutes(ctx, store.LockingStrengthShare, accountID)
+	var permission *types.RolePermission
+	if !user.IsAdminOrServiceUser() {
+		permission, err = am.checkCustomRolePermission(ctx, user, permissions.Routes, permissions.Read)
+		if err != nil {
+			return nil, status.Errorf(status.PermissionDenied, "only users with admin power can view Network Routes")
+		}
+	}
+
+	routes, err := am.Store.GetAccountRoutes(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterByRoleScope(permission, routes, getRouteGroups), nil
 }
 
 func toProtocolRoute(route *route.Route) *proto.Route {
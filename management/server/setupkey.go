@@ -8,6 +8,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/permissions"
 	"github.com/netbirdio/netbird/management/server/status"
 	"github.com/netbirdio/netbird/management/server/store"
 	"github.com/netbirdio/netbird/management/server/types"
@@ -65,8 +66,16 @@ func (am *DefaultAccountManager) CreateSetupKey(ctx context.Context, accountID s
 		return nil, status.NewUserNotPartOfAccountError()
 	}
 
+	var permission *types.RolePermission
 	if user.IsRegularUser() {
-		return nil, status.NewAdminPermissionError()
+		permission, err = am.checkCustomRolePermission(ctx, user, permissions.SetupKeys, permissions.Write)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !permission.CoversAllGroups(autoGroups) {
+		return nil, status.NewPermissionDeniedError()
 	}
 
 	var setupKey *types.SetupKey
@@ -122,8 +131,16 @@ func (am *DefaultAccountManager) SaveSetupKey(ctx context.Context, accountID str
 		return nil, status.NewUserNotPartOfAccountError()
 	}
 
+	var permission *types.RolePermission
 	if user.IsRegularUser() {
-		return nil, status.NewAdminPermissionError()
+		permission, err = am.checkCustomRolePermission(ctx, user, permissions.SetupKeys, permissions.Write)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !permission.CoversAllGroups(keyToSave.AutoGroups) {
+		return nil, status.NewPermissionDeniedError()
 	}
 
 	var oldKey *types.SetupKey
@@ -140,6 +157,10 @@ func (am *DefaultAccountManager) SaveSetupKey(ctx context.Context, accountID str
 			return err
 		}
 
+		if !permission.CoversAllGroups(oldKey.AutoGroups) {
+			return status.NewPermissionDeniedError()
+		}
+
 		if oldKey.Revoked && !keyToSave.Revoked {
 			return status.Errorf(status.InvalidArgument, "can't un-revoke a revoked setup key")
 		}
@@ -184,11 +205,20 @@ func (am *DefaultAccountManager) ListSetupKeys(ctx context.Context, accountID, u
 		return nil, status.NewUserNotPartOfAccountError()
 	}
 
+	var permission *types.RolePermission
 	if user.IsRegularUser() {
-		return nil, status.NewAdminPermissionError()
+		permission, err = am.checkCustomRolePermission(ctx, user, permissions.SetupKeys, permissions.Read)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return am.Store.GetAccountSetupKeys(ctx, store.LockingStrengthShare, accountID)
+	setupKeys, err := am.Store.GetAccountSetupKeys(ctx, store.LockingStrengthShare, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterByRoleScope(permission, setupKeys, func(key *types.SetupKey) []string { return key.AutoGroups }), nil
 }
 
 // GetSetupKey looks up a SetupKey by KeyID, returns NotFound error if not found.
@@ -202,8 +232,12 @@ func (am *DefaultAccountManager) GetSetupKey(ctx context.Context, accountID, use
 		return nil, status.NewUserNotPartOfAccountError()
 	}
 
+	var permission *types.RolePermission
 	if user.IsRegularUser() {
-		return nil, status.NewAdminPermissionError()
+		permission, err = am.checkCustomRolePermission(ctx, user, permissions.SetupKeys, permissions.Read)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	setupKey, err := am.Store.GetSetupKeyByID(ctx, store.LockingStrengthShare, accountID, keyID)
@@ -211,6 +245,10 @@ func (am *DefaultAccountManager) GetSetupKey(ctx context.Context, accountID, use
 		return nil, err
 	}
 
+	if !permission.CoversAllGroups(setupKey.AutoGroups) {
+		return nil, status.NewSetupKeyNotFoundError(keyID)
+	}
+
 	// the UpdatedAt field was introduced later, so there might be that some keys have a Zero value (e.g, null in the store file)
 	if setupKey.UpdatedAt.IsZero() {
 		setupKey.UpdatedAt = setupKey.CreatedAt
@@ -230,8 +268,12 @@ func (am *DefaultAccountManager) DeleteSetupKey(ctx context.Context, accountID,
 		return status.NewUserNotPartOfAccountError()
 	}
 
+	var permission *types.RolePermission
 	if user.IsRegularUser() {
-		return status.NewAdminPermissionError()
+		permission, err = am.checkCustomRolePermission(ctx, user, permissions.SetupKeys, permissions.Delete)
+		if err != nil {
+			return err
+		}
 	}
 
 	var deletedSetupKey *types.SetupKey
@@ -242,6 +284,10 @@ func (am *DefaultAccountManager) DeleteSetupKey(ctx context.Context, accountID,
 			return err
 		}
 
+		if !permission.CoversAllGroups(deletedSetupKey.AutoGroups) {
+			return status.NewPermissionDeniedError()
+		}
+
 		return transaction.DeleteSetupKey(ctx, store.LockingStrengthUpdate, accountID, keyID)
 	})
 	if err != nil {
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/management/server/store"
+)
+
+// SSHSessionEvent is the start or the end of a session on the SSH server of a peer
+type SSHSessionEvent struct {
+	SessionID string
+	// Ended is true for the end of the session and false for its start
+	Ended     bool
+	Timestamp time.Time
+	// RemotePeerKey is the WireGuard public key of the peer that opened the session
+	RemotePeerKey string
+	// LocalUser is the local account the session is logged in as
+	LocalUser string
+	// Duration of the session, set for the end events
+	Duration time.Duration
+}
+
+// LogPeerSSHSessions stores the SSH session events reported by the peer as activity events.
+// The events are attributed to the user of the remote peer, or to the remote peer itself if it was added with a setup key.
+func (am *DefaultAccountManager) LogPeerSSHSessions(ctx context.Context, peerPubKey string, events []SSHSessionEvent) error {
+	peer, err := am.Store.GetPeerByPeerPubKey(ctx, store.LockingStrengthShare, peerPubKey)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		remotePeer, err := am.Store.GetPeerByPeerPubKey(ctx, store.LockingStrengthShare, event.RemotePeerKey)
+		if err != nil {
+			var sErr *status.Error
+			if errors.As(err, &sErr) && sErr.Type() == status.NotFound {
+				log.WithContext(ctx).Warnf("ignoring SSH session %s on peer %s from unknown peer %s", event.SessionID, peer.ID, event.RemotePeerKey)
+				continue
+			}
+			return err
+		}
+
+		if remotePeer.AccountID != peer.AccountID {
+			log.WithContext(ctx).Warnf("ignoring SSH session %s on peer %s from peer %s of another account", event.SessionID, peer.ID, remotePeer.ID)
+			continue
+		}
+
+		initiatorID := remotePeer.UserID
+		if initiatorID == "" {
+			initiatorID = remotePeer.ID
+		}
+
+		meta := map[string]any{
+			"session_id":       event.SessionID,
+			"local_user":       event.LocalUser,
+			"peer_name":        peer.Name,
+			"peer_ip":          peer.IP.String(),
+			"source_peer_id":   remotePeer.ID,
+			"source_peer_name": remotePeer.Name,
+			"source_peer_ip":   remotePeer.IP.String(),
+		}
+
+		activityID := activity.PeerSSHSessionStarted
+		if event.Ended {
+			activityID = activity.PeerSSHSessionEnded
+			meta["duration"] = event.Duration.Round(time.Second).String()
+		}
+
+		// the events may be reported late when the peer couldn't reach management, they keep the session time
+		timestamp := event.Timestamp
+		if timestamp.IsZero() || timestamp.After(time.Now()) {
+			timestamp = time.Now()
+		}
+		am.storeEventAt(ctx, timestamp, initiatorID, peer.ID, peer.AccountID, activityID, meta)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+)
+
+func TestDefaultAccountManager_LogPeerSSHSessions(t *testing.T) {
+	manager, err := createManager(t)
+	require.NoError(t, err, "unable to create account manager")
+
+	accountID, err := manager.GetAccountIDByUserID(context.Background(), userID, "")
+	require.NoError(t, err, "unable to create an account")
+
+	addPeer := func(hostname string) *nbpeer.Peer {
+		key, err := wgtypes.GenerateKey()
+		require.NoError(t, err, "unable to generate WireGuard key")
+		peer, _, _, err := manager.AddPeer(context.Background(), "", userID, &nbpeer.Peer{
+			Key:  key.PublicKey().String(),
+			Meta: nbpeer.PeerSystemMeta{Hostname: hostname},
+		})
+		require.NoError(t, err, "unable to add peer")
+		return peer
+	}
+	server := addPeer("server")
+	client := addPeer("client")
+
+	unknownKey, err := wgtypes.GenerateKey()
+	require.NoError(t, err)
+
+	started := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	ended := started.Add(90 * time.Second)
+	err = manager.LogPeerSSHSessions(context.Background(), server.Key, []SSHSessionEvent{
+		{SessionID: "session1", Timestamp: started, RemotePeerKey: client.Key, LocalUser: "deploy"},
+		{SessionID: "session1", Ended: true, Timestamp: ended, RemotePeerKey: client.Key, LocalUser: "deploy", Duration: 90 * time.Second},
+		{SessionID: "session2", RemotePeerKey: unknownKey.PublicKey().String(), LocalUser: "root"},
+	})
+	require.NoError(t, err)
+
+	var sessionEvents []*activity.Event
+	require.Eventually(t, func() bool {
+		events, err := manager.eventStore.Get(context.Background(), accountID, 0, 100, false)
+		require.NoError(t, err)
+		sessionEvents = sessionEvents[:0]
+		for _, event := range events {
+			if event.Activity == activity.PeerSSHSessionStarted || event.Activity == activity.PeerSSHSessionEnded {
+				sessionEvents = append(sessionEvents, event)
+			}
+		}
+		return len(sessionEvents) == 2
+	}, time.Second, 10*time.Millisecond, "expected the events of the known peer only")
+
+	for _, event := range sessionEvents {
+		assert.Equal(t, userID, event.InitiatorID, "the session should be attributed to the user of the remote peer")
+		assert.Equal(t, server.ID, event.TargetID)
+		assert.Equal(t, "deploy", event.Meta["local_user"])
+		assert.Equal(t, client.ID, event.Meta["source_peer_id"])
+		if event.Activity == activity.PeerSSHSessionEnded {
+			assert.Equal(t, "1m30s", event.Meta["duration"])
+			assert.True(t, ended.Equal(event.Timestamp), "the event should keep the reported time")
+		} else {
+			assert.True(t, started.Equal(event.Timestamp), "the event should keep the reported time")
+		}
+	}
+}
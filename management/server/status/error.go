@@ -170,6 +170,11 @@ func NewNetworkResourceNotFoundError(resourceID string) error {
 	return Errorf(NotFound, "network resource: %s not found", resourceID)
 }
 
+// NewCustomRoleNotFoundError creates a new Error with NotFound type for a missing custom role.
+func NewCustomRoleNotFoundError(roleID string) error {
+	return Errorf(NotFound, "custom role: %s not found", roleID)
+}
+
 // NewPermissionDeniedError creates a new Error with PermissionDenied type for a permission denied error.
 func NewPermissionDeniedError() error {
 	return Errorf(PermissionDenied, "permission denied")
@@ -0,0 +1,304 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	nbdns "github.com/netbirdio/netbird/dns"
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
+	routerTypes "github.com/netbirdio/netbird/management/server/networks/routers/types"
+	networkTypes "github.com/netbirdio/netbird/management/server/networks/types"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/posture"
+	"github.com/netbirdio/netbird/management/server/types"
+	"github.com/netbirdio/netbird/route"
+)
+
+// ExportFormatVersion is the version of the export format produced by ExportAccounts.
+// It must be increased whenever a change to AccountExport is not backward compatible.
+const ExportFormatVersion = 1
+
+// Export is a portable snapshot of one or more accounts that can be restored into any store engine
+type Export struct {
+	Version        int
+	CreatedAt      time.Time
+	SourceEngine   Engine
+	InstallationID string
+	Accounts       []*AccountExport
+}
+
+// AccountExport holds all the objects of a single account in an engine independent form
+type AccountExport struct {
+	Id                     string
+	CreatedBy              string
+	CreatedAt              time.Time
+	Domain                 string
+	DomainCategory         string
+	IsDomainPrimaryAccount bool
+	Network                *types.Network
+	DNSSettings            types.DNSSettings
+	Settings               *types.Settings
+
+	Peers            []*nbpeer.Peer
+	Users            []*types.User
+	Groups           []*types.Group
+	Policies         []*types.Policy
+	PostureChecks    []*posture.Checks
+	Routes           []*route.Route
+	NameServerGroups []*nbdns.NameServerGroup
+	SetupKeys        []*types.SetupKey
+	Networks         []*networkTypes.Network
+	NetworkRouters   []*routerTypes.NetworkRouter
+	NetworkResources []*resourceTypes.NetworkResource
+	CustomRoles      []*types.CustomRole
+}
+
+// ExportAccounts creates an export of the given accounts. If no account IDs are provided, all accounts of the store are exported.
+func ExportAccounts(ctx context.Context, s Store, accountIDs ...string) (*Export, error) {
+	var accounts []*types.Account
+	if len(accountIDs) == 0 {
+		accounts = s.GetAllAccounts(ctx)
+	} else {
+		for _, accountID := range accountIDs {
+			account, err := s.GetAccount(ctx, accountID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get account %s: %w", accountID, err)
+			}
+			accounts = append(accounts, account)
+		}
+	}
+
+	export := &Export{
+		Version:        ExportFormatVersion,
+		CreatedAt:      time.Now().UTC(),
+		SourceEngine:   s.GetStoreEngine(),
+		InstallationID: s.GetInstallationID(),
+		Accounts:       make([]*AccountExport, 0, len(accounts)),
+	}
+
+	for _, account := range accounts {
+		export.Accounts = append(export.Accounts, newAccountExport(account))
+	}
+
+	sort.Slice(export.Accounts, func(i, j int) bool {
+		return export.Accounts[i].Id < export.Accounts[j].Id
+	})
+
+	return export, nil
+}
+
+// ImportAccounts restores the accounts of the export into the store.
+// Existing accounts are replaced only when overwrite is set, otherwise the import fails before anything is written.
+// The accounts are written in a single transaction, a failed import leaves the store unchanged.
+func ImportAccounts(ctx context.Context, s Store, export *Export, overwrite bool) error {
+	if export.Version < 1 || export.Version > ExportFormatVersion {
+		return fmt.Errorf("unsupported export format version %d, supported versions: 1-%d", export.Version, ExportFormatVersion)
+	}
+
+	err := s.ExecuteInTransaction(ctx, func(transaction Store) error {
+		for _, accountExport := range export.Accounts {
+			if accountExport.Id == "" {
+				return fmt.Errorf("export contains an account without ID")
+			}
+
+			exists, err := transaction.AccountExists(ctx, LockingStrengthUpdate, accountExport.Id)
+			if err != nil {
+				return fmt.Errorf("failed to check if account %s exists: %w", accountExport.Id, err)
+			}
+			if exists && !overwrite {
+				return fmt.Errorf("account %s already exists in the %s store", accountExport.Id, s.GetStoreEngine())
+			}
+		}
+
+		for _, accountExport := range export.Accounts {
+			if err := transaction.SaveAccount(ctx, accountExport.toAccount()); err != nil {
+				return fmt.Errorf("failed to import account %s: %w", accountExport.Id, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, accountExport := range export.Accounts {
+		log.WithContext(ctx).Infof("imported account %s with %d peers, %d users and %d groups",
+			accountExport.Id, len(accountExport.Peers), len(accountExport.Users), len(accountExport.Groups))
+	}
+
+	if s.GetInstallationID() == "" && export.InstallationID != "" {
+		if err := s.SaveInstallationID(ctx, export.InstallationID); err != nil {
+			return fmt.Errorf("failed to save installation ID: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// WriteExport encodes the export as JSON to the writer
+func WriteExport(w io.Writer, export *Export) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(export)
+}
+
+// ReadExport decodes an export from the reader
+func ReadExport(r io.Reader) (*Export, error) {
+	export := &Export{}
+	if err := json.NewDecoder(r).Decode(export); err != nil {
+		return nil, fmt.Errorf("failed to decode export: %w", err)
+	}
+	return export, nil
+}
+
+func newAccountExport(account *types.Account) *AccountExport {
+	export := &AccountExport{
+		Id:                     account.Id,
+		CreatedBy:              account.CreatedBy,
+		CreatedAt:              account.CreatedAt,
+		Domain:                 account.Domain,
+		DomainCategory:         account.DomainCategory,
+		IsDomainPrimaryAccount: account.IsDomainPrimaryAccount,
+		Network:                account.Network,
+		DNSSettings:            account.DNSSettings,
+		Settings:               account.Settings,
+		Policies:               account.Policies,
+		PostureChecks:          account.PostureChecks,
+		Networks:               account.Networks,
+		NetworkRouters:         account.NetworkRouters,
+		NetworkResources:       account.NetworkResources,
+		CustomRoles:            account.CustomRoles,
+	}
+
+	for id, peer := range account.Peers {
+		peer.ID = id
+		export.Peers = append(export.Peers, peer)
+	}
+	sort.Slice(export.Peers, func(i, j int) bool { return export.Peers[i].ID < export.Peers[j].ID })
+
+	for id, user := range account.Users {
+		user.Id = id
+		export.Users = append(export.Users, user)
+	}
+	sort.Slice(export.Users, func(i, j int) bool { return export.Users[i].Id < export.Users[j].Id })
+
+	for id, group := range account.Groups {
+		group.ID = id
+		export.Groups = append(export.Groups, group)
+	}
+	sort.Slice(export.Groups, func(i, j int) bool { return export.Groups[i].ID < export.Groups[j].ID })
+
+	for id, r := range account.Routes {
+		r.ID = id
+		export.Routes = append(export.Routes, r)
+	}
+	sort.Slice(export.Routes, func(i, j int) bool { return export.Routes[i].ID < export.Routes[j].ID })
+
+	for id, ns := range account.NameServerGroups {
+		ns.ID = id
+		export.NameServerGroups = append(export.NameServerGroups, ns)
+	}
+	sort.Slice(export.NameServerGroups, func(i, j int) bool { return export.NameServerGroups[i].ID < export.NameServerGroups[j].ID })
+
+	for _, key := range account.SetupKeys {
+		export.SetupKeys = append(export.SetupKeys, key)
+	}
+	sort.Slice(export.SetupKeys, func(i, j int) bool { return export.SetupKeys[i].Id < export.SetupKeys[j].Id })
+
+	return export
+}
+
+func (e *AccountExport) toAccount() *types.Account {
+	account := &types.Account{
+		Id:                     e.Id,
+		CreatedBy:              e.CreatedBy,
+		CreatedAt:              e.CreatedAt,
+		Domain:                 e.Domain,
+		DomainCategory:         e.DomainCategory,
+		IsDomainPrimaryAccount: e.IsDomainPrimaryAccount,
+		Network:                e.Network,
+		DNSSettings:            e.DNSSettings,
+		Settings:               e.Settings,
+		Policies:               e.Policies,
+		PostureChecks:          e.PostureChecks,
+		Networks:               e.Networks,
+		NetworkRouters:         e.NetworkRouters,
+		NetworkResources:       e.NetworkResources,
+		CustomRoles:            e.CustomRoles,
+		Peers:                  make(map[string]*nbpeer.Peer, len(e.Peers)),
+		Users:                  make(map[string]*types.User, len(e.Users)),
+		Groups:                 make(map[string]*types.Group, len(e.Groups)),
+		Routes:                 make(map[route.ID]*route.Route, len(e.Routes)),
+		NameServerGroups:       make(map[string]*nbdns.NameServerGroup, len(e.NameServerGroups)),
+		SetupKeys:              make(map[string]*types.SetupKey, len(e.SetupKeys)),
+	}
+
+	for _, peer := range e.Peers {
+		peer.AccountID = e.Id
+		account.Peers[peer.ID] = peer
+	}
+
+	for _, user := range e.Users {
+		user.AccountID = e.Id
+		for id, pat := range user.PATs {
+			pat.ID = id
+			pat.UserID = user.Id
+		}
+		account.Users[user.Id] = user
+	}
+
+	for _, group := range e.Groups {
+		group.AccountID = e.Id
+		account.Groups[group.ID] = group
+	}
+
+	for _, policy := range e.Policies {
+		policy.AccountID = e.Id
+		for _, rule := range policy.Rules {
+			rule.PolicyID = policy.ID
+		}
+	}
+
+	for _, checks := range e.PostureChecks {
+		checks.AccountID = e.Id
+	}
+
+	for _, r := range e.Routes {
+		r.AccountID = e.Id
+		account.Routes[r.ID] = r
+	}
+
+	for _, ns := range e.NameServerGroups {
+		ns.AccountID = e.Id
+		account.NameServerGroups[ns.ID] = ns
+	}
+
+	for _, key := range e.SetupKeys {
+		key.AccountID = e.Id
+		account.SetupKeys[key.Key] = key
+	}
+
+	for _, network := range e.Networks {
+		network.AccountID = e.Id
+	}
+
+	for _, router := range e.NetworkRouters {
+		router.AccountID = e.Id
+	}
+
+	for _, resource := range e.NetworkResources {
+		resource.AccountID = e.Id
+	}
+
+	for _, role := range e.CustomRoles {
+		role.AccountID = e.Id
+	}
+
+	return account
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImportAccounts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The SQLite store is not properly supported by Windows yet")
+	}
+
+	ctx := context.Background()
+	accountID := "bf1c8084-ba50-4ce7-9439-34653001fc3b"
+
+	source, cleanUpSource, err := NewTestStoreFromSQL(ctx, "../testdata/extended-store.sql", t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(cleanUpSource)
+
+	export, err := ExportAccounts(ctx, source, accountID)
+	require.NoError(t, err)
+	require.Len(t, export.Accounts, 1)
+	assert.Equal(t, ExportFormatVersion, export.Version)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteExport(&buf, export))
+
+	decoded, err := ReadExport(&buf)
+	require.NoError(t, err)
+
+	target, cleanUpTarget, err := NewTestStoreFromSQL(ctx, "", t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(cleanUpTarget)
+
+	require.NoError(t, ImportAccounts(ctx, target, decoded, false))
+
+	expected, err := source.GetAccount(ctx, accountID)
+	require.NoError(t, err)
+	imported, err := target.GetAccount(ctx, accountID)
+	require.NoError(t, err)
+
+	assert.Equal(t, expected.Domain, imported.Domain)
+	assert.Equal(t, expected.Network.Net.String(), imported.Network.Net.String())
+	assert.Len(t, imported.Peers, len(expected.Peers))
+	assert.Len(t, imported.Users, len(expected.Users))
+	assert.Len(t, imported.Groups, len(expected.Groups))
+	assert.Len(t, imported.SetupKeys, len(expected.SetupKeys))
+	assert.Len(t, imported.Routes, len(expected.Routes))
+	assert.Len(t, imported.NameServerGroups, len(expected.NameServerGroups))
+	assert.Len(t, imported.PostureChecks, len(expected.PostureChecks))
+	require.Len(t, imported.Policies, len(expected.Policies))
+	for i, policy := range expected.Policies {
+		assert.Len(t, imported.Policies[i].Rules, len(policy.Rules))
+	}
+
+	for id, user := range expected.Users {
+		require.Contains(t, imported.Users, id)
+		assert.Len(t, imported.Users[id].PATs, len(user.PATs))
+	}
+
+	t.Run("existing account is not overwritten by default", func(t *testing.T) {
+		err := ImportAccounts(ctx, target, decoded, false)
+		require.Error(t, err)
+
+		require.NoError(t, ImportAccounts(ctx, target, decoded, true))
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		err := ImportAccounts(ctx, target, &Export{Version: ExportFormatVersion + 1}, true)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,198 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	// registers the pgx database/sql driver used by the locks connections
+	_ "github.com/jackc/pgx/v5/stdlib"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	globalLockKey    = "netbird-global-lock"
+	lockQueryTimeout = 10 * time.Second
+	// lockSessions is the number of database sessions holding the advisory locks of a management instance, it is the
+	// number of connections used by the locker
+	lockSessions = 10
+
+	// lockPollInterval and lockMaxPollInterval bound the backoff of polling a lock held by another instance
+	lockPollInterval    = 10 * time.Millisecond
+	lockMaxPollInterval = time.Second
+
+	// sharedLockRetryInterval and sharedLockMaxRetryInterval bound the backoff of the store retrying a shared lock
+	sharedLockRetryInterval    = 100 * time.Millisecond
+	sharedLockMaxRetryInterval = 5 * time.Second
+)
+
+var errLockSessionLost = errors.New("lock session lost")
+
+// Locker acquires the locks shared by all management instances using the same database
+type Locker interface {
+	// Lock acquires an exclusive lock of the key and returns a function that releases the lock
+	Lock(ctx context.Context, key string) (unlock func(), err error)
+	// RLock acquires a shared lock of the key and returns a function that releases the lock
+	RLock(ctx context.Context, key string) (unlock func(), err error)
+	// Close releases the resources of the locker
+	Close() error
+}
+
+// PostgresLocker implements Locker with Postgres session advisory locks. The locks are spread by key over a fixed
+// number of sessions, so the number of held and nested locks doesn't depend on the free connections. The locks are
+// polled with pg_try_advisory_lock not to block a session waiting for another instance.
+// The advisory locks of a session don't exclude each other, the locker only excludes the other instances and relies
+// on the process-local locks of the store.
+type PostgresLocker struct {
+	db       *sql.DB
+	sessions []*lockSession
+}
+
+// NewPostgresLocker returns a new instance of PostgresLocker using the database of the DSN
+func NewPostgresLocker(ctx context.Context, dsn string) (*PostgresLocker, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("connect database: %w", err)
+	}
+	db.SetMaxOpenConns(lockSessions)
+	db.SetMaxIdleConns(lockSessions)
+
+	l := &PostgresLocker{db: db}
+	for i := 0; i < lockSessions; i++ {
+		l.sessions = append(l.sessions, &lockSession{db: db})
+	}
+	return l, nil
+}
+
+// Lock acquires an exclusive advisory lock of the key
+func (l *PostgresLocker) Lock(ctx context.Context, key string) (func(), error) {
+	return l.lock(ctx, key, "pg_try_advisory_lock", "pg_advisory_unlock")
+}
+
+// RLock acquires a shared advisory lock of the key
+func (l *PostgresLocker) RLock(ctx context.Context, key string) (func(), error) {
+	return l.lock(ctx, key, "pg_try_advisory_lock_shared", "pg_advisory_unlock_shared")
+}
+
+// Close closes the connections of the locker, the held locks are released with the sessions
+func (l *PostgresLocker) Close() error {
+	for _, s := range l.sessions {
+		s.close()
+	}
+	return l.db.Close()
+}
+
+func (l *PostgresLocker) lock(ctx context.Context, key, lockFunc, unlockFunc string) (func(), error) {
+	id := advisoryLockID(key)
+	session := l.sessions[uint64(id)%uint64(len(l.sessions))]
+
+	interval := lockPollInterval
+	for {
+		acquired, generation, err := session.query(ctx, lockFunc, id, 0)
+		if err != nil {
+			return nil, fmt.Errorf("acquire lock %s: %w", key, err)
+		}
+		if acquired {
+			return func() {
+				session.unlock(ctx, key, unlockFunc, id, generation)
+			}, nil
+		}
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("acquire lock %s: %w", key, ctx.Err())
+		}
+		interval = min(2*interval, lockMaxPollInterval)
+	}
+}
+
+// lockSession is a database session holding advisory locks. A new session replaces a broken one, the locks of the
+// broken session are released by the database.
+type lockSession struct {
+	db *sql.DB
+
+	mu   sync.Mutex
+	conn *sql.Conn
+	// generation identifies the connection the locks were acquired with
+	generation uint64
+}
+
+// query runs the advisory lock function with the session and returns its result and the session generation. A
+// non-zero generation runs the function only with the session of the generation.
+func (s *lockSession) query(ctx context.Context, lockFunc string, id int64, generation uint64) (bool, uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if generation != 0 && (s.conn == nil || s.generation != generation) {
+		return false, 0, errLockSessionLost
+	}
+
+	// the query isn't canceled with the caller, canceling it may break the session and its locks
+	queryCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), lockQueryTimeout)
+	defer cancel()
+
+	if s.conn == nil {
+		conn, err := s.db.Conn(queryCtx)
+		if err != nil {
+			return false, 0, fmt.Errorf("get lock connection: %w", err)
+		}
+		s.conn = conn
+		s.generation++
+	}
+
+	var result bool
+	if err := s.conn.QueryRowContext(queryCtx, "SELECT "+lockFunc+"($1)", id).Scan(&result); err != nil {
+		s.reset()
+		return false, 0, err
+	}
+	return result, s.generation, nil
+}
+
+func (s *lockSession) unlock(ctx context.Context, key, unlockFunc string, id int64, generation uint64) {
+	released, _, err := s.query(ctx, unlockFunc, id, generation)
+	switch {
+	case errors.Is(err, errLockSessionLost):
+		log.WithContext(ctx).Warnf("lock %s was released with its broken session already", key)
+	case err != nil:
+		log.WithContext(ctx).Errorf("failed to release lock %s, dropped the session holding it: %v", key, err)
+	case !released:
+		log.WithContext(ctx).Warnf("lock %s wasn't held by its session", key)
+	}
+}
+
+// reset drops the broken connection, closing it ends the session and its locks. It must be called with mu held.
+func (s *lockSession) reset() {
+	if s.conn == nil {
+		return
+	}
+	_ = s.conn.Raw(func(any) error { return driver.ErrBadConn })
+	_ = s.conn.Close()
+	s.conn = nil
+}
+
+func (s *lockSession) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+}
+
+// advisoryLockID maps the key to the 64-bit ID of the advisory lock
+func advisoryLockID(key string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,208 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockLocker struct {
+	mu   sync.Mutex
+	err  error
+	held map[string]int
+}
+
+func (m *mockLocker) Lock(_ context.Context, key string) (func(), error) {
+	return m.lock("write:" + key)
+}
+
+func (m *mockLocker) RLock(_ context.Context, key string) (func(), error) {
+	return m.lock("read:" + key)
+}
+
+func (m *mockLocker) lock(key string) (func(), error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.held[key]++
+
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.held[key]--
+	}, nil
+}
+
+func (m *mockLocker) setErr(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+}
+
+func (m *mockLocker) Close() error {
+	return nil
+}
+
+func TestSqlStore_SharedLocks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The SQLite store is not properly supported by Windows yet")
+	}
+
+	store, cleanUp, err := NewTestStoreFromSQL(context.Background(), "", t.TempDir())
+	t.Cleanup(cleanUp)
+	require.NoError(t, err)
+
+	sqlStore, ok := store.(*SqlStore)
+	require.True(t, ok)
+
+	locker := &mockLocker{held: map[string]int{}}
+	sqlStore.SetLocker(locker)
+
+	unlock := sqlStore.AcquireWriteLockByUID(context.Background(), "account")
+	assert.Equal(t, 1, locker.held["write:account"], "write lock should be held in the locker")
+	unlock()
+	assert.Equal(t, 0, locker.held["write:account"], "write lock should be released in the locker")
+
+	unlock = sqlStore.AcquireReadLockByUID(context.Background(), "account")
+	assert.Equal(t, 1, locker.held["read:account"], "read lock should be held in the locker")
+	unlock()
+
+	unlock = sqlStore.AcquireGlobalLock(context.Background())
+	assert.Equal(t, 1, locker.held["write:"+globalLockKey], "global lock should be held in the locker")
+	unlock()
+
+	locker.setErr(errors.New("database unavailable"))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		unlock := sqlStore.AcquireWriteLockByUID(ctx, "account")
+		unlock()
+	}()
+	cancel()
+
+	select {
+	case <-done:
+		t.Fatal("store should not fall back to the local lock if the shared lock fails")
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	locker.setErr(nil)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("store should retry the shared lock until it is acquired")
+	}
+	assert.Equal(t, 0, locker.held["write:account"], "retried write lock should be released in the locker")
+}
+
+func TestPostgresql_Locker(t *testing.T) {
+	if (os.Getenv("CI") == "true" && runtime.GOOS == "darwin") || runtime.GOOS == "windows" {
+		t.Skip("skip CI tests on darwin and windows")
+	}
+
+	t.Setenv("NETBIRD_STORE_ENGINE", string(PostgresStoreEngine))
+	_, cleanUp, err := NewTestStoreFromSQL(context.Background(), "", t.TempDir())
+	t.Cleanup(cleanUp)
+	require.NoError(t, err)
+
+	dsn, err := GetPostgresDSN()
+	require.NoError(t, err)
+
+	// two lockers act as two management instances
+	first, err := NewPostgresLocker(context.Background(), dsn)
+	require.NoError(t, err)
+	defer first.Close()
+	second, err := NewPostgresLocker(context.Background(), dsn)
+	require.NoError(t, err)
+	defer second.Close()
+
+	tryLock := func(lock func(context.Context, string) (func(), error)) (func(), error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+		return lock(ctx, "account")
+	}
+
+	unlock, err := first.Lock(context.Background(), "account")
+	require.NoError(t, err)
+
+	_, err = tryLock(second.Lock)
+	assert.Error(t, err, "write lock held by another instance should block")
+	_, err = tryLock(second.RLock)
+	assert.Error(t, err, "read lock should be blocked by the write lock of another instance")
+
+	unlock()
+
+	unlockFirst, err := tryLock(first.RLock)
+	require.NoError(t, err)
+	unlockSecond, err := tryLock(second.RLock)
+	require.NoError(t, err, "read locks should be shared between the instances")
+
+	_, err = tryLock(second.Lock)
+	assert.Error(t, err, "write lock should be blocked by the read locks")
+
+	unlockFirst()
+	unlockSecond()
+
+	unlock, err = tryLock(second.Lock)
+	require.NoError(t, err, "released lock should be acquired by another instance")
+	unlock()
+}
+
+func TestPostgresql_Locker_NestedLocks(t *testing.T) {
+	if (os.Getenv("CI") == "true" && runtime.GOOS == "darwin") || runtime.GOOS == "windows" {
+		t.Skip("skip CI tests on darwin and windows")
+	}
+
+	t.Setenv("NETBIRD_STORE_ENGINE", string(PostgresStoreEngine))
+	store, cleanUp, err := NewTestStoreFromSQL(context.Background(), "", t.TempDir())
+	t.Cleanup(cleanUp)
+	require.NoError(t, err)
+
+	dsn, err := GetPostgresDSN()
+	require.NoError(t, err)
+
+	locker, err := NewPostgresLocker(context.Background(), dsn)
+	require.NoError(t, err)
+	defer locker.Close()
+
+	sqlStore, ok := store.(*SqlStore)
+	require.True(t, ok)
+	sqlStore.SetLocker(locker)
+
+	// every sync holds the account read lock while acquiring the peer write lock
+	var wg sync.WaitGroup
+	for i := 0; i < 20*lockSessions; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			unlockAccount := sqlStore.AcquireReadLockByUID(context.Background(), "account")
+			defer unlockAccount()
+			unlockPeer := sqlStore.AcquireWriteLockByUID(context.Background(), fmt.Sprintf("peer-%d", i))
+			unlockPeer()
+		}(i)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("nested locks shouldn't exhaust the connections of the locker")
+	}
+}
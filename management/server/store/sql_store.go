@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	log "github.com/sirupsen/logrus"
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
@@ -55,6 +56,8 @@ type SqlStore struct {
 	metrics           telemetry.AppMetrics
 	installationPK    int
 	storeEngine       Engine
+	// locker shares the locks with the other management instances using the same database
+	locker Locker
 }
 
 type installation struct {
@@ -94,7 +97,7 @@ func NewSqlStore(ctx context.Context, db *gorm.DB, storeEngine Engine, metrics t
 		&types.SetupKey{}, &nbpeer.Peer{}, &types.User{}, &types.PersonalAccessToken{}, &types.Group{},
 		&types.Account{}, &types.Policy{}, &types.PolicyRule{}, &route.Route{}, &nbdns.NameServerGroup{},
 		&installation{}, &account.ExtraSettings{}, &posture.Checks{}, &nbpeer.NetworkAddress{},
-		&networkTypes.Network{}, &routerTypes.NetworkRouter{}, &resourceTypes.NetworkResource{},
+		&networkTypes.Network{}, &routerTypes.NetworkRouter{}, &resourceTypes.NetworkResource{}, &types.CustomRole{},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("auto migrate: %w", err)
@@ -110,13 +113,54 @@ func GetKeyQueryCondition(s *SqlStore) string {
 	return keyQueryCondition
 }
 
+// SetLocker makes the store locks shared with the other management instances using the same database
+func (s *SqlStore) SetLocker(locker Locker) {
+	s.locker = locker
+}
+
+// acquireSharedLock acquires the lock of the key in the locker, if the store has one. The process-local lock is held
+// already and the callers can't handle a failure, so the shared lock is retried until it is acquired. The retries
+// don't stop with the context, a lock released early would let another instance write the same resource.
+func (s *SqlStore) acquireSharedLock(ctx context.Context, key string, read bool) (unlock func()) {
+	if s.locker == nil {
+		return func() {}
+	}
+
+	lock := s.locker.Lock
+	if read {
+		lock = s.locker.RLock
+	}
+
+	lockCtx := context.WithoutCancel(ctx)
+	operation := func() error {
+		var err error
+		unlock, err = lock(lockCtx, key)
+		return err
+	}
+
+	retry := backoff.NewExponentialBackOff()
+	retry.InitialInterval = sharedLockRetryInterval
+	retry.MaxInterval = sharedLockMaxRetryInterval
+	retry.MaxElapsedTime = 0
+
+	notify := func(err error, next time.Duration) {
+		log.WithContext(ctx).Errorf("failed to acquire shared lock for ID %s, retrying in %v: %v", key, next, err)
+	}
+
+	// the backoff never stops, so the operation succeeds eventually
+	_ = backoff.RetryNotify(operation, retry, notify)
+	return unlock
+}
+
 // AcquireGlobalLock acquires global lock across all the accounts and returns a function that releases the lock
 func (s *SqlStore) AcquireGlobalLock(ctx context.Context) (unlock func()) {
 	log.WithContext(ctx).Tracef("acquiring global lock")
 	start := time.Now()
 	s.globalAccountLock.Lock()
+	sharedUnlock := s.acquireSharedLock(ctx, globalLockKey, false)
 
 	unlock = func() {
+		sharedUnlock()
 		s.globalAccountLock.Unlock()
 		log.WithContext(ctx).Tracef("released global lock in %v", time.Since(start))
 	}
@@ -138,8 +182,10 @@ func (s *SqlStore) AcquireWriteLockByUID(ctx context.Context, uniqueID string) (
 	value, _ := s.resourceLocks.LoadOrStore(uniqueID, &sync.RWMutex{})
 	mtx := value.(*sync.RWMutex)
 	mtx.Lock()
+	sharedUnlock := s.acquireSharedLock(ctx, uniqueID, false)
 
 	unlock = func() {
+		sharedUnlock()
 		mtx.Unlock()
 		log.WithContext(ctx).Tracef("released write lock for ID %s in %v", uniqueID, time.Since(start))
 	}
@@ -147,7 +193,7 @@ func (s *SqlStore) AcquireWriteLockByUID(ctx context.Context, uniqueID string) (
 	return unlock
 }
 
-// AcquireReadLockByUID acquires an ID lock for writing to a resource and returns a function that releases the lock
+// AcquireReadLockByUID acquires an ID lock for reading a resource and returns a function that releases the lock
 func (s *SqlStore) AcquireReadLockByUID(ctx context.Context, uniqueID string) (unlock func()) {
 	log.WithContext(ctx).Tracef("acquiring read lock for ID %s", uniqueID)
 
@@ -155,8 +201,10 @@ func (s *SqlStore) AcquireReadLockByUID(ctx context.Context, uniqueID string) (u
 	value, _ := s.resourceLocks.LoadOrStore(uniqueID, &sync.RWMutex{})
 	mtx := value.(*sync.RWMutex)
 	mtx.RLock()
+	sharedUnlock := s.acquireSharedLock(ctx, uniqueID, true)
 
 	unlock = func() {
+		sharedUnlock()
 		mtx.RUnlock()
 		log.WithContext(ctx).Tracef("released read lock for ID %s in %v", uniqueID, time.Since(start))
 	}
@@ -979,11 +1027,20 @@ func getGormConfig() *gorm.Config {
 
 // newPostgresStore initializes a new Postgres store.
 func newPostgresStore(ctx context.Context, metrics telemetry.AppMetrics) (Store, error) {
+	dsn, err := GetPostgresDSN()
+	if err != nil {
+		return nil, err
+	}
+	return NewPostgresqlStore(ctx, dsn, metrics)
+}
+
+// GetPostgresDSN returns the DSN of the Postgres store engine database
+func GetPostgresDSN() (string, error) {
 	dsn, ok := os.LookupEnv(postgresDsnEnv)
 	if !ok {
-		return nil, fmt.Errorf("%s is not set", postgresDsnEnv)
+		return "", fmt.Errorf("%s is not set", postgresDsnEnv)
 	}
-	return NewPostgresqlStore(ctx, dsn, metrics)
+	return dsn, nil
 }
 
 // newMysqlStore initializes a new MySQL store.
@@ -1570,6 +1627,61 @@ func (s *SqlStore) DeletePostureChecks(ctx context.Context, lockStrength Locking
 	return nil
 }
 
+// GetAccountCustomRoles retrieves custom roles for an account.
+func (s *SqlStore) GetAccountCustomRoles(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*types.CustomRole, error) {
+	var roles []*types.CustomRole
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).Find(&roles, accountIDCondition, accountID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to get custom roles from store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get custom roles from store")
+	}
+
+	return roles, nil
+}
+
+// GetCustomRoleByID retrieves a custom role by its ID and account ID.
+func (s *SqlStore) GetCustomRoleByID(ctx context.Context, lockStrength LockingStrength, accountID, roleID string) (*types.CustomRole, error) {
+	var role *types.CustomRole
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).
+		First(&role, accountAndIDQueryCondition, accountID, roleID)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, status.NewCustomRoleNotFoundError(roleID)
+		}
+		log.WithContext(ctx).Errorf("failed to get custom role from store: %s", result.Error)
+		return nil, status.Errorf(status.Internal, "failed to get custom role from store")
+	}
+
+	return role, nil
+}
+
+// SaveCustomRole saves a custom role to the database.
+func (s *SqlStore) SaveCustomRole(ctx context.Context, lockStrength LockingStrength, role *types.CustomRole) error {
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).Save(role)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to save custom role to store: %s", result.Error)
+		return status.Errorf(status.Internal, "failed to save custom role to store")
+	}
+
+	return nil
+}
+
+// DeleteCustomRole deletes a custom role from the database.
+func (s *SqlStore) DeleteCustomRole(ctx context.Context, lockStrength LockingStrength, accountID, roleID string) error {
+	result := s.db.Clauses(clause.Locking{Strength: string(lockStrength)}).
+		Delete(&types.CustomRole{}, accountAndIDQueryCondition, accountID, roleID)
+	if result.Error != nil {
+		log.WithContext(ctx).Errorf("failed to delete custom role from store: %s", result.Error)
+		return status.Errorf(status.Internal, "failed to delete custom role from store")
+	}
+
+	if result.RowsAffected == 0 {
+		return status.NewCustomRoleNotFoundError(roleID)
+	}
+
+	return nil
+}
+
 // GetAccountRoutes retrieves network routes for an account.
 func (s *SqlStore) GetAccountRoutes(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*route.Route, error) {
 	return getRecords[*route.Route](s.db, lockStrength, accountID)
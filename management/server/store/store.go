@@ -96,6 +96,11 @@ type Store interface {
 	SavePostureChecks(ctx context.Context, lockStrength LockingStrength, postureCheck *posture.Checks) error
 	DeletePostureChecks(ctx context.Context, lockStrength LockingStrength, accountID, postureChecksID string) error
 
+	GetAccountCustomRoles(ctx context.Context, lockStrength LockingStrength, accountID string) ([]*types.CustomRole, error)
+	GetCustomRoleByID(ctx context.Context, lockStrength LockingStrength, accountID, roleID string) (*types.CustomRole, error)
+	SaveCustomRole(ctx context.Context, lockStrength LockingStrength, role *types.CustomRole) error
+	DeleteCustomRole(ctx context.Context, lockStrength LockingStrength, accountID, roleID string) error
+
 	GetPeerLabelsInAccount(ctx context.Context, lockStrength LockingStrength, accountId string) ([]string, error)
 	AddPeerToAllGroup(ctx context.Context, accountID string, peerID string) error
 	AddPeerToGroup(ctx context.Context, accountId string, peerId string, groupID string) error
@@ -3,6 +3,7 @@ package types
 import (
 	"context"
 	"fmt"
+	"maps"
 	"net"
 	"net/netip"
 	"slices"
@@ -72,6 +73,8 @@ type Account struct {
 	Networks         []*networkTypes.Network          `gorm:"foreignKey:AccountID;references:id"`
 	NetworkRouters   []*routerTypes.NetworkRouter     `gorm:"foreignKey:AccountID;references:id"`
 	NetworkResources []*resourceTypes.NetworkResource `gorm:"foreignKey:AccountID;references:id"`
+
+	CustomRoles []*CustomRole `gorm:"foreignKey:AccountID;references:id"`
 }
 
 // Subclass used in gorm to only load network and not whole account
@@ -286,6 +289,7 @@ func (a *Account) GetPeerNetworkMap(
 		OfflinePeers:        expiredPeers,
 		FirewallRules:       firewallRules,
 		RoutesFirewallRules: slices.Concat(networkResourcesFirewallRules, routesFirewallRules),
+		SSHLocalUsers:       a.GetPeerSSHLocalUsers(ctx, peerID, validatedPeersMap),
 	}
 
 	if metrics != nil {
@@ -517,6 +521,44 @@ func (a *Account) GetNextPeerExpiration() (time.Duration, bool) {
 	return *nextExpiry, true
 }
 
+// GetNextPolicyScheduleTransition returns the minimum duration until a scheduled rule of an enabled policy
+// becomes active or inactive. If there is no scheduled rule that changes its state this function returns false and a duration of 0.
+func (a *Account) GetNextPolicyScheduleTransition(now time.Time) (time.Duration, bool) {
+	var nextTransition *time.Duration
+	for _, policy := range a.Policies {
+		if !policy.Enabled {
+			continue
+		}
+
+		for _, rule := range policy.Rules {
+			if !rule.Enabled || rule.Schedule == nil {
+				continue
+			}
+
+			transition, ok := rule.Schedule.NextTransition(now)
+			if !ok {
+				continue
+			}
+
+			duration := transition.Sub(now)
+			if nextTransition == nil || duration < *nextTransition {
+				nextTransition = &duration
+			}
+		}
+	}
+
+	if nextTransition == nil {
+		return 0, false
+	}
+
+	// avoids issues with ticker that can't be set to < 0
+	if *nextTransition < time.Second {
+		return time.Second, true
+	}
+
+	return *nextTransition, true
+}
+
 // GetPeersWithExpiration returns a list of peers that have Peer.LoginExpirationEnabled set to true and that were added by a user
 func (a *Account) GetPeersWithExpiration() []*nbpeer.Peer {
 	peers := make([]*nbpeer.Peer, 0)
@@ -820,6 +862,11 @@ func (a *Account) Copy() *Account {
 		networkResources = append(networkResources, resource.Copy())
 	}
 
+	customRoles := []*CustomRole{}
+	for _, role := range a.CustomRoles {
+		customRoles = append(customRoles, role.Copy())
+	}
+
 	return &Account{
 		Id:                     a.Id,
 		CreatedBy:              a.CreatedBy,
@@ -841,6 +888,7 @@ func (a *Account) Copy() *Account {
 		Networks:               nets,
 		NetworkRouters:         networkRouters,
 		NetworkResources:       networkResources,
+		CustomRoles:            customRoles,
 	}
 }
 
@@ -853,6 +901,16 @@ func (a *Account) GetGroupAll() (*Group, error) {
 	return nil, fmt.Errorf("no group ALL found")
 }
 
+// GetCustomRole returns the custom role by ID or nil if it doesn't exist
+func (a *Account) GetCustomRole(roleID string) *CustomRole {
+	for _, role := range a.CustomRoles {
+		if role.ID == roleID {
+			return role
+		}
+	}
+	return nil
+}
+
 // GetPeer looks up a Peer by ID
 func (a *Account) GetPeer(peerID string) *nbpeer.Peer {
 	return a.Peers[peerID]
@@ -931,39 +989,93 @@ func (a *Account) UserGroupsRemoveFromPeers(userID string, groups ...string) map
 // This function returns the list of peers and firewall rules that are applicable to a given peer.
 func (a *Account) GetPeerConnectionResources(ctx context.Context, peerID string, validatedPeersMap map[string]struct{}) ([]*nbpeer.Peer, []*FirewallRule) {
 	generateResources, getAccumulatedResources := a.connResourcesGenerator(ctx)
+	now := time.Now()
 	for _, policy := range a.Policies {
 		if !policy.Enabled {
 			continue
 		}
 
 		for _, rule := range policy.Rules {
-			if !rule.Enabled {
+			if !rule.IsActive(now) {
 				continue
 			}
 
-			sourcePeers, peerInSources := a.getAllPeersFromGroups(ctx, rule.Sources, peerID, policy.SourcePostureChecks, validatedPeersMap)
-			destinationPeers, peerInDestinations := a.getAllPeersFromGroups(ctx, rule.Destinations, peerID, nil, validatedPeersMap)
+			a.generateRuleConnectionResources(ctx, rule, policy.SourcePostureChecks, policy.DestinationPostureChecks, peerID, validatedPeersMap, generateResources)
+		}
+	}
 
-			if rule.Bidirectional {
-				if peerInSources {
-					generateResources(rule, destinationPeers, FirewallRuleDirectionIN)
-				}
-				if peerInDestinations {
-					generateResources(rule, sourcePeers, FirewallRuleDirectionOUT)
-				}
-			}
+	return getAccumulatedResources()
+}
+
+// GetPeerSSHLocalUsers returns the local accounts the peers connecting to the given peer may log in as on its SSH server,
+// keyed by peer ID. A peer is restricted to the accounts mapped by its accept rules as soon as any of them maps local
+// users, the rules without local users don't lift the restriction. Peers without mapped users are left out as they
+// may log in as any account.
+func (a *Account) GetPeerSSHLocalUsers(ctx context.Context, peerID string, validatedPeersMap map[string]struct{}) map[string][]string {
+	localUsers := make(map[string]map[string]struct{})
 
-			if peerInSources {
-				generateResources(rule, destinationPeers, FirewallRuleDirectionOUT)
+	collect := func(rule *PolicyRule, groupPeers []*nbpeer.Peer, direction int) {
+		if direction != FirewallRuleDirectionIN || rule.Action != PolicyTrafficActionAccept || len(rule.SSHLocalUsers) == 0 {
+			return
+		}
+		for _, peer := range groupPeers {
+			if peer == nil {
+				continue
+			}
+			if localUsers[peer.ID] == nil {
+				localUsers[peer.ID] = make(map[string]struct{})
+			}
+			for _, localUser := range rule.SSHLocalUsers {
+				localUsers[peer.ID][localUser] = struct{}{}
 			}
+		}
+	}
+
+	now := time.Now()
+	for _, policy := range a.Policies {
+		if !policy.Enabled {
+			continue
+		}
 
-			if peerInDestinations {
-				generateResources(rule, sourcePeers, FirewallRuleDirectionIN)
+		for _, rule := range policy.Rules {
+			if !rule.IsActive(now) {
+				continue
 			}
+
+			a.generateRuleConnectionResources(ctx, rule, policy.SourcePostureChecks, policy.DestinationPostureChecks, peerID, validatedPeersMap, collect)
 		}
 	}
 
-	return getAccumulatedResources()
+	result := make(map[string][]string, len(localUsers))
+	for id, users := range localUsers {
+		result[id] = slices.Sorted(maps.Keys(users))
+	}
+
+	return result
+}
+
+// generateRuleConnectionResources expands a single policy rule into the peers and firewall rules applicable to a given peer.
+// Source and destination peers failing their posture checks are left out, the same applies to the given peer itself.
+func (a *Account) generateRuleConnectionResources(ctx context.Context, rule *PolicyRule, sourcePostureChecks, destinationPostureChecks []string, peerID string, validatedPeersMap map[string]struct{}, generateResources func(*PolicyRule, []*nbpeer.Peer, int)) {
+	sourcePeers, peerInSources := a.getAllPeersFromGroups(ctx, rule.Sources, peerID, sourcePostureChecks, validatedPeersMap)
+	destinationPeers, peerInDestinations := a.getAllPeersFromGroups(ctx, rule.Destinations, peerID, destinationPostureChecks, validatedPeersMap)
+
+	if rule.Bidirectional {
+		if peerInSources {
+			generateResources(rule, destinationPeers, FirewallRuleDirectionIN)
+		}
+		if peerInDestinations {
+			generateResources(rule, sourcePeers, FirewallRuleDirectionOUT)
+		}
+	}
+
+	if peerInSources {
+		generateResources(rule, destinationPeers, FirewallRuleDirectionOUT)
+	}
+
+	if peerInDestinations {
+		generateResources(rule, sourcePeers, FirewallRuleDirectionIN)
+	}
 }
 
 // connResourcesGenerator returns generator and accumulator function which returns the result of generator calls
@@ -996,6 +1108,7 @@ func (a *Account) connResourcesGenerator(ctx context.Context) (func(*PolicyRule,
 				}
 
 				fr := FirewallRule{
+					PolicyID:  rule.PolicyID,
 					PeerIP:    peer.IP.String(),
 					Direction: direction,
 					Action:    string(rule.Action),
@@ -1007,13 +1120,13 @@ func (a *Account) connResourcesGenerator(ctx context.Context) (func(*PolicyRule,
 				}
 
 				ruleID := rule.ID + fr.PeerIP + strconv.Itoa(direction) +
-					fr.Protocol + fr.Action + strings.Join(rule.Ports, ",")
+					fr.Protocol + fr.Action + strings.Join(rule.Ports, ",") + portRangesKey(rule.PortRanges)
 				if _, ok := rulesExists[ruleID]; ok {
 					continue
 				}
 				rulesExists[ruleID] = struct{}{}
 
-				if len(rule.Ports) == 0 {
+				if len(rule.Ports) == 0 && len(rule.PortRanges) == 0 {
 					rules = append(rules, &fr)
 					continue
 				}
@@ -1023,20 +1136,36 @@ func (a *Account) connResourcesGenerator(ctx context.Context) (func(*PolicyRule,
 					pr.Port = port
 					rules = append(rules, &pr)
 				}
+
+				for _, portRange := range rule.PortRanges {
+					pr := fr // clone rule and set the port range, the range is not expanded into single ports
+					pr.PortRange = portRange
+					rules = append(rules, &pr)
+				}
 			}
 		}, func() ([]*nbpeer.Peer, []*FirewallRule) {
 			return peers, rules
 		}
 }
 
+// portRangesKey returns the port ranges in a form suitable for rule deduplication keys
+func portRangesKey(portRanges []RulePortRange) string {
+	if len(portRanges) == 0 {
+		return ""
+	}
+
+	ranges := make([]string, 0, len(portRanges))
+	for _, portRange := range portRanges {
+		ranges = append(ranges, fmt.Sprintf("%d-%d", portRange.Start, portRange.End))
+	}
+	return ":" + strings.Join(ranges, ",")
+}
+
 // getAllPeersFromGroups for given peer ID and list of groups
 //
 // Returns a list of peers from specified groups that pass specified posture checks
-// and a boolean indicating if the supplied peer ID exists within these groups.
-//
-// Important: Posture checks are applicable only to source group peers,
-// for destination group peers, call this method with an empty list of sourcePostureChecksIDs
-func (a *Account) getAllPeersFromGroups(ctx context.Context, groups []string, peerID string, sourcePostureChecksIDs []string, validatedPeersMap map[string]struct{}) ([]*nbpeer.Peer, bool) {
+// and a boolean indicating if the supplied peer ID exists within these groups and passes the posture checks.
+func (a *Account) getAllPeersFromGroups(ctx context.Context, groups []string, peerID string, postureChecksIDs []string, validatedPeersMap map[string]struct{}) ([]*nbpeer.Peer, bool) {
 	peerInGroups := false
 	uniquePeerIDs := a.getUniquePeerIDsFromGroupsIDs(ctx, groups)
 	filteredPeers := make([]*nbpeer.Peer, 0, len(uniquePeerIDs))
@@ -1047,7 +1176,7 @@ func (a *Account) getAllPeersFromGroups(ctx context.Context, groups []string, pe
 		}
 
 		// validate the peer based on policy posture checks applied
-		isValid := a.validatePostureChecksOnPeer(ctx, sourcePostureChecksIDs, peer.ID)
+		isValid := a.validatePostureChecksOnPeer(ctx, postureChecksIDs, peer.ID)
 		if !isValid {
 			continue
 		}
@@ -1129,24 +1258,30 @@ func (a *Account) GetPeerRoutesFirewallRules(ctx context.Context, peerID string,
 
 func (a *Account) getRouteFirewallRules(ctx context.Context, peerID string, policies []*Policy, route *route.Route, validatedPeersMap map[string]struct{}, distributionPeers map[string]struct{}) []*RouteFirewallRule {
 	var fwRules []*RouteFirewallRule
+	now := time.Now()
 	for _, policy := range policies {
 		if !policy.Enabled {
 			continue
 		}
 
 		for _, rule := range policy.Rules {
-			if !rule.Enabled {
+			if !rule.IsActive(now) {
 				continue
 			}
 
-			rulePeers := a.getRulePeers(rule, policy.SourcePostureChecks, peerID, distributionPeers, validatedPeersMap)
-			rules := generateRouteFirewallRules(ctx, route, rule, rulePeers, FirewallRuleDirectionIN)
+			rules := a.getRuleRouteFirewallRules(ctx, rule, policy.SourcePostureChecks, peerID, route, validatedPeersMap, distributionPeers)
 			fwRules = append(fwRules, rules...)
 		}
 	}
 	return fwRules
 }
 
+// getRuleRouteFirewallRules expands a single policy rule into the firewall rules of a route served by the given routing peer
+func (a *Account) getRuleRouteFirewallRules(ctx context.Context, rule *PolicyRule, sourcePostureChecks []string, peerID string, route *route.Route, validatedPeersMap map[string]struct{}, distributionPeers map[string]struct{}) []*RouteFirewallRule {
+	rulePeers := a.getRulePeers(rule, sourcePostureChecks, peerID, distributionPeers, validatedPeersMap)
+	return generateRouteFirewallRules(ctx, route, rule, rulePeers, FirewallRuleDirectionIN)
+}
+
 func (a *Account) getRulePeers(rule *PolicyRule, postureChecks []string, peerID string, distributionPeers map[string]struct{}, validatedPeersMap map[string]struct{}) []*nbpeer.Peer {
 	distPeersWithPolicy := make(map[string]struct{})
 	for _, id := range rule.Sources {
@@ -1396,6 +1531,7 @@ func (a *Account) GetPoliciesForNetworkResource(resourceId string) []*Policy {
 	var resourceAppliedPolicies []*Policy
 
 	networkResourceGroups := a.getNetworkResourceGroups(resourceId)
+	now := time.Now()
 
 	for _, policy := range a.Policies {
 		if !policy.Enabled {
@@ -1403,7 +1539,7 @@ func (a *Account) GetPoliciesForNetworkResource(resourceId string) []*Policy {
 		}
 
 		for _, rule := range policy.Rules {
-			if !rule.Enabled {
+			if !rule.IsActive(now) {
 				continue
 			}
 
@@ -19,6 +19,9 @@ const (
 
 // FirewallRule is a rule of the firewall.
 type FirewallRule struct {
+	// PolicyID is the ID of the policy that this rule belongs to
+	PolicyID string
+
 	// PeerIP of the peer
 	PeerIP string
 
@@ -33,15 +36,20 @@ type FirewallRule struct {
 
 	// Port of the traffic
 	Port string
+
+	// PortRange of the traffic, used instead of Port when set
+	PortRange RulePortRange
 }
 
 // IsEqual checks if two firewall rules are equal.
 func (r *FirewallRule) IsEqual(other *FirewallRule) bool {
-	return r.PeerIP == other.PeerIP &&
+	return r.PolicyID == other.PolicyID &&
+		r.PeerIP == other.PeerIP &&
 		r.Direction == other.Direction &&
 		r.Action == other.Action &&
 		r.Protocol == other.Protocol &&
-		r.Port == other.Port
+		r.Port == other.Port &&
+		r.PortRange == other.PortRange
 }
 
 // generateRouteFirewallRules generates a list of firewall rules for a given route.
@@ -33,6 +33,9 @@ type NetworkMap struct {
 	OfflinePeers        []*nbpeer.Peer
 	FirewallRules       []*FirewallRule
 	RoutesFirewallRules []*RouteFirewallRule
+	// SSHLocalUsers are the local accounts the peers may log in as on the SSH server of the peer, keyed by peer ID.
+	// Peers without an entry may log in as any account.
+	SSHLocalUsers map[string][]string
 }
 
 type Network struct {
@@ -1,5 +1,7 @@
 package types
 
+import "slices"
+
 const (
 	// PolicyTrafficActionAccept indicates that the traffic is accepted
 	PolicyTrafficActionAccept = PolicyTrafficActionType("accept")
@@ -64,23 +66,28 @@ type Policy struct {
 
 	// SourcePostureChecks are ID references to Posture checks for policy source groups
 	SourcePostureChecks []string `gorm:"serializer:json"`
+
+	// DestinationPostureChecks are ID references to Posture checks for policy destination groups
+	DestinationPostureChecks []string `gorm:"serializer:json"`
 }
 
 // Copy returns a copy of the policy.
 func (p *Policy) Copy() *Policy {
 	c := &Policy{
-		ID:                  p.ID,
-		AccountID:           p.AccountID,
-		Name:                p.Name,
-		Description:         p.Description,
-		Enabled:             p.Enabled,
-		Rules:               make([]*PolicyRule, len(p.Rules)),
-		SourcePostureChecks: make([]string, len(p.SourcePostureChecks)),
+		ID:                       p.ID,
+		AccountID:                p.AccountID,
+		Name:                     p.Name,
+		Description:              p.Description,
+		Enabled:                  p.Enabled,
+		Rules:                    make([]*PolicyRule, len(p.Rules)),
+		SourcePostureChecks:      make([]string, len(p.SourcePostureChecks)),
+		DestinationPostureChecks: make([]string, len(p.DestinationPostureChecks)),
 	}
 	for i, r := range p.Rules {
 		c.Rules[i] = r.Copy()
 	}
 	copy(c.SourcePostureChecks, p.SourcePostureChecks)
+	copy(c.DestinationPostureChecks, p.DestinationPostureChecks)
 	return c
 }
 
@@ -115,6 +122,12 @@ func (p *Policy) RuleGroups() []string {
 	return groups
 }
 
+// PostureChecks returns a list of all posture checks referenced in the policy,
+// including the source and the destination posture checks.
+func (p *Policy) PostureChecks() []string {
+	return slices.Concat(p.SourcePostureChecks, p.DestinationPostureChecks)
+}
+
 // SourceGroups returns a slice of all unique source groups referenced in the policy's rules.
 func (p *Policy) SourceGroups() []string {
 	if len(p.Rules) == 1 {
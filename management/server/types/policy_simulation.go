@@ -0,0 +1,440 @@
+package types
+
+import (
+	"context"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	resourceTypes "github.com/netbirdio/netbird/management/server/networks/resources/types"
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/status"
+	"github.com/netbirdio/netbird/route"
+)
+
+const allPeersIP = "0.0.0.0"
+
+// PolicySimulationRequest describes the traffic evaluated by Account.SimulatePolicies.
+// Exactly one of the destination fields must be set.
+type PolicySimulationRequest struct {
+	SourcePeerID string
+
+	DestinationPeerID     string
+	DestinationResourceID string
+	DestinationIP         netip.Addr
+	DestinationDomain     string
+
+	Protocol PolicyRuleProtocolType
+	// Port of the traffic. Ignored for the ALL and ICMP protocols.
+	Port uint16
+}
+
+// PolicySimulationResult is the outcome of a policy simulation
+type PolicySimulationResult struct {
+	// Allowed is true if at least one applied rule accepts the traffic and no applied rule drops it
+	Allowed bool
+
+	// Matches lists the rules that matched the traffic, including the ones not applied because of failed posture checks
+	Matches []*PolicySimulationMatch
+}
+
+// PolicySimulationMatch is a policy rule or a route that matched the simulated traffic
+type PolicySimulationMatch struct {
+	PolicyID   string
+	PolicyName string
+	RuleID     string
+	RuleName   string
+	Action     PolicyTrafficActionType
+
+	// Applied is false when the source peer failed the policy source posture checks
+	// or the destination peer failed the policy destination posture checks
+	Applied bool
+
+	// PostureChecks are the source posture checks of the policy
+	PostureChecks []string
+
+	// FailedPostureChecks are the source posture checks the source peer didn't pass
+	FailedPostureChecks []string
+
+	// DestinationPostureChecks are the destination posture checks of the policy
+	DestinationPostureChecks []string
+
+	// FailedDestinationPostureChecks are the destination posture checks the destination peer didn't pass
+	FailedDestinationPostureChecks []string
+
+	// RouteID and RoutingPeerID are set when the destination is reached through a route or a network resource
+	RouteID       route.ID
+	RoutingPeerID string
+
+	// SourceFirewallRules are the firewall rules of the source peer generated by the rule
+	SourceFirewallRules []*FirewallRule
+
+	// DestinationFirewallRules are the firewall rules of the destination peer generated by the rule
+	DestinationFirewallRules []*FirewallRule
+
+	// RouteFirewallRules are the firewall rules of the routing peer generated by the rule
+	RouteFirewallRules []*RouteFirewallRule
+}
+
+// SimulatePolicies evaluates whether the account policies allow the traffic described by the request.
+// It uses the same rule expansion as the network map calculation so that the result matches the rules clients receive.
+func (a *Account) SimulatePolicies(ctx context.Context, dnsDomain string, validatedPeersMap map[string]struct{}, req *PolicySimulationRequest) (*PolicySimulationResult, error) {
+	source := a.GetPeer(req.SourcePeerID)
+	if source == nil {
+		return nil, status.NewPeerNotFoundError(req.SourcePeerID)
+	}
+
+	switch req.Protocol {
+	case PolicyRuleProtocolALL, PolicyRuleProtocolICMP:
+		req.Port = 0
+	case PolicyRuleProtocolTCP, PolicyRuleProtocolUDP:
+	default:
+		return nil, status.Errorf(status.InvalidArgument, "unknown protocol type: %s", req.Protocol)
+	}
+
+	result := &PolicySimulationResult{}
+	if _, ok := validatedPeersMap[source.ID]; !ok {
+		return result, nil
+	}
+
+	destination, err := a.getSimulationDestinationPeer(dnsDomain, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if destination != nil {
+		a.simulatePeerTraffic(ctx, source, destination, validatedPeersMap, req, result)
+	} else {
+		a.simulateRoutedTraffic(ctx, source, validatedPeersMap, req, result)
+	}
+
+	result.Allowed = isSimulationAllowed(result.Matches)
+
+	return result, nil
+}
+
+// getSimulationDestinationPeer returns the destination peer of the request or nil if the destination isn't a peer
+func (a *Account) getSimulationDestinationPeer(dnsDomain string, req *PolicySimulationRequest) (*nbpeer.Peer, error) {
+	switch {
+	case req.DestinationPeerID != "":
+		peer := a.GetPeer(req.DestinationPeerID)
+		if peer == nil {
+			return nil, status.NewPeerNotFoundError(req.DestinationPeerID)
+		}
+		return peer, nil
+	case req.DestinationResourceID != "":
+		if !slices.ContainsFunc(a.NetworkResources, func(r *resourceTypes.NetworkResource) bool { return r.ID == req.DestinationResourceID }) {
+			return nil, status.Errorf(status.NotFound, "network resource %s not found", req.DestinationResourceID)
+		}
+		return nil, nil
+	case req.DestinationIP.IsValid():
+		for _, peer := range a.Peers {
+			if ip, ok := netip.AddrFromSlice(peer.IP); ok && ip.Unmap() == req.DestinationIP.Unmap() {
+				return peer, nil
+			}
+		}
+		return nil, nil
+	case req.DestinationDomain != "":
+		name := strings.ToLower(strings.TrimSuffix(req.DestinationDomain, "."))
+		for _, peer := range a.Peers {
+			if peer.DNSLabel != "" && dnsDomain != "" && name == peer.DNSLabel+"."+dnsDomain {
+				return peer, nil
+			}
+		}
+		return nil, nil
+	default:
+		return nil, status.Errorf(status.InvalidArgument, "destination peer, network resource, IP or domain is required")
+	}
+}
+
+// simulatePeerTraffic evaluates the rules between two peers. The traffic is decided by the inbound rules
+// of the destination peer, the outbound rules of the source peer are reported for reference.
+func (a *Account) simulatePeerTraffic(ctx context.Context, source, destination *nbpeer.Peer, validatedPeersMap map[string]struct{}, req *PolicySimulationRequest, result *PolicySimulationResult) {
+	if _, ok := validatedPeersMap[destination.ID]; !ok {
+		return
+	}
+
+	sourceIP := source.IP.String()
+	destinationIP := destination.IP.String()
+
+	a.forEachActiveRule(func(policy *Policy, rule *PolicyRule) {
+		expand := func(peerID, peerIP string, direction int, sourcePostureChecks, destinationPostureChecks []string) []*FirewallRule {
+			generateResources, getAccumulatedResources := a.connResourcesGenerator(ctx)
+			a.generateRuleConnectionResources(ctx, rule, sourcePostureChecks, destinationPostureChecks, peerID, validatedPeersMap, generateResources)
+			_, rules := getAccumulatedResources()
+			return filterSimulatedFirewallRules(rules, peerIP, direction, req)
+		}
+
+		match := newPolicySimulationMatch(policy, rule)
+		match.DestinationFirewallRules = expand(destination.ID, sourceIP, FirewallRuleDirectionIN, policy.SourcePostureChecks, policy.DestinationPostureChecks)
+		if len(match.DestinationFirewallRules) > 0 {
+			match.Applied = true
+			match.SourceFirewallRules = expand(source.ID, destinationIP, FirewallRuleDirectionOUT, policy.SourcePostureChecks, policy.DestinationPostureChecks)
+			result.Matches = append(result.Matches, match)
+			return
+		}
+
+		match.FailedPostureChecks = a.getFailedPostureChecks(ctx, policy.SourcePostureChecks, source)
+		match.FailedDestinationPostureChecks = a.getFailedPostureChecks(ctx, policy.DestinationPostureChecks, destination)
+		if len(match.FailedPostureChecks) == 0 && len(match.FailedDestinationPostureChecks) == 0 {
+			return
+		}
+
+		match.DestinationFirewallRules = expand(destination.ID, sourceIP, FirewallRuleDirectionIN, nil, nil)
+		if len(match.DestinationFirewallRules) > 0 {
+			result.Matches = append(result.Matches, match)
+		}
+	})
+}
+
+// simulateRoutedTraffic evaluates the routes and network resources distributed to the source peer
+// against the firewall rules of their routing peers. Routes not distributed to the source peer are not evaluated.
+func (a *Account) simulateRoutedTraffic(ctx context.Context, source *nbpeer.Peer, validatedPeersMap map[string]struct{}, req *PolicySimulationRequest, result *PolicySimulationResult) {
+	aclPeers, _ := a.GetPeerConnectionResources(ctx, source.ID, validatedPeersMap)
+	var peersToConnect []*nbpeer.Peer
+	for _, p := range aclPeers {
+		expired, _ := p.LoginExpired(a.Settings.PeerLoginExpiration)
+		if a.Settings.PeerLoginExpirationEnabled && expired {
+			continue
+		}
+		peersToConnect = append(peersToConnect, p)
+	}
+
+	resourcePolicies := a.GetResourcePoliciesMap()
+	routes := a.GetRoutesToSync(ctx, source.ID, peersToConnect)
+	_, resourceRoutes, _ := a.GetNetworkResourcesRoutesToSync(ctx, source.ID, resourcePolicies, a.GetResourceRoutersMap())
+
+	sourceIP, _ := netip.AddrFromSlice(source.IP)
+
+	for _, r := range slices.Concat(resourceRoutes, routes) {
+		if !isSimulationRouteMatching(r, req) {
+			continue
+		}
+
+		routingPeer := a.getRoutingPeer(r)
+		if routingPeer == nil {
+			continue
+		}
+
+		var policies []*Policy
+		var distributionPeers map[string]struct{}
+		if accountRoute, ok := a.Routes[route.ID(r.GetResourceID())]; ok {
+			if len(accountRoute.AccessControlGroups) == 0 {
+				result.Matches = append(result.Matches, &PolicySimulationMatch{
+					Action:             PolicyTrafficActionAccept,
+					Applied:            true,
+					RouteID:            accountRoute.ID,
+					RoutingPeerID:      routingPeer.ID,
+					RouteFirewallRules: getDefaultPermit(accountRoute),
+				})
+				continue
+			}
+			policies = GetAllRoutePoliciesFromGroups(a, accountRoute.AccessControlGroups)
+			distributionPeers = a.getDistributionGroupsPeers(accountRoute)
+		} else {
+			policies = resourcePolicies[r.GetResourceID()]
+			distributionPeers = getPoliciesSourcePeers(policies, a.Groups)
+		}
+
+		routeID := route.ID(r.GetResourceID())
+		a.forEachActiveRule(func(policy *Policy, rule *PolicyRule) {
+			if !slices.Contains(policies, policy) {
+				return
+			}
+
+			expand := func(postureChecks []string) []*RouteFirewallRule {
+				rules := a.getRuleRouteFirewallRules(ctx, rule, postureChecks, routingPeer.ID, r, validatedPeersMap, distributionPeers)
+				return filterSimulatedRouteFirewallRules(rules, sourceIP, req)
+			}
+
+			match := newPolicySimulationMatch(policy, rule)
+			match.RouteID = routeID
+			match.RoutingPeerID = routingPeer.ID
+			match.RouteFirewallRules = expand(policy.SourcePostureChecks)
+			if len(match.RouteFirewallRules) > 0 {
+				match.Applied = true
+				result.Matches = append(result.Matches, match)
+				return
+			}
+
+			match.FailedPostureChecks = a.getFailedPostureChecks(ctx, policy.SourcePostureChecks, source)
+			if len(match.FailedPostureChecks) == 0 {
+				return
+			}
+
+			match.RouteFirewallRules = expand(nil)
+			if len(match.RouteFirewallRules) > 0 {
+				result.Matches = append(result.Matches, match)
+			}
+		})
+	}
+}
+
+// forEachActiveRule calls f for every currently applied rule of the enabled policies
+func (a *Account) forEachActiveRule(f func(policy *Policy, rule *PolicyRule)) {
+	now := time.Now()
+	for _, policy := range a.Policies {
+		if !policy.Enabled {
+			continue
+		}
+		for _, rule := range policy.Rules {
+			if rule.IsActive(now) {
+				f(policy, rule)
+			}
+		}
+	}
+}
+
+// getFailedPostureChecks returns the IDs of the posture checks the peer doesn't pass
+func (a *Account) getFailedPostureChecks(ctx context.Context, postureChecksIDs []string, peer *nbpeer.Peer) []string {
+	var failed []string
+	for _, postureChecksID := range postureChecksIDs {
+		if !a.validatePostureChecksOnPeer(ctx, []string{postureChecksID}, peer.ID) {
+			failed = append(failed, postureChecksID)
+		}
+	}
+	return failed
+}
+
+// getRoutingPeer returns the routing peer of a route distributed in a network map
+func (a *Account) getRoutingPeer(r *route.Route) *nbpeer.Peer {
+	if r.PeerID != "" {
+		if peer := a.GetPeer(r.PeerID); peer != nil {
+			return peer
+		}
+	}
+
+	peer, err := a.FindPeerByPubKey(r.Peer)
+	if err != nil {
+		return nil
+	}
+	return peer
+}
+
+func newPolicySimulationMatch(policy *Policy, rule *PolicyRule) *PolicySimulationMatch {
+	return &PolicySimulationMatch{
+		PolicyID:                 policy.ID,
+		PolicyName:               policy.Name,
+		RuleID:                   rule.ID,
+		RuleName:                 rule.Name,
+		Action:                   rule.Action,
+		PostureChecks:            policy.SourcePostureChecks,
+		DestinationPostureChecks: policy.DestinationPostureChecks,
+	}
+}
+
+func isSimulationAllowed(matches []*PolicySimulationMatch) bool {
+	var accepted bool
+	for _, match := range matches {
+		if !match.Applied {
+			continue
+		}
+		if match.Action == PolicyTrafficActionDrop {
+			return false
+		}
+		accepted = true
+	}
+	return accepted
+}
+
+func isSimulationRouteMatching(r *route.Route, req *PolicySimulationRequest) bool {
+	switch {
+	case req.DestinationResourceID != "":
+		return r.GetResourceID() == req.DestinationResourceID
+	case req.DestinationIP.IsValid():
+		return !r.IsDynamic() && r.Network.Contains(req.DestinationIP.Unmap())
+	case req.DestinationDomain != "":
+		if !r.IsDynamic() {
+			return false
+		}
+		name := strings.ToLower(strings.TrimSuffix(req.DestinationDomain, "."))
+		for _, d := range r.Domains {
+			if isDomainMatching(strings.ToLower(string(d)), name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isDomainMatching checks if the name matches the pattern, which can be a wildcard domain
+func isDomainMatching(pattern, name string) bool {
+	if strings.HasPrefix(pattern, "*.") {
+		return strings.HasSuffix(name, pattern[1:])
+	}
+	return pattern == name
+}
+
+func isSimulationProtocolMatching(ruleProtocol string, req *PolicySimulationRequest) bool {
+	return ruleProtocol == string(PolicyRuleProtocolALL) || ruleProtocol == string(req.Protocol)
+}
+
+// isSimulationPortFiltered returns true if the ports of a rule with the given protocol restrict the traffic.
+// Clients ignore the ports of rules matching all protocols or ICMP.
+func isSimulationPortFiltered(ruleProtocol string) bool {
+	return ruleProtocol == string(PolicyRuleProtocolTCP) || ruleProtocol == string(PolicyRuleProtocolUDP)
+}
+
+func filterSimulatedFirewallRules(rules []*FirewallRule, peerIP string, direction int, req *PolicySimulationRequest) []*FirewallRule {
+	var matched []*FirewallRule
+	for _, rule := range rules {
+		if rule.Direction != direction {
+			continue
+		}
+		if rule.PeerIP != allPeersIP && rule.PeerIP != peerIP {
+			continue
+		}
+		if !isSimulationProtocolMatching(rule.Protocol, req) {
+			continue
+		}
+		if isSimulationPortFiltered(rule.Protocol) {
+			if rule.Port != "" && rule.Port != strconv.Itoa(int(req.Port)) {
+				continue
+			}
+			if rule.PortRange.Start != 0 && (req.Port < rule.PortRange.Start || req.Port > rule.PortRange.End) {
+				continue
+			}
+		}
+		matched = append(matched, rule)
+	}
+	return matched
+}
+
+func filterSimulatedRouteFirewallRules(rules []*RouteFirewallRule, sourceIP netip.Addr, req *PolicySimulationRequest) []*RouteFirewallRule {
+	var matched []*RouteFirewallRule
+	for _, rule := range rules {
+		if !isSourceInRanges(sourceIP, rule.SourceRanges) {
+			continue
+		}
+		if !isSimulationProtocolMatching(rule.Protocol, req) {
+			continue
+		}
+		if !isSimulationPortFiltered(rule.Protocol) {
+			matched = append(matched, rule)
+			continue
+		}
+		if rule.Port != 0 && rule.Port != req.Port {
+			continue
+		}
+		if rule.PortRange.Start != 0 && (req.Port < rule.PortRange.Start || req.Port > rule.PortRange.End) {
+			continue
+		}
+		matched = append(matched, rule)
+	}
+	return matched
+}
+
+func isSourceInRanges(ip netip.Addr, ranges []string) bool {
+	for _, r := range ranges {
+		prefix, err := netip.ParsePrefix(r)
+		if err != nil {
+			continue
+		}
+		if prefix.Contains(ip.Unmap()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,239 @@
+package types
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/posture"
+)
+
+func getPolicySimulationAccount() *Account {
+	return &Account{
+		Id:       "accountID",
+		Settings: &Settings{},
+		Peers: map[string]*nbpeer.Peer{
+			"peerA": {ID: "peerA", Key: "peerAKey", IP: net.IP{100, 64, 0, 1}, DNSLabel: "peer-a", Meta: nbpeer.PeerSystemMeta{WtVersion: "0.35.1"}},
+			"peerB": {ID: "peerB", Key: "peerBKey", IP: net.IP{100, 64, 0, 2}, DNSLabel: "peer-b", Meta: nbpeer.PeerSystemMeta{WtVersion: "0.35.1"}},
+			"peerC": {ID: "peerC", Key: "peerCKey", IP: net.IP{100, 64, 0, 3}, DNSLabel: "peer-c", Meta: nbpeer.PeerSystemMeta{WtVersion: "0.30.0"}},
+		},
+		Groups: map[string]*Group{
+			"clients": {ID: "clients", Name: "clients", Peers: []string{"peerA", "peerC"}},
+			"servers": {ID: "servers", Name: "servers", Peers: []string{"peerB"}},
+		},
+		Policies: []*Policy{
+			{
+				ID:      "web",
+				Name:    "Web access",
+				Enabled: true,
+				Rules: []*PolicyRule{
+					{
+						ID:            "web-rule",
+						Name:          "HTTPS",
+						Enabled:       true,
+						Sources:       []string{"clients"},
+						Destinations:  []string{"servers"},
+						Protocol:      PolicyRuleProtocolTCP,
+						Ports:         []string{"443"},
+						Action:        PolicyTrafficActionAccept,
+						Bidirectional: false,
+					},
+				},
+				SourcePostureChecks: []string{"minVersion"},
+			},
+		},
+		PostureChecks: []*posture.Checks{
+			{
+				ID:   "minVersion",
+				Name: "minVersion",
+				Checks: posture.ChecksDefinition{
+					NBVersionCheck: &posture.NBVersionCheck{MinVersion: "0.35.0"},
+				},
+			},
+		},
+	}
+}
+
+func TestAccount_SimulatePolicies_Peers(t *testing.T) {
+	validatedPeers := map[string]struct{}{"peerA": {}, "peerB": {}, "peerC": {}}
+
+	tests := []struct {
+		name            string
+		request         PolicySimulationRequest
+		expectedAllowed bool
+		expectedMatches int
+		expectedFailed  []string
+	}{
+		{
+			name:            "allowed port",
+			request:         PolicySimulationRequest{SourcePeerID: "peerA", DestinationPeerID: "peerB", Protocol: PolicyRuleProtocolTCP, Port: 443},
+			expectedAllowed: true,
+			expectedMatches: 1,
+		},
+		{
+			name:            "destination by IP",
+			request:         PolicySimulationRequest{SourcePeerID: "peerA", DestinationIP: netip.MustParseAddr("100.64.0.2"), Protocol: PolicyRuleProtocolTCP, Port: 443},
+			expectedAllowed: true,
+			expectedMatches: 1,
+		},
+		{
+			name:            "destination by domain",
+			request:         PolicySimulationRequest{SourcePeerID: "peerA", DestinationDomain: "peer-b.netbird.cloud.", Protocol: PolicyRuleProtocolTCP, Port: 443},
+			expectedAllowed: true,
+			expectedMatches: 1,
+		},
+		{
+			name:    "other port",
+			request: PolicySimulationRequest{SourcePeerID: "peerA", DestinationPeerID: "peerB", Protocol: PolicyRuleProtocolTCP, Port: 22},
+		},
+		{
+			name:    "other protocol",
+			request: PolicySimulationRequest{SourcePeerID: "peerA", DestinationPeerID: "peerB", Protocol: PolicyRuleProtocolUDP, Port: 443},
+		},
+		{
+			name:    "rule is not bidirectional",
+			request: PolicySimulationRequest{SourcePeerID: "peerB", DestinationPeerID: "peerA", Protocol: PolicyRuleProtocolTCP, Port: 443},
+		},
+		{
+			name:            "failed posture checks",
+			request:         PolicySimulationRequest{SourcePeerID: "peerC", DestinationPeerID: "peerB", Protocol: PolicyRuleProtocolTCP, Port: 443},
+			expectedMatches: 1,
+			expectedFailed:  []string{"minVersion"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			account := getPolicySimulationAccount()
+			result, err := account.SimulatePolicies(context.Background(), "netbird.cloud", validatedPeers, &tc.request)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedAllowed, result.Allowed)
+			require.Len(t, result.Matches, tc.expectedMatches)
+			if tc.expectedMatches == 0 {
+				return
+			}
+
+			match := result.Matches[0]
+			assert.Equal(t, "web", match.PolicyID)
+			assert.Equal(t, "web-rule", match.RuleID)
+			assert.Equal(t, tc.expectedFailed, match.FailedPostureChecks)
+			assert.Equal(t, len(tc.expectedFailed) == 0, match.Applied)
+			require.Len(t, match.DestinationFirewallRules, 1)
+			assert.Equal(t, account.Peers[tc.request.SourcePeerID].IP.String(), match.DestinationFirewallRules[0].PeerIP)
+		})
+	}
+}
+
+func TestAccount_SimulatePolicies_PortRanges(t *testing.T) {
+	validatedPeers := map[string]struct{}{"peerA": {}, "peerB": {}, "peerC": {}}
+
+	account := getPolicySimulationAccount()
+	account.Policies[0].Rules[0].Ports = nil
+	account.Policies[0].Rules[0].PortRanges = []RulePortRange{{Start: 8000, End: 9000}}
+
+	request := PolicySimulationRequest{SourcePeerID: "peerA", DestinationPeerID: "peerB", Protocol: PolicyRuleProtocolTCP, Port: 8500}
+	result, err := account.SimulatePolicies(context.Background(), "netbird.cloud", validatedPeers, &request)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	require.Len(t, result.Matches, 1)
+	require.Len(t, result.Matches[0].DestinationFirewallRules, 1)
+	assert.Equal(t, RulePortRange{Start: 8000, End: 9000}, result.Matches[0].DestinationFirewallRules[0].PortRange)
+
+	request.Port = 9001
+	result, err = account.SimulatePolicies(context.Background(), "netbird.cloud", validatedPeers, &request)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Empty(t, result.Matches)
+}
+func TestAccount_SimulatePolicies_DropRule(t *testing.T) {
+	account := getPolicySimulationAccount()
+	account.Policies = append(account.Policies, &Policy{
+		ID:      "deny",
+		Enabled: true,
+		Rules: []*PolicyRule{
+			{
+				ID:           "deny-rule",
+				Enabled:      true,
+				Sources:      []string{"clients"},
+				Destinations: []string{"servers"},
+				Protocol:     PolicyRuleProtocolALL,
+				Action:       PolicyTrafficActionDrop,
+			},
+		},
+	})
+
+	validatedPeers := map[string]struct{}{"peerA": {}, "peerB": {}}
+	result, err := account.SimulatePolicies(context.Background(), "", validatedPeers, &PolicySimulationRequest{
+		SourcePeerID:      "peerA",
+		DestinationPeerID: "peerB",
+		Protocol:          PolicyRuleProtocolTCP,
+		Port:              443,
+	})
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Len(t, result.Matches, 2)
+}
+
+func TestAccount_SimulatePolicies_DestinationPostureChecks(t *testing.T) {
+	account := getPolicySimulationAccount()
+	account.Peers["peerB"].Meta.WtVersion = "0.30.0"
+	account.Policies[0].SourcePostureChecks = nil
+	account.Policies[0].DestinationPostureChecks = []string{"minVersion"}
+
+	validatedPeers := map[string]struct{}{"peerA": {}, "peerB": {}, "peerC": {}}
+	result, err := account.SimulatePolicies(context.Background(), "", validatedPeers, &PolicySimulationRequest{
+		SourcePeerID:      "peerC",
+		DestinationPeerID: "peerB",
+		Protocol:          PolicyRuleProtocolTCP,
+		Port:              443,
+	})
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	require.Len(t, result.Matches, 1)
+
+	match := result.Matches[0]
+	assert.False(t, match.Applied)
+	assert.Empty(t, match.FailedPostureChecks)
+	assert.Equal(t, []string{"minVersion"}, match.DestinationPostureChecks)
+	assert.Equal(t, []string{"minVersion"}, match.FailedDestinationPostureChecks)
+}
+
+func TestAccount_SimulatePolicies_NetworkResource(t *testing.T) {
+	account := getBasicAccountsWithResource()
+	account.Settings = &Settings{}
+
+	result, err := account.SimulatePolicies(context.Background(), "", accNetResourceValidPeers, &PolicySimulationRequest{
+		SourcePeerID:  accNetResourcePeer1ID,
+		DestinationIP: netip.MustParseAddr("10.10.10.10"),
+		Protocol:      PolicyRuleProtocolTCP,
+		Port:          80,
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	require.Len(t, result.Matches, 1)
+	assert.Equal(t, accNetResourceRouter1ID, result.Matches[0].RoutingPeerID)
+	assert.Len(t, result.Matches[0].RouteFirewallRules, 1)
+
+	result, err = account.SimulatePolicies(context.Background(), "", accNetResourceValidPeers, &PolicySimulationRequest{
+		SourcePeerID:          accNetResourcePeer1ID,
+		DestinationResourceID: accNetResource1ID,
+		Protocol:              PolicyRuleProtocolTCP,
+		Port:                  443,
+	})
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Empty(t, result.Matches)
+
+	_, err = account.SimulatePolicies(context.Background(), "", accNetResourceValidPeers, &PolicySimulationRequest{
+		SourcePeerID:          accNetResourcePeer1ID,
+		DestinationResourceID: "unknown",
+		Protocol:              PolicyRuleProtocolTCP,
+	})
+	assert.Error(t, err)
+}
@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // PolicyUpdateOperationType operation type
 type PolicyUpdateOperationType int
 
@@ -61,6 +63,13 @@ type PolicyRule struct {
 
 	// PortRanges a list of port ranges.
 	PortRanges []RulePortRange `gorm:"serializer:json"`
+
+	// Schedule optionally restricts the time when the rule is applied
+	Schedule *RuleSchedule `gorm:"serializer:json"`
+
+	// SSHLocalUsers are the local accounts the peers of the rule may log in as on the SSH server of the peers
+	// they connect to. A peer mapped by any rule is restricted to the mapped accounts, otherwise any account is allowed
+	SSHLocalUsers []string `gorm:"serializer:json"`
 }
 
 // Copy returns a copy of a policy rule
@@ -78,10 +87,21 @@ func (pm *PolicyRule) Copy() *PolicyRule {
 		Protocol:      pm.Protocol,
 		Ports:         make([]string, len(pm.Ports)),
 		PortRanges:    make([]RulePortRange, len(pm.PortRanges)),
+		Schedule:      pm.Schedule.Copy(),
+		SSHLocalUsers: make([]string, len(pm.SSHLocalUsers)),
 	}
 	copy(rule.Destinations, pm.Destinations)
 	copy(rule.Sources, pm.Sources)
 	copy(rule.Ports, pm.Ports)
 	copy(rule.PortRanges, pm.PortRanges)
+	copy(rule.SSHLocalUsers, pm.SSHLocalUsers)
 	return rule
 }
+
+// IsActive returns true if the rule is enabled and its schedule, if any, allows it to be applied at the given time
+func (pm *PolicyRule) IsActive(t time.Time) bool {
+	if !pm.Enabled {
+		return false
+	}
+	return pm.Schedule == nil || pm.Schedule.IsActive(t)
+}
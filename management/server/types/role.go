@@ -0,0 +1,149 @@
+package types
+
+import (
+	"slices"
+
+	"github.com/rs/xid"
+)
+
+// CustomRole is an account defined role granting regular users access to selected modules of the management API
+type CustomRole struct {
+	// ID of the role
+	ID string `gorm:"primaryKey"`
+
+	// AccountID is a reference to Account that this object belongs
+	AccountID string `json:"-" gorm:"index"`
+
+	// Name of the role visible in the UI
+	Name string
+
+	// Description of the role visible in the UI
+	Description string
+
+	// Permissions granted by the role
+	Permissions []RolePermission `gorm:"serializer:json"`
+}
+
+// RolePermission grants operations on a module of the management API
+type RolePermission struct {
+	// Module the permission applies to, e.g. routes or dns
+	Module string
+
+	// Read allows listing and viewing the module objects
+	Read bool
+
+	// Write allows creating and updating the module objects
+	Write bool
+
+	// Delete allows deleting the module objects
+	Delete bool
+
+	// Groups optionally limits the permission to the objects related to these groups.
+	// The permission applies to all objects of the module when empty.
+	Groups []string
+}
+
+// NewCustomRole creates a new custom role with a generated ID
+func NewCustomRole(accountID, name, description string, permissions []RolePermission) *CustomRole {
+	return &CustomRole{
+		ID:          xid.New().String(),
+		AccountID:   accountID,
+		Name:        name,
+		Description: description,
+		Permissions: permissions,
+	}
+}
+
+// Copy returns a copy of the custom role
+func (r *CustomRole) Copy() *CustomRole {
+	permissions := make([]RolePermission, 0, len(r.Permissions))
+	for _, permission := range r.Permissions {
+		permission.Groups = slices.Clone(permission.Groups)
+		permissions = append(permissions, permission)
+	}
+
+	return &CustomRole{
+		ID:          r.ID,
+		AccountID:   r.AccountID,
+		Name:        r.Name,
+		Description: r.Description,
+		Permissions: permissions,
+	}
+}
+
+// EventMeta returns activity event meta related to the custom role
+func (r *CustomRole) EventMeta() map[string]any {
+	return map[string]any{"name": r.Name}
+}
+
+// GetPermission returns the permission granted for the module or nil if the role has no access to the module
+func (r *CustomRole) GetPermission(module string) *RolePermission {
+	for i := range r.Permissions {
+		if r.Permissions[i].Module == module {
+			return &r.Permissions[i]
+		}
+	}
+	return nil
+}
+
+// HasGroups checks if the role references any of the given groups
+func (r *CustomRole) HasGroups(groups ...string) bool {
+	for _, permission := range r.Permissions {
+		for _, group := range permission.Groups {
+			if slices.Contains(groups, group) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Allows checks if the permission grants the operation. The operation is one of read, write or delete.
+func (p *RolePermission) Allows(operation string) bool {
+	switch operation {
+	case "read":
+		return p.Read
+	case "write":
+		return p.Write
+	case "delete":
+		return p.Delete
+	default:
+		return false
+	}
+}
+
+// IsScoped returns true if the permission is limited to specific groups.
+// A nil permission stands for access not granted by a custom role and is never scoped.
+func (p *RolePermission) IsScoped() bool {
+	return p != nil && len(p.Groups) > 0
+}
+
+// CoversAllGroups checks if all the given groups are within the permission scope.
+// Objects without groups are only covered by permissions which are not scoped.
+func (p *RolePermission) CoversAllGroups(groups []string) bool {
+	if !p.IsScoped() {
+		return true
+	}
+	if len(groups) == 0 {
+		return false
+	}
+	for _, group := range groups {
+		if !slices.Contains(p.Groups, group) {
+			return false
+		}
+	}
+	return true
+}
+
+// CoversAnyGroup checks if at least one of the given groups is within the permission scope
+func (p *RolePermission) CoversAnyGroup(groups []string) bool {
+	if !p.IsScoped() {
+		return true
+	}
+	for _, group := range groups {
+		if slices.Contains(p.Groups, group) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRolePermission_Scope(t *testing.T) {
+	var nilPermission *RolePermission
+	unscoped := &RolePermission{Module: "routes", Read: true}
+	scoped := &RolePermission{Module: "routes", Read: true, Groups: []string{"office", "lab"}}
+
+	tests := []struct {
+		name       string
+		permission *RolePermission
+		groups     []string
+		allGroups  bool
+		anyGroup   bool
+	}{
+		{name: "nil permission", permission: nilPermission, groups: []string{"datacenter"}, allGroups: true, anyGroup: true},
+		{name: "unscoped permission", permission: unscoped, groups: []string{"datacenter"}, allGroups: true, anyGroup: true},
+		{name: "unscoped permission without groups", permission: unscoped, allGroups: true, anyGroup: true},
+		{name: "all groups in scope", permission: scoped, groups: []string{"office", "lab"}, allGroups: true, anyGroup: true},
+		{name: "some groups in scope", permission: scoped, groups: []string{"office", "datacenter"}, anyGroup: true},
+		{name: "no groups in scope", permission: scoped, groups: []string{"datacenter"}},
+		{name: "scoped permission without groups", permission: scoped},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.allGroups, tc.permission.CoversAllGroups(tc.groups))
+			assert.Equal(t, tc.anyGroup, tc.permission.CoversAnyGroup(tc.groups))
+		})
+	}
+}
+
+func TestCustomRole_GetPermission(t *testing.T) {
+	role := NewCustomRole("accountID", "operators", "", []RolePermission{
+		{Module: "routes", Read: true, Write: true},
+		{Module: "dns", Read: true, Groups: []string{"office"}},
+	})
+
+	permission := role.GetPermission("routes")
+	if assert.NotNil(t, permission) {
+		assert.True(t, permission.Allows("read"))
+		assert.True(t, permission.Allows("write"))
+		assert.False(t, permission.Allows("delete"))
+		assert.False(t, permission.Allows("unknown"))
+	}
+
+	assert.Nil(t, role.GetPermission("peers"))
+	assert.True(t, role.HasGroups("office"))
+	assert.False(t, role.HasGroups("datacenter"))
+
+	roleCopy := role.Copy()
+	roleCopy.Permissions[1].Groups[0] = "datacenter"
+	assert.Equal(t, "office", role.Permissions[1].Groups[0], "copy should not share the groups of the role")
+}
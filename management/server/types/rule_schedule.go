@@ -0,0 +1,224 @@
+package types
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// scheduleLookaheadDays is the number of days checked ahead when looking for the next schedule transition.
+// A week plus one day covers every combination of weekdays and windows spanning midnight.
+const scheduleLookaheadDays = 8
+
+// RuleTimeWindow is a time of day window in HH:MM format.
+// A window with an End before its Start spans midnight and belongs to the day it starts on.
+type RuleTimeWindow struct {
+	Start string
+	End   string
+}
+
+// RuleSchedule restricts the time when an enabled policy rule is applied.
+// All the set conditions must match for the rule to be applied.
+type RuleSchedule struct {
+	// Days of the week when the rule is applied. The rule is applied every day when empty.
+	Days []time.Weekday
+
+	// TimeWindows when the rule is applied. The rule is applied the whole day when empty.
+	TimeWindows []RuleTimeWindow
+
+	// TimeZone is an IANA time zone name used to evaluate Days and TimeWindows. Defaults to UTC.
+	TimeZone string
+
+	// StartsAt is the time from which the rule is applied
+	StartsAt *time.Time
+
+	// EndsAt is the time after which the rule is no longer applied
+	EndsAt *time.Time
+}
+
+// Copy returns a copy of the schedule
+func (s *RuleSchedule) Copy() *RuleSchedule {
+	if s == nil {
+		return nil
+	}
+
+	schedule := &RuleSchedule{
+		Days:        slices.Clone(s.Days),
+		TimeWindows: slices.Clone(s.TimeWindows),
+		TimeZone:    s.TimeZone,
+	}
+	if s.StartsAt != nil {
+		startsAt := *s.StartsAt
+		schedule.StartsAt = &startsAt
+	}
+	if s.EndsAt != nil {
+		endsAt := *s.EndsAt
+		schedule.EndsAt = &endsAt
+	}
+	return schedule
+}
+
+// Validate checks that the schedule can be evaluated
+func (s *RuleSchedule) Validate() error {
+	if _, err := time.LoadLocation(s.TimeZone); err != nil {
+		return fmt.Errorf("invalid time zone %s", s.TimeZone)
+	}
+
+	for _, day := range s.Days {
+		if day < time.Sunday || day > time.Saturday {
+			return fmt.Errorf("invalid day of week %d", day)
+		}
+	}
+
+	for _, window := range s.TimeWindows {
+		start, err := parseTimeOfDay(window.Start)
+		if err != nil {
+			return err
+		}
+		end, err := parseTimeOfDay(window.End)
+		if err != nil {
+			return err
+		}
+		if start == end {
+			return fmt.Errorf("time window %s-%s has the same start and end", window.Start, window.End)
+		}
+	}
+
+	if s.StartsAt != nil && s.EndsAt != nil && !s.EndsAt.After(*s.StartsAt) {
+		return fmt.Errorf("schedule end must be after its start")
+	}
+
+	return nil
+}
+
+// IsActive returns true if the schedule allows the rule to be applied at the given time
+func (s *RuleSchedule) IsActive(t time.Time) bool {
+	if s.StartsAt != nil && t.Before(*s.StartsAt) {
+		return false
+	}
+	if s.EndsAt != nil && !t.Before(*s.EndsAt) {
+		return false
+	}
+
+	local := t.In(s.location())
+	weekday := local.Weekday()
+
+	if len(s.TimeWindows) == 0 {
+		return s.isDayIncluded(weekday)
+	}
+
+	offset := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute + time.Duration(local.Second())*time.Second
+	for _, window := range s.TimeWindows {
+		start, errStart := parseTimeOfDay(window.Start)
+		end, errEnd := parseTimeOfDay(window.End)
+		if errStart != nil || errEnd != nil {
+			continue
+		}
+
+		if start < end {
+			if s.isDayIncluded(weekday) && offset >= start && offset < end {
+				return true
+			}
+			continue
+		}
+
+		// the window spans midnight
+		if s.isDayIncluded(weekday) && offset >= start {
+			return true
+		}
+		if s.isDayIncluded(previousWeekday(weekday)) && offset < end {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NextTransition returns the first time after t when the schedule switches between active and inactive.
+// It returns false when the schedule doesn't change anymore.
+func (s *RuleSchedule) NextTransition(t time.Time) (time.Time, bool) {
+	current := s.IsActive(t)
+	for _, candidate := range s.transitionCandidates(t) {
+		if !candidate.After(t) {
+			continue
+		}
+		if s.IsActive(candidate) != current {
+			return candidate, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// transitionCandidates returns the sorted list of times when the schedule can switch its state
+func (s *RuleSchedule) transitionCandidates(t time.Time) []time.Time {
+	var candidates []time.Time
+	if s.StartsAt != nil {
+		candidates = append(candidates, *s.StartsAt)
+	}
+	if s.EndsAt != nil {
+		candidates = append(candidates, *s.EndsAt)
+	}
+
+	base := t
+	if s.StartsAt != nil && s.StartsAt.After(base) {
+		base = *s.StartsAt
+	}
+
+	loc := s.location()
+	day := startOfDay(base.In(loc)).AddDate(0, 0, -1)
+	for i := 0; i <= scheduleLookaheadDays; i++ {
+		candidates = append(candidates, day)
+		for _, window := range s.TimeWindows {
+			if start, err := parseTimeOfDay(window.Start); err == nil {
+				candidates = append(candidates, atTimeOfDay(day, start))
+			}
+			if end, err := parseTimeOfDay(window.End); err == nil {
+				candidates = append(candidates, atTimeOfDay(day, end))
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Before(candidates[j])
+	})
+
+	return candidates
+}
+
+func (s *RuleSchedule) isDayIncluded(day time.Weekday) bool {
+	return len(s.Days) == 0 || slices.Contains(s.Days, day)
+}
+
+func (s *RuleSchedule) location() *time.Location {
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// parseTimeOfDay parses a HH:MM value into the offset from the start of the day
+func parseTimeOfDay(value string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %s, expected HH:MM", value)
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// atTimeOfDay returns the wall clock time of the given day, which keeps working across DST changes
+func atTimeOfDay(day time.Time, offset time.Duration) time.Time {
+	hours := int(offset / time.Hour)
+	minutes := int((offset % time.Hour) / time.Minute)
+	return time.Date(day.Year(), day.Month(), day.Day(), hours, minutes, 0, 0, day.Location())
+}
+
+func previousWeekday(day time.Weekday) time.Weekday {
+	return (day + 6) % 7
+}
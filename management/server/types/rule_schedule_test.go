@@ -0,0 +1,175 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleSchedule_IsActive(t *testing.T) {
+	startsAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	endsAt := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	businessHours := &RuleSchedule{
+		Days:        []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		TimeWindows: []RuleTimeWindow{{Start: "09:00", End: "17:00"}},
+		TimeZone:    "Europe/Berlin",
+	}
+
+	nightShift := &RuleSchedule{
+		Days:        []time.Weekday{time.Friday},
+		TimeWindows: []RuleTimeWindow{{Start: "22:00", End: "06:00"}},
+	}
+
+	tests := []struct {
+		name     string
+		schedule *RuleSchedule
+		time     time.Time
+		expected bool
+	}{
+		{
+			name:     "within business hours",
+			schedule: businessHours,
+			// Monday 10:00 in Berlin
+			time:     time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "after business hours in the configured time zone",
+			schedule: businessHours,
+			// Monday 17:30 in Berlin
+			time:     time.Date(2025, 3, 10, 16, 30, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "weekend",
+			schedule: businessHours,
+			time:     time.Date(2025, 3, 8, 11, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "window spanning midnight on its start day",
+			schedule: nightShift,
+			time:     time.Date(2025, 3, 7, 23, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "window spanning midnight on the next day",
+			schedule: nightShift,
+			time:     time.Date(2025, 3, 8, 5, 59, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			name:     "window spanning midnight not started on excluded day",
+			schedule: nightShift,
+			time:     time.Date(2025, 3, 9, 2, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			name:     "before the absolute start",
+			schedule: &RuleSchedule{StartsAt: &startsAt, EndsAt: &endsAt},
+			time:     startsAt.Add(-time.Second),
+			expected: false,
+		},
+		{
+			name:     "between the absolute start and end",
+			schedule: &RuleSchedule{StartsAt: &startsAt, EndsAt: &endsAt},
+			time:     startsAt,
+			expected: true,
+		},
+		{
+			name:     "at the absolute end",
+			schedule: &RuleSchedule{StartsAt: &startsAt, EndsAt: &endsAt},
+			time:     endsAt,
+			expected: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.schedule.IsActive(tc.time))
+		})
+	}
+}
+
+func TestRuleSchedule_NextTransition(t *testing.T) {
+	schedule := &RuleSchedule{
+		Days:        []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		TimeWindows: []RuleTimeWindow{{Start: "09:00", End: "17:00"}},
+	}
+
+	// Friday 16:00, the rule becomes inactive at 17:00
+	next, ok := schedule.NextTransition(time.Date(2025, 3, 7, 16, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 3, 7, 17, 0, 0, 0, time.UTC), next)
+
+	// Friday 17:00, the rule becomes active on Monday 09:00
+	next, ok = schedule.NextTransition(time.Date(2025, 3, 7, 17, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC), next)
+
+	startsAt := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	delayed := &RuleSchedule{
+		TimeWindows: []RuleTimeWindow{{Start: "09:00", End: "17:00"}},
+		StartsAt:    &startsAt,
+	}
+	next, ok = delayed.NextTransition(time.Date(2025, 3, 7, 10, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC), next)
+
+	endsAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	expired := &RuleSchedule{EndsAt: &endsAt}
+	_, ok = expired.NextTransition(time.Date(2025, 3, 7, 10, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+}
+
+func TestRuleSchedule_Validate(t *testing.T) {
+	startsAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, (&RuleSchedule{TimeWindows: []RuleTimeWindow{{Start: "22:00", End: "06:00"}}}).Validate())
+	assert.Error(t, (&RuleSchedule{TimeZone: "Mars/Olympus"}).Validate())
+	assert.Error(t, (&RuleSchedule{Days: []time.Weekday{7}}).Validate())
+	assert.Error(t, (&RuleSchedule{TimeWindows: []RuleTimeWindow{{Start: "9am", End: "17:00"}}}).Validate())
+	assert.Error(t, (&RuleSchedule{TimeWindows: []RuleTimeWindow{{Start: "09:00", End: "09:00"}}}).Validate())
+	assert.Error(t, (&RuleSchedule{StartsAt: &startsAt, EndsAt: &startsAt}).Validate())
+}
+
+func TestAccount_GetNextPolicyScheduleTransition(t *testing.T) {
+	now := time.Date(2025, 3, 7, 16, 0, 0, 0, time.UTC)
+	account := &Account{
+		Policies: []*Policy{
+			{
+				ID:      "policy1",
+				Enabled: true,
+				Rules: []*PolicyRule{
+					{ID: "unscheduled", Enabled: true},
+					{ID: "business-hours", Enabled: true, Schedule: &RuleSchedule{
+						TimeWindows: []RuleTimeWindow{{Start: "09:00", End: "17:00"}},
+					}},
+				},
+			},
+			{
+				ID:      "disabled",
+				Enabled: false,
+				Rules: []*PolicyRule{
+					{ID: "ignored", Enabled: true, Schedule: &RuleSchedule{
+						TimeWindows: []RuleTimeWindow{{Start: "16:30", End: "17:00"}},
+					}},
+				},
+			},
+		},
+	}
+
+	next, ok := account.GetNextPolicyScheduleTransition(now)
+	require.True(t, ok)
+	assert.Equal(t, time.Hour, next)
+
+	assert.True(t, account.Policies[0].Rules[1].IsActive(now))
+	assert.False(t, account.Policies[0].Rules[1].IsActive(now.Add(time.Hour)))
+
+	account.Policies[0].Rules[1].Enabled = false
+	_, ok = account.GetNextPolicyScheduleTransition(now)
+	assert.False(t, ok)
+}
@@ -51,6 +51,7 @@ type UserInfo struct {
 	Email                string                                     `json:"email"`
 	Name                 string                                     `json:"name"`
 	Role                 string                                     `json:"role"`
+	CustomRoleID         string                                     `json:"custom_role_id"`
 	AutoGroups           []string                                   `json:"auto_groups"`
 	Status               string                                     `json:"-"`
 	IsServiceUser        bool                                       `json:"is_service_user"`
@@ -77,6 +78,8 @@ type User struct {
 	NonDeletable bool
 	// ServiceUserName is only set if IsServiceUser is true
 	ServiceUserName string
+	// CustomRoleID is the ID of the custom role granting additional permissions to a user with the user role
+	CustomRoleID string
 	// AutoGroups is a list of Group IDs to auto-assign to peers registered by this user
 	AutoGroups []string                        `gorm:"serializer:json"`
 	PATs       map[string]*PersonalAccessToken `gorm:"-"`
@@ -147,6 +150,7 @@ func (u *User) ToUserInfo(userData *idp.UserData, settings *Settings) (*UserInfo
 			Email:         "",
 			Name:          u.ServiceUserName,
 			Role:          string(u.Role),
+			CustomRoleID:  u.CustomRoleID,
 			AutoGroups:    u.AutoGroups,
 			Status:        string(UserStatusActive),
 			IsServiceUser: u.IsServiceUser,
@@ -172,6 +176,7 @@ func (u *User) ToUserInfo(userData *idp.UserData, settings *Settings) (*UserInfo
 		Email:         userData.Email,
 		Name:          userData.Name,
 		Role:          string(u.Role),
+		CustomRoleID:  u.CustomRoleID,
 		AutoGroups:    autoGroups,
 		Status:        string(userStatus),
 		IsServiceUser: u.IsServiceUser,
@@ -196,6 +201,7 @@ func (u *User) Copy() *User {
 		Id:                   u.Id,
 		AccountID:            u.AccountID,
 		Role:                 u.Role,
+		CustomRoleID:         u.CustomRoleID,
 		AutoGroups:           autoGroups,
 		IsServiceUser:        u.IsServiceUser,
 		NonDeletable:         u.NonDeletable,
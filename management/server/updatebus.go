@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"sync"
+)
+
+// UpdateEventType is the kind of change announced to the other management instances
+type UpdateEventType string
+
+const (
+	// UpdateEventAccountPeers requests a network map update of the account peers, all of them if no peer IDs are set
+	UpdateEventAccountPeers UpdateEventType = "account_peers"
+	// UpdateEventPeersDeleted announces the peers were removed from the account
+	UpdateEventPeersDeleted UpdateEventType = "peers_deleted"
+	// UpdateEventPeersDisconnected requests closing the update channels of the peers, e.g. after their login expired
+	UpdateEventPeersDisconnected UpdateEventType = "peers_disconnected"
+)
+
+// UpdateEvent is a change made by a management instance that has to be delivered to the peers connected to the
+// other instances sharing the same store
+type UpdateEvent struct {
+	// Origin is the ID of the management instance that published the event
+	Origin    string          `json:"origin"`
+	Type      UpdateEventType `json:"type"`
+	AccountID string          `json:"account_id"`
+	PeerIDs   []string        `json:"peer_ids,omitempty"`
+	// Serial is the network serial of the account at the time of the event
+	Serial uint64 `json:"serial,omitempty"`
+}
+
+// UpdateEventHandler handles the events published to an UpdateBus
+type UpdateEventHandler func(ctx context.Context, event *UpdateEvent)
+
+// UpdateBus delivers the update events between the management instances sharing the same store,
+// so the peers receive the changes no matter which instance they are connected to
+type UpdateBus interface {
+	// Publish delivers the event to all handlers subscribed to the bus, including the ones of the publishing instance
+	Publish(ctx context.Context, event *UpdateEvent) error
+	// Subscribe registers a handler called for every event published to the bus
+	Subscribe(handler UpdateEventHandler)
+	// Close stops delivering the events
+	Close() error
+}
+
+// MemoryUpdateBus is the UpdateBus of a single management instance, it delivers the events within the process only
+type MemoryUpdateBus struct {
+	mu       sync.RWMutex
+	handlers []UpdateEventHandler
+	closed   bool
+}
+
+// NewMemoryUpdateBus returns a new instance of MemoryUpdateBus
+func NewMemoryUpdateBus() *MemoryUpdateBus {
+	return &MemoryUpdateBus{}
+}
+
+// Publish calls the subscribed handlers asynchronously, the publisher may hold account locks the handlers need
+func (b *MemoryUpdateBus) Publish(ctx context.Context, event *UpdateEvent) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return nil
+	}
+
+	for _, handler := range b.handlers {
+		go handler(context.WithoutCancel(ctx), event)
+	}
+	return nil
+}
+
+// Subscribe registers a handler called for every event published to the bus
+func (b *MemoryUpdateBus) Subscribe(handler UpdateEventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+// Close stops delivering the events
+func (b *MemoryUpdateBus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	b.handlers = nil
+	return nil
+}
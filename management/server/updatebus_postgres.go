@@ -0,0 +1,192 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	// registers the pgx database/sql driver used to publish the notifications
+	_ "github.com/jackc/pgx/v5/stdlib"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	postgresUpdateBusChannel = "netbird_management_updates"
+	// maxNotifyPayloadSize keeps the payload below the 8000 bytes limit of the Postgres notifications
+	maxNotifyPayloadSize       = 7900
+	updateBusReconnectInterval = 5 * time.Second
+)
+
+// PostgresUpdateBus is the UpdateBus of management instances sharing a Postgres store,
+// the events are delivered with LISTEN/NOTIFY
+type PostgresUpdateBus struct {
+	dsn string
+	db  *sql.DB
+
+	mu       sync.RWMutex
+	handlers []UpdateEventHandler
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewPostgresUpdateBus connects to the Postgres database and starts listening for the events of the other instances
+func NewPostgresUpdateBus(ctx context.Context, dsn string) (*PostgresUpdateBus, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("connect database: %w", err)
+	}
+
+	bus := &PostgresUpdateBus{
+		dsn:  dsn,
+		db:   db,
+		done: make(chan struct{}),
+	}
+
+	conn, err := bus.connect(ctx)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	ctx, bus.cancel = context.WithCancel(ctx)
+	go bus.listen(ctx, conn)
+
+	return bus, nil
+}
+
+// Publish notifies all instances listening on the bus. Events too large for a single notification
+// are split by their peer IDs.
+func (b *PostgresUpdateBus) Publish(ctx context.Context, event *UpdateEvent) error {
+	payloads, err := encodeUpdateEvent(event)
+	if err != nil {
+		return err
+	}
+
+	for _, payload := range payloads {
+		if _, err := b.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", postgresUpdateBusChannel, payload); err != nil {
+			return fmt.Errorf("notify %s event of account %s: %w", event.Type, event.AccountID, err)
+		}
+	}
+	return nil
+}
+
+// Subscribe registers a handler called for every event published to the bus
+func (b *PostgresUpdateBus) Subscribe(handler UpdateEventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, handler)
+}
+
+// Close stops listening for the events and closes the database connections
+func (b *PostgresUpdateBus) Close() error {
+	b.cancel()
+	<-b.done
+	return b.db.Close()
+}
+
+func (b *PostgresUpdateBus) connect(ctx context.Context) (*pgx.Conn, error) {
+	conn, err := pgx.Connect(ctx, b.dsn)
+	if err != nil {
+		return nil, fmt.Errorf("connect update bus listener: %w", err)
+	}
+	if _, err := conn.Exec(ctx, "LISTEN "+postgresUpdateBusChannel); err != nil {
+		_ = conn.Close(ctx)
+		return nil, fmt.Errorf("listen on update bus channel: %w", err)
+	}
+	return conn, nil
+}
+
+func (b *PostgresUpdateBus) listen(ctx context.Context, conn *pgx.Conn) {
+	defer close(b.done)
+
+	for {
+		err := b.receive(ctx, conn)
+		_ = conn.Close(context.Background())
+		if ctx.Err() != nil {
+			return
+		}
+
+		// the notifications sent while reconnecting are lost, the peers get them with the next account change
+		log.WithContext(ctx).Errorf("update bus listener disconnected, reconnecting: %v", err)
+		if conn = b.reconnect(ctx); conn == nil {
+			return
+		}
+	}
+}
+
+func (b *PostgresUpdateBus) reconnect(ctx context.Context) *pgx.Conn {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(updateBusReconnectInterval):
+		}
+
+		conn, err := b.connect(ctx)
+		if err == nil {
+			log.WithContext(ctx).Infof("update bus listener reconnected")
+			return conn
+		}
+		log.WithContext(ctx).Warnf("failed to reconnect update bus listener: %v", err)
+	}
+}
+
+func (b *PostgresUpdateBus) receive(ctx context.Context, conn *pgx.Conn) error {
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		event := &UpdateEvent{}
+		if err := json.Unmarshal([]byte(notification.Payload), event); err != nil {
+			log.WithContext(ctx).Errorf("failed to decode update bus event: %v", err)
+			continue
+		}
+
+		b.mu.RLock()
+		for _, handler := range b.handlers {
+			go handler(ctx, event)
+		}
+		b.mu.RUnlock()
+	}
+}
+
+// encodeUpdateEvent returns the payloads of the notifications carrying the event
+func encodeUpdateEvent(event *UpdateEvent) ([]string, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("encode update event: %w", err)
+	}
+	if len(payload) <= maxNotifyPayloadSize {
+		return []string{string(payload)}, nil
+	}
+	if len(event.PeerIDs) < 2 {
+		return nil, fmt.Errorf("update event of account %s is too large: %d bytes", event.AccountID, len(payload))
+	}
+
+	half := len(event.PeerIDs) / 2
+	first, second := *event, *event
+	first.PeerIDs = event.PeerIDs[:half]
+	second.PeerIDs = event.PeerIDs[half:]
+
+	firstPayloads, err := encodeUpdateEvent(&first)
+	if err != nil {
+		return nil, err
+	}
+	secondPayloads, err := encodeUpdateEvent(&second)
+	if err != nil {
+		return nil, err
+	}
+	return append(firstPayloads, secondPayloads...), nil
+}
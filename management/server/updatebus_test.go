@@ -0,0 +1,128 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/management/server/activity"
+	"github.com/netbirdio/netbird/management/server/telemetry"
+)
+
+func TestPeersUpdateManager_Subscribe(t *testing.T) {
+	bus := NewMemoryUpdateBus()
+	publisher := NewPeersUpdateManagerWithBus(nil, bus)
+	subscriber := NewPeersUpdateManagerWithBus(nil, bus)
+
+	received := make(chan *UpdateEvent, 2)
+	publisher.Subscribe(func(_ context.Context, event *UpdateEvent) {
+		received <- event
+	})
+	subscriber.Subscribe(func(_ context.Context, event *UpdateEvent) {
+		received <- event
+	})
+
+	publisher.Publish(context.Background(), &UpdateEvent{Type: UpdateEventAccountPeers, AccountID: "account"})
+
+	select {
+	case event := <-received:
+		assert.Equal(t, publisher.instanceID, event.Origin)
+		assert.Equal(t, "account", event.AccountID)
+	case <-time.After(time.Second):
+		t.Fatal("event wasn't delivered to the other instance")
+	}
+
+	select {
+	case <-received:
+		t.Fatal("event shouldn't be delivered to the publishing instance")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestEncodeUpdateEvent(t *testing.T) {
+	payloads, err := encodeUpdateEvent(&UpdateEvent{Type: UpdateEventAccountPeers, AccountID: "account"})
+	require.NoError(t, err)
+	assert.Len(t, payloads, 1)
+
+	event := &UpdateEvent{Type: UpdateEventPeersDeleted, AccountID: "account", Serial: 10}
+	for i := 0; i < 1000; i++ {
+		event.PeerIDs = append(event.PeerIDs, fmt.Sprintf("cu4k5bpl0ubs73e0nd%02d", i))
+	}
+
+	payloads, err = encodeUpdateEvent(event)
+	require.NoError(t, err)
+	assert.Greater(t, len(payloads), 1, "large event should be split")
+
+	var peerIDs []string
+	for _, payload := range payloads {
+		assert.LessOrEqual(t, len(payload), maxNotifyPayloadSize)
+		assert.Contains(t, payload, `"serial":10`)
+	}
+	for _, payload := range payloads {
+		decoded := &UpdateEvent{}
+		require.NoError(t, json.Unmarshal([]byte(payload), decoded))
+		peerIDs = append(peerIDs, decoded.PeerIDs...)
+	}
+	assert.Equal(t, event.PeerIDs, peerIDs, "split events should carry all peers")
+}
+
+func TestUpdateAccountPeers_OtherInstance(t *testing.T) {
+	manager, account, peer1, _, _ := setupNetworkMapTest(t)
+
+	metrics, err := telemetry.NewDefaultAppMetrics(context.Background())
+	require.NoError(t, err)
+
+	bus := NewMemoryUpdateBus()
+	manager.peersUpdateManager = NewPeersUpdateManagerWithBus(nil, bus)
+	manager.peersUpdateManager.Subscribe(manager.handleUpdateEvent)
+
+	other, err := BuildManager(context.Background(), manager.Store, NewPeersUpdateManagerWithBus(nil, bus), nil, "",
+		"netbird.cloud", &activity.InMemoryEventStore{}, nil, false, MocIntegratedValidator{}, metrics)
+	require.NoError(t, err)
+
+	updMsg := other.peersUpdateManager.CreateChannel(context.Background(), peer1.ID)
+	t.Cleanup(func() {
+		other.peersUpdateManager.CloseChannel(context.Background(), peer1.ID)
+	})
+
+	t.Run("account change on other instance updates peer", func(t *testing.T) {
+		manager.UpdateAccountPeers(context.Background(), account.Id)
+
+		select {
+		case msg := <-updMsg:
+			require.NotNil(t, msg.Update.NetworkMap)
+			assert.NotEmpty(t, msg.Update.NetworkMap.RemotePeers)
+		case <-time.After(time.Second):
+			t.Fatal("peer connected to the other instance didn't receive the update")
+		}
+	})
+
+	t.Run("peer deleted on other instance is disconnected", func(t *testing.T) {
+		err := manager.DeletePeer(context.Background(), account.Id, peer1.ID, userID)
+		require.NoError(t, err)
+
+		var last *UpdateMessage
+		timeout := time.After(time.Second)
+		for closed := false; !closed; {
+			select {
+			case msg, ok := <-updMsg:
+				if !ok {
+					closed = true
+					break
+				}
+				last = msg
+			case <-timeout:
+				t.Fatal("deleted peer channel wasn't closed")
+			}
+		}
+
+		require.NotNil(t, last, "deleted peer should receive an empty network map")
+		assert.True(t, last.Update.NetworkMap.RemotePeersIsEmpty)
+		assert.False(t, other.peersUpdateManager.HasChannel(peer1.ID))
+	})
+}
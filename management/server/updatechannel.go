@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/rs/xid"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netbirdio/netbird/management/proto"
@@ -26,17 +27,48 @@ type PeersUpdateManager struct {
 	channelsMux *sync.RWMutex
 	// metrics provides method to collect application metrics
 	metrics telemetry.AppMetrics
+	// bus delivers the update events between the management instances sharing the same store
+	bus UpdateBus
+	// instanceID identifies the events published by this management instance
+	instanceID string
 }
 
-// NewPeersUpdateManager returns a new instance of PeersUpdateManager
+// NewPeersUpdateManager returns a new instance of PeersUpdateManager of a single management instance
 func NewPeersUpdateManager(metrics telemetry.AppMetrics) *PeersUpdateManager {
+	return NewPeersUpdateManagerWithBus(metrics, NewMemoryUpdateBus())
+}
+
+// NewPeersUpdateManagerWithBus returns a new instance of PeersUpdateManager exchanging the update events
+// with the other management instances over the bus
+func NewPeersUpdateManagerWithBus(metrics telemetry.AppMetrics, bus UpdateBus) *PeersUpdateManager {
 	return &PeersUpdateManager{
 		peerChannels: make(map[string]chan *UpdateMessage),
 		channelsMux:  &sync.RWMutex{},
 		metrics:      metrics,
+		bus:          bus,
+		instanceID:   xid.New().String(),
+	}
+}
+
+// Publish announces the event to the other management instances
+func (p *PeersUpdateManager) Publish(ctx context.Context, event *UpdateEvent) {
+	event.Origin = p.instanceID
+	if err := p.bus.Publish(ctx, event); err != nil {
+		log.WithContext(ctx).Errorf("failed to publish %s event of account %s: %v", event.Type, event.AccountID, err)
 	}
 }
 
+// Subscribe registers the handler of the events published by the other management instances
+func (p *PeersUpdateManager) Subscribe(handler UpdateEventHandler) {
+	p.bus.Subscribe(func(ctx context.Context, event *UpdateEvent) {
+		if event.Origin == p.instanceID {
+			return
+		}
+		log.WithContext(ctx).Tracef("received %s event of account %s from instance %s", event.Type, event.AccountID, event.Origin)
+		handler(ctx, event)
+	})
+}
+
 // SendUpdate sends update message to the peer's channel
 func (p *PeersUpdateManager) SendUpdate(ctx context.Context, peerID string, update *UpdateMessage) {
 	start := time.Now()
@@ -16,6 +16,7 @@ import (
 	"github.com/netbirdio/netbird/management/server/idp"
 	"github.com/netbirdio/netbird/management/server/jwtclaims"
 	nbpeer "github.com/netbirdio/netbird/management/server/peer"
+	"github.com/netbirdio/netbird/management/server/permissions"
 	"github.com/netbirdio/netbird/management/server/status"
 	"github.com/netbirdio/netbird/management/server/store"
 	"github.com/netbirdio/netbird/management/server/types"
@@ -113,6 +114,32 @@ func (am *DefaultAccountManager) inviteNewUser(ctx context.Context, accountID, u
 		return nil, status.Errorf(status.NotFound, "initiator user with ID %s doesn't exist", userID)
 	}
 
+	// users whose custom role grants writing users can only invite regular users in the role scope
+	if !initiatorUser.HasAdminPower() {
+		permission, err := am.checkCustomRolePermission(ctx, initiatorUser, permissions.Users, permissions.Write)
+		if err != nil {
+			return nil, status.Errorf(status.PermissionDenied, "only users with admin power can invite users")
+		}
+		if invitedRole != types.UserRoleUser {
+			return nil, status.Errorf(status.PermissionDenied, "only users with admin power can invite users with the %s role", invitedRole)
+		}
+		if !permission.CoversAllGroups(invite.AutoGroups) {
+			return nil, status.NewPermissionDeniedError()
+		}
+	}
+
+	if invite.CustomRoleID != "" {
+		if !initiatorUser.HasAdminPower() {
+			return nil, status.Errorf(status.PermissionDenied, "only users with admin power can change the custom role of users")
+		}
+		if account.GetCustomRole(invite.CustomRoleID) == nil {
+			return nil, status.NewCustomRoleNotFoundError(invite.CustomRoleID)
+		}
+		if invitedRole != types.UserRoleUser {
+			return nil, status.Errorf(status.InvalidArgument, "custom roles can only be assigned to regular users with the user role")
+		}
+	}
+
 	inviterID := userID
 	if initiatorUser.IsServiceUser {
 		inviterID = account.CreatedBy
@@ -151,6 +178,7 @@ func (am *DefaultAccountManager) inviteNewUser(ctx context.Context, accountID, u
 	newUser := &types.User{
 		Id:                   idpUser.ID,
 		Role:                 invitedRole,
+		CustomRoleID:         invite.CustomRoleID,
 		AutoGroups:           invite.AutoGroups,
 		Issued:               invite.Issued,
 		IntegrationReference: invite.IntegrationReference,
@@ -249,8 +277,12 @@ func (am *DefaultAccountManager) DeleteUser(ctx context.Context, accountID, init
 	if executingUser == nil {
 		return status.Errorf(status.NotFound, "user not found")
 	}
+	var permission *types.RolePermission
 	if !executingUser.HasAdminPower() {
-		return status.Errorf(status.PermissionDenied, "only users with admin power can delete users")
+		permission, err = am.checkCustomRolePermission(ctx, executingUser, permissions.Users, permissions.Delete)
+		if err != nil {
+			return status.Errorf(status.PermissionDenied, "only users with admin power can delete users")
+		}
 	}
 
 	targetUser := account.Users[targetUserID]
@@ -258,6 +290,15 @@ func (am *DefaultAccountManager) DeleteUser(ctx context.Context, accountID, init
 		return status.Errorf(status.NotFound, "target user not found")
 	}
 
+	if !executingUser.HasAdminPower() {
+		if targetUser.Role != types.UserRoleUser || targetUser.IsServiceUser {
+			return status.Errorf(status.PermissionDenied, "only users with admin power can delete admins and service users")
+		}
+		if !permission.CoversAllGroups(targetUser.AutoGroups) {
+			return status.NewPermissionDeniedError()
+		}
+	}
+
 	if targetUser.Role == types.UserRoleOwner {
 		return status.Errorf(status.PermissionDenied, "unable to delete a user with owner role")
 	}
@@ -553,8 +594,12 @@ func (am *DefaultAccountManager) SaveOrAddUsers(ctx context.Context, accountID,
 		return nil, err
 	}
 
+	var permission *types.RolePermission
 	if !initiatorUser.HasAdminPower() || initiatorUser.IsBlocked() {
-		return nil, status.Errorf(status.PermissionDenied, "only users with admin power are authorized to perform user update operations")
+		permission, err = am.checkCustomRolePermission(ctx, initiatorUser, permissions.Users, permissions.Write)
+		if err != nil {
+			return nil, status.Errorf(status.PermissionDenied, "only users with admin power are authorized to perform user update operations")
+		}
 	}
 
 	updatedUsers := make([]*types.UserInfo, 0, len(updates))
@@ -584,11 +629,18 @@ func (am *DefaultAccountManager) SaveOrAddUsers(ctx context.Context, accountID,
 			return nil, err
 		}
 
+		if !initiatorUser.HasAdminPower() {
+			if err := validateCustomRoleUserUpdate(permission, oldUser, update, addIfNotExists); err != nil {
+				return nil, err
+			}
+		}
+
 		// only auto groups, revoked status, and integration reference can be updated for now
 		newUser := oldUser.Copy()
 		newUser.Role = update.Role
 		newUser.Blocked = update.Blocked
 		newUser.AutoGroups = update.AutoGroups
+		newUser.CustomRoleID = update.CustomRoleID
 		// these two fields can't be set via API, only via direct call to the method
 		newUser.Issued = update.Issued
 		newUser.IntegrationReference = update.IntegrationReference
@@ -677,6 +729,16 @@ func (am *DefaultAccountManager) prepareUserUpdateEvents(ctx context.Context, in
 		})
 	}
 
+	if oldUser.CustomRoleID != newUser.CustomRoleID {
+		meta := map[string]any{"custom_role": ""}
+		if role := account.GetCustomRole(newUser.CustomRoleID); role != nil {
+			meta = map[string]any{"custom_role": role.Name}
+		}
+		eventsToStore = append(eventsToStore, func() {
+			am.StoreEvent(ctx, initiatorUserID, oldUser.Id, account.Id, activity.UserCustomRoleUpdated, meta)
+		})
+	}
+
 	return eventsToStore
 }
 
@@ -797,6 +859,18 @@ func validateUserUpdate(account *types.Account, initiatorUser, oldUser, update *
 		return status.Errorf(status.PermissionDenied, "can't update a service user with owner role")
 	}
 
+	if update.CustomRoleID != oldUser.CustomRoleID && !initiatorUser.HasAdminPower() {
+		return status.Errorf(status.PermissionDenied, "only users with admin power can change the custom role of users")
+	}
+	if update.CustomRoleID != "" {
+		if account.GetCustomRole(update.CustomRoleID) == nil {
+			return status.NewCustomRoleNotFoundError(update.CustomRoleID)
+		}
+		if update.Role != types.UserRoleUser || oldUser.IsServiceUser {
+			return status.Errorf(status.InvalidArgument, "custom roles can only be assigned to regular users with the user role")
+		}
+	}
+
 	for _, newGroupID := range update.AutoGroups {
 		group, ok := account.Groups[newGroupID]
 		if !ok {
@@ -811,6 +885,24 @@ func validateUserUpdate(account *types.Account, initiatorUser, oldUser, update *
 	return nil
 }
 
+// validateCustomRoleUserUpdate validates the user update performed by a user whose custom role grants writing users.
+// Such users can only update regular users in the role scope and can't change their roles.
+func validateCustomRoleUserUpdate(permission *types.RolePermission, oldUser, update *types.User, addIfNotExists bool) error {
+	if addIfNotExists {
+		return status.Errorf(status.PermissionDenied, "only users with admin power can add users")
+	}
+	if oldUser.Role != types.UserRoleUser || oldUser.IsServiceUser {
+		return status.Errorf(status.PermissionDenied, "only users with admin power can update admins and service users")
+	}
+	if update.Role != oldUser.Role {
+		return status.Errorf(status.PermissionDenied, "only users with admin power can change the role of users")
+	}
+	if !permission.CoversAllGroups(oldUser.AutoGroups) || !permission.CoversAllGroups(update.AutoGroups) {
+		return status.NewPermissionDeniedError()
+	}
+	return nil
+}
+
 // GetOrCreateAccountByUser returns an existing account for a given user id or creates a new one if doesn't exist
 func (am *DefaultAccountManager) GetOrCreateAccountByUser(ctx context.Context, userID, domain string) (*types.Account, error) {
 	start := time.Now()
@@ -863,6 +955,20 @@ func (am *DefaultAccountManager) GetUsersFromAccount(ctx context.Context, accoun
 		return nil, err
 	}
 
+	// users with a custom role granting read access to users see the users of the role scope
+	canViewUsers := user.HasAdminPower() || user.IsServiceUser
+	var permission *types.RolePermission
+	if !canViewUsers && user.CustomRoleID != "" {
+		permission, err = am.checkCustomRolePermission(ctx, user, permissions.Users, permissions.Read)
+		canViewUsers = err == nil
+	}
+	isVisible := func(accountUser *types.User) bool {
+		if user.Id == accountUser.Id {
+			return true
+		}
+		return canViewUsers && permission.CoversAnyGroup(accountUser.AutoGroups)
+	}
+
 	queriedUsers := make([]*idp.UserData, 0)
 	if !isNil(am.idpManager) {
 		users := make(map[string]userLoggedInOnce, len(account.Users))
@@ -897,7 +1003,7 @@ func (am *DefaultAccountManager) GetUsersFromAccount(ctx context.Context, accoun
 	// in case of self-hosted, or IDP doesn't return anything, we will return the locally stored userInfo
 	if len(queriedUsers) == 0 {
 		for _, accountUser := range account.Users {
-			if !(user.HasAdminPower() || user.IsServiceUser || user.Id == accountUser.Id) {
+			if !isVisible(accountUser) {
 				// if user is not an admin then show only current user and do not show other users
 				continue
 			}
@@ -911,7 +1017,7 @@ func (am *DefaultAccountManager) GetUsersFromAccount(ctx context.Context, accoun
 	}
 
 	for _, localUser := range account.Users {
-		if !(user.HasAdminPower() || user.IsServiceUser) && user.Id != localUser.Id {
+		if !isVisible(localUser) {
 			// if user is not an admin then show only current user and do not show other users
 			continue
 		}
@@ -983,6 +1089,7 @@ func (am *DefaultAccountManager) expireAndUpdatePeers(ctx context.Context, accou
 	if len(peerIDs) != 0 {
 		// this will trigger peer disconnect from the management service
 		am.peersUpdateManager.CloseChannels(ctx, peerIDs)
+		am.peersUpdateManager.Publish(ctx, &UpdateEvent{Type: UpdateEventPeersDisconnected, AccountID: account.Id, PeerIDs: peerIDs})
 		am.UpdateAccountPeers(ctx, account.Id)
 	}
 	return nil
@@ -314,6 +314,7 @@ func TestUser_Copy(t *testing.T) {
 		Id:              "userId",
 		AccountID:       "accountId",
 		Role:            "role",
+		CustomRoleID:    "customRoleId",
 		IsServiceUser:   true,
 		ServiceUserName: "servicename",
 		AutoGroups:      []string{"group1", "group2"},
@@ -10,6 +10,7 @@ import (
 
 type Manager interface {
 	GetUser(ctx context.Context, userID string) (*types.User, error)
+	GetCustomRole(ctx context.Context, accountID, roleID string) (*types.CustomRole, error)
 }
 
 type managerImpl struct {
@@ -29,6 +30,10 @@ func (m *managerImpl) GetUser(ctx context.Context, userID string) (*types.User,
 	return m.store.GetUserByUserID(ctx, store.LockingStrengthShare, userID)
 }
 
+func (m *managerImpl) GetCustomRole(ctx context.Context, accountID, roleID string) (*types.CustomRole, error) {
+	return m.store.GetCustomRoleByID(ctx, store.LockingStrengthShare, accountID, roleID)
+}
+
 func NewManagerMock() Manager {
 	return &managerMock{}
 }
@@ -43,7 +48,23 @@ func (m *managerMock) GetUser(ctx context.Context, userID string) (*types.User,
 		return &types.User{Id: userID, Role: types.UserRoleOwner}, nil
 	case "billingUser":
 		return &types.User{Id: userID, Role: types.UserRoleBillingAdmin}, nil
+	case "networkUser":
+		return &types.User{Id: userID, Role: types.UserRoleUser, CustomRoleID: "networkRole"}, nil
 	default:
 		return nil, errors.New("user not found")
 	}
 }
+
+func (m *managerMock) GetCustomRole(ctx context.Context, accountID, roleID string) (*types.CustomRole, error) {
+	if roleID != "networkRole" {
+		return nil, errors.New("custom role not found")
+	}
+	return &types.CustomRole{
+		ID:   roleID,
+		Name: "Network",
+		Permissions: []types.RolePermission{
+			{Module: "routes", Read: true, Write: true, Delete: true},
+			{Module: "dns", Read: true, Write: true},
+		},
+	}, nil
+}
@@ -0,0 +1,133 @@
+// Package admin provides the authenticated HTTP API of the relay server operators. It lists the connected peers with
+// their relayed traffic and allows disconnecting a peer.
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netbirdio/netbird/relay/server"
+)
+
+// Relay is the relay server managed by the admin API
+type Relay interface {
+	InstanceURL() string
+	Peers() []server.PeerStats
+	DisconnectPeer(ctx context.Context, peerID string) bool
+}
+
+// Peer is a connected peer in the admin API responses
+type Peer struct {
+	ID              string    `json:"id"`
+	RemoteAddr      string    `json:"remote_addr"`
+	InstanceURL     string    `json:"instance_url"`
+	ConnectedAt     time.Time `json:"connected_at"`
+	SessionDuration int64     `json:"session_duration_seconds"`
+	BytesIn         uint64    `json:"bytes_in"`
+	BytesOut        uint64    `json:"bytes_out"`
+	MessagesIn      uint64    `json:"messages_in"`
+	MessagesOut     uint64    `json:"messages_out"`
+	BytesDropped    uint64    `json:"bytes_dropped"`
+	MessagesDropped uint64    `json:"messages_dropped"`
+}
+
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+// Handler serves the admin API, every request has to carry the admin token as a bearer token
+type Handler struct {
+	relay Relay
+	token string
+	mux   *http.ServeMux
+}
+
+// NewHandler creates a new admin API handler for the relay
+func NewHandler(relay Relay, token string) *Handler {
+	h := &Handler{
+		relay: relay,
+		token: token,
+		mux:   http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("GET /api/peers", h.listPeers)
+	h.mux.HandleFunc("DELETE /api/peers/{peerId}", h.disconnectPeer)
+	return h
+}
+
+// ServeHTTP authenticates the request and passes it to the API endpoints
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authenticated(r) {
+		writeError(w, "invalid admin token", http.StatusUnauthorized)
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) authenticated(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || h.token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) == 1
+}
+
+// listPeers returns the connected peers, the peers relaying the most traffic first
+func (h *Handler) listPeers(w http.ResponseWriter, _ *http.Request) {
+	now := time.Now()
+	instanceURL := h.relay.InstanceURL()
+
+	stats := h.relay.Peers()
+	peers := make([]Peer, 0, len(stats))
+	for _, s := range stats {
+		peers = append(peers, Peer{
+			ID:              s.ID,
+			RemoteAddr:      s.RemoteAddr,
+			InstanceURL:     instanceURL,
+			ConnectedAt:     s.ConnectedAt.UTC(),
+			SessionDuration: int64(now.Sub(s.ConnectedAt).Seconds()),
+			BytesIn:         s.BytesIn,
+			BytesOut:        s.BytesOut,
+			MessagesIn:      s.MessagesIn,
+			MessagesOut:     s.MessagesOut,
+			BytesDropped:    s.BytesDropped,
+			MessagesDropped: s.MessagesDropped,
+		})
+	}
+
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].BytesIn+peers[i].BytesOut > peers[j].BytesIn+peers[j].BytesOut
+	})
+
+	writeJSON(w, peers, http.StatusOK)
+}
+
+func (h *Handler) disconnectPeer(w http.ResponseWriter, r *http.Request) {
+	peerID := r.PathValue("peerId")
+	if !h.relay.DisconnectPeer(r.Context(), peerID) {
+		writeError(w, "peer not found", http.StatusNotFound)
+		return
+	}
+
+	log.Infof("peer %s disconnected by admin API request", peerID)
+	writeJSON(w, struct{}{}, http.StatusOK)
+}
+
+func writeError(w http.ResponseWriter, message string, code int) {
+	writeJSON(w, errorResponse{Message: message}, code)
+}
+
+func writeJSON(w http.ResponseWriter, obj any, code int) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		log.Errorf("failed to encode admin API response: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/netbirdio/netbird/relay/server"
+)
+
+const testToken = "admin-token"
+
+type mockRelay struct {
+	peers        []server.PeerStats
+	disconnected []string
+}
+
+func (m *mockRelay) InstanceURL() string {
+	return "rels://relay.example.com:443"
+}
+
+func (m *mockRelay) Peers() []server.PeerStats {
+	return m.peers
+}
+
+func (m *mockRelay) DisconnectPeer(_ context.Context, peerID string) bool {
+	for _, p := range m.peers {
+		if p.ID == peerID {
+			m.disconnected = append(m.disconnected, peerID)
+			return true
+		}
+	}
+	return false
+}
+
+func newRequest(method, path, token string) *http.Request {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+func TestHandler_Authentication(t *testing.T) {
+	h := NewHandler(&mockRelay{}, testToken)
+
+	for _, token := range []string{"", "wrong"} {
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, newRequest(http.MethodGet, "/api/peers", token))
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, "token %q should be rejected", token)
+	}
+
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, newRequest(http.MethodGet, "/api/peers", testToken))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestHandler_ListPeers(t *testing.T) {
+	relay := &mockRelay{
+		peers: []server.PeerStats{
+			{ID: "quiet", RemoteAddr: "192.0.2.1:5000", ConnectedAt: time.Now().Add(-time.Minute), BytesIn: 10, MessagesIn: 1},
+			{ID: "busy", RemoteAddr: "192.0.2.2:5000", ConnectedAt: time.Now().Add(-time.Hour), BytesIn: 1000, BytesOut: 500, MessagesIn: 10, MessagesOut: 5},
+		},
+	}
+	h := NewHandler(relay, testToken)
+
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, newRequest(http.MethodGet, "/api/peers", testToken))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var peers []Peer
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&peers))
+	require.Len(t, peers, 2)
+
+	assert.Equal(t, "busy", peers[0].ID, "peers should be sorted by traffic")
+	assert.Equal(t, "rels://relay.example.com:443", peers[0].InstanceURL)
+	assert.Equal(t, "192.0.2.2:5000", peers[0].RemoteAddr)
+	assert.Equal(t, uint64(1000), peers[0].BytesIn)
+	assert.Equal(t, uint64(500), peers[0].BytesOut)
+	assert.Equal(t, uint64(5), peers[0].MessagesOut)
+	assert.InDelta(t, time.Hour.Seconds(), peers[0].SessionDuration, 5)
+}
+
+func TestHandler_DisconnectPeer(t *testing.T) {
+	peerID := "sha-abc/def+gh=="
+	relay := &mockRelay{peers: []server.PeerStats{{ID: peerID}}}
+	h := NewHandler(relay, testToken)
+
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, newRequest(http.MethodDelete, "/api/peers/"+url.PathEscape(peerID), testToken))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, []string{peerID}, relay.disconnected, "escaped peer ID should be disconnected")
+
+	recorder = httptest.NewRecorder()
+	h.ServeHTTP(recorder, newRequest(http.MethodDelete, "/api/peers/unknown", testToken))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"slices"
 	"sync"
 	"time"
 
@@ -138,7 +139,9 @@ type Client struct {
 	readLoopMutex    sync.Mutex
 	wgReadLoop       sync.WaitGroup
 	instanceURL      *RelayAddr
-	muInstanceURL    sync.Mutex
+	// clusterSiblings are the instance URLs of the relay servers the server forwards the messages to
+	clusterSiblings []string
+	muInstanceURL   sync.Mutex
 
 	onDisconnectListener func(string)
 	onConnectedListener  func()
@@ -231,6 +234,14 @@ func (c *Client) ServerInstanceURL() (string, error) {
 	return c.instanceURL.String(), nil
 }
 
+// IsClusterSibling returns true if the relay server forwards the messages to the peers connected to the server of the
+// given instance URL. The clustered relay servers advertise their siblings after the connection is established.
+func (c *Client) IsClusterSibling(serverURL string) bool {
+	c.muInstanceURL.Lock()
+	defer c.muInstanceURL.Unlock()
+	return slices.Contains(c.clusterSiblings, serverURL)
+}
+
 // SetOnDisconnectListener sets a function that will be called when the connection to the relay server is closed.
 func (c *Client) SetOnDisconnectListener(fn func(string)) {
 	c.listenerMutex.Lock()
@@ -376,6 +387,7 @@ func (c *Client) readLoop(relayConn net.Conn) {
 
 	c.muInstanceURL.Lock()
 	c.instanceURL = nil
+	c.clusterSiblings = nil
 	c.muInstanceURL.Unlock()
 
 	c.wgReadLoop.Done()
@@ -390,6 +402,9 @@ func (c *Client) handleMsg(msgType messages.MsgType, buf []byte, bufPtr *[]byte,
 		c.bufPool.Put(bufPtr)
 	case messages.MsgTypeTransport:
 		return c.handleTransportMsg(buf, bufPtr, internallyStoppedFlag)
+	case messages.MsgTypeClusterInfo:
+		c.handleClusterInfo(buf)
+		c.bufPool.Put(bufPtr)
 	case messages.MsgTypeClose:
 		c.log.Debugf("relay connection close by server")
 		c.bufPool.Put(bufPtr)
@@ -410,6 +425,19 @@ func (c *Client) handleHealthCheck(hc *healthcheck.Receiver, internallyStoppedFl
 	hc.Heartbeat()
 }
 
+func (c *Client) handleClusterInfo(buf []byte) {
+	siblings, err := messages.UnmarshalClusterInfo(buf)
+	if err != nil {
+		c.log.Errorf("failed to parse cluster info message: %v", err)
+		return
+	}
+
+	c.log.Debugf("relay cluster siblings: %v", siblings)
+	c.muInstanceURL.Lock()
+	c.clusterSiblings = siblings
+	c.muInstanceURL.Unlock()
+}
+
 func (c *Client) handleTransportMsg(buf []byte, bufPtr *[]byte, internallyStoppedFlag *internalStopFlag) bool {
 	peerID, payload, err := messages.UnmarshalTransportMsg(buf)
 	if err != nil {
@@ -12,6 +12,7 @@ import (
 
 	"github.com/netbirdio/netbird/relay/auth/allow"
 	"github.com/netbirdio/netbird/relay/auth/hmac"
+	"github.com/netbirdio/netbird/relay/messages"
 	"github.com/netbirdio/netbird/util"
 
 	"github.com/netbirdio/netbird/relay/server"
@@ -710,3 +711,20 @@ func waitForServerToStart(errChan chan error) error {
 	}
 	return nil
 }
+
+func TestClient_ClusterSiblings(t *testing.T) {
+	c := NewClient(context.Background(), "rels://relay1.example.com:443", nil, "alice")
+
+	msg, err := messages.MarshalClusterInfo([]string{"rels://relay2.example.com:443"})
+	if err != nil {
+		t.Fatalf("failed to marshal cluster info: %s", err)
+	}
+	c.handleClusterInfo(msg)
+
+	if !c.IsClusterSibling("rels://relay2.example.com:443") {
+		t.Errorf("advertised sibling should be reached via the relay server")
+	}
+	if c.IsClusterSibling("rels://relay3.example.com:443") {
+		t.Errorf("unknown relay server shouldn't be a sibling")
+	}
+}
@@ -326,7 +326,12 @@ func (m *Manager) isForeignServer(address string) (bool, error) {
 	if err != nil {
 		return false, fmt.Errorf("relay client not connected")
 	}
-	return rAddr != address, nil
+	if rAddr == address {
+		return false, nil
+	}
+
+	// the home relay server forwards the messages to the peers connected to its cluster siblings
+	return !m.relayClient.IsClusterSibling(address), nil
 }
 
 func (m *Manager) startCleanupLoop() {
@@ -6,7 +6,9 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"net/netip"
 	"os"
 	"os/signal"
 	"syscall"
@@ -17,8 +19,10 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/netbirdio/netbird/encryption"
+	"github.com/netbirdio/netbird/relay/admin"
 	"github.com/netbirdio/netbird/relay/auth"
 	"github.com/netbirdio/netbird/relay/server"
+	"github.com/netbirdio/netbird/relay/server/cluster"
 	"github.com/netbirdio/netbird/signal/metrics"
 	"github.com/netbirdio/netbird/util"
 )
@@ -40,6 +44,17 @@ type Config struct {
 	AuthSecret            string
 	LogLevel              string
 	LogFile               string
+	// the admin API is disabled without an admin listen address
+	AdminListenAddress string
+	AdminToken         string
+	// bandwidth limits in Mbit/s, zero disables the limit
+	PeerBandwidthLimit   float64
+	PairBandwidthLimit   float64
+	GlobalBandwidthLimit float64
+	// in a cluster the instances forward the messages to the peers connected to the siblings, the instances
+	// authenticate each other with the auth secret
+	ClusterListenAddress string
+	ClusterSiblings      []string
 }
 
 func (c Config) Validate() error {
@@ -49,9 +64,48 @@ func (c Config) Validate() error {
 	if c.AuthSecret == "" {
 		return fmt.Errorf("auth secret is required")
 	}
+	if c.PeerBandwidthLimit < 0 || c.PairBandwidthLimit < 0 || c.GlobalBandwidthLimit < 0 {
+		return fmt.Errorf("bandwidth limits can't be negative")
+	}
+	if len(c.ClusterSiblings) > 0 && c.ClusterListenAddress == "" {
+		return fmt.Errorf("cluster listen address is required if cluster siblings are set")
+	}
+	if c.AdminListenAddress != "" && c.AdminToken == "" {
+		return fmt.Errorf("admin token is required if the admin API is enabled")
+	}
+	// the admin token would be sent in plain text to a remote admin API
+	if c.AdminListenAddress != "" && !c.HasTLS() && !isLoopbackAddress(c.AdminListenAddress) {
+		return fmt.Errorf("admin API listen address must be a loopback address if TLS is not configured")
+	}
 	return nil
 }
 
+// isLoopbackAddress returns true if the listen address binds only the loopback interface
+func isLoopbackAddress(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip, err := netip.ParseAddr(host)
+	return err == nil && ip.IsLoopback()
+}
+
+// BandwidthLimits returns the bandwidth limits of the relay server in bytes per second
+func (c Config) BandwidthLimits() server.LimitsConfig {
+	return server.LimitsConfig{
+		PeerBandwidth:   mbpsToBytes(c.PeerBandwidthLimit),
+		PairBandwidth:   mbpsToBytes(c.PairBandwidthLimit),
+		GlobalBandwidth: mbpsToBytes(c.GlobalBandwidthLimit),
+	}
+}
+
+func mbpsToBytes(mbps float64) int64 {
+	return int64(mbps * 1000 * 1000 / 8)
+}
+
 func (c Config) HasCertConfig() bool {
 	return c.TlsCertFile != "" && c.TlsKeyFile != ""
 }
@@ -60,6 +114,11 @@ func (c Config) HasLetsEncrypt() bool {
 	return c.LetsencryptDataDir != "" && c.LetsencryptDomains != nil && len(c.LetsencryptDomains) > 0
 }
 
+// HasTLS returns true if the relay server runs with TLS
+func (c Config) HasTLS() bool {
+	return c.LetsencryptAWSRoute53 || c.HasLetsEncrypt() || c.HasCertConfig()
+}
+
 var (
 	cobraConfig *Config
 	rootCmd     = &cobra.Command{
@@ -87,6 +146,13 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&cobraConfig.AuthSecret, "auth-secret", "s", "", "auth secret")
 	rootCmd.PersistentFlags().StringVar(&cobraConfig.LogLevel, "log-level", "info", "log level")
 	rootCmd.PersistentFlags().StringVar(&cobraConfig.LogFile, "log-file", "console", "log file")
+	rootCmd.PersistentFlags().Float64Var(&cobraConfig.PeerBandwidthLimit, "peer-bandwidth-limit", 0, "bandwidth limit in Mbit/s of the traffic a single peer can send through the relay. Disabled if 0")
+	rootCmd.PersistentFlags().Float64Var(&cobraConfig.PairBandwidthLimit, "pair-bandwidth-limit", 0, "bandwidth limit in Mbit/s of the traffic a peer can send to another peer. Disabled if 0")
+	rootCmd.PersistentFlags().Float64Var(&cobraConfig.GlobalBandwidthLimit, "global-bandwidth-limit", 0, "bandwidth limit in Mbit/s of the traffic relayed in total, shared fairly between the sending peers. Disabled if 0")
+	rootCmd.PersistentFlags().StringVar(&cobraConfig.ClusterListenAddress, "cluster-listen-address", "", "listen address for the sibling relay instances of the cluster. The links are authenticated with the auth secret but not encrypted, use a private network. Clustering is disabled if empty")
+	rootCmd.PersistentFlags().StringSliceVar(&cobraConfig.ClusterSiblings, "cluster-siblings", nil, "cluster listen addresses (host:port) of the sibling relay instances")
+	rootCmd.PersistentFlags().StringVar(&cobraConfig.AdminListenAddress, "admin-listen-address", "", "admin API listen address. The admin API lists the connected peers with their traffic and allows disconnecting them. Served with the TLS certificate of the relay, without TLS it must be a loopback address. Disabled if empty")
+	rootCmd.PersistentFlags().StringVar(&cobraConfig.AdminToken, "admin-token", "", "bearer token required by the admin API")
 
 	setFlagsFromEnvVars(rootCmd)
 }
@@ -146,6 +212,21 @@ func execute(cmd *cobra.Command, args []string) error {
 		log.Debugf("failed to create relay server: %v", err)
 		return fmt.Errorf("failed to create relay server: %v", err)
 	}
+	if limits := cobraConfig.BandwidthLimits(); limits.Enabled() {
+		log.Infof("bandwidth limits in bytes per second, peer: %d, pair: %d, global: %d", limits.PeerBandwidth, limits.PairBandwidth, limits.GlobalBandwidth)
+		srv.SetBandwidthLimits(limits)
+	}
+	if cobraConfig.ClusterListenAddress != "" {
+		err := srv.EnableCluster(cluster.Config{
+			ListenAddress: cobraConfig.ClusterListenAddress,
+			Siblings:      cobraConfig.ClusterSiblings,
+			Secret:        []byte(cobraConfig.AuthSecret),
+		})
+		if err != nil {
+			log.Debugf("failed to enable relay cluster: %v", err)
+			return fmt.Errorf("failed to enable relay cluster: %v", err)
+		}
+	}
 	log.Infof("server will be available on: %s", srv.InstanceURL())
 	go func() {
 		if err := srv.Listen(srvListenerCfg); err != nil {
@@ -153,6 +234,31 @@ func execute(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
+	var adminServer *http.Server
+	if cobraConfig.AdminListenAddress != "" {
+		adminServer = &http.Server{
+			Addr:              cobraConfig.AdminListenAddress,
+			Handler:           admin.NewHandler(srv, cobraConfig.AdminToken),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		// the admin API uses the certificate of the relay server, without TLS it is bound to a loopback address
+		if tlsConfig != nil {
+			adminServer.TLSConfig = tlsConfig.Clone()
+		}
+		go func() {
+			log.Infof("running admin API server: %s, TLS: %t", adminServer.Addr, adminServer.TLSConfig != nil)
+			var err error
+			if adminServer.TLSConfig != nil {
+				err = adminServer.ListenAndServeTLS("", "")
+			} else {
+				err = adminServer.ListenAndServe()
+			}
+			if !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalf("failed to start admin API server: %v", err)
+			}
+		}()
+	}
+
 	// it will block until exit signal
 	waitForExitSignal()
 
@@ -160,6 +266,12 @@ func execute(cmd *cobra.Command, args []string) error {
 	defer cancel()
 
 	var shutDownErrors error
+	if adminServer != nil {
+		if err := adminServer.Shutdown(ctx); err != nil {
+			shutDownErrors = multierror.Append(shutDownErrors, fmt.Errorf("failed to close admin API server: %v", err))
+		}
+	}
+
 	if err := srv.Shutdown(ctx); err != nil {
 		shutDownErrors = multierror.Append(shutDownErrors, fmt.Errorf("failed to close server: %s", err))
 	}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"strings"
 )
 
 const (
@@ -22,6 +23,7 @@ const (
 	MsgTypeHealthCheck   MsgType = 5
 	MsgTypeAuth                  = 6
 	MsgTypeAuthResponse          = 7
+	MsgTypeClusterInfo   MsgType = 8
 
 	// base size of the message
 	sizeOfVersionByte = 1
@@ -66,6 +68,8 @@ func (m MsgType) String() string {
 		return "auth"
 	case MsgTypeAuthResponse:
 		return "auth response"
+	case MsgTypeClusterInfo:
+		return "cluster info"
 	case MsgTypeTransport:
 		return "transport"
 	case MsgTypeClose:
@@ -120,6 +124,7 @@ func DetermineServerMessageType(msg []byte) (MsgType, error) {
 	case
 		MsgTypeHelloResponse,
 		MsgTypeAuthResponse,
+		MsgTypeClusterInfo,
 		MsgTypeTransport,
 		MsgTypeClose,
 		MsgTypeHealthCheck:
@@ -255,6 +260,37 @@ func UnmarshalAuthResponse(msg []byte) (string, error) {
 	return string(msg[sizeOfProtoHeader:]), nil
 }
 
+// MarshalClusterInfo creates a cluster info message.
+// The clustered relay server sends the instance URLs of its sibling servers after the auth response and whenever they
+// change. The server forwards the messages to the peers connected to a sibling, so the client reaches them via the
+// server instead of connecting to the sibling.
+func MarshalClusterInfo(siblings []string) ([]byte, error) {
+	sb := []byte(strings.Join(siblings, "\n"))
+	msg := make([]byte, sizeOfProtoHeader, sizeOfProtoHeader+len(sb))
+
+	msg[0] = byte(CurrentProtocolVersion)
+	msg[1] = byte(MsgTypeClusterInfo)
+
+	msg = append(msg, sb...)
+
+	if len(msg) > MaxHandshakeRespSize {
+		return nil, fmt.Errorf("invalid message length: %d", len(msg))
+	}
+
+	return msg, nil
+}
+
+// UnmarshalClusterInfo returns the instance URLs of the sibling servers from the cluster info message
+func UnmarshalClusterInfo(msg []byte) ([]string, error) {
+	if len(msg) < sizeOfProtoHeader {
+		return nil, ErrInvalidMessageLength
+	}
+	if len(msg) == sizeOfProtoHeader {
+		return nil, nil
+	}
+	return strings.Split(string(msg[sizeOfProtoHeader:]), "\n"), nil
+}
+
 // MarshalCloseMsg creates a close message.
 // The close message is used to close the connection gracefully between the client and the server. The server and the
 // client can send this message. After receiving this message, the server or client will close the connection.
@@ -1,6 +1,7 @@
 package messages
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -136,3 +137,40 @@ func TestMarshalHealthcheck(t *testing.T) {
 		t.Errorf("expected %d, got %d", MsgTypeHealthCheck, msgType)
 	}
 }
+
+func TestMarshalClusterInfo(t *testing.T) {
+	siblings := []string{"rels://relay1.example.com:443", "rels://relay2.example.com:443"}
+	msg, err := MarshalClusterInfo(siblings)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	msgType, err := DetermineServerMessageType(msg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+
+	if msgType != MsgTypeClusterInfo {
+		t.Errorf("expected %d, got %d", MsgTypeClusterInfo, msgType)
+	}
+
+	received, err := UnmarshalClusterInfo(msg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if strings.Join(received, ",") != strings.Join(siblings, ",") {
+		t.Errorf("expected %v, got %v", siblings, received)
+	}
+
+	msg, err = MarshalClusterInfo(nil)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	received, err = UnmarshalClusterInfo(msg)
+	if err != nil {
+		t.Fatalf("error: %v", err)
+	}
+	if len(received) != 0 {
+		t.Errorf("expected no siblings, got %v", received)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 )
 
@@ -18,6 +19,8 @@ type Metrics struct {
 
 	TransferBytesSent  metric.Int64Counter
 	TransferBytesRecv  metric.Int64Counter
+	TransferBytesDrop  metric.Int64Counter
+	TransferMsgsDrop   metric.Int64Counter
 	AuthenticationTime metric.Float64Histogram
 	PeerStoreTime      metric.Float64Histogram
 
@@ -39,6 +42,16 @@ func NewMetrics(ctx context.Context, meter metric.Meter) (*Metrics, error) {
 		return nil, err
 	}
 
+	bytesDrop, err := meter.Int64Counter("relay_transfer_dropped_bytes_total")
+	if err != nil {
+		return nil, err
+	}
+
+	msgsDrop, err := meter.Int64Counter("relay_transfer_dropped_messages_total")
+	if err != nil {
+		return nil, err
+	}
+
 	peers, err := meter.Int64UpDownCounter("relay_peers")
 	if err != nil {
 		return nil, err
@@ -68,6 +81,8 @@ func NewMetrics(ctx context.Context, meter metric.Meter) (*Metrics, error) {
 		Meter:              meter,
 		TransferBytesSent:  bytesSent,
 		TransferBytesRecv:  bytesRecv,
+		TransferBytesDrop:  bytesDrop,
+		TransferMsgsDrop:   msgsDrop,
 		AuthenticationTime: authTime,
 		PeerStoreTime:      peerStoreTime,
 		peers:              peers,
@@ -122,6 +137,13 @@ func (m *Metrics) PeerDisconnected(id string) {
 	delete(m.peerLastActive, id)
 }
 
+// TransferDropped counts a transport message dropped by the bandwidth limits, the reason is the exceeded limit
+func (m *Metrics) TransferDropped(reason string, size int) {
+	attrs := metric.WithAttributes(attribute.String("reason", reason))
+	m.TransferBytesDrop.Add(m.ctx, int64(size), attrs)
+	m.TransferMsgsDrop.Add(m.ctx, 1, attrs)
+}
+
 // PeerActivity increases the active connections
 func (m *Metrics) PeerActivity(peerID string) {
 	select {
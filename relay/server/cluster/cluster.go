@@ -0,0 +1,484 @@
+// Package cluster connects the relay instances of a deployment. The instances share the directory of their connected
+// peers and forward the transport messages to the peers connected to a sibling instance, so two peers can communicate
+// while each of them stays connected to its closest relay instance.
+package cluster
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	reconnectInterval = 5 * time.Second
+	dialTimeout       = 10 * time.Second
+
+	roleDialer   = "dialer"
+	roleAcceptor = "acceptor"
+)
+
+// DeliverFunc delivers a transport message forwarded by a sibling instance to a local peer
+type DeliverFunc func(peerID string, msg []byte)
+
+// Config is the configuration of the relay cluster.
+// ListenAddress: the address the sibling instances connect to.
+// Siblings: the cluster addresses (host:port) of the sibling instances.
+// Secret: the secret shared by the instances to authenticate each other.
+type Config struct {
+	ListenAddress string
+	Siblings      []string
+	Secret        []byte
+}
+
+// Cluster maintains the links to the sibling instances and the directory of the peers connected to them
+type Cluster struct {
+	cfg         Config
+	instanceURL string
+	deliver     DeliverFunc
+	// onSiblingsChanged is called when a sibling becomes linked or unlinked in any direction
+	onSiblingsChanged func()
+
+	listener net.Listener
+
+	// announceMu keeps the order of the peer announcements sent to the siblings, the announcements are sent without
+	// holding mu as they wait for the space in the queues of the links
+	announceMu sync.Mutex
+
+	mu         sync.Mutex
+	localPeers map[string]struct{}
+	// outbound links by the sibling instance URL
+	outbound map[string]*link
+	// the inbound link of the sibling instance the peer is connected to by the peer ID
+	directory map[string]*link
+	inbound   map[*link]struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a new cluster member. The instanceURL identifies this instance for the siblings.
+func New(cfg Config, instanceURL string, deliver DeliverFunc) *Cluster {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Cluster{
+		cfg:         cfg,
+		instanceURL: instanceURL,
+		deliver:     deliver,
+		localPeers:  make(map[string]struct{}),
+		outbound:    make(map[string]*link),
+		directory:   make(map[string]*link),
+		inbound:     make(map[*link]struct{}),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Start listens for the sibling instances and connects to them in the background
+func (c *Cluster) Start() error {
+	if len(c.cfg.Secret) == 0 {
+		return fmt.Errorf("cluster secret is required")
+	}
+
+	listener, err := net.Listen("tcp", c.cfg.ListenAddress)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", c.cfg.ListenAddress, err)
+	}
+	c.listener = listener
+	log.Infof("relay cluster listening on: %s", listener.Addr())
+
+	c.wg.Add(1)
+	go c.acceptLoop()
+
+	for _, sibling := range c.cfg.Siblings {
+		c.wg.Add(1)
+		go c.dialLoop(sibling)
+	}
+	return nil
+}
+
+// SetSiblingsListener sets the function called when the siblings returned by Siblings may have changed. It has to be
+// called before Start.
+func (c *Cluster) SetSiblingsListener(fn func()) {
+	c.onSiblingsChanged = fn
+}
+
+// Siblings returns the instance URLs of the siblings linked in both directions. The messages to the peers connected to
+// them are forwarded and their peers receive the messages of the local peers.
+func (c *Cluster) Siblings() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var siblings []string
+	for in := range c.inbound {
+		if _, ok := c.outbound[in.instance]; ok && !slices.Contains(siblings, in.instance) {
+			siblings = append(siblings, in.instance)
+		}
+	}
+	sort.Strings(siblings)
+	return siblings
+}
+
+func (c *Cluster) notifySiblingsChanged() {
+	// the links are closed on shutdown, the siblings are not gone
+	if c.onSiblingsChanged != nil && c.ctx.Err() == nil {
+		c.onSiblingsChanged()
+	}
+}
+
+// Addr returns the address the cluster listens on
+func (c *Cluster) Addr() net.Addr {
+	return c.listener.Addr()
+}
+
+// Close disconnects from the sibling instances
+func (c *Cluster) Close() {
+	c.cancel()
+	if c.listener != nil {
+		_ = c.listener.Close()
+	}
+
+	c.mu.Lock()
+	for _, l := range c.outbound {
+		l.close()
+	}
+	for l := range c.inbound {
+		l.close()
+	}
+	c.mu.Unlock()
+
+	c.wg.Wait()
+}
+
+// PeerConnected announces a peer connected to this instance to the siblings
+func (c *Cluster) PeerConnected(peerID string) {
+	c.announceMu.Lock()
+	defer c.announceMu.Unlock()
+
+	c.mu.Lock()
+	c.localPeers[peerID] = struct{}{}
+	links := c.outboundLinks()
+	c.mu.Unlock()
+
+	for _, l := range links {
+		l.sendControl(framePeerJoined, peerID)
+	}
+}
+
+// PeerDisconnected announces a peer disconnected from this instance to the siblings
+func (c *Cluster) PeerDisconnected(peerID string) {
+	c.announceMu.Lock()
+	defer c.announceMu.Unlock()
+
+	c.mu.Lock()
+	delete(c.localPeers, peerID)
+	links := c.outboundLinks()
+	c.mu.Unlock()
+
+	for _, l := range links {
+		l.sendControl(framePeerLeft, peerID)
+	}
+}
+
+// outboundLinks returns the snapshot of the outbound links, it must be called with mu held
+func (c *Cluster) outboundLinks() []*link {
+	links := make([]*link, 0, len(c.outbound))
+	for _, l := range c.outbound {
+		links = append(links, l)
+	}
+	return links
+}
+
+// HasPeer returns true if the peer is connected to a sibling instance
+func (c *Cluster) HasPeer(peerID string) bool {
+	return c.siblingLink(peerID) != nil
+}
+
+// Forward sends the transport message to the sibling instance the peer is connected to. It returns false if the peer
+// is not known or the sibling can't take more messages.
+func (c *Cluster) Forward(peerID string, msg []byte) bool {
+	l := c.siblingLink(peerID)
+	if l == nil {
+		return false
+	}
+	return l.sendTransport(peerID, msg)
+}
+
+// siblingLink returns the outbound link to the sibling instance the peer is connected to
+func (c *Cluster) siblingLink(peerID string) *link {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	in, ok := c.directory[peerID]
+	if !ok {
+		return nil
+	}
+	return c.outbound[in.instance]
+}
+
+func (c *Cluster) acceptLoop() {
+	defer c.wg.Done()
+	for {
+		conn, err := c.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Errorf("failed to accept cluster connection: %s", err)
+			}
+			return
+		}
+
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			c.handleInbound(conn)
+		}()
+	}
+}
+
+// handleInbound authenticates the sibling and receives its announcements and forwarded messages
+func (c *Cluster) handleInbound(conn net.Conn) {
+	instance, err := c.acceptHandshake(conn)
+	if err != nil {
+		log.Warnf("cluster handshake with %s failed: %s", conn.RemoteAddr(), err)
+		_ = conn.Close()
+		return
+	}
+	if instance == c.instanceURL {
+		// this instance is in its own sibling list, the dialer closes the connection
+		_ = conn.Close()
+		return
+	}
+
+	in := newLink(conn, instance)
+	c.mu.Lock()
+	if c.ctx.Err() != nil {
+		c.mu.Unlock()
+		in.close()
+		return
+	}
+	c.inbound[in] = struct{}{}
+	c.mu.Unlock()
+	log.Infof("relay cluster sibling connected: %s", instance)
+	c.notifySiblingsChanged()
+
+	defer func() {
+		in.close()
+		c.removeInbound(in)
+		log.Infof("relay cluster sibling disconnected: %s", instance)
+		c.notifySiblingsChanged()
+	}()
+
+	for {
+		frameType, payload, err := readFrame(conn)
+		if err != nil {
+			if c.ctx.Err() == nil {
+				log.Debugf("failed to read from cluster sibling %s: %s", instance, err)
+			}
+			return
+		}
+
+		switch frameType {
+		case framePeerJoined:
+			c.mu.Lock()
+			c.directory[string(payload)] = in
+			c.mu.Unlock()
+		case framePeerLeft:
+			c.mu.Lock()
+			if c.directory[string(payload)] == in {
+				delete(c.directory, string(payload))
+			}
+			c.mu.Unlock()
+		case frameTransport:
+			peerID, msg, err := decodeTransport(payload)
+			if err != nil {
+				log.Errorf("invalid transport message from cluster sibling %s: %s", instance, err)
+				return
+			}
+			c.deliver(peerID, msg)
+		default:
+			log.Warnf("unexpected frame type from cluster sibling %s: %d", instance, frameType)
+		}
+	}
+}
+
+// removeInbound forgets the peers announced over the closed inbound link
+func (c *Cluster) removeInbound(in *link) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.inbound, in)
+	for peerID, l := range c.directory {
+		if l == in {
+			delete(c.directory, peerID)
+		}
+	}
+}
+
+func (c *Cluster) acceptHandshake(conn net.Conn) (string, error) {
+	if err := conn.SetDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		return "", err
+	}
+
+	dialerHello, err := readHello(conn)
+	if err != nil {
+		return "", fmt.Errorf("read hello: %w", err)
+	}
+	if dialerHello.Instance == "" || len(dialerHello.Nonce) != nonceSize {
+		return "", fmt.Errorf("invalid hello")
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return "", err
+	}
+	err = writeHello(conn, hello{
+		Instance: c.instanceURL,
+		Nonce:    nonce,
+		Proof:    proof(c.cfg.Secret, roleAcceptor, c.instanceURL, dialerHello.Nonce),
+	})
+	if err != nil {
+		return "", fmt.Errorf("write hello: %w", err)
+	}
+
+	dialerProof, err := readHello(conn)
+	if err != nil {
+		return "", fmt.Errorf("read proof: %w", err)
+	}
+	if !hmac.Equal(dialerProof.Proof, proof(c.cfg.Secret, roleDialer, dialerHello.Instance, nonce)) {
+		return "", fmt.Errorf("invalid proof of %s", dialerHello.Instance)
+	}
+
+	return dialerHello.Instance, conn.SetDeadline(time.Time{})
+}
+
+// dialLoop keeps the outbound link to the sibling connected
+func (c *Cluster) dialLoop(address string) {
+	defer c.wg.Done()
+	for {
+		err := c.connectSibling(address)
+		if errors.Is(err, errSelf) {
+			log.Debugf("skipping cluster sibling %s, it is this instance", address)
+			return
+		}
+		if c.ctx.Err() != nil {
+			return
+		}
+		log.Debugf("cluster link to %s closed: %v", address, err)
+
+		select {
+		case <-time.After(reconnectInterval):
+		case <-c.ctx.Done():
+			return
+		}
+	}
+}
+
+var errSelf = errors.New("sibling is this instance")
+
+// connectSibling dials the sibling, sends the snapshot of the local peers and then the announcements and forwarded
+// messages until the link breaks
+func (c *Cluster) connectSibling(address string) error {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(c.ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+
+	instance, err := c.dialHandshake(conn)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("handshake: %w", err)
+	}
+	if instance == c.instanceURL {
+		_ = conn.Close()
+		return errSelf
+	}
+
+	out := newLink(conn, instance)
+	writeErr := make(chan error, 1)
+	go func() {
+		writeErr <- out.writeLoop()
+		out.close()
+	}()
+
+	// the link is useless after a read error, the sibling never sends on the outbound link
+	go func() {
+		_, _, _ = readFrame(conn)
+		out.close()
+	}()
+
+	// the announcements made after the snapshot are sent after it
+	c.announceMu.Lock()
+	c.mu.Lock()
+	if c.ctx.Err() != nil {
+		c.mu.Unlock()
+		c.announceMu.Unlock()
+		out.close()
+		return <-writeErr
+	}
+	if old, ok := c.outbound[instance]; ok {
+		old.close()
+	}
+	c.outbound[instance] = out
+	localPeers := make([]string, 0, len(c.localPeers))
+	for peerID := range c.localPeers {
+		localPeers = append(localPeers, peerID)
+	}
+	c.mu.Unlock()
+
+	for _, peerID := range localPeers {
+		out.sendControl(framePeerJoined, peerID)
+	}
+	c.announceMu.Unlock()
+	log.Infof("connected to relay cluster sibling: %s", instance)
+	c.notifySiblingsChanged()
+
+	err = <-writeErr
+
+	c.mu.Lock()
+	if c.outbound[instance] == out {
+		delete(c.outbound, instance)
+	}
+	c.mu.Unlock()
+	c.notifySiblingsChanged()
+	return err
+}
+
+func (c *Cluster) dialHandshake(conn net.Conn) (string, error) {
+	if err := conn.SetDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		return "", err
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return "", err
+	}
+	if err := writeHello(conn, hello{Instance: c.instanceURL, Nonce: nonce}); err != nil {
+		return "", fmt.Errorf("write hello: %w", err)
+	}
+
+	acceptorHello, err := readHello(conn)
+	if err != nil {
+		return "", fmt.Errorf("read hello: %w", err)
+	}
+	if acceptorHello.Instance == "" || len(acceptorHello.Nonce) != nonceSize {
+		return "", fmt.Errorf("invalid hello")
+	}
+	if !hmac.Equal(acceptorHello.Proof, proof(c.cfg.Secret, roleAcceptor, acceptorHello.Instance, nonce)) {
+		return "", fmt.Errorf("invalid proof of %s", acceptorHello.Instance)
+	}
+
+	err = writeHello(conn, hello{Proof: proof(c.cfg.Secret, roleDialer, c.instanceURL, acceptorHello.Nonce)})
+	if err != nil {
+		return "", fmt.Errorf("write proof: %w", err)
+	}
+
+	return acceptorHello.Instance, conn.SetDeadline(time.Time{})
+}
@@ -0,0 +1,111 @@
+package cluster
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type delivered struct {
+	peerID string
+	msg    []byte
+}
+
+func freeAddress(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+	return addr
+}
+
+func startCluster(t *testing.T, address string, instanceURL string, secret string, siblings ...string) (*Cluster, chan delivered) {
+	t.Helper()
+
+	received := make(chan delivered, 10)
+	c := New(Config{ListenAddress: address, Siblings: siblings, Secret: []byte(secret)}, instanceURL, func(peerID string, msg []byte) {
+		received <- delivered{peerID: peerID, msg: msg}
+	})
+	require.NoError(t, c.Start())
+	t.Cleanup(c.Close)
+	return c, received
+}
+
+func TestCluster_Forward(t *testing.T) {
+	addr1, addr2 := freeAddress(t), freeAddress(t)
+	first, received := startCluster(t, addr1, "rels://first:443", "secret", addr2, addr1)
+	second, _ := startCluster(t, addr2, "rels://second:443", "secret", addr1)
+
+	first.PeerConnected("peerA")
+	require.Eventually(t, func() bool {
+		return second.HasPeer("peerA")
+	}, 5*time.Second, 10*time.Millisecond, "peer should be announced to the sibling")
+	assert.False(t, first.HasPeer("peerA"), "local peer shouldn't be in the sibling directory")
+
+	require.True(t, second.Forward("peerA", []byte("payload")))
+	select {
+	case d := <-received:
+		assert.Equal(t, "peerA", d.peerID)
+		assert.Equal(t, []byte("payload"), d.msg)
+	case <-time.After(5 * time.Second):
+		t.Fatal("forwarded message wasn't delivered")
+	}
+
+	assert.False(t, second.Forward("unknown", []byte("payload")), "unknown peer shouldn't be forwarded")
+
+	first.PeerDisconnected("peerA")
+	require.Eventually(t, func() bool {
+		return !second.HasPeer("peerA")
+	}, 5*time.Second, 10*time.Millisecond, "peer should be removed from the sibling directory")
+}
+
+func TestCluster_SnapshotOnConnect(t *testing.T) {
+	addr1, addr2 := freeAddress(t), freeAddress(t)
+	first, _ := startCluster(t, addr1, "rels://first:443", "secret", addr2)
+	first.PeerConnected("peerA")
+
+	second, _ := startCluster(t, addr2, "rels://second:443", "secret", addr1)
+	require.Eventually(t, func() bool {
+		return second.HasPeer("peerA")
+	}, 2*reconnectInterval, 10*time.Millisecond, "peers connected before the link should be announced")
+
+	// the sibling forgets the peers of a disconnected instance
+	first.Close()
+	require.Eventually(t, func() bool {
+		return !second.HasPeer("peerA")
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestCluster_InvalidSecret(t *testing.T) {
+	addr1, addr2 := freeAddress(t), freeAddress(t)
+	first, _ := startCluster(t, addr1, "rels://first:443", "secret", addr2)
+	second, _ := startCluster(t, addr2, "rels://second:443", "other", addr1)
+
+	first.PeerConnected("peerA")
+	second.PeerConnected("peerB")
+
+	time.Sleep(500 * time.Millisecond)
+	assert.False(t, second.HasPeer("peerA"), "sibling with another secret shouldn't be trusted")
+	assert.False(t, first.HasPeer("peerB"), "sibling with another secret shouldn't be trusted")
+}
+
+func TestCluster_Siblings(t *testing.T) {
+	addr1, addr2 := freeAddress(t), freeAddress(t)
+	first, _ := startCluster(t, addr1, "rels://first:443", "secret", addr2)
+	assert.Empty(t, first.Siblings())
+
+	second, _ := startCluster(t, addr2, "rels://second:443", "secret", addr1)
+	require.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"rels://second:443"}, first.Siblings()) &&
+			assert.ObjectsAreEqual([]string{"rels://first:443"}, second.Siblings())
+	}, 2*reconnectInterval, 10*time.Millisecond, "siblings linked in both directions should be listed")
+
+	second.Close()
+	require.Eventually(t, func() bool {
+		return len(first.Siblings()) == 0
+	}, 5*time.Second, 10*time.Millisecond, "closed sibling should be removed")
+}
@@ -0,0 +1,188 @@
+package cluster
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	frameHello byte = iota + 1
+	framePeerJoined
+	framePeerLeft
+	frameTransport
+
+	frameHeaderSize = 5
+	maxFrameSize    = 64 * 1024
+	nonceSize       = 32
+
+	sendQueueSize    = 1024
+	writeTimeout     = 10 * time.Second
+	handshakeTimeout = 10 * time.Second
+)
+
+var errLinkClosed = errors.New("link closed")
+
+// hello is the handshake message of the links. The dialer sends its instance and a nonce, the acceptor answers with
+// its instance, a nonce and the proof of the dialer nonce, finally the dialer sends the proof of the acceptor nonce.
+type hello struct {
+	Instance string `json:"instance,omitempty"`
+	Nonce    []byte `json:"nonce,omitempty"`
+	Proof    []byte `json:"proof,omitempty"`
+}
+
+// link is a connection to a sibling relay instance. The outbound links send the peer announcements and the
+// forwarded transport messages, the inbound links receive them.
+type link struct {
+	conn     net.Conn
+	instance string
+
+	queue     chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newLink(conn net.Conn, instance string) *link {
+	return &link{
+		conn:     conn,
+		instance: instance,
+		queue:    make(chan []byte, sendQueueSize),
+		done:     make(chan struct{}),
+	}
+}
+
+func (l *link) close() {
+	l.closeOnce.Do(func() {
+		close(l.done)
+		_ = l.conn.Close()
+	})
+}
+
+// sendControl queues a peer announcement, it waits for the space in the queue as the announcements can't be lost
+func (l *link) sendControl(frameType byte, peerID string) {
+	select {
+	case l.queue <- encodeFrame(frameType, []byte(peerID)):
+	case <-l.done:
+	}
+}
+
+// sendTransport queues a transport message, it returns false if the queue is full or the link is closed
+func (l *link) sendTransport(peerID string, msg []byte) bool {
+	payload := make([]byte, 1+len(peerID)+len(msg))
+	payload[0] = byte(len(peerID))
+	copy(payload[1:], peerID)
+	copy(payload[1+len(peerID):], msg)
+
+	select {
+	case <-l.done:
+		return false
+	default:
+	}
+
+	select {
+	case l.queue <- encodeFrame(frameTransport, payload):
+		return true
+	default:
+		return false
+	}
+}
+
+// writeLoop writes the queued frames until the link is closed
+func (l *link) writeLoop() error {
+	for {
+		select {
+		case frame := <-l.queue:
+			if err := l.conn.SetWriteDeadline(time.Now().Add(writeTimeout)); err != nil {
+				return err
+			}
+			if _, err := l.conn.Write(frame); err != nil {
+				return err
+			}
+		case <-l.done:
+			return errLinkClosed
+		}
+	}
+}
+
+func encodeFrame(frameType byte, payload []byte) []byte {
+	frame := make([]byte, frameHeaderSize+len(payload))
+	frame[0] = frameType
+	binary.BigEndian.PutUint32(frame[1:frameHeaderSize], uint32(len(payload)))
+	copy(frame[frameHeaderSize:], payload)
+	return frame
+}
+
+func readFrame(r io.Reader) (byte, []byte, error) {
+	header := make([]byte, frameHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxFrameSize {
+		return 0, nil, fmt.Errorf("frame too large: %d", size)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	return header[0], payload, nil
+}
+
+func decodeTransport(payload []byte) (string, []byte, error) {
+	if len(payload) == 0 || len(payload) < 1+int(payload[0]) {
+		return "", nil, fmt.Errorf("invalid transport frame")
+	}
+	idLen := int(payload[0])
+	return string(payload[1 : 1+idLen]), payload[1+idLen:], nil
+}
+
+func writeHello(conn net.Conn, h hello) error {
+	payload, err := json.Marshal(h)
+	if err != nil {
+		return fmt.Errorf("marshal hello: %w", err)
+	}
+	_, err = conn.Write(encodeFrame(frameHello, payload))
+	return err
+}
+
+func readHello(conn net.Conn) (hello, error) {
+	var h hello
+	frameType, payload, err := readFrame(conn)
+	if err != nil {
+		return h, err
+	}
+	if frameType != frameHello {
+		return h, fmt.Errorf("unexpected frame type during handshake: %d", frameType)
+	}
+	if err := json.Unmarshal(payload, &h); err != nil {
+		return h, fmt.Errorf("unmarshal hello: %w", err)
+	}
+	return h, nil
+}
+
+func newNonce() ([]byte, error) {
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return nonce, nil
+}
+
+// proof signs the nonce of the other side with the shared secret, the role prevents reflecting the proof back
+func proof(secret []byte, role string, instance string, nonce []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(role))
+	mac.Write([]byte(instance))
+	mac.Write(nonce)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,272 @@
+package server
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// rebalanceInterval is the period of the fair share recalculation of the global bandwidth
+	rebalanceInterval = time.Second
+	// saturationRatio is the used part of the peer limit above which the peer is considered to want more bandwidth
+	saturationRatio = 0.9
+
+	dropReasonPair   = "pair_limit"
+	dropReasonPeer   = "peer_limit"
+	dropReasonGlobal = "global_limit"
+)
+
+// LimitsConfig is the bandwidth limits configuration of the relayed traffic in bytes per second. A zero value disables
+// the limit.
+// PeerBandwidth: the traffic a single peer can send through the relay.
+// PairBandwidth: the traffic a peer can send to a single other peer.
+// GlobalBandwidth: the traffic the relay forwards in total. It is shared fairly between the sending peers.
+type LimitsConfig struct {
+	PeerBandwidth   int64
+	PairBandwidth   int64
+	GlobalBandwidth int64
+}
+
+// Enabled returns true if any of the limits is set
+func (c LimitsConfig) Enabled() bool {
+	return c.PeerBandwidth > 0 || c.PairBandwidth > 0 || c.GlobalBandwidth > 0
+}
+
+// bandwidthLimiter drops the transport messages exceeding the bandwidth limits. The global bandwidth is shared between
+// the sending peers with max-min fairness: the peers using less than an equal share keep what they use and the rest is
+// split equally between the heavy senders. The shares are recalculated every rebalanceInterval.
+type bandwidthLimiter struct {
+	cfg    LimitsConfig
+	global *rate.Limiter
+
+	peers   map[*peerLimiter]struct{}
+	peersMu sync.Mutex
+
+	done chan struct{}
+}
+
+func newBandwidthLimiter(cfg LimitsConfig) *bandwidthLimiter {
+	l := &bandwidthLimiter{
+		cfg:   cfg,
+		peers: make(map[*peerLimiter]struct{}),
+		done:  make(chan struct{}),
+	}
+
+	if cfg.GlobalBandwidth > 0 {
+		l.global = newRateLimiter(cfg.GlobalBandwidth)
+		go l.rebalanceLoop()
+	}
+	return l
+}
+
+// newPeer registers a connected peer
+func (l *bandwidthLimiter) newPeer() *peerLimiter {
+	p := &peerLimiter{
+		parent: l,
+		pairs:  make(map[string]*rate.Limiter),
+	}
+
+	l.peersMu.Lock()
+	defer l.peersMu.Unlock()
+
+	if limit := l.peerShare(len(l.peers) + 1); limit > 0 {
+		p.limiter = newRateLimiter(limit)
+	}
+	l.peers[p] = struct{}{}
+	return p
+}
+
+// removePeer unregisters a disconnected peer
+func (l *bandwidthLimiter) removePeer(p *peerLimiter) {
+	l.peersMu.Lock()
+	defer l.peersMu.Unlock()
+	delete(l.peers, p)
+}
+
+func (l *bandwidthLimiter) stop() {
+	close(l.done)
+}
+
+// peerShare returns the limit of a peer if the global bandwidth is shared between the given number of peers
+func (l *bandwidthLimiter) peerShare(peers int) int64 {
+	limit := l.cfg.PeerBandwidth
+	if l.cfg.GlobalBandwidth == 0 {
+		return limit
+	}
+
+	// the share of a peer never rounds down to no limit
+	share := max(l.cfg.GlobalBandwidth/int64(max(peers, 1)), 1)
+	if limit == 0 || share < limit {
+		return share
+	}
+	return limit
+}
+
+func (l *bandwidthLimiter) rebalanceLoop() {
+	ticker := time.NewTicker(rebalanceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			l.rebalance()
+		case <-l.done:
+			return
+		}
+	}
+}
+
+// rebalance recalculates the fair shares of the global bandwidth from the traffic of the last interval
+func (l *bandwidthLimiter) rebalance() {
+	l.peersMu.Lock()
+	defer l.peersMu.Unlock()
+
+	type demand struct {
+		peer  *peerLimiter
+		bytes float64
+	}
+
+	var active []demand
+	var idle []*peerLimiter
+	for p := range l.peers {
+		used := float64(p.used.Swap(0)) / rebalanceInterval.Seconds()
+		if used == 0 {
+			idle = append(idle, p)
+			continue
+		}
+
+		// a peer using nearly all of its limit may want more, its demand is unknown
+		if p.limiter == nil || used >= float64(p.limiter.Limit())*saturationRatio {
+			used = math.Inf(1)
+		}
+		active = append(active, demand{peer: p, bytes: used})
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].bytes < active[j].bytes
+	})
+
+	remaining := float64(l.cfg.GlobalBandwidth)
+	for i, d := range active {
+		share := remaining / float64(len(active)-i)
+		d.peer.setLimit(l.capPeerLimit(int64(share)))
+		remaining -= math.Min(d.bytes, share)
+	}
+
+	// an idle peer starting to send gets an equal share until the next rebalance
+	for _, p := range idle {
+		p.setLimit(l.peerShare(len(active) + 1))
+	}
+}
+
+func (l *bandwidthLimiter) capPeerLimit(limit int64) int64 {
+	if l.cfg.PeerBandwidth > 0 && limit > l.cfg.PeerBandwidth {
+		return l.cfg.PeerBandwidth
+	}
+	return max(limit, 1)
+}
+
+// peerLimiter holds the limits of the traffic sent by a peer
+type peerLimiter struct {
+	parent *bandwidthLimiter
+
+	limiter *rate.Limiter
+	used    atomic.Int64
+
+	pairs   map[string]*rate.Limiter
+	pairsMu sync.Mutex
+}
+
+// allow returns true if the message of the given size can be forwarded to the destination peer. Otherwise, it returns
+// the reason of the drop.
+func (p *peerLimiter) allow(dstID string, size int) (string, bool) {
+	now := time.Now()
+
+	var reservations []*rate.Reservation
+	cancel := func() {
+		for _, r := range reservations {
+			r.CancelAt(now)
+		}
+	}
+
+	if pair := p.pairLimiter(dstID); pair != nil {
+		r, ok := reserve(pair, now, size)
+		if !ok {
+			return dropReasonPair, false
+		}
+		reservations = append(reservations, r)
+	}
+
+	if p.limiter != nil {
+		r, ok := reserve(p.limiter, now, size)
+		if !ok {
+			cancel()
+			return dropReasonPeer, false
+		}
+		reservations = append(reservations, r)
+	}
+
+	if global := p.parent.global; global != nil {
+		if _, ok := reserve(global, now, size); !ok {
+			cancel()
+			return dropReasonGlobal, false
+		}
+	}
+
+	p.used.Add(int64(size))
+	return "", true
+}
+
+// pairLimiter returns the limiter of the traffic to the destination peer. The limiters of the disconnected
+// destinations are kept until this peer disconnects.
+func (p *peerLimiter) pairLimiter(dstID string) *rate.Limiter {
+	if p.parent.cfg.PairBandwidth == 0 {
+		return nil
+	}
+
+	p.pairsMu.Lock()
+	defer p.pairsMu.Unlock()
+
+	l, ok := p.pairs[dstID]
+	if !ok {
+		l = newRateLimiter(p.parent.cfg.PairBandwidth)
+		p.pairs[dstID] = l
+	}
+	return l
+}
+
+func (p *peerLimiter) setLimit(limit int64) {
+	if p.limiter == nil {
+		return
+	}
+	p.limiter.SetLimit(rate.Limit(limit))
+	p.limiter.SetBurst(burstSize(limit))
+}
+
+// newRateLimiter returns a token bucket of the given bytes per second holding up to one second of traffic
+func newRateLimiter(limit int64) *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(limit), burstSize(limit))
+}
+
+// burstSize is one second of traffic but at least one message of the maximum size
+func burstSize(limit int64) int {
+	return int(max(limit, bufferSize))
+}
+
+// reserve takes the tokens for the message if they are available without waiting
+func reserve(l *rate.Limiter, now time.Time, size int) (*rate.Reservation, bool) {
+	r := l.ReserveN(now, size)
+	if !r.OK() {
+		return nil, false
+	}
+	if r.DelayFrom(now) > 0 {
+		r.CancelAt(now)
+		return nil, false
+	}
+	return r, true
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+func TestPeerLimiter_PeerLimit(t *testing.T) {
+	l := newBandwidthLimiter(LimitsConfig{PeerBandwidth: 2 * bufferSize})
+	defer l.stop()
+	p := l.newPeer()
+
+	for i := 0; i < 2; i++ {
+		if _, ok := p.allow("dst", bufferSize); !ok {
+			t.Fatalf("message %d within the burst should be allowed", i)
+		}
+	}
+
+	reason, ok := p.allow("dst", bufferSize)
+	if ok || reason != dropReasonPeer {
+		t.Errorf("message over the peer limit should be dropped, got %t, %q", ok, reason)
+	}
+}
+
+func TestPeerLimiter_PairLimit(t *testing.T) {
+	l := newBandwidthLimiter(LimitsConfig{PairBandwidth: bufferSize, PeerBandwidth: 3 * bufferSize})
+	defer l.stop()
+	p := l.newPeer()
+
+	if _, ok := p.allow("first", bufferSize); !ok {
+		t.Fatalf("first message to the pair should be allowed")
+	}
+
+	reason, ok := p.allow("first", bufferSize)
+	if ok || reason != dropReasonPair {
+		t.Errorf("message over the pair limit should be dropped, got %t, %q", ok, reason)
+	}
+
+	if _, ok := p.allow("second", bufferSize); !ok {
+		t.Errorf("message to another peer should be allowed")
+	}
+
+	// the dropped message mustn't consume the peer limit
+	if _, ok := p.allow("third", bufferSize); !ok {
+		t.Errorf("dropped message shouldn't consume the peer limit")
+	}
+}
+
+func TestBandwidthLimiter_FairShare(t *testing.T) {
+	const global = 100 * bufferSize
+	l := newBandwidthLimiter(LimitsConfig{GlobalBandwidth: global})
+	defer l.stop()
+
+	heavy := l.newPeer()
+	light := l.newPeer()
+	idle := l.newPeer()
+
+	// the heavy peer saturates its share, the light one uses a little
+	heavy.used.Store(int64(heavy.limiter.Limit()))
+	light.used.Store(10 * bufferSize)
+	l.rebalance()
+
+	if limit := light.limiter.Limit(); limit != rate.Limit(global/2) {
+		t.Errorf("light peer should be limited to the equal share of the active peers, got %v", limit)
+	}
+	if limit := heavy.limiter.Limit(); limit != rate.Limit(global-10*bufferSize) {
+		t.Errorf("heavy peer should get the bandwidth unused by the light peer, got %v", limit)
+	}
+	if limit := idle.limiter.Limit(); limit != rate.Limit(global/3) {
+		t.Errorf("idle peer should get an equal share once it starts sending, got %v", limit)
+	}
+
+	// both peers saturate their limits
+	heavy.used.Store(int64(heavy.limiter.Limit()))
+	light.used.Store(int64(light.limiter.Limit()))
+	l.rebalance()
+
+	if heavy.limiter.Limit() != light.limiter.Limit() {
+		t.Errorf("saturated peers should share the bandwidth equally, got %v and %v", heavy.limiter.Limit(), light.limiter.Limit())
+	}
+}
+
+func TestBandwidthLimiter_GlobalLimit(t *testing.T) {
+	l := newBandwidthLimiter(LimitsConfig{GlobalBandwidth: 2 * bufferSize})
+	defer l.stop()
+
+	first := l.newPeer()
+	second := l.newPeer()
+	// the peer shares are larger than the global bucket
+	first.setLimit(2 * bufferSize)
+	second.setLimit(2 * bufferSize)
+
+	if _, ok := first.allow("dst", bufferSize); !ok {
+		t.Fatalf("message within the global limit should be allowed")
+	}
+	if _, ok := second.allow("dst", bufferSize); !ok {
+		t.Fatalf("message within the global limit should be allowed")
+	}
+
+	reason, ok := first.allow("dst", bufferSize)
+	if ok || reason != dropReasonGlobal {
+		t.Errorf("message over the global limit should be dropped, got %t, %q", ok, reason)
+	}
+}
+
+func TestBandwidthLimiter_MorePeersThanBandwidth(t *testing.T) {
+	l := newBandwidthLimiter(LimitsConfig{GlobalBandwidth: 2})
+	defer l.stop()
+
+	peers := []*peerLimiter{l.newPeer(), l.newPeer(), l.newPeer()}
+	for i, p := range peers {
+		if p.limiter == nil {
+			t.Fatalf("peer %d should be limited if the share rounds down to zero", i)
+		}
+		p.used.Store(1)
+	}
+
+	// must not panic
+	l.rebalance()
+
+	for i, p := range peers {
+		if limit := p.limiter.Limit(); limit < 1 {
+			t.Errorf("peer %d should keep a positive limit, got %v", i, limit)
+		}
+	}
+}
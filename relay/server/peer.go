@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -12,6 +13,7 @@ import (
 	"github.com/netbirdio/netbird/relay/healthcheck"
 	"github.com/netbirdio/netbird/relay/messages"
 	"github.com/netbirdio/netbird/relay/metrics"
+	"github.com/netbirdio/netbird/relay/server/cluster"
 )
 
 const (
@@ -20,6 +22,21 @@ const (
 	errCloseConn = "failed to close connection to peer: %s"
 )
 
+// PeerStats is a snapshot of the traffic relayed for a peer. Inbound traffic is received from the peer, outbound traffic
+// is sent to the peer. Only the transport messages are counted.
+type PeerStats struct {
+	ID          string
+	RemoteAddr  string
+	ConnectedAt time.Time
+	BytesIn     uint64
+	BytesOut    uint64
+	MessagesIn  uint64
+	MessagesOut uint64
+	// traffic received from the peer and dropped by the bandwidth limits
+	BytesDropped    uint64
+	MessagesDropped uint64
+}
+
 // Peer represents a peer connection
 type Peer struct {
 	metrics *metrics.Metrics
@@ -29,18 +46,32 @@ type Peer struct {
 	conn    net.Conn
 	connMu  sync.RWMutex
 	store   *Store
+	limiter *peerLimiter
+	cluster *cluster.Cluster
+	// clusterInfo is set when the peer receives the changes of the cluster siblings, it is guarded by the clusterInfoMu
+	// of the relay
+	clusterInfo bool
+
+	connectedAt time.Time
+	bytesIn     atomic.Uint64
+	bytesOut    atomic.Uint64
+	msgsIn      atomic.Uint64
+	msgsOut     atomic.Uint64
+	bytesDrop   atomic.Uint64
+	msgsDrop    atomic.Uint64
 }
 
 // NewPeer creates a new Peer instance and prepare custom logging
 func NewPeer(metrics *metrics.Metrics, id []byte, conn net.Conn, store *Store) *Peer {
 	stringID := messages.HashIDToString(id)
 	return &Peer{
-		metrics: metrics,
-		log:     log.WithField("peer_id", stringID),
-		idS:     stringID,
-		idB:     id,
-		conn:    conn,
-		store:   store,
+		metrics:     metrics,
+		log:         log.WithField("peer_id", stringID),
+		idS:         stringID,
+		idB:         id,
+		conn:        conn,
+		store:       store,
+		connectedAt: time.Now(),
 	}
 }
 
@@ -99,6 +130,8 @@ func (p *Peer) handleMsgType(ctx context.Context, msgType messages.MsgType, hc *
 	case messages.MsgTypeHealthCheck:
 		hc.OnHCResponse()
 	case messages.MsgTypeTransport:
+		p.bytesIn.Add(uint64(n))
+		p.msgsIn.Add(1)
 		p.metrics.TransferBytesRecv.Add(ctx, int64(n))
 		p.metrics.PeerActivity(p.String())
 		p.handleTransportMsg(msg)
@@ -148,6 +181,22 @@ func (p *Peer) String() string {
 	return p.idS
 }
 
+// Stats returns the traffic relayed for the peer since it connected
+func (p *Peer) Stats() PeerStats {
+	return PeerStats{
+		ID:          p.idS,
+		RemoteAddr:  p.conn.RemoteAddr().String(),
+		ConnectedAt: p.connectedAt,
+		BytesIn:     p.bytesIn.Load(),
+		BytesOut:    p.bytesOut.Load(),
+		MessagesIn:  p.msgsIn.Load(),
+		MessagesOut: p.msgsOut.Load(),
+
+		BytesDropped:    p.bytesDrop.Load(),
+		MessagesDropped: p.msgsDrop.Load(),
+	}
+}
+
 func (p *Peer) writeWithTimeout(ctx context.Context, buf []byte) error {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
@@ -198,22 +247,49 @@ func (p *Peer) handleTransportMsg(msg []byte) {
 	}
 
 	stringPeerID := messages.HashIDToString(peerID)
-	dp, ok := p.store.Peer(stringPeerID)
-	if !ok {
+	dp, local := p.store.Peer(stringPeerID)
+	if !local && (p.cluster == nil || !p.cluster.HasPeer(stringPeerID)) {
 		p.log.Debugf("peer not found: %s", stringPeerID)
 		return
 	}
 
+	if p.limiter != nil {
+		if reason, ok := p.limiter.allow(stringPeerID, len(msg)); !ok {
+			p.log.Tracef("dropped transport message to %s: %s", stringPeerID, reason)
+			p.bytesDrop.Add(uint64(len(msg)))
+			p.msgsDrop.Add(1)
+			p.metrics.TransferDropped(reason, len(msg))
+			return
+		}
+	}
+
 	err = messages.UpdateTransportMsg(msg, p.idB)
 	if err != nil {
 		p.log.Errorf("failed to update transport message: %s", err)
 		return
 	}
 
-	n, err := dp.Write(msg)
-	if err != nil {
-		p.log.Errorf("failed to write transport message to: %s", dp.String())
+	// the peer is connected to a sibling relay instance
+	if !local {
+		if !p.cluster.Forward(stringPeerID, msg) {
+			p.log.Debugf("failed to forward transport message to %s via the relay cluster", stringPeerID)
+		}
 		return
 	}
+
+	if err := dp.writeTransport(msg); err != nil {
+		p.log.Errorf("failed to write transport message to: %s", dp.String())
+	}
+}
+
+// writeTransport writes a transport message relayed to the peer and accounts the traffic
+func (p *Peer) writeTransport(msg []byte) error {
+	n, err := p.Write(msg)
+	if err != nil {
+		return err
+	}
+	p.bytesOut.Add(uint64(n))
+	p.msgsOut.Add(1)
 	p.metrics.TransferBytesSent.Add(context.Background(), int64(n))
+	return nil
 }
@@ -0,0 +1,147 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/netbirdio/netbird/relay/messages"
+	"github.com/netbirdio/netbird/relay/metrics"
+	"github.com/netbirdio/netbird/relay/server/cluster"
+)
+
+func TestPeer_Stats(t *testing.T) {
+	m, err := metrics.NewMetrics(context.Background(), otel.Meter(""))
+	if err != nil {
+		t.Fatalf("failed to create metrics: %s", err)
+	}
+
+	store := NewStore()
+
+	srcConn, _ := net.Pipe()
+	dstConn, dstClient := net.Pipe()
+	defer dstClient.Close()
+	go func() {
+		_, _ = io.Copy(io.Discard, dstClient)
+	}()
+
+	srcID, _ := messages.HashID("source")
+	dstID, _ := messages.HashID("destination")
+	src := NewPeer(m, srcID, srcConn, store)
+	dst := NewPeer(m, dstID, dstConn, store)
+	store.AddPeer(src)
+	store.AddPeer(dst)
+
+	msg, err := messages.MarshalTransportMsg(dstID, []byte("payload"))
+	if err != nil {
+		t.Fatalf("failed to marshal transport message: %s", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		buf := append([]byte(nil), msg...)
+		src.handleMsgType(context.Background(), messages.MsgTypeTransport, nil, len(buf), buf)
+	}
+
+	srcStats := src.Stats()
+	if srcStats.BytesIn != uint64(2*len(msg)) || srcStats.MessagesIn != 2 {
+		t.Errorf("unexpected inbound traffic of the source: %d bytes, %d messages", srcStats.BytesIn, srcStats.MessagesIn)
+	}
+	if srcStats.BytesOut != 0 || srcStats.MessagesOut != 0 {
+		t.Errorf("source shouldn't have outbound traffic: %d bytes, %d messages", srcStats.BytesOut, srcStats.MessagesOut)
+	}
+
+	dstStats := dst.Stats()
+	if dstStats.BytesOut != uint64(2*len(msg)) || dstStats.MessagesOut != 2 {
+		t.Errorf("unexpected outbound traffic of the destination: %d bytes, %d messages", dstStats.BytesOut, dstStats.MessagesOut)
+	}
+	if dstStats.ID != dst.String() {
+		t.Errorf("unexpected peer ID in stats: %s", dstStats.ID)
+	}
+}
+
+func TestPeer_ForwardToClusterSibling(t *testing.T) {
+	addresses := make([]string, 2)
+	for i := range addresses {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to reserve cluster address: %s", err)
+		}
+		addresses[i] = l.Addr().String()
+		_ = l.Close()
+	}
+
+	relays := make([]*Relay, 2)
+	for i, exposed := range []string{"first:443", "second:443"} {
+		r, err := NewRelay(otel.Meter(""), exposed, false, nil)
+		if err != nil {
+			t.Fatalf("failed to create relay: %s", err)
+		}
+		err = r.EnableCluster(cluster.Config{ListenAddress: addresses[i], Siblings: []string{addresses[1-i]}, Secret: []byte("secret")})
+		if err != nil {
+			t.Fatalf("failed to enable cluster: %s", err)
+		}
+		relays[i] = r
+		defer r.metricsCancel()
+		defer r.cluster.Close()
+	}
+
+	addPeer := func(r *Relay, name string) (*Peer, net.Conn) {
+		conn, client := net.Pipe()
+		id, _ := messages.HashID(name)
+		p := NewPeer(r.metrics, id, conn, r.store)
+		p.cluster = r.cluster
+		r.store.AddPeer(p)
+		r.cluster.PeerConnected(p.String())
+		return p, client
+	}
+
+	src, _ := addPeer(relays[0], "source")
+	dst, dstClient := addPeer(relays[1], "destination")
+	defer dstClient.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !relays[0].cluster.HasPeer(dst.String()) {
+		if time.Now().After(deadline) {
+			t.Fatalf("destination peer wasn't announced to the sibling")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	msg, err := messages.MarshalTransportMsg(dst.idB, []byte("payload"))
+	if err != nil {
+		t.Fatalf("failed to marshal transport message: %s", err)
+	}
+	src.handleMsgType(context.Background(), messages.MsgTypeTransport, nil, len(msg), msg)
+
+	buf := make([]byte, bufferSize)
+	_ = dstClient.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := dstClient.Read(buf)
+	if err != nil {
+		t.Fatalf("forwarded message wasn't received: %s", err)
+	}
+
+	senderID, payload, err := messages.UnmarshalTransportMsg(buf[:n])
+	if err != nil {
+		t.Fatalf("failed to unmarshal forwarded message: %s", err)
+	}
+	if !bytes.Equal(senderID, src.idB) {
+		t.Errorf("forwarded message should carry the source peer ID")
+	}
+	if string(payload) != "payload" {
+		t.Errorf("unexpected payload: %s", payload)
+	}
+
+	// the pipe returns from the write once the message is read, the accounting follows it
+	deadline = time.Now().Add(time.Second)
+	for dst.Stats().MessagesOut != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("forwarded message should be accounted for the destination peer")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
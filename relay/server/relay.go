@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -13,8 +14,10 @@ import (
 	"go.opentelemetry.io/otel/metric"
 
 	"github.com/netbirdio/netbird/relay/auth"
+	"github.com/netbirdio/netbird/relay/messages"
 	//nolint:staticcheck
 	"github.com/netbirdio/netbird/relay/metrics"
+	"github.com/netbirdio/netbird/relay/server/cluster"
 )
 
 // Relay represents the relay server
@@ -24,9 +27,15 @@ type Relay struct {
 	validator     auth.Validator
 
 	store       *Store
+	limiter     *bandwidthLimiter
+	cluster     *cluster.Cluster
 	instanceURL string
 	preparedMsg *preparedMsg
 
+	// clusterInfoMu orders the cluster info messages sent to the peers
+	clusterInfoMu   sync.Mutex
+	clusterSiblings []string
+
 	closed  bool
 	closeMu sync.RWMutex
 }
@@ -76,6 +85,86 @@ func NewRelay(meter metric.Meter, exposedAddress string, tlsSupport bool, valida
 	return r, nil
 }
 
+// SetBandwidthLimits enables the bandwidth limits of the relayed traffic. It has to be called before the relay accepts
+// the first peer.
+func (r *Relay) SetBandwidthLimits(cfg LimitsConfig) {
+	if !cfg.Enabled() {
+		return
+	}
+	r.limiter = newBandwidthLimiter(cfg)
+}
+
+// EnableCluster connects the relay to the sibling instances. The peers connected to the siblings become reachable for
+// the local peers. It has to be called before the relay accepts the first peer.
+func (r *Relay) EnableCluster(cfg cluster.Config) error {
+	c := cluster.New(cfg, r.instanceURL, r.deliverForwarded)
+	c.SetSiblingsListener(r.broadcastClusterInfo)
+	r.cluster = c
+	if err := c.Start(); err != nil {
+		r.cluster = nil
+		return fmt.Errorf("start relay cluster: %w", err)
+	}
+	return nil
+}
+
+// broadcastClusterInfo sends the changed siblings of the cluster to the peers, the peers reach the peers connected to
+// the siblings via this instance
+func (r *Relay) broadcastClusterInfo() {
+	r.clusterInfoMu.Lock()
+	defer r.clusterInfoMu.Unlock()
+
+	siblings := r.cluster.Siblings()
+	if slices.Equal(siblings, r.clusterSiblings) {
+		return
+	}
+	r.clusterSiblings = siblings
+	log.Infof("relay cluster siblings changed: %v", siblings)
+
+	msg, err := messages.MarshalClusterInfo(siblings)
+	if err != nil {
+		log.Errorf("failed to marshal cluster info: %s", err)
+		return
+	}
+
+	for _, peer := range r.store.Peers() {
+		if !peer.clusterInfo {
+			continue
+		}
+		if _, err := peer.Write(msg); err != nil {
+			peer.log.Debugf("failed to send cluster info: %s", err)
+		}
+	}
+}
+
+// sendClusterInfo sends the siblings of the cluster to the peer and the later changes of them
+func (r *Relay) sendClusterInfo(peer *Peer) error {
+	r.clusterInfoMu.Lock()
+	defer r.clusterInfoMu.Unlock()
+
+	msg, err := messages.MarshalClusterInfo(r.clusterSiblings)
+	if err != nil {
+		return fmt.Errorf("marshal cluster info: %w", err)
+	}
+	if _, err := peer.Write(msg); err != nil {
+		return fmt.Errorf("write cluster info: %w", err)
+	}
+	peer.clusterInfo = true
+	return nil
+}
+
+// deliverForwarded writes a transport message forwarded by a sibling instance to the local peer
+func (r *Relay) deliverForwarded(peerID string, msg []byte) {
+	peer, ok := r.store.Peer(peerID)
+	if !ok {
+		log.Debugf("peer of the forwarded message not found: %s", peerID)
+		return
+	}
+
+	if err := peer.writeTransport(msg); err != nil {
+		peer.log.Errorf("failed to write forwarded transport message: %s", err)
+	}
+}
+
 // getInstanceURL checks if user supplied a URL scheme otherwise adds to the
 // provided address according to TLS definition and parses the address before returning it
 func getInstanceURL(exposedAddress string, tlsSupported bool) (string, error) {
@@ -126,14 +215,26 @@ func (r *Relay) Accept(conn net.Conn) {
 	}
 
 	peer := NewPeer(r.metrics, peerID, conn, r.store)
+	if r.limiter != nil {
+		peer.limiter = r.limiter.newPeer()
+	}
+	peer.cluster = r.cluster
 	peer.log.Infof("peer connected from: %s", conn.RemoteAddr())
 	storeTime := time.Now()
 	r.store.AddPeer(peer)
 	r.metrics.RecordPeerStoreTime(time.Since(storeTime))
 	r.metrics.PeerConnected(peer.String())
+	if r.cluster != nil {
+		r.cluster.PeerConnected(peer.String())
+	}
 	go func() {
 		peer.Work()
-		r.store.DeletePeer(peer)
+		if r.store.DeletePeer(peer) && r.cluster != nil {
+			r.cluster.PeerDisconnected(peer.String())
+		}
+		if peer.limiter != nil {
+			r.limiter.removePeer(peer.limiter)
+		}
 		peer.log.Debugf("relay connection closed")
 		r.metrics.PeerDisconnected(peer.String())
 	}()
@@ -141,6 +242,11 @@ func (r *Relay) Accept(conn net.Conn) {
 	if err := h.handshakeResponse(); err != nil {
 		log.Errorf("failed to send handshake response, close peer: %s", err)
 		peer.Close()
+	} else if r.cluster != nil && h.handshakeMethodAuth {
+		// the clients of the deprecated hello handshake don't know the cluster info message
+		if err := r.sendClusterInfo(peer); err != nil {
+			peer.log.Errorf("failed to send cluster info: %s", err)
+		}
 	}
 	r.metrics.RecordAuthenticationTime(time.Since(acceptTime))
 }
@@ -162,10 +268,38 @@ func (r *Relay) Shutdown(ctx context.Context) {
 		}(peer)
 	}
 	wg.Wait()
+	if r.cluster != nil {
+		r.cluster.Close()
+	}
+	if r.limiter != nil {
+		r.limiter.stop()
+	}
 	r.metricsCancel()
 	r.closed = true
 }
 
+// Peers returns the traffic stats of the connected peers
+func (r *Relay) Peers() []PeerStats {
+	peers := r.store.Peers()
+	stats := make([]PeerStats, 0, len(peers))
+	for _, peer := range peers {
+		stats = append(stats, peer.Stats())
+	}
+	return stats
+}
+
+// DisconnectPeer closes the connection with the peer gracefully. It returns false if the peer is not connected.
+func (r *Relay) DisconnectPeer(ctx context.Context, peerID string) bool {
+	peer, ok := r.store.Peer(peerID)
+	if !ok {
+		return false
+	}
+
+	peer.log.Infof("disconnecting peer on admin request")
+	peer.CloseGracefully(ctx)
+	return true
+}
+
 // InstanceURL returns the instance URL of the relay server
 func (r *Relay) InstanceURL() string {
 	return r.instanceURL
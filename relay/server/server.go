@@ -10,6 +10,7 @@ import (
 
 	nberrors "github.com/netbirdio/netbird/client/errors"
 	"github.com/netbirdio/netbird/relay/auth"
+	"github.com/netbirdio/netbird/relay/server/cluster"
 	"github.com/netbirdio/netbird/relay/server/listener"
 	"github.com/netbirdio/netbird/relay/server/listener/quic"
 	"github.com/netbirdio/netbird/relay/server/listener/ws"
@@ -48,6 +49,16 @@ func NewServer(meter metric.Meter, exposedAddress string, tlsSupport bool, authV
 	}, nil
 }
 
+// SetBandwidthLimits enables the bandwidth limits of the relayed traffic. It has to be called before Listen.
+func (r *Server) SetBandwidthLimits(cfg LimitsConfig) {
+	r.relay.SetBandwidthLimits(cfg)
+}
+
+// EnableCluster connects the relay server to the sibling instances. It has to be called before Listen.
+func (r *Server) EnableCluster(cfg cluster.Config) error {
+	return r.relay.EnableCluster(cfg)
+}
+
 // Listen starts the relay server.
 func (r *Server) Listen(cfg ListenerConfig) error {
 	wSListener := &ws.Listener{
@@ -106,3 +117,13 @@ func (r *Server) Shutdown(ctx context.Context) error {
 func (r *Server) InstanceURL() string {
 	return r.relay.instanceURL
 }
+
+// Peers returns the traffic stats of the peers connected to the relay server.
+func (r *Server) Peers() []PeerStats {
+	return r.relay.Peers()
+}
+
+// DisconnectPeer closes the connection with the peer. It returns false if the peer is not connected.
+func (r *Server) DisconnectPeer(ctx context.Context, peerID string) bool {
+	return r.relay.DisconnectPeer(ctx, peerID)
+}
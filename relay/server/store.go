@@ -30,20 +30,22 @@ func (s *Store) AddPeer(peer *Peer) {
 	s.peers[peer.String()] = peer
 }
 
-// DeletePeer deletes a peer from the store
-func (s *Store) DeletePeer(peer *Peer) {
+// DeletePeer deletes a peer from the store. It returns false if the peer has been already replaced by a newer
+// connection with the same ID.
+func (s *Store) DeletePeer(peer *Peer) bool {
 	s.peersLock.Lock()
 	defer s.peersLock.Unlock()
 
 	dp, ok := s.peers[peer.String()]
 	if !ok {
-		return
+		return false
 	}
 	if dp != peer {
-		return
+		return false
 	}
 
 	delete(s.peers, peer.String())
+	return true
 }
 
 // Peer returns a peer by its ID